@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// anonymizedRoot replaces the absolute target directory under --anonymize-paths.
+const anonymizedRoot = "<root>"
+
+// pathAnonymizer returns a function that replaces every occurrence of the
+// absolute root path in a string with anonymizedRoot. An occurrence only
+// matches when it ends the path component, so "/src/app" does not rewrite
+// "/src/app-old". A filesystem root such as "/" is left alone, since replacing
+// it would mangle every path rather than hide one.
+func pathAnonymizer(root string) func(string) string {
+	root = filepath.Clean(root)
+	if !filepath.IsAbs(root) || filepath.Dir(root) == root {
+		return func(s string) string { return s }
+	}
+
+	pattern := regexp.MustCompile(regexp.QuoteMeta(root) + `([^A-Za-z0-9._-]|$)`)
+	return func(s string) string {
+		if !strings.Contains(s, root) {
+			return s
+		}
+		return pattern.ReplaceAllString(s, anonymizedRoot+"${1}")
+	}
+}
+
+// pathAnonymizerHook is a logrus hook that anonymizes the target directory in
+// log messages and in string, error, and fmt.Stringer fields before they are
+// formatted.
+type pathAnonymizerHook struct {
+	anonymize func(string) string
+}
+
+// newPathAnonymizerHook returns a hook that hides root in every log entry.
+func newPathAnonymizerHook(root string) *pathAnonymizerHook {
+	return &pathAnonymizerHook{anonymize: pathAnonymizer(root)}
+}
+
+// Levels implements logrus.Hook.
+func (h *pathAnonymizerHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook. Each entry carries its own copy of the fields,
+// so rewriting them in place does not affect other entries.
+func (h *pathAnonymizerHook) Fire(entry *logrus.Entry) error {
+	entry.Message = h.anonymize(entry.Message)
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case string:
+			entry.Data[key] = h.anonymize(v)
+		case error:
+			entry.Data[key] = h.anonymize(v.Error())
+		case fmt.Stringer:
+			entry.Data[key] = h.anonymize(v.String())
+		}
+	}
+	return nil
+}
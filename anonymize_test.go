@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+func TestPathAnonymizer(t *testing.T) {
+	anonymize := pathAnonymizer("/home/alice/secret-project")
+
+	assert.Equal(t, "<root>/pkg/main.go", anonymize("/home/alice/secret-project/pkg/main.go"))
+	assert.Equal(t, "cd <root> && make", anonymize("cd /home/alice/secret-project && make"))
+	assert.Equal(t, "<root>", anonymize("/home/alice/secret-project"))
+	assert.Equal(t, "/home/alice/secret-project-old/x", anonymize("/home/alice/secret-project-old/x"),
+		"a sibling sharing the prefix is not the root")
+	assert.Equal(t, "/home/alice/other", anonymize("/home/alice/other"))
+
+	identity := pathAnonymizer(string(filepath.Separator))
+	assert.Equal(t, "/etc/passwd", identity("/etc/passwd"), "a filesystem root is never rewritten")
+}
+
+func TestPathAnonymizerHook(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	logger.AddHook(newPathAnonymizerHook("/srv/app"))
+	logger.AddHook(hook) // after the anonymizer, so it captures rewritten entries
+
+	logger.WithFields(logrus.Fields{
+		"directory": "/srv/app/pkg",
+		"error":     errors.New("open /srv/app/pkg/x.go: permission denied"),
+		"count":     3,
+	}).Warn("Failed to read /srv/app/pkg/x.go")
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, "Failed to read <root>/pkg/x.go", entry.Message)
+	assert.Equal(t, "<root>/pkg", entry.Data["directory"])
+	assert.Equal(t, "open <root>/pkg/x.go: permission denied", entry.Data["error"])
+	assert.Equal(t, 3, entry.Data["count"])
+}
+
+// TestAnonymizePaths verifies that with --anonymize-paths the absolute target
+// directory appears in neither the prompt nor the logs of a directory run.
+func TestAnonymizePaths(t *testing.T) {
+	root := t.TempDir()
+	pkg := filepath.Join(root, "pkg")
+	require.NoError(t, os.MkdirAll(pkg, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pkg, "main.go"),
+		[]byte("// generated from "+filepath.Join(root, "gen", "spec.yaml")+"\npackage main\n"), 0600))
+
+	std := logrus.StandardLogger()
+	originalHooks := std.ReplaceHooks(make(logrus.LevelHooks))
+	originalLevel := std.GetLevel()
+	t.Cleanup(func() {
+		std.ReplaceHooks(originalHooks)
+		std.SetLevel(originalLevel)
+	})
+	std.SetLevel(logrus.DebugLevel)
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithAnonymizePaths(true)
+	logrus.AddHook(newPathAnonymizerHook(cfg.TargetDir))
+	hook := test.NewGlobal()
+
+	var prompt string
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { prompt = args.String(1) }).
+		Return("# summary\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient}, llm.WithPromptTemplate("{{.Directory}}\n{{.FileContents}}"))
+	require.NoError(t, err)
+
+	r := processDirectory(context.Background(), pkg, true, filesystem.IgnoreChain{}, cfg, service)
+	require.True(t, r.success, "processDirectory failed: %v", r.err)
+
+	assert.Contains(t, prompt, "// generated from <root>/gen/spec.yaml")
+	assert.NotContains(t, prompt, root)
+
+	require.NotEmpty(t, hook.AllEntries())
+	for _, entry := range hook.AllEntries() {
+		line, err := entry.String()
+		require.NoError(t, err)
+		assert.NotContains(t, line, root)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+
+	"glance/config"
+	"glance/filesystem"
+)
+
+// bubbleReason returns why dir's ancestors should be marked for regeneration
+// after processing it, or "" when they should not. By default only a
+// successful regeneration bubbles up. With --always-bubble, a child that was up
+// to date also bubbles up when its summary is newer than its parent's (or the
+// parent has none), so a parent written before the child existed or changed
+// still catches up. In-place summaries already count toward the parent's own
+// mtime check; this matters where they do not, such as an --output-dir mirror.
+// Top-down parents never read child summaries, and have already been processed
+// anyway.
+func bubbleReason(cfg *config.Config, dir string, r result, forceDir bool) string {
+	if !r.success || cfg.TopDown {
+		return ""
+	}
+	if r.attempts > 0 && forceDir {
+		return "successfully regenerated"
+	}
+	if cfg.AlwaysBubble && r.attempts == 0 && summaryNewerThanParent(cfg, dir) {
+		return "summary newer than parent's"
+	}
+	return ""
+}
+
+// summaryNewerThanParent reports whether dir's glance output exists and is
+// newer than its parent's, or the parent within the scan root has none.
+func summaryNewerThanParent(cfg *config.Config, dir string) bool {
+	parent := filepath.Dir(dir)
+	if dir == scanRoot(cfg) || parent == dir {
+		return false
+	}
+
+	child, err := os.Stat(filepath.Join(glanceOutputDir(cfg, dir), filesystem.GlanceFilename))
+	if err != nil {
+		return false // Nothing for the parent to reflect
+	}
+	parentSummary, err := os.Stat(filepath.Join(glanceOutputDir(cfg, parent), filesystem.GlanceFilename))
+	if err != nil {
+		logrus.WithField("directory", parent).Debug("Parent has no glance output yet")
+		return true
+	}
+	return child.ModTime().After(parentSummary.ModTime())
+}
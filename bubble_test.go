@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestAlwaysBubble verifies that an up-to-date child whose summary is newer
+// than its parent's regenerates the parent only with --always-bubble. Summaries
+// live in an --output-dir mirror, where the parent's own mtime check cannot see
+// the child's newer summary.
+func TestAlwaysBubble(t *testing.T) {
+	root, mirror := t.TempDir(), t.TempDir()
+	parent := filepath.Join(root, "parent")
+	child := filepath.Join(parent, "child")
+	require.NoError(t, os.MkdirAll(child, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(mirror, "parent", "child"), 0755))
+	for _, rel := range []string{".", "parent", filepath.Join("parent", "child")} {
+		require.NoError(t, os.WriteFile(filepath.Join(root, rel, "main.go"), []byte("package main\n"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(mirror, rel, filesystem.GlanceFilename), []byte("# summary\n"), 0600))
+	}
+
+	// Everything is up to date, but the parent was summarized before the child
+	base := time.Now().Add(-time.Hour)
+	require.NoError(t, filepath.WalkDir(root, func(path string, _ fs.DirEntry, err error) error {
+		require.NoError(t, err)
+		return os.Chtimes(path, base, base)
+	}))
+	for rel, age := range map[string]time.Duration{".": time.Minute, "parent": time.Minute, filepath.Join("parent", "child"): 2 * time.Minute} {
+		stamp := base.Add(age)
+		require.NoError(t, os.Chtimes(filepath.Join(mirror, rel, filesystem.GlanceFilename), stamp, stamp))
+	}
+
+	run := func(cfg *config.Config) []string {
+		t.Helper()
+		dirs, ignoreChains, err := scanDirectories(cfg)
+		require.NoError(t, err)
+
+		var prompts []string
+		mockLLMClient := new(mocks.LLMClient)
+		mockLLMClient.On("Generate", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { prompts = append(prompts, strings.SplitN(args.String(1), "\n", 2)[0]) }).
+			Return("# regenerated\n", nil).Maybe()
+		mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+		service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+			llm.WithPromptTemplate("dir {{.Directory}}\n{{.FileContents}}"))
+		require.NoError(t, err)
+
+		results, _ := processDirectories(context.Background(), dirs, ignoreChains, cfg, service, io.Discard)
+		for _, r := range results {
+			assert.True(t, r.success, "processing %s failed: %v", r.dir, r.err)
+		}
+		return prompts
+	}
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithOutputDir(mirror)
+	assert.Empty(t, run(cfg), "by default only regenerated children bubble up")
+	assert.Equal(t, []string{"dir parent"}, run(cfg.WithAlwaysBubble(true)),
+		"with --always-bubble the parent catches up with its newer child")
+	assert.Empty(t, run(cfg.WithAlwaysBubble(true)), "once caught up, nothing bubbles")
+}
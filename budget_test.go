@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestBudgetStopsGeneration verifies that a low --max-requests cap stops LLM calls,
+// records the remaining directories as budget-skipped, and is reported in the debrief.
+func TestBudgetStopsGeneration(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	root := t.TempDir()
+	for i := 0; i < 4; i++ {
+		dir := filepath.Join(root, fmt.Sprintf("pkg%d", i))
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0600))
+	}
+
+	mockLLMClient := new(mocks.LLMClient)
+	mockClient := &MockClient{LLMClient: mockLLMClient}
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).Return("# summary\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(mockClient, llm.WithBudget(llm.NewBudget(2, 0)))
+	require.NoError(t, err)
+
+	dirsList, ignoreChains, err := filesystem.ListDirsWithIgnores(root)
+	require.NoError(t, err)
+	reverseSlice(dirsList)
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithMaxRequests(2)
+	results, _ := processDirectories(context.Background(), dirsList, ignoreChains, cfg, service, io.Discard)
+
+	mockLLMClient.AssertNumberOfCalls(t, "Generate", 2)
+
+	var succeeded, skipped int
+	for _, r := range results {
+		if r.success {
+			succeeded++
+		}
+		if r.budgetSkipped {
+			skipped++
+			assert.False(t, r.success)
+			assert.NoFileExists(t, filepath.Join(r.dir, filesystem.GlanceFilename))
+		}
+	}
+	assert.Equal(t, 2, succeeded)
+	assert.Equal(t, 3, skipped, "the remaining two packages and the root should be skipped")
+
+	hook.Reset()
+	printDebrief(results, true)
+
+	var warned bool
+	for _, entry := range hook.AllEntries() {
+		assert.NotEqual(t, logrus.ErrorLevel, entry.Level, "budget-skipped directories are not failures")
+		if entry.Level == logrus.WarnLevel && entry.Data["skipped_count"] == 3 {
+			warned = true
+		}
+	}
+	assert.True(t, warned, "debrief should report that the budget was exhausted")
+}
@@ -0,0 +1,132 @@
+// Package cache persists generated summaries across runs so that identical
+// directory content is summarized by the LLM only once.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"glance/filesystem"
+)
+
+// dirMode restricts the cache directory to the current user.
+const dirMode = 0o700
+
+// SummaryStore stores generated summaries keyed by a hash of their input.
+type SummaryStore interface {
+	// Get returns the summary stored under hash, if any.
+	Get(hash string) (string, bool)
+
+	// Put stores content under hash, replacing any previous entry.
+	Put(hash string, content string) error
+}
+
+// HashInput returns the cache key for a set of inputs, such as a model name and
+// the rendered prompt. Inputs are length-prefixed so that different splits of
+// the same bytes never collide.
+func HashInput(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		_, _ = fmt.Fprintf(h, "%d:%s", len(part), part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// DefaultDir returns the per-user summary cache directory (e.g. ~/.cache/glance).
+func DefaultDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache directory: %w", err)
+	}
+	return filepath.Join(base, "glance"), nil
+}
+
+// FileStore is a SummaryStore that keeps one file per entry in a directory.
+// Writes are atomic, so concurrent runs sharing a cache never observe partial entries.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a FileStore rooted at dir, creating the directory if needed.
+//
+// Parameters:
+//   - dir: The cache directory
+//
+// Returns:
+//   - A new FileStore
+//   - An error if the directory cannot be created
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		return nil, errors.New("cache directory cannot be empty")
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cache directory: %w", err)
+	}
+	if err := os.MkdirAll(absDir, dirMode); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	return &FileStore{dir: absDir}, nil
+}
+
+// Get implements SummaryStore. Missing or unreadable entries are reported as misses.
+func (s *FileStore) Get(hash string) (string, bool) {
+	path, err := s.entryPath(hash)
+	if err != nil {
+		return "", false
+	}
+
+	// #nosec G304 -- Path has been validated using filesystem.ValidateFilePath
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return string(content), true
+}
+
+// Put implements SummaryStore.
+func (s *FileStore) Put(hash string, content string) error {
+	path, err := s.entryPath(hash)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	_, writeErr := tmp.WriteString(content)
+	closeErr := tmp.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to move cache entry into place: %w", err)
+	}
+	return nil
+}
+
+// entryPath returns the validated path of the entry for hash.
+// Only hex-encoded SHA-256 digests are accepted as keys.
+func (s *FileStore) entryPath(hash string) (string, error) {
+	if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != sha256.Size {
+		return "", fmt.Errorf("invalid cache key %q", hash)
+	}
+
+	path, err := filesystem.ValidateFilePath(filepath.Join(s.dir, hash+".md"), s.dir, false, false)
+	if err != nil {
+		return "", fmt.Errorf("invalid cache entry path: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "glance"))
+	require.NoError(t, err)
+
+	key := HashInput("model", "prompt")
+
+	t.Run("Miss", func(t *testing.T) {
+		_, ok := store.Get(key)
+		assert.False(t, ok)
+	})
+
+	t.Run("Round trip", func(t *testing.T) {
+		require.NoError(t, store.Put(key, "# summary"))
+		content, ok := store.Get(key)
+		assert.True(t, ok)
+		assert.Equal(t, "# summary", content)
+
+		require.NoError(t, store.Put(key, "# updated"))
+		content, _ = store.Get(key)
+		assert.Equal(t, "# updated", content)
+	})
+
+	t.Run("Entries are private", func(t *testing.T) {
+		info, err := os.Stat(filepath.Join(store.dir, key+".md"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	})
+
+	t.Run("Rejects keys that are not digests", func(t *testing.T) {
+		for _, bad := range []string{"", "abc", "../../etc/passwd", key[:10]} {
+			assert.Error(t, store.Put(bad, "x"), "key %q", bad)
+			_, ok := store.Get(bad)
+			assert.False(t, ok)
+		}
+	})
+}
+
+func TestNewFileStoreRejectsEmptyDir(t *testing.T) {
+	_, err := NewFileStore("")
+	assert.Error(t, err)
+}
+
+func TestHashInput(t *testing.T) {
+	assert.Equal(t, HashInput("a", "b"), HashInput("a", "b"))
+	assert.NotEqual(t, HashInput("a", "b"), HashInput("a", "c"))
+	assert.NotEqual(t, HashInput("ab", "c"), HashInput("a", "bc"), "part boundaries must affect the key")
+	assert.Len(t, HashInput("x"), 64)
+}
+
+func TestDefaultDir(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/tmp/xdg-cache")
+	t.Setenv("HOME", "/tmp/home")
+	dir, err := DefaultDir()
+	require.NoError(t, err)
+	assert.Equal(t, "glance", filepath.Base(dir))
+}
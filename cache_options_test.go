@@ -0,0 +1,93 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"glance/cache"
+	"glance/config"
+	"glance/filesystem"
+	"glance/llm"
+)
+
+// resolveServiceConfig applies service options onto the defaults.
+func resolveServiceConfig(options []func(*llm.ServiceConfig)) llm.ServiceConfig {
+	resolved := llm.DefaultServiceConfig()
+	for _, option := range options {
+		option(&resolved)
+	}
+	return resolved
+}
+
+// TestServiceOptionsSummaryCache verifies that the summary cache is enabled by
+// default under the user cache directory and that --no-cache bypasses it.
+func TestServiceOptionsSummaryCache(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", home)
+	cacheDir, err := cache.DefaultDir()
+	require.NoError(t, err)
+
+	disabled := resolveServiceConfig(serviceOptions(config.NewDefaultConfig().WithNoCache(true), "model"))
+	assert.Nil(t, disabled.SummaryCache)
+	assert.NoDirExists(t, cacheDir, "--no-cache must not touch the cache directory")
+
+	enabled := resolveServiceConfig(serviceOptions(config.NewDefaultConfig(), "model"))
+	assert.NotNil(t, enabled.SummaryCache)
+	assert.DirExists(t, cacheDir)
+}
+
+// TestServiceOptionsGenerationSettings verifies that the system instruction
+// and generation options reach the service, so changing them changes the
+// summary cache key.
+func TestServiceOptionsGenerationSettings(t *testing.T) {
+	defaults := resolveServiceConfig(serviceOptions(config.NewDefaultConfig().WithNoCache(true), "model"))
+	assert.Empty(t, defaults.SystemInstructions)
+	assert.Empty(t, defaults.GenerationOptions)
+
+	cfg := config.NewDefaultConfig().WithNoCache(true).
+		WithSystemInstructions("Write for new contributors.").
+		WithDeterministic(true).
+		WithSafetySettings([]llm.SafetySetting{{Category: "HARM_CATEGORY_HARASSMENT", Threshold: "BLOCK_NONE"}})
+	resolved := resolveServiceConfig(serviceOptions(cfg, "model"))
+	assert.Equal(t, "Write for new contributors.", resolved.SystemInstructions)
+	assert.Equal(t, "deterministic;safety:HARM_CATEGORY_HARASSMENT=BLOCK_NONE", resolved.GenerationOptions)
+}
+
+// TestServiceOptionsFileScorer verifies that the CLI ranks prompt files with
+// the default scorer instead of listing them alphabetically.
+func TestServiceOptionsFileScorer(t *testing.T) {
+	resolved := resolveServiceConfig(serviceOptions(config.NewDefaultConfig().WithNoCache(true), "model"))
+	assert.Equal(t, filesystem.DefaultFileScorer{}, resolved.FileScorer)
+}
+
+// TestServiceOptionsPostProcessors verifies that --post-process names become
+// service post-processors, in order.
+func TestServiceOptionsPostProcessors(t *testing.T) {
+	cfg := config.NewDefaultConfig().WithNoCache(true).
+		WithPostProcessors([]string{llm.PostProcessStripPreamble, llm.PostProcessNormalizeFences})
+
+	resolved := resolveServiceConfig(serviceOptions(cfg, "model"))
+	require.Len(t, resolved.PostProcessors, 2)
+
+	summary := "Here is the summary:\n```Golang\nx\n```"
+	for _, process := range resolved.PostProcessors {
+		var err error
+		summary, err = process(summary)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, "```go\nx\n```", summary)
+}
+
+// TestServiceOptionsConcurrency verifies that --concurrency and
+// --max-inflight-llm cap in-flight generation requests and that the default
+// leaves them unlimited.
+func TestServiceOptionsConcurrency(t *testing.T) {
+	cfg := config.NewDefaultConfig().WithNoCache(true)
+	assert.Zero(t, resolveServiceConfig(serviceOptions(cfg, "model")).MaxInflight)
+	assert.Equal(t, 6, resolveServiceConfig(serviceOptions(cfg.WithConcurrency(6), "model")).MaxInflight)
+	assert.Equal(t, 2, resolveServiceConfig(serviceOptions(cfg.WithConcurrency(6).WithMaxInflightLLM(2), "model")).MaxInflight,
+		"--max-inflight-llm caps LLM calls below --concurrency")
+}
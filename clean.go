@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"glance/config"
+	"glance/filesystem"
+)
+
+// runClean removes generated glance files under cfg.TargetDir, or under
+// cfg.OutputDir when output is mirrored there, or lists them when cfg.DryRun is
+// set, and writes one line per file plus a count to out.
+func runClean(cfg *config.Config, out io.Writer) error {
+	root := cfg.TargetDir
+	if cfg.OutputDir != "" {
+		root = cfg.OutputDir
+	}
+	removed, err := filesystem.CleanGlanceFiles(root, cfg.DryRun, scanOptions(cfg)...)
+
+	verb := "Removed"
+	if cfg.DryRun {
+		verb = "Would remove"
+	}
+	for _, path := range removed {
+		fmt.Fprintf(out, "%s %s\n", verb, displayDir(root, path))
+	}
+	if err != nil {
+		return err
+	}
+
+	noun := "files"
+	if len(removed) == 1 {
+		noun = "file"
+	}
+	fmt.Fprintf(out, "%s %d %s %s\n", verb, len(removed), filesystem.GlanceFilename, noun)
+	return nil
+}
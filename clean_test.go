@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+)
+
+func TestRunClean(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "sub")
+	require.NoError(t, os.Mkdir(sub, 0755))
+	outputs := []string{filepath.Join(root, filesystem.GlanceFilename), filepath.Join(sub, filesystem.GlanceFilename)}
+	for _, p := range outputs {
+		require.NoError(t, os.WriteFile(p, []byte("summary"), 0600))
+	}
+	source := filepath.Join(sub, "main.go")
+	require.NoError(t, os.WriteFile(source, []byte("package sub"), 0600))
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithClean(true)
+
+	var out bytes.Buffer
+	require.NoError(t, runClean(cfg.WithDryRun(true), &out))
+	assert.Contains(t, out.String(), "Would remove "+filepath.Join("sub", filesystem.GlanceFilename))
+	assert.Contains(t, out.String(), "Would remove 2 "+filesystem.GlanceFilename+" files")
+	for _, p := range outputs {
+		assert.FileExists(t, p, "dry run must not delete")
+	}
+
+	out.Reset()
+	require.NoError(t, runClean(cfg, &out))
+	assert.Contains(t, out.String(), "Removed 2 "+filesystem.GlanceFilename+" files")
+	for _, p := range outputs {
+		assert.NoFileExists(t, p)
+	}
+	assert.FileExists(t, source)
+}
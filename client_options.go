@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"glance/config"
+	"glance/llm"
+)
+
+// newGenaiClient creates the genai connection shared by the Gemini tiers - can
+// be swapped in tests to observe how many are created.
+var newGenaiClient = llm.NewGenaiClient
+
+// tierClientOptions returns the client options shared by every fallback tier.
+func tierClientOptions(cfg *config.Config, model string) []llm.ClientOption {
+	options := []llm.ClientOption{
+		llm.WithModelName(model),
+		llm.WithMaxRetries(0), // Single attempt per tier; FallbackClient handles retries.
+		llm.WithMaxOutputTokens(defaultMaxOutputTokens(model)),
+	}
+	if cfg.Deterministic {
+		options = append(options, llm.WithDeterministic())
+	}
+	if len(cfg.ExtraHeaders) > 0 {
+		options = append(options, llm.WithExtraHeaders(cfg.ExtraHeaders))
+	}
+	if cfg.SystemInstructions != "" {
+		options = append(options, llm.WithSystemInstructions(cfg.SystemInstructions))
+	}
+	return options
+}
+
+// generationOptions describes the client options that change a summary without
+// appearing in its prompt, other than the system instruction, so the summary
+// cache can tell summaries generated under different settings apart.
+func generationOptions(cfg *config.Config) string {
+	var parts []string
+	if cfg.Deterministic {
+		parts = append(parts, "deterministic")
+	}
+	for _, setting := range cfg.SafetySettings {
+		parts = append(parts, "safety:"+setting.Category+"="+setting.Threshold)
+	}
+	return strings.Join(parts, ";")
+}
+
+// geminiClientOptions returns the client options for a Gemini fallback tier.
+func geminiClientOptions(cfg *config.Config, model string) []llm.ClientOption {
+	opts := append(tierClientOptions(cfg, model),
+		llm.WithBackend(cfg.GeminiBackend),
+		llm.WithBaseURL(cfg.GeminiBaseURL),
+		llm.WithTimeout(cfg.ProviderTimeout(config.ProviderGemini)),
+	)
+	for _, setting := range cfg.SafetySettings {
+		opts = append(opts, llm.WithSafetySetting(setting.Category, setting.Threshold))
+	}
+	return opts
+}
+
+// openRouterClientOptions returns the client options for the OpenRouter
+// fallback tier.
+func openRouterClientOptions(cfg *config.Config, model string) []llm.ClientOption {
+	return append(tierClientOptions(cfg, model),
+		llm.WithTimeout(cfg.ProviderTimeout(config.ProviderOpenRouter)),
+		llm.WithModelFallbacks(cfg.OpenRouterModels),
+	)
+}
+
+// newGeminiTierClients creates the primary and stable Gemini tiers on one
+// shared genai client, since they differ only in model and output limit.
+func newGeminiTierClients(cfg *config.Config) (llm.Client, llm.Client, error) {
+	shared, err := newGenaiClient(cfg.APIKey, geminiClientOptions(cfg, primaryModel)...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+
+	primaryClient, err := llm.NewGeminiClientWithGenai(shared, geminiClientOptions(cfg, primaryModel)...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create primary Gemini client: %w", err)
+	}
+
+	stableClient, err := llm.NewGeminiClientWithGenai(shared, geminiClientOptions(cfg, stableModel)...)
+	if err != nil {
+		primaryClient.Close()
+		return nil, nil, fmt.Errorf("failed to create stable Gemini fallback client: %w", err)
+	}
+	return primaryClient, stableClient, nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+
+	"glance/config"
+	"glance/llm"
+)
+
+// TestGeminiTiersShareGenaiClient verifies that both Gemini tiers of the chain
+// run on one genai client, and that the OpenRouter tier does not use it.
+func TestGeminiTiersShareGenaiClient(t *testing.T) {
+	t.Setenv("OPENROUTER_API_KEY", "test-openrouter-key")
+
+	var created int
+	orig := newGenaiClient
+	newGenaiClient = func(apiKey string, options ...llm.ClientOption) (*genai.Client, error) {
+		created++
+		return orig(apiKey, options...)
+	}
+	defer func() { newGenaiClient = orig }()
+
+	cfg := config.NewDefaultConfig().WithAPIKey("test-api-key").WithNoCache(true)
+	client, service, err := createLLMService(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, service)
+	defer client.Close()
+
+	assert.Equal(t, 1, created, "the primary and stable Gemini tiers share one genai client")
+}
@@ -0,0 +1,48 @@
+// Package clock abstracts the passage of time so that backoff, timeouts, and
+// other time-dependent behavior can be tested without real delays.
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// Clock tells the time and waits. Production code uses Real; tests use a Fake.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// Sleep waits for d to pass, returning ctx.Err() early if ctx is done first.
+	Sleep(ctx context.Context, d time.Duration) error
+
+	// After returns a channel that receives the time once d has passed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the system clock.
+type realClock struct{}
+
+// Real returns a Clock backed by the system clock.
+func Real() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
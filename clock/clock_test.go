@@ -0,0 +1,62 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestRealSleep(t *testing.T) {
+	c := Real()
+	assert.NoError(t, c.Sleep(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, c.Sleep(ctx, time.Hour), context.Canceled, "a done context ends the sleep")
+}
+
+func TestFakeSleep(t *testing.T) {
+	f := NewFake(epoch)
+
+	assert.NoError(t, f.Sleep(context.Background(), time.Second))
+	assert.NoError(t, f.Sleep(context.Background(), 2*time.Second))
+	assert.Equal(t, epoch.Add(3*time.Second), f.Now())
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, f.Sleeps())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, f.Sleep(ctx, time.Hour), context.Canceled)
+	assert.Equal(t, epoch.Add(3*time.Second), f.Now(), "a canceled sleep does not advance the clock")
+	assert.Len(t, f.Sleeps(), 2)
+}
+
+func TestFakeAfter(t *testing.T) {
+	f := NewFake(epoch)
+
+	ch := f.After(time.Minute)
+	f.Advance(59 * time.Second)
+	select {
+	case <-ch:
+		t.Fatal("fired before its deadline")
+	default:
+	}
+
+	assert.NoError(t, f.Sleep(context.Background(), time.Second), "sleeping also advances waiters")
+	select {
+	case got := <-ch:
+		assert.Equal(t, epoch.Add(time.Minute), got)
+	default:
+		t.Fatal("did not fire at its deadline")
+	}
+
+	select {
+	case got := <-f.After(0):
+		assert.Equal(t, epoch.Add(time.Minute), got)
+	default:
+		t.Fatal("a zero duration fires immediately")
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"glance/config"
+	"glance/filesystem"
+)
+
+// compareContextLines is the number of unchanged lines shown around each change
+// in a --compare diff.
+const compareContextLines = 2
+
+// compareGlance finishes r for --compare: instead of writing content, it compares
+// it with the glance.md already on disk for r.dir and records any difference.
+// Nothing is written or created.
+func compareGlance(cfg *config.Config, r result, content string, outcome dirOutcome) result {
+	outputDir := glanceOutputDir(cfg, r.dir)
+	validatedPath, err := filesystem.ValidateFilePath(filepath.Join(outputDir, filesystem.GlanceFilename), outputDir, false, false)
+	if err != nil {
+		r.err = fmt.Errorf("invalid glance.md path for %s: %w", r.dir, err)
+		return r
+	}
+
+	// #nosec G304 -- path validated against the directory's output location above
+	existing, err := os.ReadFile(validatedPath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		r.stale = true
+		r.diff = "glance.md is missing"
+	case err != nil:
+		r.err = fmt.Errorf("failed reading glance.md in %s for comparison: %w", r.dir, err)
+		return r
+	case filesystem.StripFooter(string(existing)) != content:
+		// A --footer only records when the file was written, so it never makes it stale
+		r.stale = true
+		r.diff = lineDiff(filesystem.StripFooter(string(existing)), content)
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"directory": r.dir,
+		"stale":     r.stale,
+	}).Debug("Compared generated summary with glance.md on disk")
+
+	r.success = true
+	r.outcome = outcome
+	r.attempts = 1
+	return r
+}
+
+// printCompareReport writes a diff for every directory whose glance.md differs
+// from a fresh summary, and returns the process exit code for --compare: 1 if
+// anything differs or failed, 0 otherwise.
+func printCompareReport(w io.Writer, root string, results []result) int {
+	var stale, failed int
+	for _, r := range results {
+		if r.outcome == outcomeFailed {
+			failed++
+		}
+		if !r.stale {
+			continue
+		}
+		stale++
+		_, _ = fmt.Fprintf(w, "stale: %s\n%s\n", displayDir(root, r.dir), r.diff)
+	}
+
+	if stale == 0 && failed == 0 {
+		_, _ = fmt.Fprintln(w, "All glance.md files are up to date.")
+		return 0
+	}
+	_, _ = fmt.Fprintf(w, "%d stale glance.md file(s), %d failed director(ies).\n", stale, failed)
+	return 1
+}
+
+// lineDiff returns a unified-style diff from committed to generated: changed
+// lines prefixed with - and +, each change shown with a few lines of context,
+// and @@ between changes that are far apart.
+func lineDiff(committed, generated string) string {
+	a := strings.Split(committed, "\n")
+	b := strings.Split(generated, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type diffLine struct {
+		op   byte
+		text string
+	}
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+
+	// Keep changed lines and their context; mark each gap once
+	show := make([]bool, len(lines))
+	for k, line := range lines {
+		if line.op == ' ' {
+			continue
+		}
+		for c := max(0, k-compareContextLines); c <= min(len(lines)-1, k+compareContextLines); c++ {
+			show[c] = true
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString("--- committed\n+++ generated\n")
+	gap := false
+	for k, line := range lines {
+		if !show[k] {
+			gap = true
+			continue
+		}
+		if gap || k == 0 {
+			builder.WriteString("@@\n")
+			gap = false
+		}
+		builder.WriteByte(line.op)
+		builder.WriteString(line.text)
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// runCompare runs --compare over a single directory whose LLM summary is "# summary\n".
+func runCompare(t *testing.T, dir string) []result {
+	t.Helper()
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).Return("# summary\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient})
+	require.NoError(t, err)
+
+	cfg := config.NewDefaultConfig().WithTargetDir(dir).WithCompare(true)
+	chains := map[string]filesystem.IgnoreChain{}
+	results, _ := processDirectories(context.Background(), []string{dir}, chains, cfg, service, io.Discard)
+	require.Len(t, results, 1)
+	return results
+}
+
+func TestCompareMode(t *testing.T) {
+	t.Run("Stale file is reported and left untouched", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0600))
+		glancePath := filepath.Join(dir, filesystem.GlanceFilename)
+		require.NoError(t, os.WriteFile(glancePath, []byte("# outdated\n"), 0600))
+
+		results := runCompare(t, dir)
+		assert.True(t, results[0].stale)
+		assert.Equal(t, outcomeGenerated, results[0].outcome)
+
+		var out bytes.Buffer
+		assert.Equal(t, 1, printCompareReport(&out, dir, results), "a stale file fails the run")
+		assert.Contains(t, out.String(), "stale: .\n")
+		assert.Contains(t, out.String(), "-# outdated\n+# summary\n")
+
+		content, err := os.ReadFile(glancePath)
+		require.NoError(t, err)
+		assert.Equal(t, "# outdated\n", string(content), "compare mode never writes")
+	})
+
+	t.Run("Matching file passes even when it looks fresh", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, filesystem.GlanceFilename), []byte("# summary\n"), 0600))
+
+		results := runCompare(t, dir)
+		assert.False(t, results[0].stale)
+
+		var out bytes.Buffer
+		assert.Equal(t, 0, printCompareReport(&out, dir, results))
+		assert.Contains(t, out.String(), "up to date")
+	})
+
+	t.Run("Missing file is reported and not created", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0600))
+
+		results := runCompare(t, dir)
+		assert.True(t, results[0].stale)
+
+		var out bytes.Buffer
+		assert.Equal(t, 1, printCompareReport(&out, dir, results))
+		assert.Contains(t, out.String(), "glance.md is missing")
+		assert.NoFileExists(t, filepath.Join(dir, filesystem.GlanceFilename))
+	})
+
+	t.Run("Stubs are compared too", func(t *testing.T) {
+		dir := t.TempDir()
+
+		results := runCompare(t, dir)
+		assert.True(t, results[0].stale)
+		assert.Equal(t, outcomeStub, results[0].outcome)
+		assert.NoFileExists(t, filepath.Join(dir, filesystem.GlanceFilename))
+	})
+}
+
+func TestLineDiff(t *testing.T) {
+	committed := "a\nb\nc\nd\ne\nf\ng\nh\n"
+	generated := "a\nB\nc\nd\ne\nf\ng\nH\n"
+
+	assert.Equal(t, "--- committed\n+++ generated\n"+
+		"@@\n a\n-b\n+B\n c\n d\n"+
+		"@@\n f\n g\n-h\n+H\n \n",
+		lineDiff(committed, generated))
+}
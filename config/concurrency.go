@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// ConcurrencyAuto is the --concurrency value that sizes the limit from the
+// machine and the provider (see autoConcurrency).
+const ConcurrencyAuto = "auto"
+
+// providerConcurrencyHint is how many concurrent requests each provider
+// comfortably sustains on a default account before rate limiting sets in.
+var providerConcurrencyHint = map[string]int{
+	ProviderGemini:     8,
+	ProviderOpenRouter: 4,
+}
+
+// numCPU reports the usable CPUs. It is a variable so tests can fix it.
+var numCPU = runtime.NumCPU
+
+// parseConcurrency resolves a --concurrency value: "" means unlimited (0),
+// "auto" is sized by autoConcurrency, and anything else must be a positive
+// integer, which is used as given.
+func parseConcurrency(value string, providerLimits map[string]int) (int, error) {
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(value) {
+	case "":
+		return 0, nil
+	case ConcurrencyAuto:
+		return autoConcurrency(providerLimits), nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%q is neither %q nor a positive integer", value, ConcurrencyAuto)
+	}
+	return n, nil
+}
+
+// autoConcurrency picks how many summaries may be generated at once. Each one
+// reads and formats its files before waiting on the LLM, so two per CPU keeps
+// every core busy while half of them are blocked on the network. Beyond that,
+// the LLM is the bottleneck: the limit never exceeds what the primary
+// provider, Gemini, sustains, which is its --concurrency-per-provider limit
+// when given and providerConcurrencyHint otherwise. The result is at least 1.
+func autoConcurrency(providerLimits map[string]int) int {
+	limit := 2 * numCPU()
+
+	providerLimit := providerConcurrencyHint[ProviderGemini]
+	if explicit := providerLimits[ProviderGemini]; explicit > 0 {
+		providerLimit = explicit
+	}
+
+	return max(1, min(limit, providerLimit))
+}
+
+// InflightLimit returns how many LLM generation calls may be in flight at once:
+// the smaller of Concurrency and MaxInflightLLM, ignoring whichever is unset,
+// or 0 when neither is.
+func (c *Config) InflightLimit() int {
+	switch {
+	case c.Concurrency <= 0:
+		return max(0, c.MaxInflightLLM)
+	case c.MaxInflightLLM <= 0:
+		return c.Concurrency
+	default:
+		return min(c.Concurrency, c.MaxInflightLLM)
+	}
+}
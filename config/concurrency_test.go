@@ -0,0 +1,103 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setNumCPU fixes the CPU count seen by autoConcurrency for one test.
+func setNumCPU(t *testing.T, n int) {
+	t.Helper()
+	original := numCPU
+	numCPU = func() int { return n }
+	t.Cleanup(func() { numCPU = original })
+}
+
+func TestParseConcurrency(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		cpus      int
+		providers map[string]int
+		want      int
+	}{
+		{name: "unset is unlimited", value: "", cpus: 4, want: 0},
+		{name: "auto on a small machine scales with CPUs", value: "auto", cpus: 2, want: 4},
+		{name: "auto on a large machine is capped by the provider hint", value: "auto", cpus: 64, want: providerConcurrencyHint[ProviderGemini]},
+		{name: "auto respects an explicit provider limit", value: "AUTO", cpus: 64, providers: map[string]int{ProviderGemini: 3}, want: 3},
+		{name: "auto ignores other providers' limits", value: "auto", cpus: 64, providers: map[string]int{ProviderOpenRouter: 1}, want: providerConcurrencyHint[ProviderGemini]},
+		{name: "auto is at least one", value: "auto", cpus: 0, want: 1},
+		{name: "explicit number overrides auto sizing", value: "12", cpus: 2, want: 12},
+		{name: "explicit number ignores provider limits", value: " 20 ", cpus: 2, providers: map[string]int{ProviderGemini: 3}, want: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setNumCPU(t, tt.cpus)
+			got, err := parseConcurrency(tt.value, tt.providers)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, invalid := range []string{"0", "-1", "many", "2.5"} {
+		_, err := parseConcurrency(invalid, nil)
+		assert.Error(t, err, "value %q", invalid)
+	}
+}
+
+func TestLoadConfigConcurrency(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+	setNumCPU(t, 2)
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Zero(t, cfg.Concurrency)
+
+	cfg, err = LoadConfig([]string{"glance", "--concurrency", "auto", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, 4, cfg.Concurrency)
+
+	cfg, err = LoadConfig([]string{"glance", "--concurrency", "auto", "--concurrency-per-provider", "gemini=1", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, cfg.Concurrency)
+
+	cfg, err = LoadConfig([]string{"glance", "--concurrency", "16", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, 16, cfg.Concurrency)
+
+	_, err = LoadConfig([]string{"glance", "--concurrency", "0", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfigMaxInflightLLM(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Zero(t, cfg.MaxInflightLLM)
+
+	cfg, err = LoadConfig([]string{"glance", "--max-inflight-llm", "3", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, cfg.MaxInflightLLM)
+
+	_, err = LoadConfig([]string{"glance", "--max-inflight-llm", "-1", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestInflightLimit(t *testing.T) {
+	cfg := NewDefaultConfig()
+	assert.Zero(t, cfg.InflightLimit(), "unlimited by default")
+	assert.Equal(t, 6, cfg.WithConcurrency(6).InflightLimit())
+	assert.Equal(t, 3, cfg.WithMaxInflightLLM(3).InflightLimit())
+	assert.Equal(t, 3, cfg.WithConcurrency(6).WithMaxInflightLLM(3).InflightLimit(), "the smaller limit wins")
+	assert.Equal(t, 2, cfg.WithConcurrency(2).WithMaxInflightLLM(3).InflightLimit())
+}
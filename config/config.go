@@ -1,7 +1,13 @@
 // Package config provides configuration management for the glance application.
 package config
 
-import "glance/llm"
+import (
+	"fmt"
+	"time"
+
+	"glance/filesystem"
+	"glance/llm"
+)
 
 // Config holds the application configuration parameters.
 // This structure centralizes all application settings, making them easier to
@@ -24,15 +30,369 @@ type Config struct {
 
 	// MaxFileBytes is the maximum file size in bytes to process (larger files are truncated)
 	MaxFileBytes int64
+
+	// IncludeGitMetadata adds each directory's recent commit history to the prompt
+	IncludeGitMetadata bool
+
+	// IncludeSiblings adds the names of each directory's sibling directories,
+	// its peers under the same parent, to the prompt
+	IncludeSiblings bool
+
+	// ReadCompressed enables reading gzip-compressed text files (.gz)
+	ReadCompressed bool
+
+	// OnlyDirsWith restricts processing to directories that directly contain a file
+	// with one of these extensions (e.g. ".go"). Empty means all directories qualify.
+	OnlyDirsWith []string
+
+	// MetricsFile is the absolute path of a Prometheus textfile to write after the run.
+	// Empty disables metrics export.
+	MetricsFile string
+
+	// EventsFile is the absolute path of a newline-delimited JSON event stream
+	// written during the run. Empty disables the stream.
+	EventsFile string
+
+	// Only is the absolute path of a subtree of TargetDir to restrict processing
+	// to. The scan still descends from TargetDir so inherited .gitignore rules
+	// apply. Empty processes the whole target directory.
+	Only string
+
+	// OutputDir is the absolute root of a tree mirroring TargetDir that glance
+	// output files are written to instead of the source directories. Empty
+	// writes each summary into the directory it describes.
+	OutputDir string
+
+	// TempDir is the absolute directory for the temp files behind atomic
+	// summary writes. Empty creates them next to the file being written.
+	TempDir string
+
+	// Explain prints the regeneration decision for each directory
+	Explain bool
+
+	// Staged restricts processing to directories holding files with staged git
+	// changes, plus their ancestors
+	Staged bool
+
+	// Compare regenerates every summary in memory and reports those that differ
+	// from the glance.md files on disk, without writing anything
+	Compare bool
+
+	// QuietSuccess suppresses success summary lines so only failures are reported
+	QuietSuccess bool
+
+	// MaxRequests caps the number of LLM requests per run (0 means unlimited)
+	MaxRequests int64
+
+	// MaxTokens caps the prompt tokens sent to the LLM per run (0 means unlimited)
+	MaxTokens int64
+
+	// MaxRuntime bounds the wall-clock time of the whole run (0 means unlimited)
+	MaxRuntime time.Duration
+
+	// GeminiBackend selects the Gemini Developer API or Vertex AI
+	GeminiBackend llm.Backend
+
+	// GeminiBaseURL overrides the Gemini endpoint (empty keeps the backend default)
+	GeminiBaseURL string
+
+	// SafetySettings override Gemini's content filtering thresholds for this run
+	// (--safety category=threshold); empty keeps the API defaults
+	SafetySettings []llm.SafetySetting
+
+	// Deterministic requests temperature-0 greedy decoding for reproducible summaries
+	Deterministic bool
+
+	// SystemInstructions are sent as the system instruction of every LLM request
+	// (--system or --system-file); empty keeps the providers' defaults
+	SystemInstructions string
+
+	// ProviderConcurrency limits concurrent requests per provider ("gemini", "openrouter").
+	// Providers without an entry are unlimited.
+	ProviderConcurrency map[string]int
+
+	// Concurrency caps how many summaries are generated at once across the
+	// whole run (--concurrency, resolved from "auto" at load time); 0 means unlimited
+	Concurrency int
+
+	// MaxInflightLLM caps how many LLM generation calls are in flight at once,
+	// independently of --concurrency (--max-inflight-llm); 0 means no separate cap
+	MaxInflightLLM int
+
+	// OpenRouterModels are the models OpenRouter falls back to, in order, when
+	// the OpenRouter tier's model is unavailable
+	OpenRouterModels []string
+
+	// TimeoutSeconds is the per-request LLM timeout for every provider
+	// (zero uses each provider's default; see ProviderTimeout)
+	TimeoutSeconds int
+
+	// AllowedModels restricts the provider:model combinations glance may call,
+	// as set by allowed_models in the global configuration file. Empty allows all.
+	AllowedModels []string
+
+	// IgnoreCase makes extension and filename rules case-insensitive (".go" matches "MAIN.GO")
+	IgnoreCase bool
+
+	// NoCache disables the persistent summary cache shared across runs
+	NoCache bool
+
+	// MaxSubGlanceBytes caps the combined subdirectory summaries in each prompt
+	// (zero means no limit)
+	MaxSubGlanceBytes int64
+
+	// SubGlanceDepth is how many levels of descendant summaries go into each
+	// prompt: 1 (or less) gathers only immediate children's summaries, 2 also
+	// their children's, and so on
+	SubGlanceDepth int
+
+	// ExtraHeaders are added to every LLM request (e.g. gateway cost-center tags)
+	ExtraHeaders map[string]string
+
+	// SampleLargeFiles keeps a head, middle, and tail sample of files over MaxFileBytes
+	// instead of truncating them
+	SampleLargeFiles bool
+
+	// MaxOpenFiles caps how many files are open for reading at once across all
+	// concurrent reads
+	MaxOpenFiles int
+
+	// MaxFileAge skips files not modified within this duration (zero disables the filter)
+	MaxFileAge time.Duration
+
+	// DumpPrompt names a directory whose rendered prompt is printed instead of
+	// generating any glance files (empty for a normal run)
+	DumpPrompt string
+
+	// Clean removes generated glance files under TargetDir instead of generating them
+	Clean bool
+
+	// DryRun reports what Clean would remove without deleting anything
+	DryRun bool
+
+	// Verify checks existing glance files under TargetDir for corruption (invalid
+	// UTF-8, unclosed code fences, empty content) instead of generating them
+	Verify bool
+
+	// Stdout writes every summary to standard output under a "===== path ====="
+	// header instead of writing glance.md files
+	Stdout bool
+
+	// Serve runs the HTTP API ("glance serve") instead of a one-shot run,
+	// summarizing directories under TargetDir on request
+	Serve bool
+
+	// ServeAddr is the address the HTTP API listens on
+	ServeAddr string
+
+	// ServeToken, when set, is the shared bearer token every API request
+	// except the health check must present (from GLANCE_SERVE_TOKEN)
+	ServeToken string
+
+	// RespectGlobalGitignore applies the user's global git excludes file
+	// (core.excludesFile) as the first rule of the root ignore chain
+	RespectGlobalGitignore bool
+
+	// IncludeHidden summarizes hidden files and directories (names starting
+	// with "."), such as .github/workflows. The .git directory, glance output
+	// files, and gitignored paths are still skipped.
+	IncludeHidden bool
+
+	// PackageRootTemplate is the prompt template used for package-root directories
+	// (see filesystem.IsPackageRoot). Empty keeps the normal template.
+	PackageRootTemplate string
+
+	// PackageRootMaxFileBytes raises the per-file size limit for package-root
+	// directories. Zero, or a value below MaxFileBytes, keeps the normal limit.
+	PackageRootMaxFileBytes int64
+
+	// Order selects the directory processing order: OrderDepth, OrderSizeAsc,
+	// or OrderSizeDesc. Children are always processed before their parents.
+	Order string
+
+	// TopDown processes directories parent-first in BFS order so top-level
+	// summaries are written first. Subdirectory summaries are then not yet
+	// available, so they are never included in prompts and a regenerated child
+	// does not mark its parents for regeneration.
+	TopDown bool
+
+	// AlwaysBubble marks a directory's ancestors for regeneration when it was
+	// up to date but its summary is newer than its parent's, not only when it
+	// was regenerated this run
+	AlwaysBubble bool
+
+	// RollupDepth summarizes directories at least this many levels above the
+	// deepest directory beneath them purely from their subdirectory summaries,
+	// ignoring their own files (0 disables roll-ups)
+	RollupDepth int
+
+	// Rollup marks the directory being processed as a roll-up, summarized only
+	// from its subdirectory summaries. It is set per directory from RollupDepth.
+	Rollup bool
+
+	// Siblings names the directory being processed's sibling directories. It is
+	// set per directory from the scan results when IncludeSiblings is on.
+	Siblings []string
+
+	// SplitLargeDirs summarizes directories with more than this many files as
+	// groups of related files, one section per group (0 disables splitting)
+	SplitLargeDirs int
+
+	// SkipGenerated leaves generated and minified files out of prompts
+	// (see filesystem.IsLikelyGenerated)
+	SkipGenerated bool
+
+	// DetectEncoding transcodes UTF-16 and Latin-1 files to UTF-8 instead of
+	// replacing their invalid bytes
+	DetectEncoding bool
+
+	// DescribeImages runs a vision pre-pass that describes the images in each
+	// directory and adds the descriptions to its summary prompt
+	DescribeImages bool
+
+	// MaxImages caps how many images are described per directory (0 for no limit)
+	MaxImages int
+
+	// MaxImageBytes skips images larger than this many bytes (0 for no limit)
+	MaxImageBytes int64
+
+	// ContextFiles are glob patterns, relative to TargetDir, naming repository-level
+	// files (e.g. README.md) loaded once and shared with every directory's prompt
+	ContextFiles []string
+
+	// MaxContextBytes caps the combined size of the context files (0 for no limit)
+	MaxContextBytes int64
+
+	// Strict fails a directory when any file in its gather path cannot be
+	// validated or read, instead of silently skipping the file
+	Strict bool
+
+	// LinkSources appends links to the summarized source files to each glance.md:
+	// GitHub permalinks when the origin remote is on GitHub, relative links otherwise
+	LinkSources bool
+
+	// PostProcessors names the built-in output post-processors applied to each
+	// summary before it is written, in order (see llm.BuiltinPostProcessor)
+	PostProcessors []string
+
+	// PromptVars are user-supplied variables available to prompt templates as
+	// {{.Vars.key}} (--prompt-var key=value)
+	PromptVars map[string]string
+
+	// StrictTemplate fails a directory whose template references a PromptVars
+	// key that was not supplied, instead of rendering it empty
+	StrictTemplate bool
+
+	// AnonymizePaths replaces the absolute TargetDir with a placeholder in
+	// prompts and log output, so the local path never reaches the LLM or logs
+	AnonymizePaths bool
+
+	// NoEmptyStubs skips directories with no analyzable content instead of writing
+	// an "Empty directory" or "No analyzable text content" stub for them
+	NoEmptyStubs bool
+
+	// IncrementalPrompt updates a regenerated directory's existing summary from
+	// a git diff of its files instead of summarizing their full contents
+	IncrementalPrompt bool
+
+	// MinFiles is the number of analyzable files a directory needs before it is
+	// sent to the LLM; directories below it are stubbed like empty ones (0 disables)
+	MinFiles int
+
+	// MaxIgnoredRatio, when positive, skips directories where more than this
+	// fraction of the files are gitignored, since what is left is rarely
+	// representative (0 disables)
+	MaxIgnoredRatio float64
+
+	// ChunkSummarizeBytes, when positive, pre-summarizes files larger than this
+	// many bytes, in chunks of at most this size, and puts the summaries in the
+	// directory prompt instead of the files (0 disables)
+	ChunkSummarizeBytes int
+
+	// FailOnEmptySummary fails a directory, instead of writing its glance.md,
+	// when the generated summary is shorter than MinSummaryLength characters
+	FailOnEmptySummary bool
+
+	// MinSummaryLength is the shortest acceptable summary, in characters of
+	// trimmed text, when FailOnEmptySummary is set
+	MinSummaryLength int
+
+	// IncludeStats prepends a file/line/extension stats block to each glance.md
+	IncludeStats bool
+
+	// Footer appends a footer recording when each summary was generated and by
+	// which model; it is ignored wherever summaries are compared or hashed
+	Footer bool
+
+	// DirPromptTemplate is a prompt template set by a .glance.toml prompt_file.
+	// When non-empty it replaces the service's template for that directory subtree.
+	DirPromptTemplate string
 }
 
+// ServeCommand is the first argument that runs glance as an HTTP server.
+const ServeCommand = "serve"
+
+// Provider names accepted by --concurrency-per-provider
+const (
+	// ProviderGemini covers every Gemini fallback tier
+	ProviderGemini = "gemini"
+
+	// ProviderOpenRouter covers the OpenRouter fallback tier
+	ProviderOpenRouter = "openrouter"
+)
+
+// Directory processing orders accepted by --order
+const (
+	// OrderDepth processes the deepest directories first (the default)
+	OrderDepth = "depth"
+
+	// OrderSizeAsc processes smaller directories first within each dependency level
+	OrderSizeAsc = "size-asc"
+
+	// OrderSizeDesc processes larger directories first within each dependency level
+	OrderSizeDesc = "size-desc"
+)
+
 // Default constants used in configuration
 const (
 	// DefaultMaxRetries is the default retries per fallback tier.
 	DefaultMaxRetries = 3
 
+	// DefaultGeminiTimeoutSeconds is the default per-request timeout for
+	// Gemini tiers
+	DefaultGeminiTimeoutSeconds = 60
+
+	// DefaultOpenRouterTimeoutSeconds is the default per-request timeout for
+	// the OpenRouter tier, whose slower routed models need longer
+	DefaultOpenRouterTimeoutSeconds = 120
+
+	// DefaultServeAddr is the default listen address for "glance serve",
+	// reachable only from the local machine
+	DefaultServeAddr = "localhost:8080"
+
 	// DefaultMaxFileBytes is the default maximum file size (5MB)
 	DefaultMaxFileBytes = 5 * 1024 * 1024
+
+	// DefaultChunkSummarizeBytes is the default file size above which
+	// --chunk-summarize pre-summarizes a file (32KB)
+	DefaultChunkSummarizeBytes = 32 * 1024
+
+	// DefaultMinSummaryLength is the default shortest acceptable summary for
+	// --fail-on-empty-summary, in characters
+	DefaultMinSummaryLength = 50
+
+	// DefaultMaxImages is the default number of images described per directory
+	DefaultMaxImages = 5
+
+	// DefaultMaxImageBytes is the default size limit for described images (4MB)
+	DefaultMaxImageBytes = 4 * 1024 * 1024
+
+	// DefaultMaxContextBytes is the default combined size limit for context files (32KB)
+	DefaultMaxContextBytes = 32 * 1024
+
+	// DefaultGitHistoryCommits is the number of recent commits included per directory
+	// when git metadata is enabled
+	DefaultGitHistoryCommits = 5
 )
 
 // NewDefaultConfig creates a new Config with default values.
@@ -40,15 +400,63 @@ const (
 // customized using the With* methods.
 func NewDefaultConfig() *Config {
 	return &Config{
-		APIKey:         "",
-		TargetDir:      "",
-		Force:          false,
-		PromptTemplate: llm.DefaultTemplate(),
-		MaxRetries:     DefaultMaxRetries,
-		MaxFileBytes:   DefaultMaxFileBytes,
+		APIKey:           "",
+		TargetDir:        "",
+		Force:            false,
+		PromptTemplate:   llm.DefaultTemplate(),
+		MaxRetries:       DefaultMaxRetries,
+		MaxFileBytes:     DefaultMaxFileBytes,
+		GeminiBackend:    llm.BackendGeminiAPI,
+		IgnoreCase:       true,
+		MaxOpenFiles:     filesystem.DefaultMaxOpenFiles,
+		Order:            OrderDepth,
+		MaxImages:        DefaultMaxImages,
+		MaxImageBytes:    DefaultMaxImageBytes,
+		MaxContextBytes:  DefaultMaxContextBytes,
+		MinSummaryLength: DefaultMinSummaryLength,
 	}
 }
 
+// Validate checks that the numeric limits are in range. The With* builders
+// accept any value so callers can assemble a Config freely; LoadConfig calls
+// Validate on the result so out-of-range values never reach a run.
+//
+// Returns:
+//   - An error describing the first out-of-range value, or nil
+func (c *Config) Validate() error {
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("invalid configuration: MaxRetries must be 0 or more, got %d", c.MaxRetries)
+	}
+	if c.MaxFileBytes < 0 {
+		return fmt.Errorf("invalid configuration: MaxFileBytes must be 0 or more, got %d", c.MaxFileBytes)
+	}
+	if c.MaxImages < 0 {
+		return fmt.Errorf("invalid configuration: MaxImages must be 0 or more, got %d", c.MaxImages)
+	}
+	if c.MaxImageBytes < 0 {
+		return fmt.Errorf("invalid configuration: MaxImageBytes must be 0 or more, got %d", c.MaxImageBytes)
+	}
+	if c.MaxContextBytes < 0 {
+		return fmt.Errorf("invalid configuration: MaxContextBytes must be 0 or more, got %d", c.MaxContextBytes)
+	}
+	// Zero leaves these limits off, so any limit that is set is at least 1
+	if c.Concurrency < 0 {
+		return fmt.Errorf("invalid configuration: Concurrency must be at least 1, or 0 for unlimited, got %d", c.Concurrency)
+	}
+	if c.MaxInflightLLM < 0 {
+		return fmt.Errorf("invalid configuration: MaxInflightLLM must be at least 1, or 0 for unlimited, got %d", c.MaxInflightLLM)
+	}
+	if c.MaxOpenFiles < 1 {
+		return fmt.Errorf("invalid configuration: MaxOpenFiles must be at least 1, got %d", c.MaxOpenFiles)
+	}
+	for _, entry := range c.AllowedModels {
+		if err := validateAllowedModel(entry); err != nil {
+			return fmt.Errorf("invalid configuration: %w", err)
+		}
+	}
+	return nil
+}
+
 // WithAPIKey returns a new Config with the specified API key.
 func (c *Config) WithAPIKey(apiKey string) *Config {
 	// Create a copy of the config to ensure immutability
@@ -91,3 +499,540 @@ func (c *Config) WithMaxFileBytes(maxFileBytes int64) *Config {
 	newConfig.MaxFileBytes = maxFileBytes
 	return &newConfig
 }
+
+// WithIncludeGitMetadata returns a new Config with the specified git metadata setting.
+func (c *Config) WithIncludeGitMetadata(include bool) *Config {
+	newConfig := *c
+	newConfig.IncludeGitMetadata = include
+	return &newConfig
+}
+
+// WithIncludeSiblings returns a new Config with the specified sibling names setting.
+func (c *Config) WithIncludeSiblings(include bool) *Config {
+	newConfig := *c
+	newConfig.IncludeSiblings = include
+	return &newConfig
+}
+
+// WithReadCompressed returns a new Config with the specified compressed-file reading setting.
+func (c *Config) WithReadCompressed(readCompressed bool) *Config {
+	newConfig := *c
+	newConfig.ReadCompressed = readCompressed
+	return &newConfig
+}
+
+// WithOnlyDirsWith returns a new Config with the specified qualifying file extensions.
+func (c *Config) WithOnlyDirsWith(exts []string) *Config {
+	newConfig := *c
+	newConfig.OnlyDirsWith = exts
+	return &newConfig
+}
+
+// WithEventsFile returns a new Config with the specified event stream path.
+func (c *Config) WithEventsFile(path string) *Config {
+	newConfig := *c
+	newConfig.EventsFile = path
+	return &newConfig
+}
+
+// WithMetricsFile returns a new Config with the specified metrics file path.
+func (c *Config) WithMetricsFile(path string) *Config {
+	newConfig := *c
+	newConfig.MetricsFile = path
+	return &newConfig
+}
+
+// WithExplain returns a new Config with the specified explain setting.
+func (c *Config) WithExplain(explain bool) *Config {
+	newConfig := *c
+	newConfig.Explain = explain
+	return &newConfig
+}
+
+// WithStaged returns a new Config with the specified staged-changes setting.
+func (c *Config) WithStaged(staged bool) *Config {
+	newConfig := *c
+	newConfig.Staged = staged
+	return &newConfig
+}
+
+// WithCompare returns a new Config with the specified compare mode setting.
+func (c *Config) WithCompare(compare bool) *Config {
+	newConfig := *c
+	newConfig.Compare = compare
+	return &newConfig
+}
+
+// WithQuietSuccess returns a new Config with the specified quiet-success setting.
+func (c *Config) WithQuietSuccess(quiet bool) *Config {
+	newConfig := *c
+	newConfig.QuietSuccess = quiet
+	return &newConfig
+}
+
+// WithMaxRequests returns a new Config with the specified LLM request cap.
+func (c *Config) WithMaxRequests(maxRequests int64) *Config {
+	newConfig := *c
+	newConfig.MaxRequests = maxRequests
+	return &newConfig
+}
+
+// WithMaxRuntime returns a new Config with the specified run deadline.
+func (c *Config) WithMaxRuntime(maxRuntime time.Duration) *Config {
+	newConfig := *c
+	newConfig.MaxRuntime = maxRuntime
+	return &newConfig
+}
+
+// WithMaxTokens returns a new Config with the specified prompt token cap.
+func (c *Config) WithMaxTokens(maxTokens int64) *Config {
+	newConfig := *c
+	newConfig.MaxTokens = maxTokens
+	return &newConfig
+}
+
+// WithGeminiBackend returns a new Config with the specified Gemini backend.
+func (c *Config) WithGeminiBackend(backend llm.Backend) *Config {
+	newConfig := *c
+	newConfig.GeminiBackend = backend
+	return &newConfig
+}
+
+// WithGeminiBaseURL returns a new Config with the specified Gemini endpoint override.
+func (c *Config) WithGeminiBaseURL(baseURL string) *Config {
+	newConfig := *c
+	newConfig.GeminiBaseURL = baseURL
+	return &newConfig
+}
+
+// WithSafetySettings returns a new Config with the specified Gemini safety settings.
+func (c *Config) WithSafetySettings(settings []llm.SafetySetting) *Config {
+	newConfig := *c
+	newConfig.SafetySettings = settings
+	return &newConfig
+}
+
+// WithDeterministic returns a new Config with the specified deterministic setting.
+func (c *Config) WithDeterministic(deterministic bool) *Config {
+	newConfig := *c
+	newConfig.Deterministic = deterministic
+	return &newConfig
+}
+
+// WithAllowedModels returns a new Config with the specified model allowlist.
+func (c *Config) WithAllowedModels(models []string) *Config {
+	newConfig := *c
+	newConfig.AllowedModels = models
+	return &newConfig
+}
+
+// WithTimeoutSeconds returns a new Config with the specified request timeout.
+func (c *Config) WithTimeoutSeconds(seconds int) *Config {
+	newConfig := *c
+	newConfig.TimeoutSeconds = seconds
+	return &newConfig
+}
+
+// ProviderTimeout returns the request timeout in seconds for provider:
+// TimeoutSeconds when set, otherwise the provider's default.
+func (c *Config) ProviderTimeout(provider string) int {
+	if c.TimeoutSeconds > 0 {
+		return c.TimeoutSeconds
+	}
+	if provider == ProviderOpenRouter {
+		return DefaultOpenRouterTimeoutSeconds
+	}
+	return DefaultGeminiTimeoutSeconds
+}
+
+// WithSystemInstructions returns a new Config with the specified system instructions.
+func (c *Config) WithSystemInstructions(instructions string) *Config {
+	newConfig := *c
+	newConfig.SystemInstructions = instructions
+	return &newConfig
+}
+
+// WithProviderConcurrency returns a new Config with the specified per-provider concurrency limits.
+func (c *Config) WithProviderConcurrency(limits map[string]int) *Config {
+	newConfig := *c
+	newConfig.ProviderConcurrency = limits
+	return &newConfig
+}
+
+// WithConcurrency returns a new Config with the specified overall concurrency limit.
+func (c *Config) WithConcurrency(limit int) *Config {
+	newConfig := *c
+	newConfig.Concurrency = limit
+	return &newConfig
+}
+
+// WithMaxInflightLLM returns a new Config with the specified in-flight LLM call limit.
+func (c *Config) WithMaxInflightLLM(limit int) *Config {
+	newConfig := *c
+	newConfig.MaxInflightLLM = limit
+	return &newConfig
+}
+
+// WithOpenRouterModels returns a new Config with the specified OpenRouter fallback models.
+func (c *Config) WithOpenRouterModels(models []string) *Config {
+	newConfig := *c
+	newConfig.OpenRouterModels = models
+	return &newConfig
+}
+
+// WithIgnoreCase returns a new Config with the specified case-insensitive matching setting.
+func (c *Config) WithIgnoreCase(ignoreCase bool) *Config {
+	newConfig := *c
+	newConfig.IgnoreCase = ignoreCase
+	return &newConfig
+}
+
+// WithNoCache returns a new Config with the specified summary cache setting.
+func (c *Config) WithNoCache(noCache bool) *Config {
+	newConfig := *c
+	newConfig.NoCache = noCache
+	return &newConfig
+}
+
+// WithMaxSubGlanceBytes returns a new Config with the specified subglances limit.
+func (c *Config) WithMaxSubGlanceBytes(maxBytes int64) *Config {
+	newConfig := *c
+	newConfig.MaxSubGlanceBytes = maxBytes
+	return &newConfig
+}
+
+// WithSubGlanceDepth returns a new Config with the specified subglance depth.
+func (c *Config) WithSubGlanceDepth(depth int) *Config {
+	newConfig := *c
+	newConfig.SubGlanceDepth = depth
+	return &newConfig
+}
+
+// WithExtraHeaders returns a new Config with the specified extra request headers.
+func (c *Config) WithExtraHeaders(headers map[string]string) *Config {
+	newConfig := *c
+	newConfig.ExtraHeaders = headers
+	return &newConfig
+}
+
+// WithSampleLargeFiles returns a new Config with the specified large-file sampling setting.
+func (c *Config) WithSampleLargeFiles(sample bool) *Config {
+	newConfig := *c
+	newConfig.SampleLargeFiles = sample
+	return &newConfig
+}
+
+// WithMaxOpenFiles returns a new Config with the specified open-file limit.
+func (c *Config) WithMaxOpenFiles(maxOpenFiles int) *Config {
+	newConfig := *c
+	newConfig.MaxOpenFiles = maxOpenFiles
+	return &newConfig
+}
+
+// WithMaxFileAge returns a new Config with the specified maximum file age.
+func (c *Config) WithMaxFileAge(maxAge time.Duration) *Config {
+	newConfig := *c
+	newConfig.MaxFileAge = maxAge
+	return &newConfig
+}
+
+// WithDumpPrompt returns a new Config with the specified prompt-dump directory.
+func (c *Config) WithDumpPrompt(dir string) *Config {
+	newConfig := *c
+	newConfig.DumpPrompt = dir
+	return &newConfig
+}
+
+// WithClean returns a new Config with the specified clean mode setting.
+func (c *Config) WithClean(clean bool) *Config {
+	newConfig := *c
+	newConfig.Clean = clean
+	return &newConfig
+}
+
+// WithVerify returns a new Config with the specified verify mode setting.
+func (c *Config) WithVerify(verify bool) *Config {
+	newConfig := *c
+	newConfig.Verify = verify
+	return &newConfig
+}
+
+// WithStdout returns a new Config with the specified stdout output setting.
+func (c *Config) WithStdout(stdout bool) *Config {
+	newConfig := *c
+	newConfig.Stdout = stdout
+	return &newConfig
+}
+
+// WithServe returns a new Config with the specified serve mode, listen
+// address, and shared API token.
+func (c *Config) WithServe(serve bool, addr, token string) *Config {
+	newConfig := *c
+	newConfig.Serve = serve
+	newConfig.ServeAddr = addr
+	newConfig.ServeToken = token
+	return &newConfig
+}
+
+// WithDryRun returns a new Config with the specified dry-run setting.
+func (c *Config) WithDryRun(dryRun bool) *Config {
+	newConfig := *c
+	newConfig.DryRun = dryRun
+	return &newConfig
+}
+
+// WithIncludeHidden returns a new Config with the specified hidden-path setting.
+func (c *Config) WithIncludeHidden(include bool) *Config {
+	newConfig := *c
+	newConfig.IncludeHidden = include
+	return &newConfig
+}
+
+// WithRespectGlobalGitignore returns a new Config with the specified global excludes setting.
+func (c *Config) WithRespectGlobalGitignore(respect bool) *Config {
+	newConfig := *c
+	newConfig.RespectGlobalGitignore = respect
+	return &newConfig
+}
+
+// WithPackageRootTemplate returns a new Config with the specified package-root prompt template.
+func (c *Config) WithPackageRootTemplate(template string) *Config {
+	newConfig := *c
+	newConfig.PackageRootTemplate = template
+	return &newConfig
+}
+
+// WithPackageRootMaxFileBytes returns a new Config with the specified package-root file size limit.
+func (c *Config) WithPackageRootMaxFileBytes(maxFileBytes int64) *Config {
+	newConfig := *c
+	newConfig.PackageRootMaxFileBytes = maxFileBytes
+	return &newConfig
+}
+
+// WithDirPromptTemplate returns a new Config with the specified per-directory prompt template.
+func (c *Config) WithDirPromptTemplate(template string) *Config {
+	newConfig := *c
+	newConfig.DirPromptTemplate = template
+	return &newConfig
+}
+
+// WithOrder returns a new Config with the specified directory processing order.
+func (c *Config) WithOrder(order string) *Config {
+	newConfig := *c
+	newConfig.Order = order
+	return &newConfig
+}
+
+// WithOnly returns a new Config restricted to the specified subtree.
+func (c *Config) WithOnly(dir string) *Config {
+	newConfig := *c
+	newConfig.Only = dir
+	return &newConfig
+}
+
+// WithOutputDir returns a new Config with the specified output root.
+func (c *Config) WithOutputDir(dir string) *Config {
+	newConfig := *c
+	newConfig.OutputDir = dir
+	return &newConfig
+}
+
+// WithTempDir returns a new Config with the specified temp file directory.
+func (c *Config) WithTempDir(dir string) *Config {
+	newConfig := *c
+	newConfig.TempDir = dir
+	return &newConfig
+}
+
+// WithDescribeImages returns a new Config with the specified image description setting.
+func (c *Config) WithDescribeImages(describe bool) *Config {
+	newConfig := *c
+	newConfig.DescribeImages = describe
+	return &newConfig
+}
+
+// WithMaxImages returns a new Config with the specified per-directory image limit.
+func (c *Config) WithMaxImages(maxImages int) *Config {
+	newConfig := *c
+	newConfig.MaxImages = maxImages
+	return &newConfig
+}
+
+// WithMaxImageBytes returns a new Config with the specified image size limit.
+func (c *Config) WithMaxImageBytes(maxBytes int64) *Config {
+	newConfig := *c
+	newConfig.MaxImageBytes = maxBytes
+	return &newConfig
+}
+
+// WithContextFiles returns a new Config with the specified context file patterns.
+func (c *Config) WithContextFiles(patterns []string) *Config {
+	newConfig := *c
+	newConfig.ContextFiles = patterns
+	return &newConfig
+}
+
+// WithMaxContextBytes returns a new Config with the specified context file size limit.
+func (c *Config) WithMaxContextBytes(maxBytes int64) *Config {
+	newConfig := *c
+	newConfig.MaxContextBytes = maxBytes
+	return &newConfig
+}
+
+// WithTopDown returns a new Config with the specified top-down processing setting.
+func (c *Config) WithTopDown(topDown bool) *Config {
+	newConfig := *c
+	newConfig.TopDown = topDown
+	return &newConfig
+}
+
+// WithDetectEncoding returns a new Config with the specified encoding detection setting.
+func (c *Config) WithDetectEncoding(detect bool) *Config {
+	newConfig := *c
+	newConfig.DetectEncoding = detect
+	return &newConfig
+}
+
+// WithSkipGenerated returns a new Config with the specified generated-file setting.
+func (c *Config) WithSkipGenerated(skip bool) *Config {
+	newConfig := *c
+	newConfig.SkipGenerated = skip
+	return &newConfig
+}
+
+// WithStrict returns a new Config with the specified strict-read setting.
+func (c *Config) WithStrict(strict bool) *Config {
+	newConfig := *c
+	newConfig.Strict = strict
+	return &newConfig
+}
+
+// WithPostProcessors returns a new Config with the specified output post-processors.
+func (c *Config) WithPostProcessors(names []string) *Config {
+	newConfig := *c
+	newConfig.PostProcessors = names
+	return &newConfig
+}
+
+// WithPromptVars returns a new Config with the specified prompt template variables.
+func (c *Config) WithPromptVars(vars map[string]string) *Config {
+	newConfig := *c
+	newConfig.PromptVars = vars
+	return &newConfig
+}
+
+// WithStrictTemplate returns a new Config with the specified strict-template setting.
+func (c *Config) WithStrictTemplate(strict bool) *Config {
+	newConfig := *c
+	newConfig.StrictTemplate = strict
+	return &newConfig
+}
+
+// WithLinkSources returns a new Config with the specified source link setting.
+func (c *Config) WithLinkSources(link bool) *Config {
+	newConfig := *c
+	newConfig.LinkSources = link
+	return &newConfig
+}
+
+// WithAnonymizePaths returns a new Config with the specified path anonymization setting.
+func (c *Config) WithAnonymizePaths(anonymize bool) *Config {
+	newConfig := *c
+	newConfig.AnonymizePaths = anonymize
+	return &newConfig
+}
+
+// WithNoEmptyStubs returns a new Config with the specified empty-directory stub setting.
+func (c *Config) WithNoEmptyStubs(noEmptyStubs bool) *Config {
+	newConfig := *c
+	newConfig.NoEmptyStubs = noEmptyStubs
+	return &newConfig
+}
+
+// WithAlwaysBubble returns a new Config with the specified always-bubble setting.
+func (c *Config) WithAlwaysBubble(alwaysBubble bool) *Config {
+	newConfig := *c
+	newConfig.AlwaysBubble = alwaysBubble
+	return &newConfig
+}
+
+// WithRollupDepth returns a new Config with the specified roll-up depth.
+func (c *Config) WithRollupDepth(depth int) *Config {
+	newConfig := *c
+	newConfig.RollupDepth = depth
+	return &newConfig
+}
+
+// WithRollup returns a new Config with the specified roll-up setting.
+func (c *Config) WithRollup(rollup bool) *Config {
+	newConfig := *c
+	newConfig.Rollup = rollup
+	return &newConfig
+}
+
+// WithSiblings returns a new Config with the specified sibling directory names.
+func (c *Config) WithSiblings(siblings []string) *Config {
+	newConfig := *c
+	newConfig.Siblings = siblings
+	return &newConfig
+}
+
+// WithSplitLargeDirs returns a new Config with the specified largest group
+// size for splitting large directories.
+func (c *Config) WithSplitLargeDirs(maxPerGroup int) *Config {
+	newConfig := *c
+	newConfig.SplitLargeDirs = maxPerGroup
+	return &newConfig
+}
+
+// WithMaxIgnoredRatio returns a new Config with the specified gitignored-file ratio limit.
+func (c *Config) WithMaxIgnoredRatio(ratio float64) *Config {
+	newConfig := *c
+	newConfig.MaxIgnoredRatio = ratio
+	return &newConfig
+}
+
+// WithIncrementalPrompt returns a new Config with the specified incremental prompt setting.
+func (c *Config) WithIncrementalPrompt(enabled bool) *Config {
+	newConfig := *c
+	newConfig.IncrementalPrompt = enabled
+	return &newConfig
+}
+
+// WithMinFiles returns a new Config with the specified minimum file count.
+func (c *Config) WithMinFiles(minFiles int) *Config {
+	newConfig := *c
+	newConfig.MinFiles = minFiles
+	return &newConfig
+}
+
+// WithChunkSummarizeBytes returns a new Config with the specified file size
+// threshold for chunk pre-summaries.
+func (c *Config) WithChunkSummarizeBytes(thresholdBytes int) *Config {
+	newConfig := *c
+	newConfig.ChunkSummarizeBytes = thresholdBytes
+	return &newConfig
+}
+
+// WithFailOnEmptySummary returns a new Config with the specified empty-summary
+// check and its minimum length in characters.
+func (c *Config) WithFailOnEmptySummary(enabled bool, minLength int) *Config {
+	newConfig := *c
+	newConfig.FailOnEmptySummary = enabled
+	newConfig.MinSummaryLength = minLength
+	return &newConfig
+}
+
+// WithFooter returns a new Config with the specified provenance footer setting.
+func (c *Config) WithFooter(footer bool) *Config {
+	newConfig := *c
+	newConfig.Footer = footer
+	return &newConfig
+}
+
+// WithIncludeStats returns a new Config with the specified stats block setting.
+func (c *Config) WithIncludeStats(includeStats bool) *Config {
+	newConfig := *c
+	newConfig.IncludeStats = includeStats
+	return &newConfig
+}
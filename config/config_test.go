@@ -226,7 +226,7 @@ func TestWithMaxRetries(t *testing.T) {
 	}{
 		{"Zero retries", 0, 0},
 		{"Positive retries", 10, 10},
-		{"Negative retries", -5, -5}, // Should accept any value, validation happens elsewhere
+		{"Negative retries", -5, -5}, // Should accept any value; LoadConfig rejects it via Validate
 	}
 
 	for _, tc := range testCases {
@@ -254,7 +254,7 @@ func TestWithMaxFileBytes(t *testing.T) {
 		{"Zero bytes", 0, 0},
 		{"1 KB", 1024, 1024},
 		{"10 MB", 10 * 1024 * 1024, 10 * 1024 * 1024},
-		{"Negative bytes", -1024, -1024}, // Should accept any value, validation happens elsewhere
+		{"Negative bytes", -1024, -1024}, // Should accept any value; LoadConfig rejects it via Validate
 	}
 
 	for _, tc := range testCases {
@@ -324,3 +324,38 @@ func repeatString(s string, count int) string {
 	}
 	return result
 }
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     *Config
+		wantErr string
+	}{
+		{"Defaults", NewDefaultConfig(), ""},
+		{"Zero retries", NewDefaultConfig().WithMaxRetries(0), ""},
+		{"Zero max file bytes", NewDefaultConfig().WithMaxFileBytes(0), ""},
+		{"One open file", NewDefaultConfig().WithMaxOpenFiles(1), ""},
+		{"Unlimited concurrency", NewDefaultConfig().WithConcurrency(0), ""},
+		{"Concurrency of one", NewDefaultConfig().WithConcurrency(1), ""},
+		{"Unlimited in-flight LLM calls", NewDefaultConfig().WithMaxInflightLLM(0), ""},
+		{"One in-flight LLM call", NewDefaultConfig().WithMaxInflightLLM(1), ""},
+		{"Negative retries", NewDefaultConfig().WithMaxRetries(-1), "MaxRetries must be 0 or more, got -1"},
+		{"Negative max file bytes", NewDefaultConfig().WithMaxFileBytes(-1), "MaxFileBytes must be 0 or more, got -1"},
+		{"Zero open files", NewDefaultConfig().WithMaxOpenFiles(0), "MaxOpenFiles must be at least 1, got 0"},
+		{"Negative concurrency", NewDefaultConfig().WithConcurrency(-1), "Concurrency must be at least 1, or 0 for unlimited, got -1"},
+		{"Negative in-flight LLM calls", NewDefaultConfig().WithMaxInflightLLM(-2), "MaxInflightLLM must be at least 1, or 0 for unlimited, got -2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"glance/filesystem"
+)
+
+// DirConfigFilename is the per-directory configuration file. Its settings apply to
+// the directory containing it and are inherited by every descendant directory.
+const DirConfigFilename = filesystem.DirConfigFilename
+
+// DirOverrides holds the settings a .glance.toml file may override.
+// Nil fields leave the inherited value untouched, so files merge field by field.
+// The model cannot be overridden: one failover chain, checked against
+// allowed_models at startup and sharing its rate limits, serves the whole run.
+type DirOverrides struct {
+	// MaxFileBytes overrides the per-file size limit
+	MaxFileBytes *int64 `toml:"max_file_bytes"`
+
+	// IncludeGitMetadata overrides whether recent commits are added to the prompt
+	IncludeGitMetadata *bool `toml:"include_git_metadata"`
+
+	// ReadCompressed overrides whether gzipped text files are read
+	ReadCompressed *bool `toml:"read_compressed"`
+
+	// PromptFile is a prompt template path, relative to the directory holding the
+	// .glance.toml file. It must lie within the target directory.
+	PromptFile *string `toml:"prompt_file"`
+}
+
+// LoadDirOverrides reads the .glance.toml file in dir, if there is one.
+//
+// Parameters:
+//   - dir: The directory whose .glance.toml should be read
+//   - root: The target directory; dir and any referenced prompt file must lie within it
+//
+// Returns:
+//   - The parsed overrides, or nil if dir has no .glance.toml
+//   - An error describing a malformed file or an invalid setting
+func LoadDirOverrides(dir, root string) (*DirOverrides, error) {
+	if root == "" {
+		return nil, errors.New("root cannot be empty for validation")
+	}
+
+	path, err := validateFilePath(filepath.Join(dir, DirConfigFilename), root, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s path: %w", DirConfigFilename, err)
+	}
+
+	// #nosec G304 -- The path has been validated using filesystem.ValidateFilePath
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var overrides DirOverrides
+	meta, err := toml.Decode(string(data), &overrides)
+	if err != nil {
+		return nil, fmt.Errorf("malformed %s: %w", path, err)
+	}
+	if meta.IsDefined("model") {
+		return nil, fmt.Errorf("unsupported setting in %s: model cannot be set per directory; "+
+			"the same model failover chain summarizes every directory", path)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
+		}
+		slices.Sort(keys)
+		return nil, fmt.Errorf("unsupported setting(s) in %s: %s", path, strings.Join(keys, ", "))
+	}
+
+	if overrides.MaxFileBytes != nil && *overrides.MaxFileBytes <= 0 {
+		return nil, fmt.Errorf("invalid %s: max_file_bytes must be positive", path)
+	}
+
+	if overrides.PromptFile != nil {
+		promptPath := *overrides.PromptFile
+		if !filepath.IsAbs(promptPath) {
+			promptPath = filepath.Join(dir, promptPath)
+		}
+		validPrompt, err := validateFilePath(promptPath, root, false, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid prompt_file in %s: %w", path, err)
+		}
+		overrides.PromptFile = &validPrompt
+	}
+
+	return &overrides, nil
+}
+
+// Apply returns a copy of c with the overrides merged in.
+// A prompt file is read here so that a missing or unreadable template is reported
+// against the directory that configured it.
+func (o *DirOverrides) Apply(c *Config) (*Config, error) {
+	merged := *c
+	if o.MaxFileBytes != nil {
+		merged.MaxFileBytes = *o.MaxFileBytes
+	}
+	if o.IncludeGitMetadata != nil {
+		merged.IncludeGitMetadata = *o.IncludeGitMetadata
+	}
+	if o.ReadCompressed != nil {
+		merged.ReadCompressed = *o.ReadCompressed
+	}
+	if o.PromptFile != nil {
+		// #nosec G304 -- The path was validated using filesystem.ValidateFilePath in LoadDirOverrides
+		data, err := os.ReadFile(*o.PromptFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read prompt_file %s: %w", *o.PromptFile, err)
+		}
+		merged.DirPromptTemplate = string(data)
+	}
+	return &merged, nil
+}
+
+// DirConfigResolver computes the effective configuration of each directory by
+// merging the .glance.toml files of the target directory and its descendants,
+// outermost first, onto the global configuration. Results are memoized.
+type DirConfigResolver struct {
+	base     *Config
+	resolved map[string]resolvedDirConfig
+}
+
+// resolvedDirConfig memoizes a directory's effective configuration and the error,
+// if any, from applying its own .glance.toml.
+type resolvedDirConfig struct {
+	cfg *Config
+	err error
+}
+
+// NewDirConfigResolver creates a resolver rooted at base.TargetDir.
+func NewDirConfigResolver(base *Config) *DirConfigResolver {
+	return &DirConfigResolver{
+		base:     base,
+		resolved: make(map[string]resolvedDirConfig),
+	}
+}
+
+// Resolve returns the effective configuration for dir.
+//
+// A malformed .glance.toml is reported as an error for the directory that holds it,
+// together with the configuration inherited from its ancestors. Descendants ignore
+// the malformed file and keep inheriting from those ancestors, so one bad file never
+// aborts the run.
+//
+// Parameters:
+//   - dir: An absolute directory path within the target directory
+//
+// Returns:
+//   - The effective configuration for dir (never nil)
+//   - An error if dir's own .glance.toml could not be applied
+func (r *DirConfigResolver) Resolve(dir string) (*Config, error) {
+	dir = filepath.Clean(dir)
+	if cached, ok := r.resolved[dir]; ok {
+		return cached.cfg, cached.err
+	}
+
+	root := filepath.Clean(r.base.TargetDir)
+	inherited := r.base
+	if dir != root {
+		rel, err := filepath.Rel(root, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return r.base, fmt.Errorf("directory %s is outside the target directory %s", dir, root)
+		}
+		// Ancestor errors were already reported for the ancestor itself
+		inherited, _ = r.Resolve(filepath.Dir(dir))
+	}
+
+	effective := inherited
+	overrides, err := LoadDirOverrides(dir, root)
+	if err == nil && overrides != nil {
+		var merged *Config
+		if merged, err = overrides.Apply(inherited); err == nil {
+			effective = merged
+		}
+	}
+
+	r.resolved[dir] = resolvedDirConfig{cfg: effective, err: err}
+	return effective, err
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeDirConfig writes a .glance.toml with the given content into dir.
+func writeDirConfig(t *testing.T, dir, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DirConfigFilename), []byte(content), 0600))
+}
+
+func TestDirConfigResolverInheritance(t *testing.T) {
+	root := t.TempDir()
+	svc := filepath.Join(root, "services")
+	api := filepath.Join(svc, "api")
+	web := filepath.Join(root, "web")
+	for _, d := range []string{api, web} {
+		require.NoError(t, os.MkdirAll(d, 0755))
+	}
+
+	writeDirConfig(t, root, "max_file_bytes = 1000\n")
+	writeDirConfig(t, svc, "include_git_metadata = true\nread_compressed = true\n")
+	writeDirConfig(t, api, "# only flip one field back\nread_compressed = false\n")
+
+	base := NewDefaultConfig().WithTargetDir(root)
+	resolver := NewDirConfigResolver(base)
+
+	t.Run("Root applies its own file", func(t *testing.T) {
+		cfg, err := resolver.Resolve(root)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1000), cfg.MaxFileBytes)
+		assert.False(t, cfg.IncludeGitMetadata)
+	})
+
+	t.Run("Sibling inherits only the root", func(t *testing.T) {
+		cfg, err := resolver.Resolve(web)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1000), cfg.MaxFileBytes)
+		assert.False(t, cfg.IncludeGitMetadata)
+		assert.False(t, cfg.ReadCompressed)
+	})
+
+	t.Run("Fields merge individually down the tree", func(t *testing.T) {
+		cfg, err := resolver.Resolve(api)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1000), cfg.MaxFileBytes, "inherited from root")
+		assert.True(t, cfg.IncludeGitMetadata, "inherited from services")
+		assert.False(t, cfg.ReadCompressed, "overridden by api")
+	})
+
+	t.Run("Global config is not mutated", func(t *testing.T) {
+		assert.Equal(t, int64(DefaultMaxFileBytes), base.MaxFileBytes)
+		assert.False(t, base.IncludeGitMetadata)
+	})
+}
+
+func TestDirConfigResolverMalformedFile(t *testing.T) {
+	root := t.TempDir()
+	bad := filepath.Join(root, "bad")
+	child := filepath.Join(bad, "child")
+	require.NoError(t, os.MkdirAll(child, 0755))
+
+	writeDirConfig(t, root, "read_compressed = true\n")
+	writeDirConfig(t, bad, "max_file_bytes = [unterminated\n")
+
+	resolver := NewDirConfigResolver(NewDefaultConfig().WithTargetDir(root))
+
+	cfg, err := resolver.Resolve(bad)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), filepath.Join(bad, DirConfigFilename), "error should name the offending file")
+	assert.Contains(t, err.Error(), "malformed")
+	assert.True(t, cfg.ReadCompressed, "inherited settings are still returned")
+
+	_, err = resolver.Resolve(bad)
+	assert.Error(t, err, "memoized resolution must keep reporting the error")
+
+	childCfg, err := resolver.Resolve(child)
+	assert.NoError(t, err, "descendants are not failed by an ancestor's malformed file")
+	assert.True(t, childCfg.ReadCompressed)
+}
+
+func TestLoadDirOverrides(t *testing.T) {
+	root := t.TempDir()
+
+	t.Run("Missing file", func(t *testing.T) {
+		overrides, err := LoadDirOverrides(root, root)
+		assert.NoError(t, err)
+		assert.Nil(t, overrides)
+	})
+
+	t.Run("Unknown keys are rejected", func(t *testing.T) {
+		dir := filepath.Join(root, "unknown")
+		writeDirConfig(t, dir, "language = \"fr\"\n")
+		_, err := LoadDirOverrides(dir, root)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported setting(s)")
+		assert.Contains(t, err.Error(), "language")
+	})
+
+	t.Run("The model cannot be overridden", func(t *testing.T) {
+		dir := filepath.Join(root, "model")
+		writeDirConfig(t, dir, "model = \"gemini-2.5-pro\"\n")
+		_, err := LoadDirOverrides(dir, root)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "model cannot be set per directory")
+	})
+
+	t.Run("Non-positive file budget is rejected", func(t *testing.T) {
+		dir := filepath.Join(root, "zero")
+		writeDirConfig(t, dir, "max_file_bytes = 0\n")
+		_, err := LoadDirOverrides(dir, root)
+		assert.Error(t, err)
+	})
+
+	t.Run("Prompt file relative to the directory", func(t *testing.T) {
+		dir := filepath.Join(root, "prompted")
+		writeDirConfig(t, dir, "prompt_file = \"prompt.txt\"\n")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "prompt.txt"), []byte("réponds en français: {{.Directory}}"), 0600))
+
+		overrides, err := LoadDirOverrides(dir, root)
+		require.NoError(t, err)
+		cfg, err := overrides.Apply(NewDefaultConfig())
+		require.NoError(t, err)
+		assert.Equal(t, "réponds en français: {{.Directory}}", cfg.DirPromptTemplate)
+	})
+
+	t.Run("Prompt file outside the target directory is rejected", func(t *testing.T) {
+		outside := filepath.Join(t.TempDir(), "prompt.txt")
+		require.NoError(t, os.WriteFile(outside, []byte("x"), 0600))
+
+		dir := filepath.Join(root, "escape")
+		writeDirConfig(t, dir, "prompt_file = \""+filepath.ToSlash(outside)+"\"\n")
+		_, err := LoadDirOverrides(dir, root)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid prompt_file")
+	})
+
+	t.Run("Empty root is rejected", func(t *testing.T) {
+		_, err := LoadDirOverrides(root, "")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// GlobalConfigFilename is the user-wide configuration file, read from the
+// glance directory under the user's configuration directory (for example
+// ~/.config/glance/config.toml on Linux). Its settings apply to every run.
+const GlobalConfigFilename = "config.toml"
+
+// userConfigDir locates the user's configuration directory. Tests replace it
+// to point at a temporary directory.
+var userConfigDir = os.UserConfigDir
+
+// GlobalSettings holds the settings the global configuration file may set.
+type GlobalSettings struct {
+	// AllowedModels restricts glance to these provider:model combinations,
+	// e.g. "gemini:gemini-2.5-flash". Empty allows every model.
+	AllowedModels []string `toml:"allowed_models"`
+}
+
+// GlobalConfigPath returns the path of the global configuration file.
+//
+// Returns:
+//   - The path, whether or not the file exists
+//   - An error if the user's configuration directory cannot be determined
+func GlobalConfigPath() (string, error) {
+	dir, err := userConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user configuration directory: %w", err)
+	}
+	return filepath.Join(dir, "glance", GlobalConfigFilename), nil
+}
+
+// LoadGlobalSettings reads the global configuration file, if there is one.
+// A missing file, or a system without a user configuration directory, yields
+// empty settings.
+//
+// Returns:
+//   - The parsed settings (never nil)
+//   - An error describing a malformed file or an unsupported setting
+func LoadGlobalSettings() (*GlobalSettings, error) {
+	path, err := GlobalConfigPath()
+	if err != nil {
+		return &GlobalSettings{}, nil
+	}
+
+	validPath, err := validateFilePath(path, filepath.Dir(path), false, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid global configuration path: %w", err)
+	}
+
+	// #nosec G304 -- The path has been validated using filesystem.ValidateFilePath
+	data, err := os.ReadFile(validPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return &GlobalSettings{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", validPath, err)
+	}
+
+	var settings GlobalSettings
+	meta, err := toml.Decode(string(data), &settings)
+	if err != nil {
+		return nil, fmt.Errorf("malformed %s: %w", validPath, err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
+		}
+		slices.Sort(keys)
+		return nil, fmt.Errorf("unsupported setting(s) in %s: %s", validPath, strings.Join(keys, ", "))
+	}
+	return &settings, nil
+}
+
+// validateAllowedModel checks that entry has the form provider:model with a
+// known provider.
+func validateAllowedModel(entry string) error {
+	provider, model, found := strings.Cut(entry, ":")
+	if !found || strings.TrimSpace(model) == "" {
+		return fmt.Errorf("allowed model %q is not of the form provider:model", entry)
+	}
+	if provider != ProviderGemini && provider != ProviderOpenRouter {
+		return fmt.Errorf("allowed model %q has unknown provider %q (expected %q or %q)", entry, provider, ProviderGemini, ProviderOpenRouter)
+	}
+	return nil
+}
+
+// CheckModelAllowed reports whether the allowlist permits model on provider.
+// An empty allowlist permits every model.
+//
+// Parameters:
+//   - provider: The provider name, e.g. ProviderGemini
+//   - model: The model name, e.g. "gemini-2.5-flash"
+//
+// Returns:
+//   - nil if the model may be used, or an error naming the model and the allowlist
+func (c *Config) CheckModelAllowed(provider, model string) error {
+	if len(c.AllowedModels) == 0 || slices.Contains(c.AllowedModels, provider+":"+model) {
+		return nil
+	}
+	return fmt.Errorf("model %s:%s is not in allowed_models (%s); edit the global configuration file to permit it",
+		provider, model, strings.Join(c.AllowedModels, ", "))
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useGlobalConfig points the global configuration file at a temporary
+// directory for the rest of the test, writing content there unless it is empty.
+func useGlobalConfig(t *testing.T, content string) {
+	t.Helper()
+	dir := t.TempDir()
+	original := userConfigDir
+	userConfigDir = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { userConfigDir = original })
+
+	if content == "" {
+		return
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "glance"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "glance", GlobalConfigFilename), []byte(content), 0o600))
+}
+
+func TestLoadGlobalSettings(t *testing.T) {
+	t.Run("missing file yields empty settings", func(t *testing.T) {
+		useGlobalConfig(t, "")
+		settings, err := LoadGlobalSettings()
+		require.NoError(t, err)
+		assert.Empty(t, settings.AllowedModels)
+	})
+
+	t.Run("reads allowed models", func(t *testing.T) {
+		useGlobalConfig(t, `allowed_models = ["gemini:gemini-2.5-flash", "openrouter:x-ai/grok-4.1-fast"]`)
+		settings, err := LoadGlobalSettings()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"gemini:gemini-2.5-flash", "openrouter:x-ai/grok-4.1-fast"}, settings.AllowedModels)
+	})
+
+	t.Run("rejects malformed file", func(t *testing.T) {
+		useGlobalConfig(t, `allowed_models = [`)
+		_, err := LoadGlobalSettings()
+		assert.ErrorContains(t, err, "malformed")
+	})
+
+	t.Run("rejects unsupported settings", func(t *testing.T) {
+		useGlobalConfig(t, `allowed_modles = ["gemini:gemini-2.5-flash"]`)
+		_, err := LoadGlobalSettings()
+		assert.ErrorContains(t, err, "allowed_modles")
+	})
+}
+
+func TestCheckModelAllowed(t *testing.T) {
+	cfg := NewDefaultConfig()
+	assert.NoError(t, cfg.CheckModelAllowed(ProviderGemini, "gemini-3-pro"), "an empty allowlist allows every model")
+
+	cfg = cfg.WithAllowedModels([]string{"gemini:gemini-2.5-flash"})
+	assert.NoError(t, cfg.CheckModelAllowed(ProviderGemini, "gemini-2.5-flash"))
+
+	err := cfg.CheckModelAllowed(ProviderGemini, "gemini-3-pro")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "gemini:gemini-3-pro")
+	assert.Contains(t, err.Error(), "gemini:gemini-2.5-flash")
+
+	assert.Error(t, cfg.CheckModelAllowed(ProviderOpenRouter, "gemini-2.5-flash"), "the provider is part of the match")
+}
+
+func TestValidateAllowedModels(t *testing.T) {
+	for _, entry := range []string{"gemini-2.5-flash", "gemini:", "vertex:gemini-2.5-flash"} {
+		cfg := NewDefaultConfig().WithAllowedModels([]string{entry})
+		assert.Error(t, cfg.Validate(), entry)
+	}
+	assert.NoError(t, NewDefaultConfig().WithAllowedModels([]string{"openrouter:x-ai/grok-4.1-fast"}).Validate())
+}
+
+func TestLoadConfigAllowedModels(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	useGlobalConfig(t, `allowed_models = ["gemini:gemini-2.5-flash"]`)
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gemini:gemini-2.5-flash"}, cfg.AllowedModels)
+
+	useGlobalConfig(t, `allowed_models = ["gemini-2.5-flash"]`)
+	_, err = LoadConfig([]string{"glance", "/test/dir"})
+	assert.ErrorContains(t, err, "provider:model")
+}
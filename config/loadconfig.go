@@ -6,10 +6,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
 
+	"glance/filesystem"
 	"glance/llm"
 )
 
@@ -68,31 +74,314 @@ func LoadConfig(args []string) (*Config, error) {
 	// Define flags
 	cmdFlags := flag.NewFlagSet(args[0], flag.ContinueOnError)
 	var (
-		force      bool
-		promptFile string
+		force              bool
+		profile            string
+		promptFile         string
+		includeGitMetadata bool
+		includeSiblings    bool
+		readCompressed     bool
+		onlyDirsWith       string
+		postProcess        string
+		metricsFile        string
+		explain            bool
+		compare            bool
+		staged             bool
+		quietSuccess       bool
+		maxRequests        int64
+		maxTokens          int64
+		geminiBackend      string
+		geminiBaseURL      string
+		deterministic      bool
+		providerLimits     string
+		concurrency        string
+		maxInflightLLM     int
+		systemText         string
+		systemFile         string
+		openRouterModels   string
+		timeoutSeconds     int
+		ignoreCase         bool
+		noCache            bool
+		includeStats       bool
+		footer             bool
+		useRepoRoot        bool
+		maxFileAge         time.Duration
+		maxRuntime         time.Duration
+		dumpPrompt         string
+		serveAddr          string
+		stdout             bool
+		clean              bool
+		verify             bool
+		dryRun             bool
+		sampleLargeFiles   bool
+		headers            headerFlags
+		promptVars         varFlags
+		safety             safetyFlags
+		strictTemplate     bool
+		maxSubGlanceBytes  int64
+		subGlanceDepth     int
+		maxOpenFiles       int
+		noEmptyStubs       bool
+		minFiles           int
+		incrementalPrompt  bool
+		maxIgnoredRatio    float64
+		failOnEmpty        bool
+		chunkSummarize     bool
+		chunkBytes         int
+		minSummaryLength   int
+		globalGitignore    bool
+		includeHidden      bool
+		eventsFile         string
+		outputDir          string
+		tempDir            string
+		only               string
+		strict             bool
+		anonymizePaths     bool
+		linkSources        bool
+		order              string
+		topDown            bool
+		alwaysBubble       bool
+		rollupDepth        int
+		splitLargeDirs     int
+		skipGenerated      bool
+		detectEncoding     bool
+		describeImages     bool
+		maxImages          int
+		maxImageBytes      int64
+		contextFiles       patternFlags
+		maxContextBytes    int64
+		packageRootFile    string
+		packageRootBytes   int64
 	)
 
 	cmdFlags.BoolVar(&force, "force", false, "regenerate glance.md even if it already exists")
 	cmdFlags.StringVar(&promptFile, "prompt-file", "", "path to custom prompt file (overrides default)")
+	cmdFlags.BoolVar(&includeGitMetadata, "include-git-metadata", false, "include each directory's recent git commits in the prompt")
+	cmdFlags.BoolVar(&includeSiblings, "include-siblings", false, "include the names of each directory's sibling directories in the prompt")
+	cmdFlags.BoolVar(&readCompressed, "read-compressed", false, "decompress and include gzipped text files (.gz)")
+	cmdFlags.StringVar(&onlyDirsWith, "only-dirs-with", "", "comma-separated extensions; only process directories directly containing such files (e.g. .go,.py)")
+	cmdFlags.StringVar(&only, "only", "", "process only this subdirectory of the target directory and its descendants, keeping inherited .gitignore rules")
+	cmdFlags.StringVar(&tempDir, "temp-dir", "", "directory for the temp files behind atomic summary writes (default: next to each .glance.md); on another filesystem, files are copied into place")
+	cmdFlags.StringVar(&outputDir, "output-dir", "", "write glance output files into a tree mirroring the target directory under this root")
+	cmdFlags.StringVar(&eventsFile, "events-file", "", "stream newline-delimited JSON progress events to this path (within the current directory)")
+	cmdFlags.StringVar(&metricsFile, "metrics-file", "", "write Prometheus textfile metrics for the run to this path (within the current directory)")
+	cmdFlags.BoolVar(&quietSuccess, "quiet-success", false, "suppress success summary lines and report only failures")
+	cmdFlags.Int64Var(&maxRequests, "max-requests", 0, "stop making LLM requests after this many (0 means unlimited)")
+	cmdFlags.Int64Var(&maxTokens, "max-tokens", 0, "stop making LLM requests once this many prompt tokens are spent (0 means unlimited)")
+	cmdFlags.DurationVar(&maxRuntime, "max-runtime", 0, "stop the run cleanly after this much wall-clock time, e.g. 10m (0 means unlimited)")
+	cmdFlags.StringVar(&geminiBackend, "gemini-backend", string(llm.BackendGeminiAPI), "Gemini backend: \"gemini\" (API key) or \"vertex\" (Vertex AI with application default credentials)")
+	cmdFlags.StringVar(&geminiBaseURL, "gemini-base-url", "", "override the Gemini endpoint, e.g. to use a proxy")
+	cmdFlags.Var(&safety, "safety", "override a Gemini safety threshold as category=threshold, e.g. dangerous-content=none (repeatable)")
+	cmdFlags.BoolVar(&deterministic, "deterministic", false, "use temperature 0 for reproducible summaries")
+	cmdFlags.StringVar(&systemText, "system", "", "system instruction sent with every LLM request, e.g. a persona such as \"You are a senior engineer writing concise docs\"")
+	cmdFlags.StringVar(&systemFile, "system-file", "", "path to a file holding the system instruction (instead of --system)")
+	cmdFlags.IntVar(&timeoutSeconds, "timeout", 0, fmt.Sprintf("per-request LLM timeout in seconds (0 uses provider defaults: %ds for Gemini, %ds for OpenRouter)", DefaultGeminiTimeoutSeconds, DefaultOpenRouterTimeoutSeconds))
+	cmdFlags.StringVar(&concurrency, "concurrency", "", "maximum summaries generated at once, or \"auto\" to size it from the CPU count and provider (default unlimited)")
+	cmdFlags.IntVar(&maxInflightLLM, "max-inflight-llm", 0, "maximum LLM generation calls in flight at once, independent of --concurrency (0 means no separate limit)")
+	cmdFlags.StringVar(&providerLimits, "concurrency-per-provider", "", "comma-separated provider=limit pairs capping concurrent requests (e.g. gemini=10,openrouter=2)")
+	cmdFlags.StringVar(&openRouterModels, "openrouter-models", os.Getenv("OPENROUTER_MODELS"), "comma-separated models OpenRouter tries, in order, when the OpenRouter fallback model is unavailable (default $OPENROUTER_MODELS)")
+	cmdFlags.BoolVar(&ignoreCase, "ignore-case", true, "match file extensions and names case-insensitively (use --ignore-case=false to opt out)")
+	cmdFlags.BoolVar(&noCache, "no-cache", false, "do not read or write the summary cache shared across runs")
+	cmdFlags.IntVar(&subGlanceDepth, "subglance-depth", 1, "how many levels of descendant summaries each prompt includes (1 means immediate subdirectories only)")
+	cmdFlags.Int64Var(&maxSubGlanceBytes, "max-subglance-bytes", 0, "cap the combined subdirectory summaries in each prompt, excerpting the longest (0 means unlimited)")
+	cmdFlags.IntVar(&maxOpenFiles, "max-open-files", filesystem.DefaultMaxOpenFiles, "maximum number of files open for reading at once, shared across all directories")
+	cmdFlags.Var(&headers, "header", "extra HTTP header for every LLM request as key=value (repeatable)")
+	cmdFlags.BoolVar(&sampleLargeFiles, "sample-large-files", false, "keep the head, a middle sample, and the tail of files over the size limit instead of truncating them")
+	cmdFlags.DurationVar(&maxFileAge, "max-file-age", 0, "skip files not modified within this duration, e.g. 8760h for one year (0 disables)")
+	cmdFlags.BoolVar(&includeHidden, "include-hidden", false, "also summarize hidden files and directories such as .github (never .git; gitignore rules still apply)")
+	cmdFlags.BoolVar(&globalGitignore, "respect-global-gitignore", false, "also skip paths matched by your global git excludes file (core.excludesFile)")
+	cmdFlags.StringVar(&packageRootFile, "package-root-template", "", "prompt template file for package-root directories (those with package.json, go.mod, Cargo.toml, or pyproject.toml)")
+	cmdFlags.Int64Var(&packageRootBytes, "package-root-max-file-bytes", 0, "larger per-file size limit for package-root directories (0 keeps the normal limit)")
+	cmdFlags.StringVar(&order, "order", OrderDepth, "directory processing order: depth, size-asc, or size-desc (children always precede parents)")
+	cmdFlags.IntVar(&rollupDepth, "rollup-depth", 0, "summarize directories at least this many levels above their deepest subdirectory only from subdirectory summaries, ignoring their own files (0 disables)")
+	cmdFlags.IntVar(&splitLargeDirs, "split-large-dirs", 0, "summarize directories with more than this many files as groups of related files, then combine the group summaries (0 disables)")
+	cmdFlags.BoolVar(&alwaysBubble, "always-bubble", false, "also regenerate ancestors of up-to-date directories whose summaries are newer than their parent's")
+	cmdFlags.BoolVar(&topDown, "top-down", false, "process parents before children in BFS order, without including subdirectory summaries in prompts")
+	cmdFlags.BoolVar(&skipGenerated, "skip-generated", false, "leave generated and minified files (e.g. *.pb.go, *.min.js, \"DO NOT EDIT\" headers) out of prompts")
+	cmdFlags.BoolVar(&detectEncoding, "detect-encoding", false, "transcode UTF-16 (with a byte order mark) and Latin-1 files to UTF-8 instead of replacing invalid bytes")
+	cmdFlags.BoolVar(&describeImages, "describe-images", false, "describe the images in each directory with a vision model and include the descriptions in its prompt")
+	cmdFlags.IntVar(&maxImages, "max-images", DefaultMaxImages, "with --describe-images, the most images described per directory (0 means unlimited)")
+	cmdFlags.Int64Var(&maxImageBytes, "max-image-bytes", DefaultMaxImageBytes, "with --describe-images, skip images larger than this many bytes (0 means unlimited)")
+	cmdFlags.Var(&contextFiles, "context-file", "glob, relative to the target directory, of repository files (e.g. README.md) added to every prompt as context (repeatable)")
+	cmdFlags.Int64Var(&maxContextBytes, "max-context-bytes", DefaultMaxContextBytes, "with --context-file, the combined size limit for context files (0 means unlimited)")
+	cmdFlags.BoolVar(&strict, "strict", false, "fail a directory when any of its files or child summaries cannot be read, instead of skipping them")
+	cmdFlags.BoolVar(&anonymizePaths, "anonymize-paths", false, "replace the absolute target directory with <root> in prompts and logs")
+	cmdFlags.BoolVar(&noEmptyStubs, "no-empty-stubs", false, "write nothing for directories with no analyzable content instead of a minimal stub")
+	cmdFlags.Float64Var(&maxIgnoredRatio, "max-ignored-ratio", 0, "skip directories where more than this fraction (0-1) of the files are gitignored, e.g. 0.9 (0 disables)")
+	cmdFlags.BoolVar(&incrementalPrompt, "incremental-prompt", false, "update an existing glance.md from the git diff of its directory's files instead of their full contents (not with --force, --compare, or --stdout)")
+	cmdFlags.IntVar(&minFiles, "min-files", 0, "only send a directory to the LLM if it has at least this many analyzable files (or child summaries); others get a stub (0 disables)")
+	cmdFlags.BoolVar(&chunkSummarize, "chunk-summarize", false, "summarize files larger than --chunk-summarize-bytes with separate LLM calls and put those summaries in the directory prompt instead of the files")
+	cmdFlags.IntVar(&chunkBytes, "chunk-summarize-bytes", DefaultChunkSummarizeBytes, "with --chunk-summarize, the file size above which files are pre-summarized, and the largest chunk sent per call")
+	cmdFlags.BoolVar(&failOnEmpty, "fail-on-empty-summary", false, "fail a directory, without writing its glance.md, when the generated summary is shorter than --min-summary-length")
+	cmdFlags.IntVar(&minSummaryLength, "min-summary-length", DefaultMinSummaryLength, "with --fail-on-empty-summary, the shortest acceptable summary in characters")
+	cmdFlags.BoolVar(&linkSources, "link-sources", false, "end each glance.md with links to its source files (GitHub permalinks when origin is on GitHub)")
+	cmdFlags.Var(&promptVars, "prompt-var", "variable for custom prompt templates as key=value, referenced as {{.Vars.key}} (repeatable)")
+	cmdFlags.BoolVar(&strictTemplate, "strict-template", false, "fail a directory whose prompt template references a --prompt-var that was not given, instead of rendering it empty")
+	cmdFlags.StringVar(&postProcess, "post-process", "", "comma-separated post-processors applied to each summary before writing: strip-preamble, normalize-fences")
+	cmdFlags.BoolVar(&footer, "footer", false, "end each generated glance.md with a footer naming the model and generation time (ignored when deciding what to regenerate)")
+	cmdFlags.BoolVar(&includeStats, "include-stats", false, "begin each glance.md with a file count, line count, and extension breakdown")
+	cmdFlags.BoolVar(&useRepoRoot, "use-repo-root", false, "when no directory is given, start from the enclosing git repository root instead of the current directory")
+	cmdFlags.StringVar(&dumpPrompt, "dump-prompt", "", "print the rendered LLM prompt for this directory and exit without calling the LLM or writing files")
+	cmdFlags.BoolVar(&stdout, "stdout", false, "write every summary to stdout under a \"===== path =====\" header instead of writing glance.md files")
+	cmdFlags.StringVar(&serveAddr, "addr", DefaultServeAddr, "with the serve command, the address the HTTP API listens on")
+	cmdFlags.BoolVar(&clean, "clean", false, "remove generated glance files under the target directory instead of generating them")
+	cmdFlags.BoolVar(&dryRun, "dry-run", false, "with --clean, list the files that would be removed without deleting them")
+	cmdFlags.BoolVar(&verify, "verify", false, "check existing glance files for invalid UTF-8, unclosed code fences, and empty content, exiting nonzero on problems, without generating anything")
+	cmdFlags.BoolVar(&explain, "explain", false, "print why each directory is or is not regenerated")
+	cmdFlags.BoolVar(&staged, "staged", false, "only process directories containing files with staged git changes, plus their ancestors (e.g. for a pre-commit hook)")
+	cmdFlags.StringVar(&profile, "profile", "", "apply a named set of options, overridden by explicit flags: "+strings.Join(ProfileNames(), ", "))
+	cmdFlags.BoolVar(&compare, "compare", false, "regenerate every summary in memory and report (exiting nonzero) any glance.md that differs, without writing; pair with --deterministic")
+
+	// "glance serve [flags] [root]" runs the HTTP API instead of a one-shot run
+	flagArgs := args[1:]
+	serve := len(flagArgs) > 0 && flagArgs[0] == ServeCommand
+	if serve {
+		flagArgs = flagArgs[1:]
+	}
 
 	// Parse flags
-	if err := cmdFlags.Parse(args[1:]); err != nil {
+	if err := cmdFlags.Parse(flagArgs); err != nil {
 		return nil, fmt.Errorf("failed to parse command-line arguments: %w", err)
 	}
+	if err := applyProfile(cmdFlags, profile); err != nil {
+		return nil, err
+	}
+
+	if maxRequests < 0 || maxTokens < 0 {
+		return nil, errors.New("--max-requests and --max-tokens must not be negative")
+	}
+	if maxInflightLLM < 0 {
+		return nil, errors.New("--max-inflight-llm must not be negative")
+	}
+	if minFiles < 0 {
+		return nil, errors.New("--min-files must not be negative")
+	}
+	if maxIgnoredRatio < 0 || maxIgnoredRatio > 1 {
+		return nil, errors.New("--max-ignored-ratio must be between 0 and 1")
+	}
+	if chunkBytes < 1 {
+		return nil, errors.New("--chunk-summarize-bytes must be at least 1")
+	}
+	if !chunkSummarize {
+		chunkBytes = 0
+	}
+	if minSummaryLength < 1 {
+		return nil, errors.New("--min-summary-length must be at least 1")
+	}
+	if maxRuntime < 0 {
+		return nil, errors.New("--max-runtime must not be negative")
+	}
+	if maxFileAge < 0 {
+		return nil, errors.New("--max-file-age must not be negative")
+	}
+	if subGlanceDepth < 1 {
+		return nil, errors.New("--subglance-depth must be at least 1")
+	}
+	if maxSubGlanceBytes < 0 {
+		return nil, errors.New("--max-subglance-bytes must not be negative")
+	}
+	if order != OrderDepth && order != OrderSizeAsc && order != OrderSizeDesc {
+		return nil, fmt.Errorf("unknown --order %q (expected %q, %q, or %q)", order, OrderDepth, OrderSizeAsc, OrderSizeDesc)
+	}
+	if topDown && order != OrderDepth {
+		return nil, errors.New("--top-down cannot be combined with --order")
+	}
+	if rollupDepth < 0 {
+		return nil, errors.New("--rollup-depth must not be negative")
+	}
+	if topDown && alwaysBubble {
+		return nil, errors.New("--top-down cannot be combined with --always-bubble, since top-down parents never read subdirectory summaries")
+	}
+	if topDown && rollupDepth > 0 {
+		return nil, errors.New("--top-down cannot be combined with --rollup-depth, since roll-ups are built from subdirectory summaries")
+	}
+	if splitLargeDirs < 0 {
+		return nil, errors.New("--split-large-dirs must not be negative")
+	}
+	if packageRootBytes < 0 {
+		return nil, errors.New("--package-root-max-file-bytes must not be negative")
+	}
+	if maxOpenFiles < 1 {
+		return nil, errors.New("--max-open-files must be at least 1")
+	}
+	if dryRun && !clean {
+		return nil, errors.New("--dry-run requires --clean")
+	}
+	if clean && dumpPrompt != "" {
+		return nil, errors.New("--clean and --dump-prompt cannot be combined")
+	}
+	if compare && clean {
+		return nil, errors.New("--compare and --clean cannot be combined")
+	}
+	if verify && (serve || clean || compare || stdout || dumpPrompt != "") {
+		return nil, errors.New("--verify cannot be combined with serve, --clean, --compare, --stdout, or --dump-prompt")
+	}
+	if serve && (clean || compare || dumpPrompt != "") {
+		return nil, errors.New("the serve command cannot be combined with --clean, --compare, or --dump-prompt")
+	}
+	if stdout && (serve || clean || compare || dumpPrompt != "") {
+		return nil, errors.New("--stdout cannot be combined with serve, --clean, --compare, or --dump-prompt")
+	}
+	if !serve && serveAddr != DefaultServeAddr {
+		return nil, errors.New("--addr requires the serve command")
+	}
+	if timeoutSeconds < 0 {
+		return nil, errors.New("--timeout must not be negative")
+	}
+
+	providerConcurrency, err := parseProviderConcurrency(providerLimits)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --concurrency-per-provider: %w", err)
+	}
+	concurrencyLimit, err := parseConcurrency(concurrency, providerConcurrency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --concurrency: %w", err)
+	}
+
+	systemInstructions, err := loadSystemInstructions(systemText, systemFile)
+	if err != nil {
+		return nil, err
+	}
+
+	postProcessors, err := parsePostProcessors(postProcess)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --post-process: %w", err)
+	}
+
+	globalSettings, err := LoadGlobalSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load global configuration: %w", err)
+	}
+
+	backend, err := llm.ParseBackend(geminiBackend)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --gemini-backend: %w", err)
+	}
 
 	// Validate target directory — default to current directory when omitted
 	if cmdFlags.NArg() > 1 {
-		return nil, errors.New("too many arguments: at most one directory may be specified")
+		return nil, fmt.Errorf("too many arguments: at most one directory may be specified, got %d (did your shell expand a glob?)", cmdFlags.NArg())
 	}
 
 	// Get target directory and validate it
 	targetDir := "."
 	if cmdFlags.NArg() == 1 {
 		targetDir = cmdFlags.Arg(0)
+	} else if useRepoRoot {
+		if root, ok := filesystem.FindRepoRoot(targetDir); ok {
+			logrus.WithField("repo_root", root).Debug("Using git repository root as target directory")
+			targetDir = root
+		} else {
+			logrus.Warn("--use-repo-root: no git repository found; using the current directory")
+		}
+	}
+
+	if err := checkNotGlob(targetDir); err != nil {
+		return nil, err
 	}
 
 	// Check if directory exists and is actually a directory
-	// The directoryChecker will clean the path and verify it's a directory
+	// The directoryChecker will clean the path (trailing separators, "." and
+	// ".." elements) and verify it's a directory
 	validatedDir, err := dirChecker.CheckDirectory(targetDir)
 	if err != nil {
 		return nil, err
@@ -117,8 +406,10 @@ func LoadConfig(args []string) (*Config, error) {
 	}
 
 	// Get API key from environment
+	// Vertex AI authenticates with application default credentials instead,
+	// and --clean and --verify never call the LLM
 	apiKey := os.Getenv("GEMINI_API_KEY")
-	if apiKey == "" {
+	if apiKey == "" && backend != llm.BackendVertexAI && !clean && !verify {
 		return nil, errors.New("GEMINI_API_KEY is missing: please set this environment variable or add it to your .env file")
 	}
 
@@ -133,12 +424,429 @@ func LoadConfig(args []string) (*Config, error) {
 		promptTemplate = llm.DefaultTemplate()
 	}
 
+	// Package roots may use their own template; an empty path must not fall back
+	// to prompt.txt the way --prompt-file does
+	var packageRootTemplate string
+	if packageRootFile != "" {
+		packageRootTemplate, err = loadPromptTemplate(packageRootFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load package root template: %w", err)
+		}
+	}
+
+	// The prompt-dump directory must lie within the target directory
+	if dumpPrompt != "" {
+		dumpPrompt, err = filesystem.ValidateDirPath(dumpPrompt, absDir, true, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --dump-prompt directory: %w", err)
+		}
+	}
+
+	// Validate the metrics file path against the current working directory
+	if metricsFile != "" {
+		metricsFile, err = resolveMetricsFile(metricsFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The event stream follows the same rule
+	if eventsFile != "" {
+		eventsFile, err = resolveOutputFile(eventsFile, "events file")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The --only subtree is relative to, and must lie within, the target directory
+	if only != "" {
+		if !filepath.IsAbs(only) {
+			only = filepath.Join(absDir, only)
+		}
+		only, err = filesystem.ValidateDirPath(only, absDir, true, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --only directory: %w", err)
+		}
+	}
+
+	// Mirrored output must stay out of the scanned tree, and vice versa
+	if outputDir != "" {
+		outputDir, err = resolveOutputDir(outputDir, absDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if tempDir != "" {
+		tempDir, err = dirChecker.CheckDirectory(tempDir)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --temp-dir: %w", err)
+		}
+		tempDir, err = filepath.Abs(tempDir)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --temp-dir: %w", err)
+		}
+	}
+
 	// Apply all configuration settings using the builder pattern
 	cfg = cfg.
 		WithAPIKey(apiKey).
 		WithTargetDir(absDir).
 		WithForce(force).
-		WithPromptTemplate(promptTemplate)
+		WithPromptTemplate(promptTemplate).
+		WithIncludeGitMetadata(includeGitMetadata).
+		WithIncludeSiblings(includeSiblings).
+		WithReadCompressed(readCompressed).
+		WithOnlyDirsWith(parseExtensionList(onlyDirsWith)).
+		WithMetricsFile(metricsFile).
+		WithEventsFile(eventsFile).
+		WithOutputDir(outputDir).
+		WithTempDir(tempDir).
+		WithOnly(only).
+		WithExplain(explain).
+		WithCompare(compare).
+		WithStaged(staged).
+		WithQuietSuccess(quietSuccess).
+		WithMaxRequests(maxRequests).
+		WithMaxTokens(maxTokens).
+		WithMaxRuntime(maxRuntime).
+		WithGeminiBackend(backend).
+		WithGeminiBaseURL(geminiBaseURL).
+		WithSafetySettings(safety.settings).
+		WithDeterministic(deterministic).
+		WithSystemInstructions(systemInstructions).
+		WithProviderConcurrency(providerConcurrency).
+		WithConcurrency(concurrencyLimit).
+		WithMaxInflightLLM(maxInflightLLM).
+		WithOpenRouterModels(parseModelList(openRouterModels)).
+		WithTimeoutSeconds(timeoutSeconds).
+		WithAllowedModels(globalSettings.AllowedModels).
+		WithIgnoreCase(ignoreCase).
+		WithNoCache(noCache).
+		WithIncludeStats(includeStats).
+		WithFooter(footer).
+		WithMaxFileAge(maxFileAge).
+		WithDumpPrompt(dumpPrompt).
+		WithClean(clean).
+		WithDryRun(dryRun).
+		WithVerify(verify).
+		WithStdout(stdout).
+		WithServe(serve, serveAddr, os.Getenv("GLANCE_SERVE_TOKEN")).
+		WithSampleLargeFiles(sampleLargeFiles).
+		WithExtraHeaders(headers.values).
+		WithMaxSubGlanceBytes(maxSubGlanceBytes).
+		WithSubGlanceDepth(subGlanceDepth).
+		WithMaxOpenFiles(maxOpenFiles).
+		WithNoEmptyStubs(noEmptyStubs).
+		WithMinFiles(minFiles).
+		WithIncrementalPrompt(incrementalPrompt).
+		WithMaxIgnoredRatio(maxIgnoredRatio).
+		WithChunkSummarizeBytes(chunkBytes).
+		WithFailOnEmptySummary(failOnEmpty, minSummaryLength).
+		WithRespectGlobalGitignore(globalGitignore).
+		WithIncludeHidden(includeHidden).
+		WithStrict(strict).
+		WithAnonymizePaths(anonymizePaths).
+		WithLinkSources(linkSources).
+		WithPostProcessors(postProcessors).
+		WithPromptVars(promptVars.values).
+		WithStrictTemplate(strictTemplate).
+		WithOrder(order).
+		WithTopDown(topDown).
+		WithAlwaysBubble(alwaysBubble).
+		WithRollupDepth(rollupDepth).
+		WithSplitLargeDirs(splitLargeDirs).
+		WithSkipGenerated(skipGenerated).
+		WithDetectEncoding(detectEncoding).
+		WithDescribeImages(describeImages).
+		WithMaxImages(maxImages).
+		WithMaxImageBytes(maxImageBytes).
+		WithContextFiles(contextFiles.patterns).
+		WithMaxContextBytes(maxContextBytes).
+		WithPackageRootTemplate(packageRootTemplate).
+		WithPackageRootMaxFileBytes(packageRootBytes)
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
 
 	return cfg, nil
 }
+
+// checkNotGlob rejects a target directory argument that is an unexpanded glob
+// such as "src/*". glance takes exactly one directory and does not expand
+// patterns itself. Paths that exist are always accepted, since directory names
+// may legitimately contain glob characters.
+func checkNotGlob(path string) error {
+	if !strings.ContainsAny(path, "*?[") {
+		return nil
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	return fmt.Errorf("target directory %q looks like a glob pattern; glance takes a single directory and summarizes everything under it", path)
+}
+
+// resolveOutputDir absolutizes the --output-dir root and ensures it neither is
+// nor contains targetDir, so mirrored output never overwrites the source. A
+// root inside targetDir, such as docs/glance, is allowed; scans and freshness
+// checks exclude it.
+func resolveOutputDir(path, targetDir string) (string, error) {
+	absPath, err := filepath.Abs(filepath.Clean(path))
+	if err != nil {
+		return "", fmt.Errorf("invalid --output-dir path: %w", err)
+	}
+	rel, err := filepath.Rel(absPath, targetDir)
+	if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid --output-dir %q: must not be or contain the target directory %q", absPath, targetDir)
+	}
+	return absPath, nil
+}
+
+// parseExtensionList splits a comma-separated extension list such as ".go, py"
+// into normalized extensions with a leading dot. Empty entries are dropped.
+func parseExtensionList(list string) []string {
+	var exts []string
+	for _, part := range strings.Split(list, ",") {
+		ext := strings.TrimSpace(part)
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		exts = append(exts, ext)
+	}
+	return exts
+}
+
+// parseModelList splits a comma-separated model list such as
+// "openai/gpt-4o-mini, anthropic/claude-3.5-haiku". Empty entries are dropped.
+func parseModelList(list string) []string {
+	var models []string
+	for _, part := range strings.Split(list, ",") {
+		if model := strings.TrimSpace(part); model != "" {
+			models = append(models, model)
+		}
+	}
+	return models
+}
+
+// parsePostProcessors parses a list such as "strip-preamble,normalize-fences"
+// into post-processor names, rejecting any that are not built in.
+func parsePostProcessors(list string) ([]string, error) {
+	var names []string
+	for _, part := range strings.Split(list, ",") {
+		name := strings.TrimSpace(part)
+		if name == "" {
+			continue
+		}
+		if _, ok := llm.BuiltinPostProcessor(name); !ok {
+			return nil, fmt.Errorf("unknown post-processor %q (available: %s)", name, strings.Join(llm.BuiltinPostProcessorNames(), ", "))
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// parseProviderConcurrency parses a list such as "gemini=10,openrouter=2" into
+// per-provider concurrency limits. An empty list yields nil (no limits).
+func parseProviderConcurrency(list string) (map[string]int, error) {
+	var limits map[string]int
+	for _, part := range strings.Split(list, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		provider, value, found := strings.Cut(part, "=")
+		provider = strings.ToLower(strings.TrimSpace(provider))
+		if !found {
+			return nil, fmt.Errorf("%q is not of the form provider=limit", part)
+		}
+		if provider != ProviderGemini && provider != ProviderOpenRouter {
+			return nil, fmt.Errorf("unknown provider %q (expected %q or %q)", provider, ProviderGemini, ProviderOpenRouter)
+		}
+
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("limit for %q must be a positive integer", provider)
+		}
+
+		if limits == nil {
+			limits = make(map[string]int)
+		}
+		limits[provider] = limit
+	}
+	return limits, nil
+}
+
+// headerFlags collects repeated --header key=value flags.
+type headerFlags struct {
+	values map[string]string
+}
+
+// String implements flag.Value.
+func (h *headerFlags) String() string {
+	pairs := make([]string, 0, len(h.values))
+	for name, value := range h.values {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Set implements flag.Value, parsing and validating one key=value header.
+// Reserved headers such as Authorization are rejected so they cannot replace credentials.
+func (h *headerFlags) Set(pair string) error {
+	name, value, found := strings.Cut(pair, "=")
+	name = strings.TrimSpace(name)
+	if !found || name == "" {
+		return fmt.Errorf("%q is not of the form key=value", pair)
+	}
+	if strings.ContainsAny(name, " \t:\r\n") {
+		return fmt.Errorf("invalid header name %q", name)
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("header %q value must not contain line breaks", name)
+	}
+	if llm.IsReservedHeader(name) {
+		return fmt.Errorf("header %q is reserved and cannot be overridden", name)
+	}
+
+	if h.values == nil {
+		h.values = make(map[string]string)
+	}
+	h.values[name] = strings.TrimSpace(value)
+	return nil
+}
+
+// safetyFlags collects repeated --safety category=threshold flags. A later
+// flag for the same category replaces the earlier one.
+type safetyFlags struct {
+	settings []llm.SafetySetting
+}
+
+// String implements flag.Value.
+func (s *safetyFlags) String() string {
+	pairs := make([]string, len(s.settings))
+	for i, setting := range s.settings {
+		pairs[i] = setting.Category + "=" + setting.Threshold
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Set implements flag.Value, parsing one category=threshold setting.
+func (s *safetyFlags) Set(pair string) error {
+	category, threshold, found := strings.Cut(pair, "=")
+	if !found || strings.TrimSpace(category) == "" || strings.TrimSpace(threshold) == "" {
+		return fmt.Errorf("%q is not of the form category=threshold", pair)
+	}
+	setting, err := llm.ParseSafetySetting(category, threshold)
+	if err != nil {
+		return err
+	}
+
+	for i := range s.settings {
+		if s.settings[i].Category == setting.Category {
+			s.settings[i] = setting
+			return nil
+		}
+	}
+	s.settings = append(s.settings, setting)
+	return nil
+}
+
+// varFlags collects repeated --prompt-var key=value flags.
+type varFlags struct {
+	values map[string]string
+}
+
+// String implements flag.Value.
+func (v *varFlags) String() string {
+	pairs := make([]string, 0, len(v.values))
+	for key, value := range v.values {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Set implements flag.Value, parsing one key=value variable. Keys must be
+// identifiers so templates can reference them as {{.Vars.key}}.
+func (v *varFlags) Set(pair string) error {
+	key, value, found := strings.Cut(pair, "=")
+	key = strings.TrimSpace(key)
+	if !found || key == "" {
+		return fmt.Errorf("%q is not of the form key=value", pair)
+	}
+	if !isIdentifier(key) {
+		return fmt.Errorf("prompt variable name %q must contain only letters, digits, and underscores, and not start with a digit", key)
+	}
+
+	if v.values == nil {
+		v.values = make(map[string]string)
+	}
+	v.values[key] = value
+	return nil
+}
+
+// isIdentifier reports whether name is a valid Go template field name.
+func isIdentifier(name string) bool {
+	for i, r := range name {
+		if r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r)) {
+			continue
+		}
+		return false
+	}
+	return name != ""
+}
+
+// patternFlags collects repeated glob pattern flags.
+type patternFlags struct {
+	patterns []string
+}
+
+// String implements flag.Value.
+func (p *patternFlags) String() string {
+	return strings.Join(p.patterns, ",")
+}
+
+// Set implements flag.Value, rejecting malformed patterns and absolute paths,
+// since patterns are resolved relative to the target directory.
+func (p *patternFlags) Set(pattern string) error {
+	if filepath.IsAbs(pattern) {
+		return fmt.Errorf("pattern %q must be relative to the target directory", pattern)
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	p.patterns = append(p.patterns, pattern)
+	return nil
+}
+
+// resolveMetricsFile absolutizes the metrics file path and ensures it lies within
+// the current working directory.
+func resolveMetricsFile(path string) (string, error) {
+	return resolveOutputFile(path, "metrics file")
+}
+
+// resolveOutputFile absolutizes the path of a file glance will write and ensures
+// it lies within the current working directory. what names the file in errors.
+func resolveOutputFile(path, what string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	absPath, err := filepath.Abs(filepath.Clean(path))
+	if err != nil {
+		return "", fmt.Errorf("invalid %s path: %w", what, err)
+	}
+
+	validPath, err := validateFilePath(absPath, cwd, false, false)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s path: %w", what, err)
+	}
+	return validPath, nil
+}
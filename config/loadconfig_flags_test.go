@@ -0,0 +1,576 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"glance/llm"
+)
+
+func TestLoadConfigAnonymizePaths(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.AnonymizePaths, "off by default")
+
+	cfg, err = LoadConfig([]string{"glance", "--anonymize-paths", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.AnonymizePaths)
+}
+
+func TestLoadConfigLinkSources(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.LinkSources, "off by default")
+
+	cfg, err = LoadConfig([]string{"glance", "--link-sources", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.LinkSources)
+}
+
+func TestLoadConfigTopDown(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.TopDown, "bottom-up by default")
+
+	cfg, err = LoadConfig([]string{"glance", "--top-down", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.TopDown)
+
+	_, err = LoadConfig([]string{"glance", "--top-down", "--order=size-desc", "/test/dir"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--top-down cannot be combined with --order")
+}
+
+func TestLoadConfigSkipGenerated(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.SkipGenerated, "generated files are kept by default")
+
+	cfg, err = LoadConfig([]string{"glance", "--skip-generated", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.SkipGenerated)
+}
+
+func TestLoadConfigDetectEncoding(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.DetectEncoding, "files are read as UTF-8 by default")
+
+	cfg, err = LoadConfig([]string{"glance", "--detect-encoding", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.DetectEncoding)
+}
+
+func TestLoadConfigOutputDir(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Empty(t, cfg.OutputDir, "summaries are written in place by default")
+
+	cfg, err = LoadConfig([]string{"glance", "--output-dir", "/srv/glance/../out", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, "/srv/out", cfg.OutputDir)
+
+	cfg, err = LoadConfig([]string{"glance", "--output-dir", "/test/dir/docs/glance", "/test/dir"})
+	require.NoError(t, err, "an output root inside the target directory is allowed")
+	assert.Equal(t, "/test/dir/docs/glance", cfg.OutputDir)
+
+	for _, dir := range []string{"/test/dir", "/test"} {
+		_, err = LoadConfig([]string{"glance", "--output-dir", dir, "/test/dir"})
+		require.Error(t, err, dir)
+		assert.Contains(t, err.Error(), "must not be or contain the target directory", dir)
+	}
+}
+
+func TestLoadConfigDescribeImages(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.DescribeImages, "off by default")
+	assert.Equal(t, DefaultMaxImages, cfg.MaxImages)
+	assert.Equal(t, int64(DefaultMaxImageBytes), cfg.MaxImageBytes)
+
+	cfg, err = LoadConfig([]string{"glance", "--describe-images", "--max-images=2", "--max-image-bytes=1024", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.DescribeImages)
+	assert.Equal(t, 2, cfg.MaxImages)
+	assert.Equal(t, int64(1024), cfg.MaxImageBytes)
+
+	_, err = LoadConfig([]string{"glance", "--max-images=-1", "/test/dir"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "MaxImages must be 0 or more")
+}
+
+func TestLoadConfigOnly(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	root := t.TempDir()
+	sub := filepath.Join(root, "pkg", "api")
+	require.NoError(t, os.MkdirAll(sub, 0755))
+
+	cfg, err := LoadConfig([]string{"glance", root})
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Only, "the whole target directory by default")
+
+	cfg, err = LoadConfig([]string{"glance", "--only", filepath.Join("pkg", "api"), root})
+	require.NoError(t, err)
+	assert.Equal(t, sub, cfg.Only, "relative paths resolve against the target directory")
+
+	_, err = LoadConfig([]string{"glance", "--only", "missing", root})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --only directory")
+
+	_, err = LoadConfig([]string{"glance", "--only", t.TempDir(), root})
+	require.Error(t, err, "the subtree must lie within the target directory")
+}
+
+func TestLoadConfigContextFiles(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Empty(t, cfg.ContextFiles)
+	assert.Equal(t, int64(DefaultMaxContextBytes), cfg.MaxContextBytes)
+
+	cfg, err = LoadConfig([]string{"glance", "--context-file", "README.md", "--context-file", "docs/*.md", "--max-context-bytes", "1024", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"README.md", "docs/*.md"}, cfg.ContextFiles)
+	assert.Equal(t, int64(1024), cfg.MaxContextBytes)
+
+	_, err = LoadConfig([]string{"glance", "--context-file", "[README", "/test/dir"})
+	assert.Error(t, err, "malformed patterns are rejected")
+
+	_, err = LoadConfig([]string{"glance", "--context-file", "/etc/passwd", "/test/dir"})
+	assert.Error(t, err, "patterns must be relative to the target directory")
+
+	_, err = LoadConfig([]string{"glance", "--max-context-bytes", "-1", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfigCompare(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.Compare)
+
+	cfg, err = LoadConfig([]string{"glance", "--compare", "--deterministic", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.Compare)
+
+	_, err = LoadConfig([]string{"glance", "--compare", "--clean", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfigPostProcess(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Empty(t, cfg.PostProcessors)
+
+	cfg, err = LoadConfig([]string{"glance", "--post-process", "strip-preamble, normalize-fences", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"strip-preamble", "normalize-fences"}, cfg.PostProcessors)
+
+	_, err = LoadConfig([]string{"glance", "--post-process", "prettier", "/test/dir"})
+	assert.ErrorContains(t, err, "unknown post-processor")
+}
+
+func TestLoadConfigStaged(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.Staged)
+
+	cfg, err = LoadConfig([]string{"glance", "--staged", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.Staged)
+}
+
+func TestLoadConfigPromptVars(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Empty(t, cfg.PromptVars)
+	assert.False(t, cfg.StrictTemplate)
+
+	cfg, err = LoadConfig([]string{"glance",
+		"--prompt-var", "project=glance",
+		"--prompt-var", "audience=new contributors, mostly",
+		"--prompt-var", "team_2=",
+		"--strict-template",
+		"/test/dir",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"project":  "glance",
+		"audience": "new contributors, mostly",
+		"team_2":   "",
+	}, cfg.PromptVars)
+	assert.True(t, cfg.StrictTemplate)
+
+	for _, bad := range []string{"project", "=glance", "2fa=yes", "my-project=glance"} {
+		_, err = LoadConfig([]string{"glance", "--prompt-var", bad, "/test/dir"})
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestLoadConfigSafety(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Empty(t, cfg.SafetySettings)
+
+	cfg, err = LoadConfig([]string{"glance",
+		"--safety", "dangerous-content=none",
+		"--safety", "HARM_CATEGORY_HATE_SPEECH=high",
+		"--safety", "dangerous-content=low",
+		"/test/dir",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []llm.SafetySetting{
+		{Category: llm.HarmCategoryDangerousContent, Threshold: llm.HarmBlockLowAndAbove},
+		{Category: llm.HarmCategoryHateSpeech, Threshold: llm.HarmBlockHighAndAbove},
+	}, cfg.SafetySettings, "a repeated category keeps its position and takes the last threshold")
+
+	for _, bad := range []string{"dangerous-content", "=none", "dangerous-content=", "violence=none", "harassment=sometimes"} {
+		_, err = LoadConfig([]string{"glance", "--safety", bad, "/test/dir"})
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestLoadConfigRollupDepth(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Zero(t, cfg.RollupDepth)
+	assert.False(t, cfg.Rollup)
+
+	cfg, err = LoadConfig([]string{"glance", "--rollup-depth", "2", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, cfg.RollupDepth)
+	assert.False(t, cfg.Rollup, "roll-up is decided per directory, not at load time")
+
+	_, err = LoadConfig([]string{"glance", "--rollup-depth", "-1", "/test/dir"})
+	assert.Error(t, err)
+	_, err = LoadConfig([]string{"glance", "--rollup-depth", "1", "--top-down", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfigIncludeHidden(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.IncludeHidden)
+
+	cfg, err = LoadConfig([]string{"glance", "--include-hidden", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.IncludeHidden)
+}
+
+func TestLoadConfigSplitLargeDirs(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Zero(t, cfg.SplitLargeDirs)
+
+	cfg, err = LoadConfig([]string{"glance", "--split-large-dirs", "40", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, 40, cfg.SplitLargeDirs)
+
+	_, err = LoadConfig([]string{"glance", "--split-large-dirs", "-1", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfigTempDir(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Empty(t, cfg.TempDir, "temp files default to the destination directory")
+
+	cfg, err = LoadConfig([]string{"glance", "--temp-dir", "/scratch", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, filepath.IsAbs(cfg.TempDir))
+	assert.Equal(t, "scratch", filepath.Base(cfg.TempDir))
+}
+
+func TestLoadConfigSubGlanceDepth(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, cfg.SubGlanceDepth, "immediate children only by default")
+
+	cfg, err = LoadConfig([]string{"glance", "--subglance-depth", "3", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, cfg.SubGlanceDepth)
+
+	_, err = LoadConfig([]string{"glance", "--subglance-depth", "0", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfigTimeout(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Zero(t, cfg.TimeoutSeconds)
+	assert.Equal(t, DefaultGeminiTimeoutSeconds, cfg.ProviderTimeout(ProviderGemini))
+	assert.Equal(t, DefaultOpenRouterTimeoutSeconds, cfg.ProviderTimeout(ProviderOpenRouter))
+
+	cfg, err = LoadConfig([]string{"glance", "--timeout", "15", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, 15, cfg.ProviderTimeout(ProviderGemini))
+	assert.Equal(t, 15, cfg.ProviderTimeout(ProviderOpenRouter))
+
+	_, err = LoadConfig([]string{"glance", "--timeout", "-1", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfigAlwaysBubble(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.AlwaysBubble)
+
+	cfg, err = LoadConfig([]string{"glance", "--always-bubble", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.AlwaysBubble)
+
+	_, err = LoadConfig([]string{"glance", "--always-bubble", "--top-down", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfigServe(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{
+		"GEMINI_API_KEY":     "test-api-key",
+		"GLANCE_SERVE_TOKEN": "shared-token",
+	})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.Serve)
+
+	cfg, err = LoadConfig([]string{"glance", "serve", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.Serve)
+	assert.Equal(t, DefaultServeAddr, cfg.ServeAddr)
+	assert.Equal(t, "shared-token", cfg.ServeToken)
+	assert.Contains(t, cfg.TargetDir, "test")
+
+	cfg, err = LoadConfig([]string{"glance", "serve", "--addr", ":9090", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, ":9090", cfg.ServeAddr)
+
+	_, err = LoadConfig([]string{"glance", "--addr", ":9090", "/test/dir"})
+	assert.Error(t, err)
+	_, err = LoadConfig([]string{"glance", "serve", "--clean", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfigFailOnEmptySummary(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.FailOnEmptySummary)
+	assert.Equal(t, DefaultMinSummaryLength, cfg.MinSummaryLength)
+
+	cfg, err = LoadConfig([]string{"glance", "--fail-on-empty-summary", "--min-summary-length", "200", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.FailOnEmptySummary)
+	assert.Equal(t, 200, cfg.MinSummaryLength)
+
+	_, err = LoadConfig([]string{"glance", "--min-summary-length", "0", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfigStdout(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.Stdout)
+
+	cfg, err = LoadConfig([]string{"glance", "--stdout", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.Stdout)
+
+	_, err = LoadConfig([]string{"glance", "--stdout", "--compare", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfigChunkSummarize(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, cfg.ChunkSummarizeBytes)
+
+	cfg, err = LoadConfig([]string{"glance", "--chunk-summarize", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultChunkSummarizeBytes, cfg.ChunkSummarizeBytes)
+
+	cfg, err = LoadConfig([]string{"glance", "--chunk-summarize", "--chunk-summarize-bytes", "1000", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, 1000, cfg.ChunkSummarizeBytes)
+
+	_, err = LoadConfig([]string{"glance", "--chunk-summarize-bytes", "0", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfigIncrementalPrompt(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.IncrementalPrompt)
+
+	cfg, err = LoadConfig([]string{"glance", "--incremental-prompt", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.IncrementalPrompt)
+}
+
+func TestLoadConfigOpenRouterModels(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	t.Setenv("OPENROUTER_MODELS", "")
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Nil(t, cfg.OpenRouterModels)
+
+	t.Setenv("OPENROUTER_MODELS", "openai/gpt-4o-mini, anthropic/claude-3.5-haiku,")
+	cfg, err = LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"openai/gpt-4o-mini", "anthropic/claude-3.5-haiku"}, cfg.OpenRouterModels)
+
+	cfg, err = LoadConfig([]string{"glance", "--openrouter-models", "meta-llama/llama-3.3-70b-instruct", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"meta-llama/llama-3.3-70b-instruct"}, cfg.OpenRouterModels, "the flag overrides the environment")
+}
+
+func TestLoadConfigMaxIgnoredRatio(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Zero(t, cfg.MaxIgnoredRatio)
+
+	cfg, err = LoadConfig([]string{"glance", "--max-ignored-ratio", "0.9", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, 0.9, cfg.MaxIgnoredRatio)
+
+	for _, bad := range []string{"-0.1", "1.5"} {
+		_, err = LoadConfig([]string{"glance", "--max-ignored-ratio", bad, "/test/dir"})
+		assert.Error(t, err, "--max-ignored-ratio %s", bad)
+	}
+}
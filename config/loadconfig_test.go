@@ -6,9 +6,13 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"glance/filesystem"
+	"glance/llm"
 )
 
 // mockDirectoryChecker implements directoryChecker for testing
@@ -158,6 +162,8 @@ func TestLoadConfigAllFlags(t *testing.T) {
 		"glance",
 		"--force",
 		"--prompt-file", customPromptPath,
+		"--include-git-metadata",
+		"--read-compressed",
 		"/test/target/dir",
 	}
 
@@ -171,6 +177,8 @@ func TestLoadConfigAllFlags(t *testing.T) {
 	assert.True(t, cfg.Force, "Force flag should be true")
 	assert.Equal(t, customPromptContent, cfg.PromptTemplate, "Prompt template should be loaded from file")
 	assert.Equal(t, "/test/target/dir", cfg.TargetDir, "Target directory should be set correctly")
+	assert.True(t, cfg.IncludeGitMetadata, "Git metadata flag should be true")
+	assert.True(t, cfg.ReadCompressed, "Read compressed flag should be true")
 }
 
 func TestLoadConfigDefaults(t *testing.T) {
@@ -201,6 +209,7 @@ func TestLoadConfigDefaults(t *testing.T) {
 	assert.NotEmpty(t, cfg.PromptTemplate, "Default prompt template should be used")
 	assert.Equal(t, DefaultMaxRetries, cfg.MaxRetries, "Default max retries should be used")
 	assert.Equal(t, int64(DefaultMaxFileBytes), cfg.MaxFileBytes, "Default max file bytes should be used")
+	assert.False(t, cfg.IncludeGitMetadata, "Git metadata should be disabled by default")
 }
 
 func TestLoadConfigWithCustomPromptFile(t *testing.T) {
@@ -571,3 +580,518 @@ func TestLoadConfigInvalidDirectory(t *testing.T) {
 }
 
 // Note: These tests were moved to template_test.go
+
+func TestParseExtensionList(t *testing.T) {
+	assert.Nil(t, parseExtensionList(""))
+	assert.Equal(t, []string{".go", ".py"}, parseExtensionList(".go,py"))
+	assert.Equal(t, []string{".go", ".ts"}, parseExtensionList(" .go , , ts "))
+}
+
+func TestLoadConfigMetricsFile(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cwd := t.TempDir()
+	t.Chdir(cwd)
+
+	t.Run("Relative path resolves within cwd", func(t *testing.T) {
+		cfg, err := LoadConfig([]string{"glance", "--metrics-file", "out/glance.prom", "/test/dir"})
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(cwd, "out", "glance.prom"), cfg.MetricsFile)
+	})
+
+	t.Run("Path outside cwd is rejected", func(t *testing.T) {
+		_, err := LoadConfig([]string{"glance", "--metrics-file", "../escape.prom", "/test/dir"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid metrics file path")
+	})
+}
+
+func TestLoadConfigBudget(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	t.Run("Caps are parsed", func(t *testing.T) {
+		cfg, err := LoadConfig([]string{"glance", "--max-requests", "10", "--max-tokens", "50000", "/test/dir"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), cfg.MaxRequests)
+		assert.Equal(t, int64(50000), cfg.MaxTokens)
+	})
+
+	t.Run("Negative caps are rejected", func(t *testing.T) {
+		_, err := LoadConfig([]string{"glance", "--max-requests", "-1", "/test/dir"})
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadConfigGeminiBackend(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+
+	t.Run("Vertex AI does not require an API key", func(t *testing.T) {
+		cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": ""})
+		defer cleanupEnv()
+
+		cfg, err := LoadConfig([]string{"glance", "--gemini-backend", "vertex", "--gemini-base-url", "https://proxy.example.com/", "/test/dir"})
+		require.NoError(t, err)
+		assert.Equal(t, llm.BackendVertexAI, cfg.GeminiBackend)
+		assert.Equal(t, "https://proxy.example.com/", cfg.GeminiBaseURL)
+	})
+
+	t.Run("Unknown backend is rejected", func(t *testing.T) {
+		cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+		defer cleanupEnv()
+
+		_, err := LoadConfig([]string{"glance", "--gemini-backend", "bedrock", "/test/dir"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "--gemini-backend")
+	})
+}
+
+func TestParseProviderConcurrency(t *testing.T) {
+	limits, err := parseProviderConcurrency("")
+	require.NoError(t, err)
+	assert.Nil(t, limits)
+
+	limits, err = parseProviderConcurrency(" gemini=10, OpenRouter=2 ")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{ProviderGemini: 10, ProviderOpenRouter: 2}, limits)
+
+	for _, invalid := range []string{"gemini", "gemini=0", "gemini=x", "anthropic=3"} {
+		_, err := parseProviderConcurrency(invalid)
+		assert.Error(t, err, "%q should be rejected", invalid)
+	}
+}
+
+func TestLoadConfigIgnoreCase(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.IgnoreCase, "case-insensitive matching is the default")
+
+	cfg, err = LoadConfig([]string{"glance", "--ignore-case=false", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.IgnoreCase)
+}
+
+func TestLoadConfigIncludeStats(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.IncludeStats)
+
+	cfg, err = LoadConfig([]string{"glance", "--include-stats", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.IncludeStats)
+}
+
+func TestLoadConfigIncludeSiblings(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.IncludeSiblings)
+
+	cfg, err = LoadConfig([]string{"glance", "--include-siblings", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.IncludeSiblings)
+}
+
+func TestLoadConfigUseRepoRoot(t *testing.T) {
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	repo := t.TempDir()
+	nested := filepath.Join(repo, "pkg", "sub")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+	require.NoError(t, os.Mkdir(filepath.Join(repo, ".git"), 0755))
+	t.Chdir(nested)
+
+	t.Run("Defaults to the repo root", func(t *testing.T) {
+		mockChecker, cleanup := setupMockDirectoryChecker(true, "")
+		defer cleanup()
+
+		cfg, err := LoadConfig([]string{"glance", "--use-repo-root"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{repo}, mockChecker.checkedPaths)
+		assert.Equal(t, repo, cfg.TargetDir)
+	})
+
+	t.Run("Explicit argument wins", func(t *testing.T) {
+		mockChecker, cleanup := setupMockDirectoryChecker(true, "")
+		defer cleanup()
+
+		_, err := LoadConfig([]string{"glance", "--use-repo-root", "/test/dir"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/test/dir"}, mockChecker.checkedPaths)
+	})
+
+	t.Run("Without the flag the current directory is used", func(t *testing.T) {
+		mockChecker, cleanup := setupMockDirectoryChecker(true, "")
+		defer cleanup()
+
+		_, err := LoadConfig([]string{"glance"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"."}, mockChecker.checkedPaths)
+	})
+}
+
+func TestLoadConfigMaxFileAge(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Zero(t, cfg.MaxFileAge, "the age filter is disabled by default")
+
+	cfg, err = LoadConfig([]string{"glance", "--max-file-age", "720h", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, 720*time.Hour, cfg.MaxFileAge)
+
+	_, err = LoadConfig([]string{"glance", "--max-file-age", "-1h", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfigMaxRuntime(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Zero(t, cfg.MaxRuntime, "runs are unbounded by default")
+
+	cfg, err = LoadConfig([]string{"glance", "--max-runtime", "15m", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Minute, cfg.MaxRuntime)
+
+	_, err = LoadConfig([]string{"glance", "--max-runtime", "-1s", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfigMinFiles(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Zero(t, cfg.MinFiles, "every directory with files is summarized by default")
+
+	cfg, err = LoadConfig([]string{"glance", "--min-files", "3", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, cfg.MinFiles)
+
+	_, err = LoadConfig([]string{"glance", "--min-files", "-1", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfigDumpPrompt(t *testing.T) {
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	root := t.TempDir()
+	pkg := filepath.Join(root, "pkg")
+	require.NoError(t, os.Mkdir(pkg, 0755))
+	outside := t.TempDir()
+
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+
+	cfg, err := LoadConfig([]string{"glance", "--dump-prompt", pkg, root})
+	require.NoError(t, err)
+	assert.Equal(t, pkg, cfg.DumpPrompt)
+
+	_, err = LoadConfig([]string{"glance", "--dump-prompt", outside, root})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--dump-prompt")
+}
+
+func TestLoadConfigClean(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	t.Chdir(t.TempDir()) // keep any repository .env out of the test
+	t.Setenv("GEMINI_API_KEY", "")
+
+	cfg, err := LoadConfig([]string{"glance", "--clean", "--dry-run", "/test/dir"})
+	require.NoError(t, err, "--clean does not need an API key")
+	assert.True(t, cfg.Clean)
+	assert.True(t, cfg.DryRun)
+
+	_, err = LoadConfig([]string{"glance", "--dry-run", "/test/dir"})
+	assert.EqualError(t, err, "--dry-run requires --clean")
+}
+
+func TestLoadConfigVerify(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	t.Chdir(t.TempDir()) // keep any repository .env out of the test
+	t.Setenv("GEMINI_API_KEY", "")
+
+	cfg, err := LoadConfig([]string{"glance", "--verify", "/test/dir"})
+	require.NoError(t, err, "--verify does not need an API key")
+	assert.True(t, cfg.Verify)
+
+	for _, conflicting := range []string{"--clean", "--compare", "--stdout"} {
+		_, err = LoadConfig([]string{"glance", "--verify", conflicting, "/test/dir"})
+		assert.ErrorContains(t, err, "--verify cannot be combined", conflicting)
+	}
+}
+
+func TestLoadConfigSampleLargeFiles(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.SampleLargeFiles)
+
+	cfg, err = LoadConfig([]string{"glance", "--sample-large-files", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.SampleLargeFiles)
+}
+
+func TestLoadConfigExtraHeaders(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Empty(t, cfg.ExtraHeaders)
+
+	cfg, err = LoadConfig([]string{"glance",
+		"--header", "X-Cost-Center=platform",
+		"--header", "X-Request-Id = abc=123",
+		"/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"X-Cost-Center": "platform", "X-Request-Id": "abc=123"}, cfg.ExtraHeaders)
+
+	for _, bad := range []string{"Authorization=Bearer x", "content-type=text/plain", "novalue", "=empty", "Bad Name=x", "X-A=line\nbreak"} {
+		_, err = LoadConfig([]string{"glance", "--header", bad, "/test/dir"})
+		assert.Error(t, err, "header %q should be rejected", bad)
+	}
+}
+
+func TestLoadConfigMaxSubGlanceBytes(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Zero(t, cfg.MaxSubGlanceBytes, "no limit by default")
+
+	cfg, err = LoadConfig([]string{"glance", "--max-subglance-bytes", "65536", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(65536), cfg.MaxSubGlanceBytes)
+
+	_, err = LoadConfig([]string{"glance", "--max-subglance-bytes", "-1", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfigMaxOpenFiles(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, filesystem.DefaultMaxOpenFiles, cfg.MaxOpenFiles)
+
+	cfg, err = LoadConfig([]string{"glance", "--max-open-files", "16", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, 16, cfg.MaxOpenFiles)
+
+	_, err = LoadConfig([]string{"glance", "--max-open-files", "0", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfigNoEmptyStubs(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.NoEmptyStubs)
+
+	cfg, err = LoadConfig([]string{"glance", "--no-empty-stubs", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.NoEmptyStubs)
+}
+
+func TestLoadConfigRespectGlobalGitignore(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.RespectGlobalGitignore, "off by default")
+
+	cfg, err = LoadConfig([]string{"glance", "--respect-global-gitignore", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.RespectGlobalGitignore)
+}
+
+func TestLoadConfigEventsFile(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cwd := t.TempDir()
+	t.Chdir(cwd)
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Empty(t, cfg.EventsFile, "no stream by default")
+
+	cfg, err = LoadConfig([]string{"glance", "--events-file", "run.jsonl", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(cwd, "run.jsonl"), cfg.EventsFile)
+
+	_, err = LoadConfig([]string{"glance", "--events-file", "../escape.jsonl", "/test/dir"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid events file path")
+}
+
+func TestLoadConfigStrict(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.False(t, cfg.Strict, "lenient by default")
+
+	cfg, err = LoadConfig([]string{"glance", "--strict", "/test/dir"})
+	require.NoError(t, err)
+	assert.True(t, cfg.Strict)
+}
+
+func TestLoadConfigOrder(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, OrderDepth, cfg.Order)
+
+	for _, order := range []string{OrderDepth, OrderSizeAsc, OrderSizeDesc} {
+		cfg, err = LoadConfig([]string{"glance", "--order", order, "/test/dir"})
+		require.NoError(t, err)
+		assert.Equal(t, order, cfg.Order)
+	}
+
+	_, err = LoadConfig([]string{"glance", "--order", "random", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfigPackageRoot(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Empty(t, cfg.PackageRootTemplate)
+	assert.Zero(t, cfg.PackageRootMaxFileBytes)
+
+	// An absolute path outside the working directory is allowed, like --prompt-file
+	templatePath := filepath.Join(t.TempDir(), "package.tmpl")
+	require.NoError(t, os.WriteFile(templatePath, []byte("package {{.Directory}}"), 0600))
+
+	cfg, err = LoadConfig([]string{"glance", "--package-root-template", templatePath, "--package-root-max-file-bytes", "10485760", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, "package {{.Directory}}", cfg.PackageRootTemplate)
+	assert.Equal(t, int64(10485760), cfg.PackageRootMaxFileBytes)
+
+	_, err = LoadConfig([]string{"glance", "--package-root-template", filepath.Join(t.TempDir(), "missing.tmpl"), "/test/dir"})
+	assert.Error(t, err)
+
+	_, err = LoadConfig([]string{"glance", "--package-root-max-file-bytes", "-1", "/test/dir"})
+	assert.Error(t, err)
+}
+
+func TestLoadConfigTargetDirNormalization(t *testing.T) {
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "src"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "src", "main.go"), []byte("package main\n"), 0600))
+	t.Chdir(root)
+
+	t.Run("Trailing separator is cleaned", func(t *testing.T) {
+		cfg, err := LoadConfig([]string{"glance", filepath.Join(root, "src") + string(filepath.Separator)})
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(root, "src"), cfg.TargetDir)
+	})
+
+	t.Run("Dot paths resolve to the directory", func(t *testing.T) {
+		cfg, err := LoadConfig([]string{"glance", "."})
+		require.NoError(t, err)
+		assert.Equal(t, root, cfg.TargetDir)
+
+		cfg, err = LoadConfig([]string{"glance", "src/../src/."})
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(root, "src"), cfg.TargetDir)
+	})
+
+	t.Run("File target is rejected", func(t *testing.T) {
+		_, err := LoadConfig([]string{"glance", "src/main.go"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is a file, not a directory")
+	})
+
+	t.Run("Glob target is rejected", func(t *testing.T) {
+		_, err := LoadConfig([]string{"glance", "src/*"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "looks like a glob pattern")
+	})
+
+	t.Run("Existing directory with glob characters is accepted", func(t *testing.T) {
+		require.NoError(t, os.Mkdir(filepath.Join(root, "[draft]"), 0755))
+		cfg, err := LoadConfig([]string{"glance", "[draft]"})
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(root, "[draft]"), cfg.TargetDir)
+	})
+
+	t.Run("Shell-expanded glob is reported", func(t *testing.T) {
+		_, err := LoadConfig([]string{"glance", "src", "[draft]"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "did your shell expand a glob?")
+	})
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// profiles maps each --profile name to the flag values it stands for. Values
+// are flag syntax, so a profile is validated exactly like the flags it bundles.
+var profiles = map[string]map[string]string{
+	// ci: reproducible output that fails loudly and reports only problems
+	"ci": {
+		"deterministic":         "true",
+		"quiet-success":         "true",
+		"strict":                "true",
+		"fail-on-empty-summary": "true",
+	},
+
+	// interactive: show why each directory is or is not regenerated
+	"interactive": {
+		"explain": "true",
+	},
+
+	// cheap: cap spending and keep prompts small
+	"cheap": {
+		"max-requests":        "100",
+		"max-tokens":          "500000",
+		"skip-generated":      "true",
+		"max-subglance-bytes": "32768",
+	},
+}
+
+// ProfileNames returns the names accepted by --profile, sorted.
+func ProfileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyProfile sets the flags bundled by the named profile on an already
+// parsed flag set. Flags given explicitly on the command line keep their
+// values, so they always override the profile. An empty name does nothing.
+func applyProfile(flags *flag.FlagSet, name string) error {
+	if name == "" {
+		return nil
+	}
+	values, ok := profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(ProfileNames(), ", "))
+	}
+
+	explicit := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	for flagName, value := range values {
+		if explicit[flagName] {
+			continue
+		}
+		if err := flags.Set(flagName, value); err != nil {
+			return fmt.Errorf("profile %q: invalid --%s: %w", name, flagName, err)
+		}
+	}
+	return nil
+}
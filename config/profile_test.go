@@ -0,0 +1,65 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigProfiles(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	load := func(args ...string) *Config {
+		t.Helper()
+		cfg, err := LoadConfig(append(append([]string{"glance"}, args...), "/test/dir"))
+		require.NoError(t, err)
+		return cfg
+	}
+
+	t.Run("ci", func(t *testing.T) {
+		cfg := load("--profile", "ci")
+		assert.True(t, cfg.Deterministic)
+		assert.True(t, cfg.QuietSuccess)
+		assert.True(t, cfg.Strict)
+		assert.True(t, cfg.FailOnEmptySummary)
+	})
+
+	t.Run("interactive", func(t *testing.T) {
+		cfg := load("--profile", "interactive")
+		assert.True(t, cfg.Explain)
+	})
+
+	t.Run("cheap", func(t *testing.T) {
+		cfg := load("--profile", "cheap")
+		assert.Equal(t, int64(100), cfg.MaxRequests)
+		assert.Equal(t, int64(500000), cfg.MaxTokens)
+		assert.True(t, cfg.SkipGenerated)
+		assert.Equal(t, int64(32768), cfg.MaxSubGlanceBytes)
+	})
+
+	t.Run("explicit flags override the profile", func(t *testing.T) {
+		cfg := load("--max-requests", "5", "--profile", "cheap")
+		assert.Equal(t, int64(5), cfg.MaxRequests, "a flag before --profile still wins")
+		assert.Equal(t, int64(500000), cfg.MaxTokens)
+
+		cfg = load("--profile", "ci", "--deterministic=false")
+		assert.False(t, cfg.Deterministic)
+		assert.True(t, cfg.Strict)
+	})
+
+	t.Run("no profile", func(t *testing.T) {
+		cfg := load()
+		assert.False(t, cfg.Deterministic)
+		assert.Zero(t, cfg.MaxRequests)
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		_, err := LoadConfig([]string{"glance", "--profile", "turbo", "/test/dir"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cheap, ci, interactive")
+	})
+}
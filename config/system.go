@@ -0,0 +1,50 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// loadSystemInstructions resolves --system and --system-file into the system
+// instructions sent with every request. At most one may be given; with neither
+// the result is empty and the providers' defaults apply.
+//
+// Parameters:
+//   - text: The --system text
+//   - path: The --system-file path
+//
+// Returns:
+//   - The system instructions, trimmed of surrounding whitespace
+//   - An error if both are given, or the file is missing, unreadable, or empty
+func loadSystemInstructions(text, path string) (string, error) {
+	if path == "" {
+		return strings.TrimSpace(text), nil
+	}
+	if text != "" {
+		return "", errors.New("--system and --system-file cannot be combined")
+	}
+
+	absPath, err := filepath.Abs(filepath.Clean(path))
+	if err != nil {
+		return "", fmt.Errorf("invalid --system-file path: %w", err)
+	}
+	// Like --prompt-file, the file may be anywhere but must exist and not be a directory
+	validPath, err := validateFilePath(absPath, "/", true, true)
+	if err != nil {
+		return "", fmt.Errorf("invalid --system-file: %w", err)
+	}
+
+	// #nosec G304 -- The path has been validated using filesystem.ValidateFilePath
+	data, err := os.ReadFile(validPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read --system-file: %w", err)
+	}
+	instructions := strings.TrimSpace(string(data))
+	if instructions == "" {
+		return "", fmt.Errorf("--system-file %s is empty", validPath)
+	}
+	return instructions, nil
+}
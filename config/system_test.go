@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSystemInstructions(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "system.txt")
+	require.NoError(t, os.WriteFile(file, []byte("You are a senior engineer writing concise docs.\n"), 0600))
+	blank := filepath.Join(dir, "blank.txt")
+	require.NoError(t, os.WriteFile(blank, []byte(" \n"), 0600))
+
+	instructions, err := loadSystemInstructions("", "")
+	require.NoError(t, err)
+	assert.Empty(t, instructions)
+
+	instructions, err = loadSystemInstructions("  Be terse. ", "")
+	require.NoError(t, err)
+	assert.Equal(t, "Be terse.", instructions)
+
+	instructions, err = loadSystemInstructions("", file)
+	require.NoError(t, err)
+	assert.Equal(t, "You are a senior engineer writing concise docs.", instructions)
+
+	_, err = loadSystemInstructions("Be terse.", file)
+	assert.ErrorContains(t, err, "cannot be combined")
+
+	_, err = loadSystemInstructions("", filepath.Join(dir, "missing.txt"))
+	assert.ErrorContains(t, err, "invalid --system-file")
+
+	_, err = loadSystemInstructions("", dir)
+	assert.Error(t, err, "a directory is not a system file")
+
+	_, err = loadSystemInstructions("", blank)
+	assert.ErrorContains(t, err, "is empty")
+}
+
+func TestLoadConfigSystemInstructions(t *testing.T) {
+	_, cleanup := setupMockDirectoryChecker(true, "")
+	defer cleanup()
+	cleanupEnv := setupEnvVars(t, map[string]string{"GEMINI_API_KEY": "test-api-key"})
+	defer cleanupEnv()
+
+	file := filepath.Join(t.TempDir(), "persona.txt")
+	require.NoError(t, os.WriteFile(file, []byte("Write for new contributors."), 0600))
+
+	cfg, err := LoadConfig([]string{"glance", "/test/dir"})
+	require.NoError(t, err)
+	assert.Empty(t, cfg.SystemInstructions)
+
+	cfg, err = LoadConfig([]string{"glance", "--system", "Be terse.", "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, "Be terse.", cfg.SystemInstructions)
+
+	cfg, err = LoadConfig([]string{"glance", "--system-file", file, "/test/dir"})
+	require.NoError(t, err)
+	assert.Equal(t, "Write for new contributors.", cfg.SystemInstructions)
+
+	_, err = LoadConfig([]string{"glance", "--system-file", file + ".missing", "/test/dir"})
+	assert.Error(t, err)
+}
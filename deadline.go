@@ -0,0 +1,17 @@
+package main
+
+import (
+	"context"
+
+	"glance/config"
+)
+
+// runContext returns the context bounding the whole run. With --max-runtime it
+// carries a deadline, after which in-flight LLM calls are cancelled and the
+// remaining directories are skipped; otherwise it is never done.
+func runContext(cfg *config.Config) (context.Context, context.CancelFunc) {
+	if cfg.MaxRuntime <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), cfg.MaxRuntime)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// slowClient is a mock client whose Generate takes delay to answer, or gives up
+// early when its context is done.
+type slowClient struct {
+	*MockClient
+	delay time.Duration
+}
+
+func (c *slowClient) Generate(ctx context.Context, prompt string) (string, error) {
+	select {
+	case <-time.After(c.delay):
+		return c.MockClient.Generate(ctx, prompt)
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// TestMaxRuntimeStopsRun verifies that a --max-runtime deadline cancels the
+// in-flight request, skips the remaining directories, and is reported in the debrief.
+func TestMaxRuntimeStopsRun(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	root := t.TempDir()
+	for i := 0; i < 4; i++ {
+		dir := filepath.Join(root, fmt.Sprintf("pkg%d", i))
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0600))
+	}
+
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).Return("# summary\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	client := &slowClient{MockClient: &MockClient{LLMClient: mockLLMClient}, delay: 200 * time.Millisecond}
+	service, err := llm.NewService(client)
+	require.NoError(t, err)
+
+	dirsList, ignoreChains, err := filesystem.ListDirsWithIgnores(root)
+	require.NoError(t, err)
+	reverseSlice(dirsList)
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithMaxRuntime(300 * time.Millisecond)
+	ctx, cancel := runContext(cfg)
+	defer cancel()
+
+	start := time.Now()
+	results, _ := processDirectories(ctx, dirsList, ignoreChains, cfg, service, io.Discard)
+	elapsed := time.Since(start)
+
+	assert.Less(t, elapsed, 800*time.Millisecond, "the run should stop near the deadline, not process all five directories")
+	require.Len(t, results, 5)
+
+	var succeeded, skipped int
+	for _, r := range results {
+		switch {
+		case r.success:
+			succeeded++
+		case r.deadlineSkipped:
+			skipped++
+			assert.ErrorIs(t, r.err, context.DeadlineExceeded)
+			assert.NoFileExists(t, filepath.Join(r.dir, filesystem.GlanceFilename))
+		default:
+			t.Errorf("%s failed instead of being skipped: %v", r.dir, r.err)
+		}
+	}
+	assert.GreaterOrEqual(t, succeeded, 1, "directories finished before the deadline keep their summaries")
+	assert.GreaterOrEqual(t, skipped, 2, "the in-flight directory and everything after it are skipped")
+	assert.False(t, results[len(results)-1].success, "the root comes last and is never reached")
+
+	hook.Reset()
+	printDebrief(results, true)
+
+	var warned bool
+	for _, entry := range hook.AllEntries() {
+		assert.NotEqual(t, logrus.ErrorLevel, entry.Level, "deadline-skipped directories are not failures")
+		if entry.Level == logrus.WarnLevel && entry.Data["deadline_skipped_count"] == skipped {
+			warned = true
+		}
+	}
+	assert.True(t, warned, "debrief should report that the deadline was reached")
+}
+
+func TestRunContextWithoutMaxRuntime(t *testing.T) {
+	ctx, cancel := runContext(config.NewDefaultConfig())
+	defer cancel()
+
+	_, hasDeadline := ctx.Deadline()
+	assert.False(t, hasDeadline)
+	assert.NoError(t, ctx.Err())
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"glance/metrics"
+	"glance/ui"
+)
+
+// printDebrief reports the outcome of the run: a single summary line followed by
+// one error line per failed directory, plus a warning when the LLM budget or --max-runtime ran out.
+// When quietSuccess is set, the summary line is omitted and only problems are reported.
+func printDebrief(results []result, quietSuccess bool) {
+	counts := make(map[dirOutcome]int)
+	var totalBudgetSkipped, totalDeadlineSkipped int
+	for _, r := range results {
+		counts[r.outcome]++
+		if r.budgetSkipped {
+			totalBudgetSkipped++
+		}
+		if r.deadlineSkipped {
+			totalDeadlineSkipped++
+		}
+	}
+
+	if !quietSuccess {
+		logrus.WithFields(logrus.Fields{
+			"total_dirs":      len(results),
+			"generated_count": counts[outcomeGenerated],
+			"stub_count":      counts[outcomeStub],
+			"skipped_count":   counts[outcomeSkipped],
+			"failure_count":   counts[outcomeFailed],
+		}).Info("Directory processing summary")
+	}
+
+	if totalBudgetSkipped > 0 {
+		logrus.WithField("skipped_count", totalBudgetSkipped).
+			Warn("LLM budget exhausted (--max-requests/--max-tokens); remaining directories were skipped")
+	}
+
+	if totalDeadlineSkipped > 0 {
+		logrus.WithField("deadline_skipped_count", totalDeadlineSkipped).
+			Warn("Run deadline reached (--max-runtime); in-flight and remaining directories were skipped")
+	}
+
+	for _, r := range results {
+		if r.outcome == outcomeFailed {
+			// Use the UI error reporting
+			ui.ReportError(r.err, fmt.Sprintf("Failed to process %s (attempts: %d)", r.dir, r.attempts))
+		}
+	}
+}
+
+// exportMetrics writes a Prometheus textfile summarizing the run to path,
+// which must lie within the current working directory.
+func exportMetrics(path string, results []result, tokens int64, duration time.Duration) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	collector := metrics.NewCollector()
+	for _, r := range results {
+		// Directories skipped because the budget or run time ran out were not failures
+		collector.RecordDirectory(r.outcome != outcomeFailed)
+	}
+	collector.AddTokens(int(tokens))
+	collector.SetRunDuration(duration)
+
+	return collector.WriteTextfile(path, cwd)
+}
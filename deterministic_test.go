@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"glance/config"
+	"glance/llm"
+)
+
+// applyClientOptions resolves client options onto the library defaults.
+func applyClientOptions(options []llm.ClientOption) llm.ClientOptions {
+	resolved := llm.DefaultClientOptions()
+	for _, option := range options {
+		option(&resolved)
+	}
+	return resolved
+}
+
+// TestDeterministicClientOptions verifies that --deterministic reaches every tier's options.
+func TestDeterministicClientOptions(t *testing.T) {
+	cfg := config.NewDefaultConfig().WithDeterministic(true)
+
+	gemini := applyClientOptions(geminiClientOptions(cfg, "gemini-2.5-flash"))
+	assert.True(t, gemini.Deterministic)
+	assert.Equal(t, float32(0), gemini.Temperature)
+
+	openRouter := applyClientOptions(tierClientOptions(cfg, "x-ai/grok-4.1-fast"))
+	assert.True(t, openRouter.Deterministic)
+	assert.Equal(t, float32(0), openRouter.Temperature)
+
+	defaults := applyClientOptions(tierClientOptions(config.NewDefaultConfig(), "gemini-2.5-flash"))
+	assert.False(t, defaults.Deterministic)
+	assert.Equal(t, llm.DefaultClientOptions().Temperature, defaults.Temperature)
+}
+
+// TestSystemInstructionsClientOptions verifies that --system and --system-file
+// reach every tier's options.
+func TestSystemInstructionsClientOptions(t *testing.T) {
+	cfg := config.NewDefaultConfig().WithSystemInstructions("You are a senior engineer writing concise docs.")
+
+	gemini := applyClientOptions(geminiClientOptions(cfg, "gemini-2.5-flash"))
+	assert.Equal(t, "You are a senior engineer writing concise docs.", gemini.SystemInstructions)
+
+	openRouter := applyClientOptions(openRouterClientOptions(cfg, "x-ai/grok-4.1-fast"))
+	assert.Equal(t, "You are a senior engineer writing concise docs.", openRouter.SystemInstructions)
+
+	defaults := applyClientOptions(geminiClientOptions(config.NewDefaultConfig(), "gemini-2.5-flash"))
+	assert.Empty(t, defaults.SystemInstructions)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestDirConfigOverridesInProcessing verifies that .glance.toml overrides reach the
+// prompt for their subtree and that a malformed file fails only its own directory.
+func TestDirConfigOverridesInProcessing(t *testing.T) {
+	root := t.TempDir()
+	french := filepath.Join(root, "french")
+	nested := filepath.Join(french, "nested")
+	broken := filepath.Join(root, "broken")
+	for _, d := range []string{nested, broken} {
+		require.NoError(t, os.MkdirAll(d, 0755))
+	}
+	for _, d := range []string{root, french, nested, broken} {
+		require.NoError(t, os.WriteFile(filepath.Join(d, "main.go"), []byte("package x\n"), 0600))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(french, "prompt.txt"), []byte("en français: {{.Directory}}"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(french, config.DirConfigFilename), []byte("prompt_file = \"prompt.txt\"\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(broken, config.DirConfigFilename), []byte("read_compressed = \n"), 0600))
+
+	var prompts []string
+	mockLLMClient := new(mocks.LLMClient)
+	mockClient := &MockClient{LLMClient: mockLLMClient}
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { prompts = append(prompts, args.String(1)) }).
+		Return("# summary\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(mockClient, llm.WithPromptTemplate("default: {{.Directory}}"))
+	require.NoError(t, err)
+
+	dirsList, ignoreChains, err := filesystem.ListDirsWithIgnores(root)
+	require.NoError(t, err)
+	reverseSlice(dirsList)
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root)
+	results, _ := processDirectories(context.Background(), dirsList, ignoreChains, cfg, service, io.Discard)
+
+	byDir := make(map[string]result)
+	for _, r := range results {
+		byDir[r.dir] = r
+	}
+	require.Len(t, byDir, 4, "every directory gets a result")
+
+	assert.False(t, byDir[broken].success)
+	require.Error(t, byDir[broken].err)
+	assert.Contains(t, byDir[broken].err.Error(), config.DirConfigFilename)
+	assert.True(t, byDir[french].success)
+	assert.True(t, byDir[nested].success)
+	assert.True(t, byDir[root].success, "a malformed file must not abort the run")
+
+	assert.ElementsMatch(t, []string{
+		"en français: french/nested",
+		"en français: french",
+		"default: .",
+	}, prompts)
+	assert.False(t, strings.Contains(strings.Join(prompts, "\n"), "broken"))
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"glance/config"
+	"glance/llm"
+)
+
+// errDirNotProcessed reports a directory the normal scan would skip.
+var errDirNotProcessed = errors.New("not processed by glance (hidden or ignored)")
+
+// directoryInputs are the prompt inputs gathered for one directory.
+type directoryInputs struct {
+	promptDir  string
+	files      map[string]string
+	subGlances string
+	options    []llm.PromptDataOption
+}
+
+// gatherDirectoryInputs gathers dir's files and subdirectory glance outputs
+// exactly as processDirectory does, without calling the LLM or writing any
+// files.
+//
+// The directory must be one the normal scan would process: within cfg.TargetDir
+// and not hidden or excluded by .gitignore.
+func gatherDirectoryInputs(ctx context.Context, cfg *config.Config, dir string) (directoryInputs, error) {
+	dirs, ignoreChains, err := listAllDirsWithIgnores(cfg.TargetDir, scanOptions(cfg)...)
+	if err != nil {
+		return directoryInputs{}, fmt.Errorf("failed to scan %s: %w", cfg.TargetDir, err)
+	}
+	ignoreChain, ok := ignoreChains[dir]
+	if !ok {
+		return directoryInputs{}, fmt.Errorf("%s is %w", dir, errDirNotProcessed)
+	}
+
+	dirCfg, err := config.NewDirConfigResolver(cfg).Resolve(dir)
+	if err != nil {
+		return directoryInputs{}, err
+	}
+	dirCfg = rollupConfig(packageRootConfig(dirCfg, dir), dir, dirHeights(dirs))
+	dirCfg = siblingsConfig(dirCfg, dir, siblingDirs(dirs))
+
+	subdirs, err := readSubdirectories(dir, ignoreChain, ignoreOptions(dirCfg)...)
+	if err != nil {
+		return directoryInputs{}, err
+	}
+	subGlances, err := subGlancesFor(dirCfg, dir, subdirs, ignoreChain)
+	if err != nil {
+		return directoryInputs{}, fmt.Errorf("gatherSubGlances failed: %w", err)
+	}
+	fileContents, err := localFilesFor(dir, ignoreChain, dirCfg)
+	if err != nil {
+		return directoryInputs{}, fmt.Errorf("gatherLocalFiles failed: %w", err)
+	}
+
+	return directoryInputs{
+		promptDir:  promptDir(dirCfg, dir),
+		files:      fileContents,
+		subGlances: subGlances,
+		options:    append(promptOptions(dirCfg, dir), imagePromptOptions(ctx, dirCfg, dir, ignoreChain)...),
+	}, nil
+}
+
+// dumpPrompt writes the fully-rendered prompt for cfg.DumpPrompt to out, built
+// from the inputs gatherDirectoryInputs collects.
+func dumpPrompt(cfg *config.Config, llmService *llm.Service, out io.Writer) error {
+	inputs, err := gatherDirectoryInputs(context.Background(), cfg, cfg.DumpPrompt)
+	if err != nil {
+		return err
+	}
+
+	prompt, err := llmService.RenderPrompt(inputs.promptDir, inputs.files, inputs.subGlances, inputs.options...)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(out, prompt)
+	return err
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestDumpPrompt verifies that --dump-prompt renders the directory's prompt with
+// its relative name, file contents, and subdirectory glance output, without
+// calling the LLM or writing any files.
+func TestDumpPrompt(t *testing.T) {
+	root := t.TempDir()
+	pkg := filepath.Join(root, "pkg")
+	sub := filepath.Join(pkg, "sub")
+	require.NoError(t, os.MkdirAll(sub, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pkg, "main.go"), []byte("package pkg // marker-content"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(sub, filesystem.GlanceFilename), []byte("sub summary marker"), 0600))
+
+	mockLLMClient := new(mocks.LLMClient)
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+		llm.WithPromptTemplate("dir={{.Directory}}\nsubs={{.SubGlances}}\nfiles={{.FileContents}}"))
+	require.NoError(t, err)
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithDumpPrompt(pkg)
+	var out bytes.Buffer
+	require.NoError(t, dumpPrompt(cfg, service, &out))
+
+	prompt := out.String()
+	assert.Contains(t, prompt, "dir=pkg\n")
+	assert.Contains(t, prompt, "marker-content")
+	assert.Contains(t, prompt, "main.go")
+	assert.Contains(t, prompt, "sub summary marker")
+
+	mockLLMClient.AssertNotCalled(t, "Generate")
+	mockLLMClient.AssertNotCalled(t, "CountTokens")
+	_, statErr := os.Stat(filepath.Join(pkg, filesystem.GlanceFilename))
+	assert.True(t, os.IsNotExist(statErr), "dump mode must not write glance output")
+}
+
+func TestDumpPromptRejectsIgnoredDirectory(t *testing.T) {
+	root := t.TempDir()
+	hidden := filepath.Join(root, ".hidden")
+	require.NoError(t, os.Mkdir(hidden, 0755))
+
+	service, err := llm.NewService(&MockClient{LLMClient: new(mocks.LLMClient)}, llm.WithPromptTemplate("{{.Directory}}"))
+	require.NoError(t, err)
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithDumpPrompt(hidden)
+	err = dumpPrompt(cfg, service, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "hidden or ignored")
+}
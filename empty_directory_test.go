@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -43,7 +45,7 @@ func TestProcessDirectoryUsesRelativePath(t *testing.T) {
 	ignoreChain := filesystem.IgnoreChain{}
 
 	// Act
-	r := processDirectory(subdir, true, ignoreChain, cfg, service)
+	r := processDirectory(context.Background(), subdir, true, ignoreChain, cfg, service)
 
 	// Assert
 	require.True(t, r.success, "processDirectory should succeed: %v", r.err)
@@ -73,7 +75,7 @@ func TestProcessDirectoryUsesRelativePathForRoot(t *testing.T) {
 	cfg := config.NewDefaultConfig().WithTargetDir(root).WithMaxFileBytes(1 << 20)
 	ignoreChain := filesystem.IgnoreChain{}
 
-	r := processDirectory(root, true, ignoreChain, cfg, service)
+	r := processDirectory(context.Background(), root, true, ignoreChain, cfg, service)
 
 	require.True(t, r.success, "processDirectory should succeed: %v", r.err)
 	assert.Equal(t, "dir: .", capturedPrompt, "root dir should render exactly as '.'")
@@ -103,7 +105,7 @@ func TestProcessDirectoryUsesRelativePathForNestedDir(t *testing.T) {
 	cfg := config.NewDefaultConfig().WithTargetDir(root).WithMaxFileBytes(1 << 20)
 	ignoreChain := filesystem.IgnoreChain{}
 
-	r := processDirectory(nested, true, ignoreChain, cfg, service)
+	r := processDirectory(context.Background(), nested, true, ignoreChain, cfg, service)
 
 	require.True(t, r.success, "processDirectory should succeed: %v", r.err)
 	assert.NotContains(t, capturedPrompt, root, "prompt must not contain the absolute root path")
@@ -130,7 +132,7 @@ func TestEmptyDirectorySkipsLLM(t *testing.T) {
 		ignoreChain := filesystem.IgnoreChain{}
 
 		// Act
-		r := processDirectory(dir, true, ignoreChain, cfg, service)
+		r := processDirectory(context.Background(), dir, true, ignoreChain, cfg, service)
 
 		// Assert: success, no LLM call
 		assert.True(t, r.success, "processDirectory should succeed on empty directory")
@@ -172,7 +174,7 @@ func TestEmptyDirectorySkipsLLM(t *testing.T) {
 		ignoreChain := filesystem.IgnoreChain{}
 
 		// Act
-		r := processDirectory(dir, true, ignoreChain, cfg, service)
+		r := processDirectory(context.Background(), dir, true, ignoreChain, cfg, service)
 
 		// Assert: success, no LLM call
 		assert.True(t, r.success)
@@ -210,7 +212,7 @@ func TestEmptyDirectorySkipsLLM(t *testing.T) {
 		ignoreChain := filesystem.IgnoreChain{}
 
 		// Act
-		r := processDirectory(dir, true, ignoreChain, cfg, service)
+		r := processDirectory(context.Background(), dir, true, ignoreChain, cfg, service)
 
 		// Assert
 		assert.True(t, r.success)
@@ -253,10 +255,65 @@ func TestEmptyDirectorySkipsLLM(t *testing.T) {
 		ignoreChain := filesystem.IgnoreChain{}
 
 		// Act
-		r := processDirectory(dir, true, ignoreChain, cfg, service)
+		r := processDirectory(context.Background(), dir, true, ignoreChain, cfg, service)
 
 		// Assert: LLM WAS called because there is child context
 		assert.True(t, r.success)
 		mockLLMClient.AssertCalled(t, "Generate", mock.Anything, mock.Anything)
 	})
 }
+
+// TestNoEmptyStubs verifies that --no-empty-stubs writes nothing for directories with
+// no analyzable content and does not mark their parents for regeneration.
+func TestNoEmptyStubs(t *testing.T) {
+	t.Run("empty directory writes no file", func(t *testing.T) {
+		dir := t.TempDir()
+
+		mockLLMClient := new(mocks.LLMClient)
+		service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient})
+		require.NoError(t, err)
+
+		cfg := config.NewDefaultConfig().WithMaxFileBytes(1 << 20).WithNoEmptyStubs(true)
+
+		r := processDirectory(context.Background(), dir, true, filesystem.IgnoreChain{}, cfg, service)
+
+		assert.True(t, r.success)
+		assert.NoError(t, r.err)
+		assert.Zero(t, r.attempts, "a skipped directory must not trigger parent propagation")
+		assert.NoFileExists(t, filepath.Join(dir, filesystem.GlanceFilename))
+		mockLLMClient.AssertNotCalled(t, "Generate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("parent is not marked for regeneration", func(t *testing.T) {
+		for _, noStubs := range []bool{false, true} {
+			// BubbleUpParents never marks the root itself, so nest the empty directory
+			root := t.TempDir()
+			web := filepath.Join(root, "web")
+			assets := filepath.Join(web, "assets")
+			require.NoError(t, os.MkdirAll(assets, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(assets, "logo.png"), []byte{0x89, 0x50, 0x4e, 0x47, 0x00, 0x00}, 0600))
+			require.NoError(t, os.WriteFile(filepath.Join(web, "app.js"), []byte("export {}\n"), 0600))
+
+			mockLLMClient := new(mocks.LLMClient)
+			mockLLMClient.On("Generate", mock.Anything, mock.Anything).Return("# summary\n", nil)
+			mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+			service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient}, llm.WithPromptTemplate("{{.Directory}}"))
+			require.NoError(t, err)
+
+			dirsList, ignoreChains, err := filesystem.ListDirsWithIgnores(root)
+			require.NoError(t, err)
+			reverseSlice(dirsList)
+
+			cfg := config.NewDefaultConfig().WithTargetDir(root).WithNoEmptyStubs(noStubs)
+			_, needsRegen := processDirectories(context.Background(), dirsList, ignoreChains, cfg, service, io.Discard)
+
+			if noStubs {
+				assert.NoFileExists(t, filepath.Join(assets, filesystem.GlanceFilename))
+				assert.False(t, needsRegen[web], "no bubble-up when the empty directory is skipped")
+			} else {
+				assert.FileExists(t, filepath.Join(assets, filesystem.GlanceFilename))
+				assert.True(t, needsRegen[web], "a written stub bubbles up to the parent")
+			}
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"glance/config"
+)
+
+// errEmptySummary marks a summary too short to be worth writing under
+// --fail-on-empty-summary.
+var errEmptySummary = errors.New("generated summary is empty or nearly empty")
+
+// checkSummaryLength enforces --fail-on-empty-summary: a summary shorter than
+// cfg.MinSummaryLength characters, ignoring surrounding whitespace, fails the
+// directory instead of producing a near-blank glance.md. serviceOptions runs
+// it as the Service's summary check, so it measures the model's text alone,
+// before post-processors, source links, stats, hashes, or footers. With
+// --stream that text has already been printed; only the write is prevented.
+//
+// Returns:
+//   - An error wrapping errEmptySummary if the summary is too short, or nil
+func checkSummaryLength(cfg *config.Config, summary string) error {
+	if !cfg.FailOnEmptySummary {
+		return nil
+	}
+	if n := utf8.RuneCountInString(strings.TrimSpace(summary)); n < cfg.MinSummaryLength {
+		return fmt.Errorf("%w: %d characters, below --min-summary-length %d", errEmptySummary, n, cfg.MinSummaryLength)
+	}
+	return nil
+}
+
+// emptySummaryExitCode returns 1 if --fail-on-empty-summary failed any
+// directory in results, so CI can treat near-blank summaries as a failed run,
+// and 0 otherwise.
+func emptySummaryExitCode(results []result) int {
+	for _, r := range results {
+		if errors.Is(r.err, errEmptySummary) {
+			return 1
+		}
+	}
+	return 0
+}
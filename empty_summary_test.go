@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+func TestFailOnEmptySummary(t *testing.T) {
+	run := func(t *testing.T, summary string, cfg *config.Config) (result, string) {
+		t.Helper()
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0600))
+
+		mockLLMClient := new(mocks.LLMClient)
+		mockLLMClient.On("Generate", mock.Anything, mock.Anything).Return(summary, nil).Maybe()
+		mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+		cfg = cfg.WithTargetDir(dir).WithNoCache(true)
+		service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient}, serviceOptions(cfg, "model")...)
+		require.NoError(t, err)
+
+		r := processDirectory(context.Background(), dir, true, nil, cfg, service)
+		return r, filepath.Join(dir, filesystem.GlanceFilename)
+	}
+
+	t.Run("Below the threshold fails without writing", func(t *testing.T) {
+		cfg := config.NewDefaultConfig().WithFailOnEmptySummary(true, 20)
+
+		r, glancePath := run(t, "#\n\n  ok  \n", cfg)
+
+		assert.False(t, r.success)
+		assert.True(t, errors.Is(r.err, errEmptySummary), "unexpected error: %v", r.err)
+		_, statErr := os.Stat(glancePath)
+		assert.True(t, os.IsNotExist(statErr), "a near-empty summary must not be written")
+	})
+
+	t.Run("Above the threshold writes the summary", func(t *testing.T) {
+		cfg := config.NewDefaultConfig().WithFailOnEmptySummary(true, 20)
+		summary := "# main\n\n" + strings.Repeat("Describes the package. ", 2)
+
+		r, glancePath := run(t, summary, cfg)
+
+		require.True(t, r.success, "processDirectory should succeed: %v", r.err)
+		content, err := os.ReadFile(glancePath)
+		require.NoError(t, err)
+		assert.Equal(t, summary, string(content))
+	})
+
+	t.Run("Post-processing does not count toward the length", func(t *testing.T) {
+		cfg := config.NewDefaultConfig().WithFailOnEmptySummary(true, 20).WithLinkSources(true)
+
+		r, glancePath := run(t, "#\n\n  ok  \n", cfg)
+
+		assert.False(t, r.success, "the appended source links must not lift a near-empty summary over the threshold")
+		assert.True(t, errors.Is(r.err, errEmptySummary), "unexpected error: %v", r.err)
+		assert.NoFileExists(t, glancePath)
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		r, glancePath := run(t, "ok", config.NewDefaultConfig())
+
+		require.True(t, r.success, "processDirectory should succeed: %v", r.err)
+		_, err := os.Stat(glancePath)
+		assert.NoError(t, err)
+	})
+}
+
+func TestEmptySummaryExitCode(t *testing.T) {
+	assert.Equal(t, 0, emptySummaryExitCode([]result{{success: true}, {err: errors.New("other failure")}}))
+	assert.Equal(t, 1, emptySummaryExitCode([]result{{success: true}, {err: checkSummaryLength(
+		config.NewDefaultConfig().WithFailOnEmptySummary(true, 10), "ok")}}))
+}
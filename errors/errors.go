@@ -84,6 +84,11 @@ type baseError struct {
 	severity   ErrorSeverity
 	suggestion string
 	cause      error
+
+	// self is the specific error type embedding this baseError, so the With*
+	// builders return it rather than the bare baseError and errors.As can still
+	// match the specific type after a code or suggestion has been attached.
+	self GlanceError
 }
 
 // Error returns the error message.
@@ -141,22 +146,31 @@ func (e *baseError) Unwrap() error {
 	return e.cause
 }
 
+// outer returns the specific error type embedding e, or e itself for a
+// general error.
+func (e *baseError) outer() GlanceError {
+	if e.self != nil {
+		return e.self
+	}
+	return e
+}
+
 // WithCode sets the error code.
 func (e *baseError) WithCode(code string) GlanceError {
 	e.code = code
-	return e
+	return e.outer()
 }
 
 // WithSeverity sets the error severity level.
 func (e *baseError) WithSeverity(severity ErrorSeverity) GlanceError {
 	e.severity = severity
-	return e
+	return e.outer()
 }
 
 // WithSuggestion sets a suggestion for resolving the error.
 func (e *baseError) WithSuggestion(suggestion string) GlanceError {
 	e.suggestion = suggestion
-	return e
+	return e.outer()
 }
 
 // -----------------------------------------------------------------------------
@@ -208,7 +222,7 @@ func Wrap(err error, message string) GlanceError {
 
 // NewFileSystemError creates a new file system error.
 func NewFileSystemError(message string, cause error) GlanceError {
-	return &FileSystemError{
+	e := &FileSystemError{
 		baseError: baseError{
 			errorType: "FileSystem",
 			message:   message,
@@ -216,11 +230,13 @@ func NewFileSystemError(message string, cause error) GlanceError {
 			cause:     cause,
 		},
 	}
+	e.self = e
+	return e
 }
 
 // NewAPIError creates a new API error.
 func NewAPIError(message string, cause error) GlanceError {
-	return &APIError{
+	e := &APIError{
 		baseError: baseError{
 			errorType: "API",
 			message:   message,
@@ -228,11 +244,13 @@ func NewAPIError(message string, cause error) GlanceError {
 			cause:     cause,
 		},
 	}
+	e.self = e
+	return e
 }
 
 // NewConfigError creates a new configuration error.
 func NewConfigError(message string, cause error) GlanceError {
-	return &ConfigError{
+	e := &ConfigError{
 		baseError: baseError{
 			errorType: "Config",
 			message:   message,
@@ -240,11 +258,13 @@ func NewConfigError(message string, cause error) GlanceError {
 			cause:     cause,
 		},
 	}
+	e.self = e
+	return e
 }
 
 // NewValidationError creates a new validation error.
 func NewValidationError(message string, cause error) GlanceError {
-	return &ValidationError{
+	e := &ValidationError{
 		baseError: baseError{
 			errorType: "Validation",
 			message:   message,
@@ -252,6 +272,8 @@ func NewValidationError(message string, cause error) GlanceError {
 			cause:     cause,
 		},
 	}
+	e.self = e
+	return e
 }
 
 // -----------------------------------------------------------------------------
@@ -355,7 +377,7 @@ func WrapValidationError(err error, message string) GlanceError {
 // This is useful for maintaining error chains while using sentinel errors.
 func (e *baseError) WithCause(cause error) GlanceError {
 	e.cause = cause
-	return e
+	return e.outer()
 }
 
 // -----------------------------------------------------------------------------
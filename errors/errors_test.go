@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBaseError(t *testing.T) {
@@ -221,3 +222,16 @@ func TestErrorFormat(t *testing.T) {
 		assert.Contains(t, err.Error(), part)
 	}
 }
+
+func TestBuildersPreserveErrorType(t *testing.T) {
+	apiErr := NewAPIError("rate limited", nil).WithCode("API-001").WithSuggestion("retry later")
+	assert.True(t, IsAPIError(apiErr), "attaching a code or suggestion keeps the specific type")
+	assert.False(t, IsValidationError(apiErr))
+
+	valErr := NewValidationError("bad input", nil).WithSeverity(ErrorSeverityWarning).WithCause(errors.New("cause"))
+	assert.True(t, IsValidationError(valErr))
+
+	var target *APIError
+	require.ErrorAs(t, fmt.Errorf("context: %w", apiErr), &target)
+	assert.Equal(t, "API-001", target.Code())
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"glance/config"
+	"glance/events"
+	"glance/filesystem"
+)
+
+// runEvents receives progress events for --events-file. It stays nil, which
+// discards every event, unless main opens a stream.
+var runEvents *events.Emitter
+
+// openEventStream creates cfg.EventsFile, which must lie within the current
+// working directory, and starts an emitter writing to it. The returned function
+// flushes the stream and closes the file.
+func openEventStream(cfg *config.Config) (*events.Emitter, func(), error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get current working directory: %w", err)
+	}
+
+	validPath, err := filesystem.ValidateFilePath(cfg.EventsFile, cwd, false, false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid events file path: %w", err)
+	}
+
+	// #nosec G304 -- Path has been validated using filesystem.ValidateFilePath
+	f, err := os.OpenFile(validPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, filesystem.DefaultFileMode)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open events file: %w", err)
+	}
+
+	emitter := events.NewEmitter(f, events.DefaultBufferSize)
+	closeStream := func() {
+		if err := emitter.Close(); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"path":  validPath,
+				"error": err,
+			}).Error("Failed to write events file")
+		}
+		if dropped := emitter.Dropped(); dropped > 0 {
+			logrus.WithField("dropped", dropped).Warn("Some progress events were dropped because the events file could not keep up")
+		}
+		if err := f.Close(); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"path":  validPath,
+				"error": err,
+			}).Error("Failed to close events file")
+		}
+	}
+	return emitter, closeStream, nil
+}
+
+// emitDirCompleted reports a finished directory, including the prompt tokens
+// counted while processing it.
+func emitDirCompleted(cfg *config.Config, r result, tokens int64) {
+	outcome := &events.Outcome{
+		Success:  r.success,
+		Status:   r.outcome.String(),
+		Attempts: r.attempts,
+		Tokens:   tokens,
+	}
+	if r.err != nil {
+		outcome.Error = r.err.Error()
+	}
+	runEvents.Emit(events.Event{Type: events.DirCompleted, Dir: displayDir(cfg.TargetDir, r.dir), Outcome: outcome})
+}
+
+// emitRunCompleted reports the outcome of the whole run, classified by outcome
+// as printDebrief does. Generated and stubbed directories both count as
+// succeeded; up-to-date ones count as skipped even though they did not fail.
+func emitRunCompleted(results []result, tokens int64, duration time.Duration) {
+	summary := &events.Summary{
+		Directories: len(results),
+		TotalTokens: tokens,
+		DurationMS:  duration.Milliseconds(),
+	}
+	for _, r := range results {
+		switch r.outcome {
+		case outcomeGenerated, outcomeStub:
+			summary.Succeeded++
+		case outcomeSkipped:
+			summary.Skipped++
+		default:
+			summary.Failed++
+		}
+	}
+	runEvents.Emit(events.Event{Type: events.RunCompleted, Summary: summary})
+}
@@ -0,0 +1,148 @@
+// Package events emits a newline-delimited JSON stream of run progress events
+// for dashboards and other programs that monitor glance while it runs.
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Type identifies the kind of event.
+type Type string
+
+// Event types, in the order a run emits them.
+const (
+	// ScanStarted is emitted before the directory scan begins
+	ScanStarted Type = "scan_started"
+
+	// DirStarted is emitted when a directory is picked up for processing
+	DirStarted Type = "dir_started"
+
+	// DirCompleted is emitted when a directory is finished, whatever the outcome
+	DirCompleted Type = "dir_completed"
+
+	// RunCompleted is emitted once after every directory is finished
+	RunCompleted Type = "run_completed"
+)
+
+// DefaultBufferSize is the number of events an Emitter queues before dropping.
+const DefaultBufferSize = 1024
+
+// Event is a single line of the event stream. Outcome is set only on
+// dir_completed events and Summary only on run_completed events; their fields
+// are flattened into the event's JSON object, so their JSON names must not collide.
+type Event struct {
+	Type Type      `json:"type"`
+	Time time.Time `json:"time"`
+
+	// Dir is the directory relative to the target directory for dir_* events,
+	// and the absolute target directory for scan_started
+	Dir string `json:"dir,omitempty"`
+
+	*Outcome
+	*Summary
+}
+
+// Outcome describes how a directory finished.
+type Outcome struct {
+	Success bool `json:"success"`
+
+	// Status is one of "generated", "stub", "skipped", or "failed"
+	Status   string `json:"status"`
+	Attempts int    `json:"attempts"`
+	Tokens   int64  `json:"tokens"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Summary describes a finished run.
+type Summary struct {
+	Directories int   `json:"directories"`
+	Succeeded   int   `json:"succeeded"`
+	Failed      int   `json:"failed"`
+	Skipped     int   `json:"skipped"`
+	TotalTokens int64 `json:"total_tokens"`
+	DurationMS  int64 `json:"duration_ms"`
+}
+
+// Emitter writes events to an io.Writer from a background goroutine, so a slow
+// reader never stalls processing. When the queue is full, new events are dropped
+// and counted rather than blocking. A nil *Emitter discards every event.
+type Emitter struct {
+	queue   chan Event
+	done    chan struct{}
+	dropped atomic.Int64
+	now     func() time.Time
+
+	closeOnce sync.Once
+	err       error // first write error; read only after done is closed
+}
+
+// NewEmitter starts an emitter that writes one JSON object per line to w.
+//
+// Parameters:
+//   - w: The destination of the stream
+//   - bufferSize: How many events may be queued before new ones are dropped
+//     (values below 1 use DefaultBufferSize)
+func NewEmitter(w io.Writer, bufferSize int) *Emitter {
+	if bufferSize < 1 {
+		bufferSize = DefaultBufferSize
+	}
+	e := &Emitter{
+		queue: make(chan Event, bufferSize),
+		done:  make(chan struct{}),
+		now:   time.Now,
+	}
+	go e.run(w)
+	return e
+}
+
+// run encodes queued events until the queue is closed. After the first write
+// error the remaining events are drained and discarded.
+func (e *Emitter) run(w io.Writer) {
+	defer close(e.done)
+	enc := json.NewEncoder(w)
+	for ev := range e.queue {
+		if e.err != nil {
+			continue
+		}
+		e.err = enc.Encode(ev)
+	}
+}
+
+// Emit queues ev without blocking, stamping its time if unset. Emit must not be
+// called after Close.
+func (e *Emitter) Emit(ev Event) {
+	if e == nil {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = e.now().UTC()
+	}
+	select {
+	case e.queue <- ev:
+	default:
+		e.dropped.Add(1)
+	}
+}
+
+// Dropped returns how many events were discarded because the queue was full.
+func (e *Emitter) Dropped() int64 {
+	if e == nil {
+		return 0
+	}
+	return e.dropped.Load()
+}
+
+// Close flushes queued events and stops the emitter. It returns the first write
+// error, if any. Closing more than once is safe.
+func (e *Emitter) Close() error {
+	if e == nil {
+		return nil
+	}
+	e.closeOnce.Do(func() { close(e.queue) })
+	<-e.done
+	return e.err
+}
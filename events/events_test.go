@@ -0,0 +1,125 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeLines parses a newline-delimited JSON stream into generic objects.
+func decodeLines(t *testing.T, data []byte) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var obj map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &obj), "line %q", scanner.Text())
+		lines = append(lines, obj)
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+func TestEmitterWritesJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewEmitter(&buf, 0)
+	fixed := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	e.now = func() time.Time { return fixed }
+
+	e.Emit(Event{Type: ScanStarted, Dir: "/repo"})
+	e.Emit(Event{Type: DirCompleted, Dir: "pkg", Outcome: &Outcome{Success: false, Status: "failed", Attempts: 1, Tokens: 42, Error: "boom"}})
+	e.Emit(Event{Type: RunCompleted, Summary: &Summary{Directories: 1, Failed: 1, TotalTokens: 42, DurationMS: 1500}})
+	require.NoError(t, e.Close())
+
+	lines := decodeLines(t, buf.Bytes())
+	require.Len(t, lines, 3)
+
+	assert.Equal(t, map[string]any{"type": "scan_started", "time": "2026-01-02T03:04:05Z", "dir": "/repo"}, lines[0])
+	assert.Equal(t, map[string]any{
+		"type": "dir_completed", "time": "2026-01-02T03:04:05Z", "dir": "pkg",
+		"success": false, "status": "failed", "attempts": float64(1), "tokens": float64(42), "error": "boom",
+	}, lines[1], "outcome fields are flattened, and false success is kept")
+	assert.Equal(t, map[string]any{
+		"type": "run_completed", "time": "2026-01-02T03:04:05Z",
+		"directories": float64(1), "succeeded": float64(0), "failed": float64(1), "skipped": float64(0), "total_tokens": float64(42), "duration_ms": float64(1500),
+	}, lines[2])
+}
+
+// blockingWriter blocks every write until released.
+type blockingWriter struct {
+	release chan struct{}
+	buf     bytes.Buffer
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	return w.buf.Write(p)
+}
+
+func TestEmitterDoesNotBlockOnSlowWriter(t *testing.T) {
+	w := &blockingWriter{release: make(chan struct{})}
+	e := NewEmitter(w, 2)
+
+	finished := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			e.Emit(Event{Type: DirStarted, Dir: "pkg"})
+		}
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Emit blocked on a stalled writer")
+	}
+
+	close(w.release)
+	require.NoError(t, e.Close())
+	written := len(decodeLines(t, w.buf.Bytes()))
+	assert.Equal(t, int64(10), int64(written)+e.Dropped(), "every event is either written or counted as dropped")
+	assert.Positive(t, e.Dropped())
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestEmitterReportsWriteError(t *testing.T) {
+	e := NewEmitter(failingWriter{}, 0)
+	e.Emit(Event{Type: ScanStarted})
+	e.Emit(Event{Type: RunCompleted, Summary: &Summary{}})
+	assert.EqualError(t, e.Close(), "disk full")
+	assert.EqualError(t, e.Close(), "disk full", "Close is idempotent")
+}
+
+func TestNilEmitter(t *testing.T) {
+	var e *Emitter
+	e.Emit(Event{Type: ScanStarted})
+	assert.Zero(t, e.Dropped())
+	assert.NoError(t, e.Close())
+}
+
+func TestEmitterConcurrentEmit(t *testing.T) {
+	e := NewEmitter(io.Discard, 0)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				e.Emit(Event{Type: DirStarted})
+			}
+		}()
+	}
+	wg.Wait()
+	assert.NoError(t, e.Close())
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/events"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestEventStreamForSmallRun verifies the event sequence emitted for a two-directory run.
+func TestEventStreamForSmallRun(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "pkg")
+	require.NoError(t, os.Mkdir(sub, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(sub, "pkg.go"), []byte("package pkg\n"), 0600))
+
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).Return("# summary\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil)
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient}, llm.WithPromptTemplate("{{.Directory}}"))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	runEvents = events.NewEmitter(&buf, events.DefaultBufferSize)
+	t.Cleanup(func() { runEvents = nil })
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root)
+	dirs, ignoreChains, err := scanDirectories(cfg)
+	require.NoError(t, err)
+	results, _ := processDirectories(context.Background(), dirs, ignoreChains, cfg, service, io.Discard)
+	emitRunCompleted(results, service.TokensCounted(), time.Second)
+	require.NoError(t, runEvents.Close())
+
+	var stream []events.Event
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var ev events.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &ev), "line %q", scanner.Text())
+		assert.False(t, ev.Time.IsZero(), "every event is timestamped")
+		stream = append(stream, ev)
+	}
+
+	var types []events.Type
+	var dirsSeen []string
+	for _, ev := range stream {
+		types = append(types, ev.Type)
+		dirsSeen = append(dirsSeen, ev.Dir)
+	}
+	assert.Equal(t, []events.Type{
+		events.ScanStarted,
+		events.DirStarted, events.DirCompleted,
+		events.DirStarted, events.DirCompleted,
+		events.RunCompleted,
+	}, types)
+	assert.Equal(t, []string{root, "pkg", "pkg", ".", ".", ""}, dirsSeen, "deepest directories first")
+
+	require.NotNil(t, stream[2].Outcome)
+	assert.Equal(t, events.Outcome{Success: true, Status: "generated", Attempts: 1, Tokens: 10}, *stream[2].Outcome)
+
+	require.NotNil(t, stream[5].Summary)
+	assert.Equal(t, events.Summary{Directories: 2, Succeeded: 2, TotalTokens: 20, DurationMS: 1000}, *stream[5].Summary)
+}
+
+// TestEmitRunCompletedCountsOutcomes verifies that the run summary classifies
+// directories by outcome, so up-to-date directories count as skipped as they
+// do in the debrief.
+func TestEmitRunCompletedCountsOutcomes(t *testing.T) {
+	var buf bytes.Buffer
+	runEvents = events.NewEmitter(&buf, events.DefaultBufferSize)
+	t.Cleanup(func() { runEvents = nil })
+
+	emitRunCompleted([]result{
+		{dir: "a", success: true, outcome: outcomeGenerated, attempts: 1},
+		{dir: "b", success: true, outcome: outcomeStub, attempts: 1},
+		{dir: "c", success: true, outcome: outcomeSkipped},
+		{dir: "d", success: true, outcome: outcomeSkipped},
+		{dir: "e", outcome: outcomeFailed, attempts: 1},
+	}, 0, time.Second)
+	require.NoError(t, runEvents.Close())
+
+	var ev events.Event
+	require.NoError(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &ev))
+	require.NotNil(t, ev.Summary)
+	assert.Equal(t, events.Summary{Directories: 5, Succeeded: 2, Skipped: 2, Failed: 1, DurationMS: 1000}, *ev.Summary)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestExplainOutput verifies that --explain prints one decision line per directory.
+func TestExplainOutput(t *testing.T) {
+	root := t.TempDir()
+	fresh := filepath.Join(root, "fresh")
+	stale := filepath.Join(root, "stale")
+	for _, d := range []string{fresh, stale} {
+		require.NoError(t, os.MkdirAll(d, 0755))
+	}
+
+	old := time.Now().Add(-time.Hour)
+	older := old.Add(-time.Minute)
+	writeAt := func(path string, content string, when time.Time) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		require.NoError(t, os.Chtimes(path, when, when))
+	}
+	writeAt(filepath.Join(fresh, "a.go"), "package fresh\n", older)
+	writeAt(filepath.Join(fresh, filesystem.GlanceFilename), "# fresh\n", old)
+	writeAt(filepath.Join(stale, filesystem.GlanceFilename), "# stale\n", old)
+	writeAt(filepath.Join(stale, "b.go"), "package stale\n", time.Now())
+	for _, d := range []string{fresh, stale} {
+		require.NoError(t, os.Chtimes(d, older, older))
+	}
+
+	mockLLMClient := new(mocks.LLMClient)
+	mockClient := &MockClient{LLMClient: mockLLMClient}
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).Return("# summary\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(mockClient)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	origOut := explainOut
+	explainOut = &out
+	defer func() { explainOut = origOut }()
+
+	dirsList := []string{fresh, stale, root}
+	ignoreChains := map[string]filesystem.IgnoreChain{}
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithExplain(true)
+
+	processDirectories(context.Background(), dirsList, ignoreChains, cfg, service, io.Discard)
+
+	assert.Equal(t,
+		"fresh: skipped (up-to-date)\n"+
+			"stale: newer file b.go found\n"+
+			".: "+filesystem.GlanceFilename+" missing\n",
+		out.String())
+}
+
+// TestExplainDisabled verifies that nothing is printed without --explain.
+func TestExplainDisabled(t *testing.T) {
+	var out bytes.Buffer
+	origOut := explainOut
+	explainOut = &out
+	defer func() { explainOut = origOut }()
+
+	explainDecision(config.NewDefaultConfig().WithTargetDir("/tmp"), "/tmp/x", "forced")
+	assert.Empty(t, out.String())
+}
@@ -0,0 +1,114 @@
+// Package filesystem provides functionality for scanning, reading, and managing
+// filesystem operations in the glance application.
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// renameFile moves a finished temp file into place. It is a variable so tests
+// can simulate a rename across filesystems.
+var renameFile = os.Rename
+
+// WriteFileAtomic writes data to path so readers see either the old file or
+// the complete new one, never a partial write. The data goes to a temp file
+// that is then renamed over path.
+//
+// By default the temp file is created next to path, so the rename never
+// crosses a filesystem boundary. A tempDir can be given instead for trees
+// where only the destination file itself is writable; if the rename from it
+// then fails because tempDir is on another filesystem, the temp file is copied
+// over path instead, which is not atomic but still never leaves path
+// truncated because of a failed write to the temp file.
+//
+// Parameters:
+//   - path: The destination file path, already validated by the caller
+//   - data: The content to write
+//   - tempDir: The directory for the temp file; empty means path's directory
+//
+// Returns:
+//   - An error if the temp file cannot be written or moved into place
+func WriteFileAtomic(path string, data []byte, tempDir string) error {
+	if tempDir == "" {
+		tempDir = filepath.Dir(path)
+	}
+
+	tmp, err := os.CreateTemp(tempDir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, DefaultFileMode); err != nil {
+		return fmt.Errorf("failed to set temp file permissions: %w", err)
+	}
+
+	err = renameFile(tmpPath, path)
+	if err == nil {
+		// The rename updates the directory's mod time after the file's own;
+		// touch the file so freshness checks do not see the directory as newer
+		now := time.Now()
+		if err := os.Chtimes(path, now, now); err != nil {
+			return fmt.Errorf("failed to update file times: %w", err)
+		}
+		return nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return fmt.Errorf("failed to move temp file into place: %w", err)
+	}
+
+	log.WithFields(logrus.Fields{
+		"path":     path,
+		"temp_dir": tempDir,
+	}).Debug("Temp file is on another filesystem, copying into place")
+	if err := copyFile(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to copy temp file into place: %w", err)
+	}
+	return nil
+}
+
+// copyFile copies the file at src over dst, creating dst with DefaultFileMode
+// if it does not exist.
+func copyFile(src, dst string) error {
+	// #nosec G304 -- src is a temp file this package just created
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	// #nosec G302 G304 -- dst is validated by the caller of WriteFileAtomic; using DefaultFileMode (0600)
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, DefaultFileMode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
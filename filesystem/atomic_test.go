@@ -0,0 +1,97 @@
+package filesystem
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tempFiles lists the leftover atomic-write temp files in dir.
+func tempFiles(t *testing.T, dir string) []string {
+	matches, err := filepath.Glob(filepath.Join(dir, ".*.tmp-*"))
+	require.NoError(t, err)
+	return matches
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	t.Run("replaces the file via a temp file in its directory", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, GlanceFilename)
+		require.NoError(t, os.WriteFile(path, []byte("old"), DefaultFileMode))
+
+		var renamedFrom string
+		original := renameFile
+		renameFile = func(from, to string) error {
+			renamedFrom = from
+			return original(from, to)
+		}
+		defer func() { renameFile = original }()
+
+		require.NoError(t, WriteFileAtomic(path, []byte("new"), ""))
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "new", string(content))
+		assert.Equal(t, dir, filepath.Dir(renamedFrom), "temp file is created next to the destination")
+		assert.Empty(t, tempFiles(t, dir))
+		if runtime.GOOS != "windows" {
+			info, err := os.Stat(path)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(DefaultFileMode), info.Mode().Perm())
+		}
+	})
+
+	t.Run("copies into place when the temp dir is on another filesystem", func(t *testing.T) {
+		dir, tempDir := t.TempDir(), t.TempDir()
+		path := filepath.Join(dir, GlanceFilename)
+		require.NoError(t, os.WriteFile(path, []byte("a much longer old summary"), DefaultFileMode))
+
+		original := renameFile
+		renameFile = func(from, to string) error {
+			assert.Equal(t, tempDir, filepath.Dir(from))
+			return &os.LinkError{Op: "rename", Old: from, New: to, Err: syscall.EXDEV}
+		}
+		defer func() { renameFile = original }()
+
+		require.NoError(t, WriteFileAtomic(path, []byte("new"), tempDir))
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "new", string(content), "the copy truncates the old content")
+		assert.Empty(t, tempFiles(t, tempDir), "the temp file is removed after copying")
+		assert.Empty(t, tempFiles(t, dir))
+	})
+
+	t.Run("other rename failures leave the destination untouched", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, GlanceFilename)
+		require.NoError(t, os.WriteFile(path, []byte("old"), DefaultFileMode))
+
+		original := renameFile
+		renameFile = func(string, string) error { return errors.New("permission denied") }
+		defer func() { renameFile = original }()
+
+		err := WriteFileAtomic(path, []byte("new"), "")
+		assert.ErrorContains(t, err, "permission denied")
+
+		content, readErr := os.ReadFile(path)
+		require.NoError(t, readErr)
+		assert.Equal(t, "old", string(content))
+		assert.Empty(t, tempFiles(t, dir))
+	})
+
+	t.Run("a missing temp dir fails before touching the destination", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, GlanceFilename)
+
+		err := WriteFileAtomic(path, []byte("new"), filepath.Join(dir, "missing"))
+		assert.Error(t, err)
+		assert.NoFileExists(t, path)
+	})
+}
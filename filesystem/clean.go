@@ -0,0 +1,84 @@
+// Package filesystem provides functionality for scanning, reading, and managing
+// filesystem operations in the glance application.
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CleanGlanceFiles removes the glance output file from every directory that a
+// normal run would process under root. Hidden and ignored directories are left
+// alone, as are files with any other name (including LegacyGlanceFilename, which
+// may be user-authored). Every path is validated against root before removal.
+//
+// Parameters:
+//   - root: The target directory to clean
+//   - dryRun: When true, report what would be removed without deleting anything
+//   - options: Scan options matching the normal run, such as WithRootIgnore
+//
+// Returns:
+//   - The paths removed (or that would be removed in a dry run), in scan order
+//   - An error if scanning fails or a file cannot be removed; paths removed
+//     before the failure are still returned
+func CleanGlanceFiles(root string, dryRun bool, options ...ScanOption) ([]string, error) {
+	paths, err := ListGlanceFiles(root, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, validPath := range paths {
+		if !dryRun {
+			if err := os.Remove(validPath); err != nil {
+				return removed, fmt.Errorf("failed to remove %s: %w", validPath, err)
+			}
+		}
+		log.WithFields(logrus.Fields{
+			"path":    validPath,
+			"dry_run": dryRun,
+		}).Debug("Removed glance output")
+		removed = append(removed, validPath)
+	}
+
+	return removed, nil
+}
+
+// ListGlanceFiles returns the existing glance output files in every directory
+// that a normal run would process under root, as CleanGlanceFiles sees them.
+// Every path is validated against root.
+//
+// Parameters:
+//   - root: The directory tree to search
+//   - options: Scan options matching the normal run, such as WithRootIgnore
+//
+// Returns:
+//   - The validated paths of existing glance files, in scan order
+//   - An error if scanning fails
+func ListGlanceFiles(root string, options ...ScanOption) ([]string, error) {
+	dirs, _, err := ListDirsWithIgnores(root, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+
+	var paths []string
+	for _, dir := range dirs {
+		validPath, err := ValidateFilePath(filepath.Join(dir, GlanceFilename), root, false, true)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				log.WithFields(logrus.Fields{
+					"directory": dir,
+					"error":     err,
+				}).Debug("Skipping glance output that failed validation")
+			}
+			continue
+		}
+		paths = append(paths, validPath)
+	}
+	return paths, nil
+}
@@ -0,0 +1,83 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cleanFixture builds a tree with glance output in several directories, plus files
+// that must survive a clean.
+func cleanFixture(t *testing.T) (root string, outputs []string, keep []string) {
+	t.Helper()
+	root = t.TempDir()
+	for _, d := range []string{"a/b", "c", ".hidden", "ignored"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, d), 0755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("ignored/\n"), 0644))
+
+	outputs = []string{
+		filepath.Join(root, GlanceFilename),
+		filepath.Join(root, "a", GlanceFilename),
+		filepath.Join(root, "a", "b", GlanceFilename),
+		filepath.Join(root, "c", GlanceFilename),
+	}
+	keep = []string{
+		filepath.Join(root, "a", "main.go"),
+		filepath.Join(root, "c", LegacyGlanceFilename),
+		filepath.Join(root, ".hidden", GlanceFilename),
+		filepath.Join(root, "ignored", GlanceFilename),
+	}
+	for _, p := range append(append([]string{}, outputs...), keep...) {
+		require.NoError(t, os.WriteFile(p, []byte("content"), 0644))
+	}
+	return root, outputs, keep
+}
+
+func TestCleanGlanceFiles(t *testing.T) {
+	t.Run("Removes only glance output in scanned directories", func(t *testing.T) {
+		root, outputs, keep := cleanFixture(t)
+
+		removed, err := CleanGlanceFiles(root, false)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, outputs, removed)
+
+		for _, p := range outputs {
+			assert.NoFileExists(t, p)
+		}
+		for _, p := range keep {
+			assert.FileExists(t, p)
+		}
+	})
+
+	t.Run("Dry run deletes nothing", func(t *testing.T) {
+		root, outputs, keep := cleanFixture(t)
+
+		removed, err := CleanGlanceFiles(root, true)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, outputs, removed)
+
+		for _, p := range append(outputs, keep...) {
+			assert.FileExists(t, p)
+		}
+	})
+
+	t.Run("Tree without output", func(t *testing.T) {
+		removed, err := CleanGlanceFiles(t.TempDir(), false)
+		require.NoError(t, err)
+		assert.Empty(t, removed)
+	})
+
+	t.Run("Directory named like the output is kept", func(t *testing.T) {
+		root := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(root, GlanceFilename), 0755))
+
+		removed, err := CleanGlanceFiles(root, false)
+		require.NoError(t, err)
+		assert.Empty(t, removed)
+		assert.DirExists(t, filepath.Join(root, GlanceFilename))
+	})
+}
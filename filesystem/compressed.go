@@ -0,0 +1,88 @@
+// Package filesystem provides functionality for scanning, reading, and managing
+// filesystem operations in the glance application.
+package filesystem
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// GzipExtension is the file extension recognized as gzip-compressed content.
+const GzipExtension = ".gz"
+
+// IsGzipFile reports whether a file name carries the gzip extension.
+func IsGzipFile(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), GzipExtension)
+}
+
+// ReadGzipTextFile decompresses a gzip file and returns its contents if the
+// decompressed data is text. Text detection uses the same content sniffing as
+// IsTextFile, applied to the decompressed stream.
+//
+// The decompressed stream is bounded by maxBytes (or MaxDefaultFileSize when maxBytes
+// is 0) so that a small archive cannot expand into unbounded memory.
+//
+// Parameters:
+//   - path: The absolute path to the gzip file
+//   - maxBytes: The maximum number of decompressed bytes to keep (0 for the default cap)
+//   - baseDir: Base directory for path validation. Must be non-empty for proper security validation.
+//
+// Returns:
+//   - The decompressed contents as a string (empty when not text)
+//   - true if the decompressed content is text, false otherwise
+//   - An error, if any occurred during validation, reading, or decompression
+func ReadGzipTextFile(path string, maxBytes int64, baseDir string) (string, bool, error) {
+	if baseDir == "" {
+		return "", false, errors.New("baseDir cannot be empty for validation")
+	}
+
+	validatedPath, err := ValidateFilePath(path, baseDir, true, true)
+	if err != nil {
+		return "", false, fmt.Errorf("path validation failed: %w", err)
+	}
+
+	// Open the validated path, holding a global open-file slot
+	f, err := openLimited(validatedPath)
+	if err != nil {
+		return "", false, err
+	}
+	defer func() {
+		_ = f.Close() // read-only context
+	}()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid gzip data in %q: %w", path, err)
+	}
+	defer func() {
+		_ = gz.Close()
+	}()
+
+	limit := maxBytes
+	if limit <= 0 {
+		limit = MaxDefaultFileSize
+	}
+
+	// Read one byte past the limit so truncation can be detected
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, io.LimitReader(gz, limit+1)); err != nil {
+		return "", false, fmt.Errorf("failed to decompress %q: %w", path, err)
+	}
+
+	data := buf.Bytes()
+	sniff := data
+	if len(sniff) > 512 {
+		sniff = sniff[:512]
+	}
+	if !isTextContentType(http.DetectContentType(sniff)) {
+		return "", false, nil
+	}
+
+	contentStr := strings.ToValidUTF8(string(data), "�")
+	return TruncateContent(contentStr, limit), true, nil
+}
@@ -0,0 +1,101 @@
+// Package filesystem provides functionality for scanning, reading, and managing
+// filesystem operations in the glance application.
+package filesystem
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LoadContextFiles reads the text files under root matching any of patterns, so
+// they can be shared as context by every directory's prompt. Patterns use
+// filepath.Match syntax relative to root, e.g. "README.md" or "docs/*.md".
+// Matches outside root, directories, and binary files are skipped. Files are
+// read in path order until maxBytes of content has been collected; the file
+// that crosses the limit is truncated and the rest are left out.
+//
+// Parameters:
+//   - root: The directory patterns are resolved against
+//   - patterns: Glob patterns naming the context files
+//   - maxBytes: The total size limit for all context files (0 for no limit)
+//
+// Returns:
+//   - A map of paths relative to root to file contents, or nil if nothing matched
+//   - An error if root is invalid or a pattern is malformed
+func LoadContextFiles(root string, patterns []string, maxBytes int64) (map[string]string, error) {
+	validRoot, err := ValidateDirPath(root, root, true, true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid context file root: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var paths []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(validRoot, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid context file pattern %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			log.WithField("pattern", pattern).Warn("Context file pattern matched no files")
+		}
+		for _, match := range matches {
+			if !seen[match] {
+				seen[match] = true
+				paths = append(paths, match)
+			}
+		}
+	}
+	sort.Strings(paths)
+
+	var files map[string]string
+	var total int64
+	for _, path := range paths {
+		validPath, err := ValidateFilePath(path, validRoot, false, true)
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"path":  path,
+				"error": err,
+			}).Debug("Skipping context file that failed validation")
+			continue
+		}
+		isText, err := IsTextFile(validPath, validRoot)
+		if err != nil || !isText {
+			log.WithField("path", validPath).Debug("Skipping unreadable or binary context file")
+			continue
+		}
+
+		if maxBytes > 0 && total >= maxBytes {
+			log.WithFields(logrus.Fields{
+				"path":      validPath,
+				"max_bytes": maxBytes,
+			}).Warn("Context file size limit reached; leaving out remaining context files")
+			break
+		}
+		limit := int64(0)
+		if maxBytes > 0 {
+			limit = maxBytes - total
+		}
+		content, err := ReadTextFile(validPath, limit, validRoot)
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"path":  validPath,
+				"error": err,
+			}).Debug("Skipping unreadable context file")
+			continue
+		}
+
+		relPath, err := filepath.Rel(validRoot, validPath)
+		if err != nil {
+			relPath = validPath
+		}
+		if files == nil {
+			files = make(map[string]string)
+		}
+		files[relPath] = content
+		total += int64(len(content))
+	}
+	return files, nil
+}
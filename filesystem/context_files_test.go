@@ -0,0 +1,71 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadContextFiles(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("# Project\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "CONTRIBUTING.md"), []byte("Be kind.\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "logo.png"), []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "docs", "adr.md"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "docs", "ARCHITECTURE.md"), []byte("Layers.\n"), 0600))
+
+	t.Run("Matches are read once, relative to root", func(t *testing.T) {
+		files, err := LoadContextFiles(root, []string{"*.md", "README.md", "docs/*"}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"CONTRIBUTING.md":                        "Be kind.\n",
+			"README.md":                              "# Project\n",
+			filepath.Join("docs", "ARCHITECTURE.md"): "Layers.\n",
+		}, files, "duplicates, directories, and binary files are left out")
+	})
+
+	t.Run("Binary matches are skipped", func(t *testing.T) {
+		files, err := LoadContextFiles(root, []string{"*.png"}, 0)
+		require.NoError(t, err)
+		assert.Nil(t, files)
+	})
+
+	t.Run("Size limit truncates and then stops", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.md"), []byte(strings.Repeat("a", 60)), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "b.md"), []byte(strings.Repeat("b", 60)), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "c.md"), []byte("c"), 0600))
+
+		files, err := LoadContextFiles(dir, []string{"*.md"}, 100)
+		require.NoError(t, err)
+		assert.Equal(t, strings.Repeat("a", 60), files["a.md"])
+		assert.True(t, strings.HasPrefix(files["b.md"], strings.Repeat("b", 40)), "the file crossing the limit is truncated")
+		assert.NotContains(t, files, "c.md", "files past the limit are left out")
+	})
+
+	t.Run("Unmatched pattern is not an error", func(t *testing.T) {
+		files, err := LoadContextFiles(root, []string{"MISSING.md"}, 0)
+		require.NoError(t, err)
+		assert.Nil(t, files)
+	})
+
+	t.Run("Malformed pattern is an error", func(t *testing.T) {
+		_, err := LoadContextFiles(root, []string{"[README"}, 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("Matches outside root are skipped", func(t *testing.T) {
+		outside := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(outside, "secret.md"), []byte("secret"), 0600))
+		rel, err := filepath.Rel(root, filepath.Join(outside, "secret.md"))
+		require.NoError(t, err)
+
+		files, err := LoadContextFiles(root, []string{rel}, 0)
+		require.NoError(t, err)
+		assert.Nil(t, files)
+	})
+}
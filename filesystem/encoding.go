@@ -0,0 +1,62 @@
+// Package filesystem provides functionality for scanning, reading, and managing
+// filesystem operations in the glance application.
+package filesystem
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Names reported by DecodeText for the encodings it detects.
+const (
+	EncodingUTF8    = "utf-8"
+	EncodingUTF16LE = "utf-16le"
+	EncodingUTF16BE = "utf-16be"
+	EncodingLatin1  = "windows-1252"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// DecodeText transcodes content to UTF-8, detecting its encoding. UTF-16 is
+// recognized by its byte order mark; content without one that is not valid
+// UTF-8 is taken to be Latin-1, read as its Windows-1252 superset, since every
+// byte sequence is valid there. A UTF-8 byte order mark is dropped.
+//
+// Parameters:
+//   - content: The raw file content
+//
+// Returns:
+//   - The content as UTF-8
+//   - The name of the detected encoding, one of the Encoding* constants
+func DecodeText(content []byte) (string, string) {
+	switch {
+	case bytes.HasPrefix(content, bomUTF8):
+		return string(content[len(bomUTF8):]), EncodingUTF8
+	case bytes.HasPrefix(content, bomUTF16LE):
+		return decodeWith(unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM), content), EncodingUTF16LE
+	case bytes.HasPrefix(content, bomUTF16BE):
+		return decodeWith(unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM), content), EncodingUTF16BE
+	case utf8.Valid(content):
+		return string(content), EncodingUTF8
+	default:
+		return decodeWith(charmap.Windows1252, content), EncodingLatin1
+	}
+}
+
+// decodeWith transcodes content from enc to UTF-8, replacing anything that
+// cannot be decoded (such as an odd trailing byte in UTF-16) with U+FFFD.
+func decodeWith(enc encoding.Encoding, content []byte) string {
+	decoded, err := enc.NewDecoder().Bytes(content)
+	if err != nil {
+		return string(bytes.ToValidUTF8(content, []byte("�")))
+	}
+	return string(bytes.ToValidUTF8(decoded, []byte("�")))
+}
@@ -0,0 +1,74 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// utf16LE encodes ASCII text as UTF-16LE with a byte order mark.
+func utf16LE(s string) []byte {
+	out := []byte{0xFF, 0xFE}
+	for _, b := range []byte(s) {
+		out = append(out, b, 0)
+	}
+	return out
+}
+
+func TestDecodeText(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		want     string
+		encoding string
+	}{
+		{"UTF-8", []byte("héllo"), "héllo", EncodingUTF8},
+		{"UTF-8 with BOM", append([]byte{0xEF, 0xBB, 0xBF}, "hi"...), "hi", EncodingUTF8},
+		{"UTF-16LE", utf16LE("package main\n"), "package main\n", EncodingUTF16LE},
+		{"UTF-16BE", []byte{0xFE, 0xFF, 0, 'o', 0, 'k'}, "ok", EncodingUTF16BE},
+		{"Latin-1", []byte("caf\xe9 na\xefve"), "café naïve", EncodingLatin1},
+		{"Empty", nil, "", EncodingUTF8},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, encoding := DecodeText(tc.content)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.encoding, encoding)
+		})
+	}
+}
+
+func TestGatherLocalFilesDetectEncoding(t *testing.T) {
+	testDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(testDir, "utf16.txt"), utf16LE("hello from windows\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(testDir, "latin1.txt"), []byte("caf\xe9 cr\xe8me\n"), 0644))
+
+	t.Run("Disabled keeps replacement characters", func(t *testing.T) {
+		results, err := GatherLocalFiles(testDir, nil, 0)
+		require.NoError(t, err)
+		assert.Equal(t, "caf� cr�me\n", results["latin1.txt"])
+		assert.NotEqual(t, "hello from windows\n", results["utf16.txt"])
+		assert.Contains(t, results["utf16.txt"], "\x00", "UTF-16 is passed through undecoded")
+	})
+
+	t.Run("Enabled transcodes to UTF-8", func(t *testing.T) {
+		results, err := GatherLocalFiles(testDir, nil, 0, WithDetectEncoding(true))
+		require.NoError(t, err)
+		assert.Equal(t, "café crème\n", results["latin1.txt"])
+		assert.Equal(t, "hello from windows\n", results["utf16.txt"])
+	})
+
+	t.Run("Transcoding happens before truncation", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "big.txt"), utf16LE(strings.Repeat("a", 100)), 0644))
+
+		results, err := GatherLocalFiles(dir, nil, 50, WithDetectEncoding(true))
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(results["big.txt"], strings.Repeat("a", 50)), "the limit applies to decoded text")
+	})
+}
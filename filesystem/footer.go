@@ -0,0 +1,40 @@
+// Package filesystem provides functionality for scanning, reading, and managing
+// filesystem operations in the glance application.
+package filesystem
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// footerPattern matches a footer written by RenderFooter, including the blank
+// line that separates it from the summary.
+var footerPattern = regexp.MustCompile(`(?s)\n<!-- glance:footer -->\n.*?<!-- /glance:footer -->\n`)
+
+// RenderFooter formats the provenance footer placed at the end of a generated
+// glance output: when it was generated and by which model.
+//
+// Parameters:
+//   - model: The model (or fallback chain) that generated the summary
+//   - at: The generation time, rendered in UTC
+//
+// Returns:
+//   - The footer, starting with the newline that leaves a blank line after a
+//     newline-terminated summary
+func RenderFooter(model string, at time.Time) string {
+	return fmt.Sprintf("\n<!-- glance:footer -->\n---\n_Generated by glance on %s with %s._\n<!-- /glance:footer -->\n",
+		at.UTC().Format(time.RFC3339), model)
+}
+
+// StripFooter removes a footer written by RenderFooter, so content that differs
+// only in when it was generated compares, hashes, and prompts the same.
+//
+// Parameters:
+//   - content: A glance output file's content
+//
+// Returns:
+//   - The content without its footer; content without one is returned unchanged
+func StripFooter(content string) string {
+	return footerPattern.ReplaceAllString(content, "")
+}
@@ -0,0 +1,20 @@
+package filesystem
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderAndStripFooter(t *testing.T) {
+	at := time.Date(2024, 3, 1, 9, 30, 0, 0, time.FixedZone("CET", 3600))
+	footer := RenderFooter("gemini-2.5-flash", at)
+	assert.Contains(t, footer, "_Generated by glance on 2024-03-01T08:30:00Z with gemini-2.5-flash._", "times are rendered in UTC")
+
+	summary := "## Purpose\nParses things.\n" + RenderSubGlanceHash(HashSubGlances("child"))
+	assert.Equal(t, summary, StripFooter(summary+footer))
+	assert.Equal(t, summary, StripFooter(summary), "content without a footer is unchanged")
+	assert.Equal(t, StripFooter(summary+footer), StripFooter(summary+RenderFooter("gemini-2.5-flash", at.Add(time.Hour))),
+		"outputs differing only in their footer strip to the same content")
+}
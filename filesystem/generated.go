@@ -0,0 +1,80 @@
+// Package filesystem provides functionality for scanning, reading, and managing
+// filesystem operations in the glance application.
+package filesystem
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// generatedSuffixes are file name endings used by common code generators.
+var generatedSuffixes = []string{
+	".pb.go",
+	".pb.gw.go",
+	"_pb2.py",
+	"_pb2_grpc.py",
+	".pb.h",
+	".pb.cc",
+	".g.dart",
+	".freezed.dart",
+}
+
+// generatedHeader matches the marker lines generators put at the top of their
+// output, such as Go's "// Code generated ... DO NOT EDIT." and "@generated".
+var generatedHeader = regexp.MustCompile(`(?i)(\bgenerated\b.*\bdo not edit\b|@generated\b)`)
+
+const (
+	// generatedHeaderLines is how many leading lines are searched for a generator marker
+	generatedHeaderLines = 10
+
+	// minifiedMinBytes is the smallest content checked for minification
+	minifiedMinBytes = 1024
+
+	// minifiedAvgLineLength is the average line length, in bytes, above which
+	// content is treated as minified; hand-written code averages well under 100
+	minifiedAvgLineLength = 300
+)
+
+// IsLikelyGenerated reports whether a text file looks machine-generated or
+// minified, and so is token-heavy but of little use in a summary. It checks, in
+// order: a ".min." infix or generator suffix (such as ".pb.go") in the file
+// name, a "generated ... DO NOT EDIT" or "@generated" marker in the first few
+// lines, and an average line length typical of minified output.
+//
+// Parameters:
+//   - path: The file's path; only the base name is inspected
+//   - content: The file's text content
+//
+// Returns:
+//   - true if the file is likely generated or minified
+func IsLikelyGenerated(path, content string) bool {
+	name := strings.ToLower(filepath.Base(path))
+	if strings.Contains(name, ".min.") {
+		return true
+	}
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+
+	header := strings.SplitN(content, "\n", generatedHeaderLines+1)
+	if len(header) > generatedHeaderLines {
+		header = header[:generatedHeaderLines]
+	}
+	for _, line := range header {
+		if generatedHeader.MatchString(line) {
+			return true
+		}
+	}
+
+	if len(content) >= minifiedMinBytes {
+		lines := strings.Count(strings.TrimRight(content, "\n"), "\n") + 1
+		if len(content)/lines > minifiedAvgLineLength {
+			return true
+		}
+	}
+
+	return false
+}
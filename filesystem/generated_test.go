@@ -0,0 +1,64 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsLikelyGenerated(t *testing.T) {
+	normalGo := "// Package api serves requests.\npackage api\n\nfunc Handle() error {\n\treturn nil\n}\n"
+	minifiedJS := "!function(e){" + strings.Repeat("var a=e.b||{};a.c=function(d){return d*2};", 100) + "}(window);\n"
+
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    bool
+	}{
+		{"go generated header", "api/zz_generated.go", "// Code generated by controller-gen. DO NOT EDIT.\n\npackage api\n", true},
+		{"header after license", "gen.go", strings.Repeat("// Copyright 2024 Acme\n", 3) + "\n// Code generated by stringer; DO NOT EDIT.\npackage x\n", true},
+		{"@generated marker", "schema.ts", "/**\n * @generated SignedSource<<abc>>\n */\nexport type A = {};\n", true},
+		{"protobuf suffix", "api/service.pb.go", normalGo, true},
+		{"python protobuf suffix", "service_pb2.py", "import sys\n", true},
+		{"min in name", "static/app.min.js", "console.log(1);\n", true},
+		{"min in name, any case", "static/Theme.MIN.css", "a{}\n", true},
+		{"minified content", "static/bundle.js", minifiedJS, true},
+		{"normal go file", "api/handler.go", normalGo, false},
+		{"marker below the header", "notes.go", strings.Repeat("// line\n", 10) + "// Code generated by hand. DO NOT EDIT.\n", false},
+		{"mentions generation without the marker", "gen.go", "// Package gen generates docs. Edit freely.\npackage gen\n", false},
+		{"short single long line", "data.txt", strings.Repeat("x", 900), false},
+		{"name containing min but not .min.", "admin.js", normalGo, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsLikelyGenerated(tt.path, tt.content))
+		})
+	}
+}
+
+func TestGatherLocalFilesSkipGenerated(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"handler.go":    "package api\n\nfunc Handle() {}\n",
+		"types.pb.go":   "package api\n",
+		"zz_gen.go":     "// Code generated by tool. DO NOT EDIT.\npackage api\n",
+		"vendor.min.js": "var a=1;\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+
+	all, err := GatherLocalFiles(dir, nil, 1024)
+	require.NoError(t, err)
+	assert.Len(t, all, 4, "generated files are kept by default")
+
+	kept, err := GatherLocalFiles(dir, nil, 1024, WithSkipGenerated(true))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"handler.go": files["handler.go"]}, kept)
+}
@@ -0,0 +1,232 @@
+// Package filesystem provides functionality for scanning, reading, and managing
+// filesystem operations in the glance application.
+package filesystem
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// RecentCommits returns up to n one-line commit summaries ("<hash> <subject>") for
+// commits that touched dir, newest first.
+//
+// Directories outside a git work tree, repositories without commits, and machines
+// without a git binary all yield an empty history rather than an error, so callers
+// can treat git metadata as a best-effort enrichment.
+//
+// Parameters:
+//   - dir: The directory whose history should be listed
+//   - n: The maximum number of commits to return
+//
+// Returns:
+//   - The commit summaries, newest first
+//   - An error if git is available and dir has history but the log could not be read
+func RecentCommits(dir string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		log.WithField("directory", dir).Debug("git not found in PATH, skipping history")
+		return nil, nil
+	}
+
+	// Not a git work tree, or a repository without any commits yet.
+	// #nosec G204 -- fixed git subcommand; dir is passed as a -C argument, not a shell string
+	if err := exec.Command(gitPath, "-C", dir, "rev-parse", "--verify", "-q", "HEAD").Run(); err != nil {
+		log.WithField("directory", dir).Debug("Directory has no git history")
+		return nil, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	// The "." pathspec is resolved relative to dir, limiting history to this directory.
+	// #nosec G204 -- fixed git subcommand; dir is passed as a -C argument, not a shell string
+	cmd := exec.Command(gitPath, "-C", dir, "log", "--oneline", "--no-color", "-n", strconv.Itoa(n), "--", ".")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("git log failed for %s: %w: %s", dir, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var commits []string
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			commits = append(commits, line)
+		}
+	}
+	return commits, nil
+}
+
+// FindRepoRoot walks up from start looking for a directory containing a .git
+// entry. Both .git directories and .git files (worktrees, submodules) count.
+// Unlike RecentCommits it does not need a git binary.
+//
+// Parameters:
+//   - start: The directory to begin searching from; relative paths are resolved
+//     against the working directory
+//
+// Returns:
+//   - The absolute path of the repository root, if found
+//   - Whether a repository root was found
+func FindRepoRoot(start string) (string, bool) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", false
+	}
+
+	for {
+		if _, err := os.Lstat(filepath.Join(dir, ".git")); err == nil {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// GitOrigin returns the URL of the "origin" remote and the commit checked out
+// at HEAD for the repository containing dir. Like RecentCommits it is
+// best-effort: a missing git binary, a directory outside a work tree, a
+// repository without commits, or one without an origin remote all report false.
+//
+// Parameters:
+//   - dir: A directory inside the repository
+//
+// Returns:
+//   - The origin remote URL as configured (https or ssh form)
+//   - The full SHA of HEAD
+//   - Whether both were found
+func GitOrigin(dir string) (string, string, bool) {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		log.WithField("directory", dir).Debug("git not found in PATH, skipping origin lookup")
+		return "", "", false
+	}
+
+	// #nosec G204 -- fixed git subcommand; dir is passed as a -C argument, not a shell string
+	remote, err := exec.Command(gitPath, "-C", dir, "remote", "get-url", "origin").Output()
+	if err != nil {
+		log.WithField("directory", dir).Debug("Repository has no origin remote")
+		return "", "", false
+	}
+
+	// #nosec G204 -- fixed git subcommand; dir is passed as a -C argument, not a shell string
+	commit, err := exec.Command(gitPath, "-C", dir, "rev-parse", "--verify", "-q", "HEAD").Output()
+	if err != nil {
+		log.WithField("directory", dir).Debug("Repository has no commits")
+		return "", "", false
+	}
+
+	return strings.TrimSpace(string(remote)), strings.TrimSpace(string(commit)), true
+}
+
+// StagedFiles returns the absolute paths of the files under dir with changes
+// staged in the git index, as listed by "git diff --cached". Like RecentCommits
+// it is best-effort about git itself: a missing git binary or a directory
+// outside a work tree yields no files rather than an error.
+//
+// Parameters:
+//   - dir: The directory whose staged files should be listed
+//
+// Returns:
+//   - The staged file paths, in git's order (nil when there are none)
+//   - An error if dir is in a work tree but the index could not be read
+func StagedFiles(dir string) ([]string, error) {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		log.WithField("directory", dir).Debug("git not found in PATH, no staged files")
+		return nil, nil
+	}
+
+	// #nosec G204 -- fixed git subcommand; dir is passed as a -C argument, not a shell string
+	if err := exec.Command(gitPath, "-C", dir, "rev-parse", "--is-inside-work-tree").Run(); err != nil {
+		log.WithField("directory", dir).Debug("Directory is not in a git work tree, no staged files")
+		return nil, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	// --relative limits the diff to dir and reports paths relative to it
+	// #nosec G204 -- fixed git subcommand; dir is passed as a -C argument, not a shell string
+	cmd := exec.Command(gitPath, "-C", dir, "diff", "--cached", "--name-only", "--relative", "-z")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("git diff --cached failed for %s: %w: %s", dir, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var files []string
+	for _, name := range strings.Split(stdout.String(), "\x00") {
+		if name != "" {
+			files = append(files, filepath.Join(dir, filepath.FromSlash(name)))
+		}
+	}
+	return files, nil
+}
+
+// DiffSinceSummary returns the changes to the files directly in dir since the
+// summary at summaryPath was last committed, or since HEAD when the summary
+// was never committed, as a unified diff with paths relative to dir. glance's
+// own files are left out. Like RecentCommits it is best-effort about git: a
+// missing git binary, a directory outside a work tree, or a repository without
+// commits yields an empty diff. So do untracked files in dir, since the diff
+// could not show them and callers should then fall back to the full contents.
+//
+// Parameters:
+//   - dir: The directory whose files should be compared
+//   - summaryPath: The summary whose last commit is the base of the diff
+//
+// Returns:
+//   - The diff, empty when there are no changes or they cannot all be shown
+//   - An error if git is available and dir has history but the diff failed
+func DiffSinceSummary(dir, summaryPath string) (string, error) {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		log.WithField("directory", dir).Debug("git not found in PATH, no diff")
+		return "", nil
+	}
+
+	// #nosec G204 -- fixed git subcommand; dir is passed as a -C argument, not a shell string
+	if err := exec.Command(gitPath, "-C", dir, "rev-parse", "--verify", "-q", "HEAD").Run(); err != nil {
+		log.WithField("directory", dir).Debug("Directory has no git history, no diff")
+		return "", nil
+	}
+
+	// Pathspecs are relative to dir: "*" under glob magic never crosses a slash
+	pathspecs := []string{"--", ":(glob)*", ":(exclude)" + GlanceFilename, ":(exclude)" + LegacyGlanceFilename}
+
+	// #nosec G204 -- fixed git subcommand; dir is passed as a -C argument, not a shell string
+	untracked, err := exec.Command(gitPath, append([]string{"-C", dir, "ls-files", "--others", "--exclude-standard", "-z"}, pathspecs...)...).Output()
+	if err != nil {
+		return "", fmt.Errorf("git ls-files failed for %s: %w", dir, err)
+	}
+	if len(bytes.TrimRight(untracked, "\x00")) > 0 {
+		log.WithField("directory", dir).Debug("Directory has untracked files, no diff")
+		return "", nil
+	}
+
+	base := "HEAD"
+	// #nosec G204 -- fixed git subcommand; dir is passed as a -C argument, not a shell string
+	if out, logErr := exec.Command(gitPath, "-C", dir, "log", "-1", "--format=%H", "--", summaryPath).Output(); logErr == nil {
+		if commit := strings.TrimSpace(string(out)); commit != "" {
+			base = commit
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	// #nosec G204 -- fixed git subcommand; dir is passed as a -C argument, not a shell string
+	cmd := exec.Command(gitPath, append([]string{"-C", dir, "diff", "--no-color", "--no-ext-diff", "--relative", base}, pathspecs...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git diff failed for %s: %w: %s", dir, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,254 @@
+package filesystem
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runGit executes a git command in dir with a fixed test identity.
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %v failed: %s", args, out)
+}
+
+func TestRecentCommits(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+
+	repo := t.TempDir()
+	sub := filepath.Join(repo, "pkg")
+	other := filepath.Join(repo, "other")
+	require.NoError(t, os.MkdirAll(sub, 0755))
+	require.NoError(t, os.MkdirAll(other, 0755))
+
+	runGit(t, repo, "init", "-q")
+
+	commitFile := func(path, content, msg string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		runGit(t, repo, "add", "-A")
+		runGit(t, repo, "commit", "-q", "-m", msg)
+	}
+	commitFile(filepath.Join(sub, "a.go"), "package pkg\n", "add pkg a")
+	commitFile(filepath.Join(other, "b.go"), "package other\n", "add other b")
+	commitFile(filepath.Join(sub, "c.go"), "package pkg\n", "add pkg c")
+
+	t.Run("Lists commits touching the directory newest first", func(t *testing.T) {
+		commits, err := RecentCommits(sub, 10)
+		require.NoError(t, err)
+		require.Len(t, commits, 2)
+		assert.Contains(t, commits[0], "add pkg c")
+		assert.Contains(t, commits[1], "add pkg a")
+	})
+
+	t.Run("Respects the limit", func(t *testing.T) {
+		commits, err := RecentCommits(repo, 2)
+		require.NoError(t, err)
+		require.Len(t, commits, 2)
+		assert.Contains(t, commits[0], "add pkg c")
+		assert.Contains(t, commits[1], "add other b")
+	})
+
+	t.Run("Non-git directory yields empty history", func(t *testing.T) {
+		commits, err := RecentCommits(t.TempDir(), 5)
+		assert.NoError(t, err)
+		assert.Empty(t, commits)
+	})
+
+	t.Run("Repository without commits yields empty history", func(t *testing.T) {
+		empty := t.TempDir()
+		runGit(t, empty, "init", "-q")
+		commits, err := RecentCommits(empty, 5)
+		assert.NoError(t, err)
+		assert.Empty(t, commits)
+	})
+
+	t.Run("Zero limit yields empty history", func(t *testing.T) {
+		commits, err := RecentCommits(repo, 0)
+		assert.NoError(t, err)
+		assert.Empty(t, commits)
+	})
+}
+
+func TestFindRepoRoot(t *testing.T) {
+	t.Run("Nested start finds the root", func(t *testing.T) {
+		repo := t.TempDir()
+		nested := filepath.Join(repo, "a", "b", "c")
+		require.NoError(t, os.MkdirAll(nested, 0755))
+		require.NoError(t, os.Mkdir(filepath.Join(repo, ".git"), 0755))
+
+		root, ok := FindRepoRoot(nested)
+		assert.True(t, ok)
+		assert.Equal(t, repo, root)
+
+		root, ok = FindRepoRoot(repo)
+		assert.True(t, ok, "the root itself should be found")
+		assert.Equal(t, repo, root)
+	})
+
+	t.Run("Worktree .git file counts", func(t *testing.T) {
+		repo := t.TempDir()
+		sub := filepath.Join(repo, "sub")
+		require.NoError(t, os.Mkdir(sub, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(repo, ".git"), []byte("gitdir: /elsewhere\n"), 0600))
+
+		root, ok := FindRepoRoot(sub)
+		assert.True(t, ok)
+		assert.Equal(t, repo, root)
+	})
+
+	t.Run("Non-repo directory returns false", func(t *testing.T) {
+		dir := t.TempDir()
+		if _, inRepo := FindRepoRoot(filepath.Dir(dir)); inRepo {
+			t.Skip("temp directory is inside a git repository")
+		}
+
+		root, ok := FindRepoRoot(dir)
+		assert.False(t, ok)
+		assert.Empty(t, root)
+	})
+}
+
+func TestGitOrigin(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-q")
+
+	_, _, ok := GitOrigin(repo)
+	assert.False(t, ok, "no origin and no commits")
+
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "a.go"), []byte("package a\n"), 0600))
+	runGit(t, repo, "add", "-A")
+	runGit(t, repo, "commit", "-q", "-m", "add a")
+
+	_, _, ok = GitOrigin(repo)
+	assert.False(t, ok, "commits but no origin")
+
+	runGit(t, repo, "remote", "add", "origin", "git@github.com:acme/widgets.git")
+	head, err := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+	require.NoError(t, err)
+
+	remote, commit, ok := GitOrigin(repo)
+	require.True(t, ok)
+	assert.Equal(t, "git@github.com:acme/widgets.git", remote)
+	assert.Equal(t, strings.TrimSpace(string(head)), commit)
+}
+
+func TestStagedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+
+	repo := t.TempDir()
+	sub := filepath.Join(repo, "pkg")
+	require.NoError(t, os.MkdirAll(sub, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "root.go"), []byte("package root\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(sub, "a.go"), []byte("package pkg\n"), 0644))
+	runGit(t, repo, "init", "-q")
+
+	t.Run("Nothing staged", func(t *testing.T) {
+		files, err := StagedFiles(repo)
+		require.NoError(t, err)
+		assert.Empty(t, files)
+	})
+
+	runGit(t, repo, "add", "-A")
+
+	t.Run("Staged files in a repository without commits", func(t *testing.T) {
+		files, err := StagedFiles(repo)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{filepath.Join(repo, "root.go"), filepath.Join(sub, "a.go")}, files)
+	})
+
+	t.Run("Limited to the directory", func(t *testing.T) {
+		files, err := StagedFiles(sub)
+		require.NoError(t, err)
+		assert.Equal(t, []string{filepath.Join(sub, "a.go")}, files)
+	})
+
+	t.Run("Outside a work tree", func(t *testing.T) {
+		files, err := StagedFiles(t.TempDir())
+		require.NoError(t, err)
+		assert.Nil(t, files)
+	})
+}
+
+func TestDiffSinceSummary(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+
+	repo := t.TempDir()
+	sub := filepath.Join(repo, "pkg")
+	require.NoError(t, os.MkdirAll(sub, 0755))
+	summary := filepath.Join(repo, GlanceFilename)
+	write := func(path, content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	write(filepath.Join(repo, "main.go"), "package main\n\nfunc main() {}\n")
+	write(filepath.Join(sub, "a.go"), "package pkg\n")
+	runGit(t, repo, "init", "-q")
+	runGit(t, repo, "add", "-A")
+	runGit(t, repo, "commit", "-q", "-m", "init")
+
+	t.Run("Unchanged directory has an empty diff", func(t *testing.T) {
+		diff, err := DiffSinceSummary(repo, summary)
+		require.NoError(t, err)
+		assert.Empty(t, diff)
+	})
+
+	t.Run("Uncommitted summary diffs against HEAD", func(t *testing.T) {
+		write(filepath.Join(repo, "main.go"), "package main\n\nfunc main() { run() }\n")
+		write(summary, "# summary\n")
+		write(filepath.Join(sub, "a.go"), "package pkg // changed\n")
+
+		diff, err := DiffSinceSummary(repo, summary)
+		require.NoError(t, err)
+		assert.Contains(t, diff, "+func main() { run() }")
+		assert.Contains(t, diff, "a/main.go")
+		assert.NotContains(t, diff, "a.go", "subdirectories are not part of the diff")
+		assert.NotContains(t, diff, "# summary", "the summary itself is not part of the diff")
+	})
+
+	t.Run("Committed summary is the diff base", func(t *testing.T) {
+		runGit(t, repo, "add", "-A")
+		runGit(t, repo, "commit", "-q", "-m", "summarize")
+		write(filepath.Join(repo, "main.go"), "package main\n\nfunc main() { run(); stop() }\n")
+		runGit(t, repo, "commit", "-q", "-am", "later change")
+
+		diff, err := DiffSinceSummary(repo, summary)
+		require.NoError(t, err)
+		assert.Contains(t, diff, "-func main() { run() }", "changes committed after the summary are included")
+		assert.Contains(t, diff, "+func main() { run(); stop() }")
+	})
+
+	t.Run("Untracked files yield an empty diff", func(t *testing.T) {
+		write(filepath.Join(repo, "new.go"), "package main\n")
+		defer os.Remove(filepath.Join(repo, "new.go"))
+
+		diff, err := DiffSinceSummary(repo, summary)
+		require.NoError(t, err)
+		assert.Empty(t, diff)
+	})
+
+	t.Run("Outside a work tree", func(t *testing.T) {
+		diff, err := DiffSinceSummary(t.TempDir(), summary)
+		require.NoError(t, err)
+		assert.Empty(t, diff)
+	})
+}
@@ -0,0 +1,105 @@
+// Package filesystem provides functionality for scanning, reading, and managing
+// filesystem operations in the glance application.
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	gitignore "github.com/sabhiram/go-gitignore"
+)
+
+// gitExcludesFileSetting returns the raw core.excludesFile value from git config,
+// or "" when it is unset or git is unavailable. It is a variable so tests can
+// supply a fake setting.
+var gitExcludesFileSetting = func() string {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		log.Debug("git not found in PATH, using the default global excludes location")
+		return ""
+	}
+
+	// git exits non-zero when the key is unset, which is the common case
+	// #nosec G204 -- fixed git subcommand with no user-controlled arguments
+	out, err := exec.Command(gitPath, "config", "--get", "core.excludesFile").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// GlobalExcludesFile returns the path of the user's global git excludes file, as
+// git would resolve it: core.excludesFile when set (with a leading "~/" expanded),
+// otherwise $XDG_CONFIG_HOME/git/ignore or ~/.config/git/ignore.
+//
+// Returns:
+//   - The path of the global excludes file, which may not exist, or "" if no
+//     location can be determined
+func GlobalExcludesFile() string {
+	if setting := gitExcludesFileSetting(); setting != "" {
+		return expandHome(setting)
+	}
+
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "git", "ignore")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "git", "ignore")
+}
+
+// expandHome replaces a leading "~" or "~/" with the user's home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// LoadGlobalGitignore compiles the user's global git excludes file (see
+// GlobalExcludesFile).
+//
+// Returns:
+//   - The compiled matcher, or nil if there is no global excludes file
+//   - The path that was loaded, or "" if none
+//   - An error if the file exists but cannot be validated or parsed
+func LoadGlobalGitignore() (*gitignore.GitIgnore, string, error) {
+	path := GlobalExcludesFile()
+	if path == "" {
+		return nil, "", nil
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid global excludes path %q: %w", path, err)
+	}
+
+	// The excludes file is user-chosen and usually outside the target directory,
+	// so validate it against its own directory: this still cleans the path and
+	// ensures it is an existing regular file.
+	validPath, err := ValidateFilePath(absPath, filepath.Dir(absPath), false, true)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			log.WithField("path", absPath).Debug("No global git excludes file")
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("invalid global excludes file: %w", err)
+	}
+
+	matcher, err := gitignore.CompileIgnoreFile(validPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read global excludes file %s: %w", validPath, err)
+	}
+	return matcher, validPath, nil
+}
@@ -0,0 +1,129 @@
+package filesystem
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeExcludesSetting replaces the core.excludesFile lookup for the duration of a test.
+func fakeExcludesSetting(t *testing.T, setting string) {
+	t.Helper()
+	orig := gitExcludesFileSetting
+	gitExcludesFileSetting = func() string { return setting }
+	t.Cleanup(func() { gitExcludesFileSetting = orig })
+}
+
+func TestGlobalExcludesFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	t.Run("core.excludesFile wins", func(t *testing.T) {
+		fakeExcludesSetting(t, "/etc/gitignore_global")
+		assert.Equal(t, "/etc/gitignore_global", GlobalExcludesFile())
+	})
+
+	t.Run("tilde is expanded", func(t *testing.T) {
+		fakeExcludesSetting(t, "~/.gitignore_global")
+		assert.Equal(t, filepath.Join(home, ".gitignore_global"), GlobalExcludesFile())
+	})
+
+	t.Run("XDG default", func(t *testing.T) {
+		fakeExcludesSetting(t, "")
+		xdg := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", xdg)
+		assert.Equal(t, filepath.Join(xdg, "git", "ignore"), GlobalExcludesFile())
+	})
+
+	t.Run("home default", func(t *testing.T) {
+		fakeExcludesSetting(t, "")
+		t.Setenv("XDG_CONFIG_HOME", "")
+		assert.Equal(t, filepath.Join(home, ".config", "git", "ignore"), GlobalExcludesFile())
+	})
+}
+
+func TestLoadGlobalGitignore(t *testing.T) {
+	t.Run("missing file is not an error", func(t *testing.T) {
+		fakeExcludesSetting(t, filepath.Join(t.TempDir(), "nope"))
+		matcher, path, err := LoadGlobalGitignore()
+		require.NoError(t, err)
+		assert.Nil(t, matcher)
+		assert.Empty(t, path)
+	})
+
+	t.Run("existing file is compiled", func(t *testing.T) {
+		excludes := filepath.Join(t.TempDir(), "gitignore_global")
+		require.NoError(t, os.WriteFile(excludes, []byte("*.swp\n"), 0600))
+		fakeExcludesSetting(t, excludes)
+
+		matcher, path, err := LoadGlobalGitignore()
+		require.NoError(t, err)
+		require.NotNil(t, matcher)
+		assert.Equal(t, excludes, path)
+		assert.True(t, matcher.MatchesPath("notes.swp"))
+	})
+
+	t.Run("setting from real git config", func(t *testing.T) {
+		if _, err := exec.LookPath("git"); err != nil {
+			t.Skip("git not available")
+		}
+
+		dir := t.TempDir()
+		excludes := filepath.Join(dir, "gitignore_global")
+		require.NoError(t, os.WriteFile(excludes, []byte(".DS_Store\n"), 0600))
+		gitconfig := filepath.Join(dir, "gitconfig")
+		require.NoError(t, os.WriteFile(gitconfig, []byte("[core]\n\texcludesFile = "+excludes+"\n"), 0600))
+		t.Setenv("GIT_CONFIG_GLOBAL", gitconfig)
+		t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+		t.Chdir(dir) // keep any enclosing repository's config out of the lookup
+
+		_, path, err := LoadGlobalGitignore()
+		require.NoError(t, err)
+		assert.Equal(t, excludes, path)
+	})
+}
+
+func TestListDirsWithRootIgnore(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"src", "scratch", filepath.Join("src", "scratch")} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("build/\n"), 0600))
+
+	excludes := filepath.Join(t.TempDir(), "gitignore_global")
+	require.NoError(t, os.WriteFile(excludes, []byte("*.swp\nscratch/\n"), 0600))
+	fakeExcludesSetting(t, excludes)
+	matcher, _, err := LoadGlobalGitignore()
+	require.NoError(t, err)
+
+	t.Run("without the global rule", func(t *testing.T) {
+		dirs, _, err := ListDirsWithIgnores(root)
+		require.NoError(t, err)
+		assert.Len(t, dirs, 4)
+	})
+
+	t.Run("global rule is first in the root chain", func(t *testing.T) {
+		dirs, chains, err := ListDirsWithIgnores(root, WithRootIgnore(matcher))
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{root, filepath.Join(root, "src")}, dirs, "scratch directories are excluded at every depth")
+
+		rootChain := chains[root]
+		require.Len(t, rootChain, 2, "global rule plus the root .gitignore")
+		assert.Same(t, matcher, rootChain[0].Matcher)
+		assert.Equal(t, root, rootChain[0].OriginDir)
+
+		src := filepath.Join(root, "src")
+		assert.True(t, ShouldIgnoreFile(filepath.Join(src, "main.go.swp"), src, chains[src]))
+		assert.False(t, ShouldIgnoreFile(filepath.Join(src, "main.go"), src, chains[src]))
+	})
+
+	t.Run("nil matcher is ignored", func(t *testing.T) {
+		_, chains, err := ListDirsWithIgnores(root, WithRootIgnore(nil))
+		require.NoError(t, err)
+		assert.Len(t, chains[root], 1)
+	})
+}
@@ -0,0 +1,177 @@
+// Package filesystem provides functionality for scanning, reading, and managing
+// filesystem operations in the glance application.
+package filesystem
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// FileGroup is a set of related files from one directory, summarized together
+// when a directory is too large to summarize in one piece.
+type FileGroup struct {
+	// Name describes the group, e.g. the filename prefix its files share
+	Name string
+
+	// Files maps each file's name to its content, as in GatherLocalFiles
+	Files map[string]string
+}
+
+// fileCluster is a set of file names sharing a prefix key.
+type fileCluster struct {
+	key   string
+	names []string
+}
+
+// GroupFiles splits files into groups of at most maxPerGroup related files.
+// Files are clustered by the words their names start with (split at
+// underscores, hyphens, dots, and camelCase boundaries), using longer prefixes
+// for clusters that are still too large and splitting clusters that cannot be
+// told apart into numbered parts. Neighboring small clusters are then packed
+// together, so a directory of many one-off files does not become many tiny
+// groups.
+//
+// Parameters:
+//   - files: A map of file names to their contents
+//   - maxPerGroup: The largest number of files in a group; zero or less, or a
+//     files map no larger than it, yields a single group
+//
+// Returns:
+//   - The groups, ordered by name; their files together are exactly files
+func GroupFiles(files map[string]string, maxPerGroup int) []FileGroup {
+	if len(files) == 0 {
+		return nil
+	}
+	if maxPerGroup <= 0 || len(files) <= maxPerGroup {
+		return []FileGroup{{Name: "all files", Files: files}}
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	clusters := clusterByPrefix(names, 1, maxPerGroup)
+
+	var groups []FileGroup
+	var packed []fileCluster
+	count := 0
+	flush := func() {
+		if len(packed) == 0 {
+			return
+		}
+		group := FileGroup{Name: packedName(packed), Files: make(map[string]string, count)}
+		for _, cluster := range packed {
+			for _, name := range cluster.names {
+				group.Files[name] = files[name]
+			}
+		}
+		groups = append(groups, group)
+		packed, count = nil, 0
+	}
+	for _, cluster := range clusters {
+		if count+len(cluster.names) > maxPerGroup {
+			flush()
+		}
+		packed = append(packed, cluster)
+		count += len(cluster.names)
+	}
+	flush()
+	return groups
+}
+
+// clusterByPrefix clusters sorted names by their first depth name words,
+// refining any cluster larger than maxPerGroup with one more word. A cluster
+// whose names have no more words to tell them apart is split into parts.
+func clusterByPrefix(names []string, depth, maxPerGroup int) []fileCluster {
+	var keys []string
+	members := make(map[string][]string)
+	deeper := make(map[string]bool)
+	for _, name := range names {
+		words := nameWords(name)
+		key := strings.Join(words[:min(depth, len(words))], "_")
+		if _, ok := members[key]; !ok {
+			keys = append(keys, key)
+		}
+		members[key] = append(members[key], name)
+		if len(words) > depth {
+			deeper[key] = true
+		}
+	}
+	sort.Strings(keys)
+
+	var clusters []fileCluster
+	for _, key := range keys {
+		cluster := members[key]
+		switch {
+		case len(cluster) <= maxPerGroup:
+			clusters = append(clusters, fileCluster{key: key, names: cluster})
+		case deeper[key]:
+			clusters = append(clusters, clusterByPrefix(cluster, depth+1, maxPerGroup)...)
+		default:
+			parts := (len(cluster) + maxPerGroup - 1) / maxPerGroup
+			for i := 0; i < parts; i++ {
+				end := min((i+1)*maxPerGroup, len(cluster))
+				clusters = append(clusters, fileCluster{
+					key:   fmt.Sprintf("%s (part %d of %d)", key, i+1, parts),
+					names: cluster[i*maxPerGroup : end],
+				})
+			}
+		}
+	}
+	return clusters
+}
+
+// packedName names a group made of one or more neighboring clusters.
+func packedName(clusters []fileCluster) string {
+	first, last := clusters[0].key, clusters[len(clusters)-1].key
+	if len(clusters) == 1 {
+		return first
+	}
+	return first + " to " + last
+}
+
+// nameWords splits a file name, without its directory and extension, into
+// lowercase words at non-alphanumeric characters and camelCase boundaries
+// (including the end of an acronym, as in "HTTPServer").
+// A name with no words (such as ".env") is its own single word.
+func nameWords(name string) []string {
+	base := filepath.Base(name)
+	if stem := strings.TrimSuffix(base, filepath.Ext(base)); stem != "" {
+		base = stem
+	}
+
+	var words []string
+	var current []rune
+	runes := []rune(base)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(current) > 0 {
+				words = append(words, strings.ToLower(string(current)))
+				current = nil
+			}
+			continue
+		}
+		// A word starts at "userHandler" -> "Handler" and "HTTPServer" -> "Server"
+		if unicode.IsUpper(r) && len(current) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				words = append(words, strings.ToLower(string(current)))
+				current = nil
+			}
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		words = append(words, strings.ToLower(string(current)))
+	}
+	if len(words) == 0 {
+		words = []string{strings.ToLower(base)}
+	}
+	return words
+}
@@ -0,0 +1,100 @@
+package filesystem
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// groupNames returns each group's name mapped to its sorted file names.
+func groupNames(groups []FileGroup) map[string][]string {
+	names := make(map[string][]string, len(groups))
+	for _, group := range groups {
+		for name := range group.Files {
+			names[group.Name] = append(names[group.Name], name)
+		}
+	}
+	for _, files := range names {
+		sort.Strings(files)
+	}
+	return names
+}
+
+func fileSet(names ...string) map[string]string {
+	files := make(map[string]string, len(names))
+	for _, name := range names {
+		files[name] = "content of " + name
+	}
+	return files
+}
+
+func TestGroupFiles(t *testing.T) {
+	t.Run("small directories stay whole", func(t *testing.T) {
+		files := fileSet("a.go", "b.go", "c.go")
+		assert.Equal(t, []FileGroup{{Name: "all files", Files: files}}, GroupFiles(files, 3))
+		assert.Equal(t, []FileGroup{{Name: "all files", Files: files}}, GroupFiles(files, 0))
+		assert.Nil(t, GroupFiles(nil, 2))
+	})
+
+	t.Run("files cluster by shared name prefix", func(t *testing.T) {
+		files := fileSet(
+			"handler_user.go", "handler_user_test.go", "handlerOrder.go", "handler_order_test.go",
+			"store_user.go", "store_order.go",
+		)
+		groups := GroupFiles(files, 2)
+		assert.Equal(t, map[string][]string{
+			"handler_order": {"handlerOrder.go", "handler_order_test.go"},
+			"handler_user":  {"handler_user.go", "handler_user_test.go"},
+			"store":         {"store_order.go", "store_user.go"},
+		}, groupNames(groups))
+	})
+
+	t.Run("neighboring small clusters are packed together", func(t *testing.T) {
+		files := fileSet("alpha.go", "beta.go", "gamma.go", "delta.go", "epsilon.go")
+		groups := GroupFiles(files, 3)
+		assert.Equal(t, map[string][]string{
+			"alpha to delta":   {"alpha.go", "beta.go", "delta.go"},
+			"epsilon to gamma": {"epsilon.go", "gamma.go"},
+		}, groupNames(groups))
+	})
+
+	t.Run("indistinguishable clusters are split into parts", func(t *testing.T) {
+		var names []string
+		for i := 0; i < 5; i++ {
+			names = append(names, fmt.Sprintf("page-%d.md", i))
+		}
+		groups := GroupFiles(fileSet(names...), 2)
+		require.Len(t, groups, 3)
+		assert.Equal(t, "page_0 to page_1", groups[0].Name)
+	})
+
+	t.Run("every file lands in exactly one group within the limit", func(t *testing.T) {
+		var names []string
+		for i := 0; i < 23; i++ {
+			names = append(names, fmt.Sprintf("part_%c_%d.txt", 'a'+i%4, i))
+		}
+		files := fileSet(names...)
+		seen := make(map[string]int)
+		for _, group := range GroupFiles(files, 4) {
+			assert.LessOrEqual(t, len(group.Files), 4, group.Name)
+			for name, content := range group.Files {
+				seen[name]++
+				assert.Equal(t, files[name], content)
+			}
+		}
+		assert.Len(t, seen, len(files))
+		for name, count := range seen {
+			assert.Equal(t, 1, count, name)
+		}
+	})
+}
+
+func TestNameWords(t *testing.T) {
+	assert.Equal(t, []string{"user", "handler"}, nameWords("userHandler.go"))
+	assert.Equal(t, []string{"http", "server", "test"}, nameWords("sub/HTTPServer_test.go"))
+	assert.Equal(t, []string{"api", "v2", "routes"}, nameWords("api-v2.routes.ts"))
+	assert.Equal(t, []string{"env"}, nameWords(".env"))
+}
@@ -3,9 +3,11 @@
 package filesystem
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 
+	gitignore "github.com/sabhiram/go-gitignore"
 	"github.com/sirupsen/logrus"
 )
 
@@ -21,24 +23,92 @@ const (
 	// from older versions do not have stale summaries fed back to the LLM.
 	LegacyGlanceFilename = "glance.md"
 
+	// DirConfigFilename is the per-directory configuration file read by the
+	// config package. Like SkipMarkerFilename it is never summarized.
+	DirConfigFilename = ".glance.toml"
+
 	// NodeModulesDir is a heavy directory that should be skipped by default
 	NodeModulesDir = "node_modules"
+
+	// GitDir is git's own metadata directory (a file in worktrees and
+	// submodules). It is ignored even when hidden files are included.
+	GitDir = ".git"
 )
 
+// IgnoreOption adjusts the built-in rules applied by ShouldIgnoreFile and
+// ShouldIgnoreDir.
+type IgnoreOption func(*ignoreOptions)
+
+// ignoreOptions holds the settings applied by IgnoreOption values.
+type ignoreOptions struct {
+	includeHidden bool
+	excludeDirs   []string
+}
+
+// IncludeHidden stops hidden files and directories (names starting with ".")
+// from being ignored. glance output files, GitDir, and gitignore rules still
+// apply.
+func IncludeHidden(enabled bool) IgnoreOption {
+	return func(o *ignoreOptions) {
+		o.includeHidden = enabled
+	}
+}
+
+// ExcludeDir ignores dir and everything beneath it, e.g. an output tree kept
+// inside the directory being summarized. An empty dir is ignored.
+func ExcludeDir(dir string) IgnoreOption {
+	return func(o *ignoreOptions) {
+		if dir != "" {
+			o.excludeDirs = append(o.excludeDirs, filepath.Clean(dir))
+		}
+	}
+}
+
+// isExcludedDir reports whether path is, or lies within, a directory excluded
+// by opts (see ExcludeDir).
+func isExcludedDir(path string, opts []IgnoreOption) bool {
+	var o ignoreOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	for _, dir := range o.excludeDirs {
+		if withinDir(path, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// isIgnoredHidden reports whether name is hidden and hidden names are not
+// included by opts. GitDir is always reported, whatever the options.
+func isIgnoredHidden(name string, opts []IgnoreOption) bool {
+	if name == GitDir {
+		return true
+	}
+	var o ignoreOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return !o.includeHidden && strings.HasPrefix(name, ".")
+}
+
 // ShouldIgnoreFile determines if a file should be ignored during processing.
 // A file is ignored if:
 // - It's our own output file (GlanceFilename) to avoid feeding it back to the LLM
-// - It's a hidden file (name starts with ".")
+// - It's a SkipMarkerFilename or DirConfigFilename
+// - It's a hidden file (name starts with "."), unless IncludeHidden is given
+// - It's named GitDir
 // - It matches any gitignore rule in the provided chain
 //
 // Parameters:
 //   - path: The absolute path to the file
 //   - baseDir: The base directory relative to which the file is being evaluated
 //   - ignoreChain: A chain of gitignore matchers to check for ignored files
+//   - opts: Adjustments to the built-in rules, such as IncludeHidden
 //
 // Returns:
 //   - true if the file should be ignored, false otherwise
-func ShouldIgnoreFile(path string, baseDir string, ignoreChain IgnoreChain) bool {
+func ShouldIgnoreFile(path string, baseDir string, ignoreChain IgnoreChain, opts ...IgnoreOption) bool {
 	// Get the file name without the path
 	filename := filepath.Base(path)
 
@@ -50,8 +120,15 @@ func ShouldIgnoreFile(path string, baseDir string, ignoreChain IgnoreChain) bool
 		return true
 	}
 
-	// Always ignore hidden files
-	if strings.HasPrefix(filename, ".") {
+	// Skip markers and directory configs are instructions to glance, not
+	// content, even with IncludeHidden
+	if filename == SkipMarkerFilename || filename == DirConfigFilename {
+		log.WithField("file", path).Debug("Ignoring glance control file")
+		return true
+	}
+
+	// Ignore hidden files unless they are included
+	if isIgnoredHidden(filename, opts) {
 		log.WithField("file", path).Debug("Ignoring hidden file")
 		return true
 	}
@@ -66,23 +143,27 @@ func ShouldIgnoreFile(path string, baseDir string, ignoreChain IgnoreChain) bool
 
 // ShouldIgnoreDir determines if a directory should be ignored during processing.
 // A directory is ignored if:
-// - It's a hidden directory (name starts with ".")
+// - It's a hidden directory (name starts with "."), unless IncludeHidden is given
+// - It's git's own GitDir
 // - It's a node_modules directory
+// - It's, or lies within, a directory excluded with ExcludeDir
 // - It matches any gitignore rule in the provided chain
+// - It has a recursive SkipMarkerFilename
 //
 // Parameters:
 //   - path: The absolute path to the directory
 //   - baseDir: The base directory relative to which the directory is being evaluated
 //   - ignoreChain: A chain of gitignore matchers to check for ignored directories
+//   - opts: Adjustments to the built-in rules, such as IncludeHidden and ExcludeDir
 //
 // Returns:
 //   - true if the directory should be ignored, false otherwise
-func ShouldIgnoreDir(path string, baseDir string, ignoreChain IgnoreChain) bool {
+func ShouldIgnoreDir(path string, baseDir string, ignoreChain IgnoreChain, opts ...IgnoreOption) bool {
 	// Get the directory name without the path
 	dirname := filepath.Base(path)
 
-	// Always ignore hidden directories
-	if strings.HasPrefix(dirname, ".") {
+	// Ignore hidden directories unless they are included
+	if isIgnoredHidden(dirname, opts) {
 		log.WithField("directory", path).Debug("Ignoring hidden directory")
 		return true
 	}
@@ -93,15 +174,32 @@ func ShouldIgnoreDir(path string, baseDir string, ignoreChain IgnoreChain) bool
 		return true
 	}
 
+	// Excluded directories, such as an in-tree output root, are never content
+	if isExcludedDir(path, opts) {
+		log.WithField("directory", path).Debug("Ignoring excluded directory")
+		return true
+	}
+
 	// Check gitignore rules
 	if MatchesGitignore(path, baseDir, ignoreChain, true) {
 		return true
 	}
 
+	// A recursive skip marker excludes the whole subtree
+	if ReadSkipMarker(path) == SkipRecursive {
+		log.WithField("directory", path).Debug("Ignoring directory with recursive skip marker")
+		return true
+	}
+
 	return false
 }
 
-// MatchesGitignore checks if a path matches any gitignore rule in the provided chain.
+// MatchesGitignore checks if a path is ignored by the rules in the provided
+// chain. As in git, rules are applied in chain order (outermost .gitignore
+// first) and the last matching pattern wins, so a "!pattern" in a nested
+// .gitignore re-includes a path an enclosing one ignored, provided its rule
+// has a Reincluder. Directories the scan prunes are never descended, so a
+// negation cannot re-include a path inside an ignored directory.
 //
 // Parameters:
 //   - path: The absolute path to check
@@ -110,15 +208,20 @@ func ShouldIgnoreDir(path string, baseDir string, ignoreChain IgnoreChain) bool
 //   - isDir: Whether the path is a directory (affects matching for patterns with trailing slashes)
 //
 // Returns:
-//   - true if the path matches any gitignore rule, false otherwise
+//   - true if the path is ignored after every applicable rule, false otherwise
 func MatchesGitignore(path string, baseDir string, ignoreChain IgnoreChain, isDir bool) bool {
-	// Check if the path matches any gitignore rule in the chain
+	ignored := false
 	for _, rule := range ignoreChain {
 		// Skip rules from directories that are not ancestors of the current path
 		if !strings.HasPrefix(baseDir, rule.OriginDir) {
 			continue
 		}
 
+		// Once ignored, only a rule that can re-include paths changes the outcome
+		if ignored && rule.Reincluder == nil {
+			continue
+		}
+
 		// Get the path relative to the rule's origin
 		relPath, err := filepath.Rel(rule.OriginDir, path)
 		if err != nil {
@@ -133,26 +236,105 @@ func MatchesGitignore(path string, baseDir string, ignoreChain IgnoreChain, isDi
 		// Convert to slash path for consistent matching
 		relPath = filepath.ToSlash(relPath)
 
-		// For directories, we need to test both with and without trailing slash
-		// because gitignore patterns like "dir/" only match "dir/" and not "dir"
-		if isDir {
-			if rule.Matcher.MatchesPath(relPath) || rule.Matcher.MatchesPath(relPath+"/") {
-				log.WithFields(logrus.Fields{
-					"path":       path,
-					"origin_dir": rule.OriginDir,
-				}).Debug("Path matched by gitignore rule")
-				return true
-			}
-		} else {
-			if rule.Matcher.MatchesPath(relPath) {
+		if !ignored {
+			ignored = matchesIgnorePatterns(rule.Matcher, relPath, isDir, false)
+			if ignored {
 				log.WithFields(logrus.Fields{
 					"path":       path,
 					"origin_dir": rule.OriginDir,
 				}).Debug("Path matched by gitignore rule")
-				return true
 			}
+			continue
+		}
+
+		ignored = matchesIgnorePatterns(rule.Reincluder, relPath, isDir, true)
+		if !ignored {
+			log.WithFields(logrus.Fields{
+				"path":       path,
+				"origin_dir": rule.OriginDir,
+			}).Debug("Path re-included by gitignore negation")
 		}
 	}
 
-	return false
+	return ignored
+}
+
+// matchesIgnorePatterns reports whether matcher ignores relPath. For
+// directories, patterns like "dir/" only match "dir/" and not "dir", so both
+// forms are tested: either form matching ignores the directory, while an
+// already-ignored directory (stillIgnored) stays ignored only if neither form
+// is negated.
+func matchesIgnorePatterns(matcher *gitignore.GitIgnore, relPath string, isDir, stillIgnored bool) bool {
+	if !isDir {
+		return matcher.MatchesPath(relPath)
+	}
+	if stillIgnored {
+		return matcher.MatchesPath(relPath) && matcher.MatchesPath(relPath+"/")
+	}
+	return matcher.MatchesPath(relPath) || matcher.MatchesPath(relPath+"/")
+}
+
+// CountGitignoredFiles counts the files directly in dir and how many of them
+// the ignore chain excludes, for judging how much of a directory is gitignored.
+// glance's own files and excluded hidden files are not counted at all, since
+// they are never content. Subdirectories are not searched.
+//
+// Parameters:
+//   - dir: The directory to inspect
+//   - ignoreChain: A chain of gitignore matchers to check for ignored files
+//   - opts: Adjustments to the built-in ignore rules, such as IncludeHidden
+//
+// Returns:
+//   - The number of files counted
+//   - How many of those match a gitignore rule
+//   - An error, if the directory could not be read
+func CountGitignoredFiles(dir string, ignoreChain IgnoreChain, opts ...IgnoreOption) (int, int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var total, ignored int
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || MatchesName(name, GlanceFilename, LegacyGlanceFilename, SkipMarkerFilename, DirConfigFilename) || isIgnoredHidden(name, opts) {
+			continue
+		}
+		total++
+		if MatchesGitignore(filepath.Join(dir, name), dir, ignoreChain, false) {
+			ignored++
+		}
+	}
+	return total, ignored, nil
+}
+
+// HasFileWithExtension reports whether dir directly contains at least one non-ignored
+// file whose extension is in exts. Extensions are compared including the leading dot
+// (e.g. ".go") and, by default, ignoring case. Subdirectories are not searched.
+//
+// Parameters:
+//   - dir: The directory to inspect
+//   - exts: The file extensions to look for
+//   - ignoreChain: A chain of gitignore matchers to check for ignored files
+//   - opts: Adjustments to the built-in ignore rules, such as IncludeHidden
+//
+// Returns:
+//   - true if a matching file exists, false otherwise
+//   - an error, if the directory could not be read
+func HasFileWithExtension(dir string, exts []string, ignoreChain IgnoreChain, opts ...IgnoreOption) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || !MatchesExtension(e.Name(), exts) {
+			continue
+		}
+		if ShouldIgnoreFile(filepath.Join(dir, e.Name()), dir, ignoreChain, opts...) {
+			continue
+		}
+		return true, nil
+	}
+	return false, nil
 }
@@ -0,0 +1,78 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMatchesGitignoreCrossFileNegation verifies that a "!pattern" in a nested
+// .gitignore re-includes paths an enclosing .gitignore ignored, with the last
+// matching rule across the chain winning, as in git.
+func TestMatchesGitignoreCrossFileNegation(t *testing.T) {
+	root := t.TempDir()
+	child := filepath.Join(root, "child")
+	require.NoError(t, os.MkdirAll(filepath.Join(child, "keep"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("*.log\nkeep\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(child, ".gitignore"), []byte("!keep.log\n!keep\nlocal.txt\n"), 0600))
+
+	rootRule, err := LoadIgnoreRule(root)
+	require.NoError(t, err)
+	require.NotNil(t, rootRule)
+	childRule, err := LoadIgnoreRule(child)
+	require.NoError(t, err)
+	require.NotNil(t, childRule)
+	chain := IgnoreChain{*rootRule, *childRule}
+
+	tests := []struct {
+		name     string
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"child negation re-includes a file", filepath.Join(child, "keep.log"), false, false},
+		{"child negation re-includes a directory", filepath.Join(child, "keep"), true, false},
+		{"parent ignore still applies to other files", filepath.Join(child, "debug.log"), false, true},
+		{"child ignore applies on its own", filepath.Join(child, "local.txt"), false, true},
+		{"unmatched file is kept", filepath.Join(child, "main.go"), false, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, MatchesGitignore(tc.path, child, chain, tc.isDir))
+		})
+	}
+
+	// The root's own files are not affected by the child's negations
+	assert.True(t, MatchesGitignore(filepath.Join(root, "keep.log"), root, chain, false))
+
+	// Rules built without a Reincluder keep the earlier first-match behavior
+	legacy := IgnoreChain{{OriginDir: root, Matcher: rootRule.Matcher}, {OriginDir: child, Matcher: childRule.Matcher}}
+	assert.True(t, MatchesGitignore(filepath.Join(child, "keep.log"), child, legacy, false))
+}
+
+// TestListDirsWithIgnoresCrossFileNegation verifies that the scan lists a
+// directory a parent .gitignore ignores when a nested .gitignore re-includes it.
+func TestListDirsWithIgnoresCrossFileNegation(t *testing.T) {
+	root := t.TempDir()
+	child := filepath.Join(root, "child")
+	require.NoError(t, os.MkdirAll(filepath.Join(child, "keep"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(child, "build"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("keep/\nbuild/\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(child, ".gitignore"), []byte("!keep/\n"), 0600))
+
+	dirs, chains, err := ListDirsWithIgnores(root)
+	require.NoError(t, err)
+
+	assert.Contains(t, dirs, filepath.Join(child, "keep"))
+	assert.NotContains(t, dirs, filepath.Join(child, "build"))
+	assert.False(t, ShouldIgnoreFile(filepath.Join(child, "keep", "main.go"), filepath.Join(child, "keep"), chains[filepath.Join(child, "keep")]))
+}
+
+func TestLoadIgnoreRuleMissingFile(t *testing.T) {
+	rule, err := LoadIgnoreRule(t.TempDir())
+	assert.NoError(t, err)
+	assert.Nil(t, rule)
+}
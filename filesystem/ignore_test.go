@@ -277,3 +277,97 @@ func TestMatchesGitignore(t *testing.T) {
 		})
 	}
 }
+
+func TestHasFileWithExtension(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("docs"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".hidden.go"), []byte("package x"), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "pkg.go"), 0755))
+
+	t.Run("No qualifying files", func(t *testing.T) {
+		ok, err := HasFileWithExtension(dir, []string{".go"}, nil)
+		require.NoError(t, err)
+		assert.False(t, ok, "hidden files and directories must not qualify")
+	})
+
+	t.Run("Qualifying file present", func(t *testing.T) {
+		ok, err := HasFileWithExtension(dir, []string{".py", ".md"}, nil)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("Missing directory", func(t *testing.T) {
+		_, err := HasFileWithExtension(filepath.Join(dir, "missing"), []string{".go"}, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestCountGitignoredFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("*.log\n.env\n"), 0600))
+	for _, name := range []string{"main.go", "a.log", "b.log", ".env", GlanceFilename, SkipMarkerFilename} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("x"), 0600))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "c.log"), 0755))
+	gitignoreObj, err := gitignore.CompileIgnoreFile(filepath.Join(dir, ".gitignore"))
+	require.NoError(t, err)
+	chain := IgnoreChain{{OriginDir: dir, Matcher: gitignoreObj}}
+
+	total, ignored, err := CountGitignoredFiles(dir, chain)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total, "hidden files, glance files, and directories are not counted")
+	assert.Equal(t, 2, ignored)
+
+	total, ignored, err = CountGitignoredFiles(dir, chain, IncludeHidden(true))
+	require.NoError(t, err)
+	assert.Equal(t, 5, total, "included hidden files are counted")
+	assert.Equal(t, 3, ignored)
+
+	_, _, err = CountGitignoredFiles(filepath.Join(dir, "missing"), chain)
+	assert.Error(t, err)
+}
+
+func TestIncludeHidden(t *testing.T) {
+	testDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(testDir, ".gitignore"), []byte(".secret/\n.env\n"), 0600))
+	gitignoreObj, err := gitignore.CompileIgnoreFile(filepath.Join(testDir, ".gitignore"))
+	require.NoError(t, err)
+	chain := IgnoreChain{{OriginDir: testDir, Matcher: gitignoreObj}}
+
+	dir := func(name string, opts ...IgnoreOption) bool {
+		return ShouldIgnoreDir(filepath.Join(testDir, name), testDir, chain, opts...)
+	}
+	file := func(name string, opts ...IgnoreOption) bool {
+		return ShouldIgnoreFile(filepath.Join(testDir, name), testDir, chain, opts...)
+	}
+
+	assert.True(t, dir(".github"), "hidden directories are ignored by default")
+	assert.True(t, file(".editorconfig"), "hidden files are ignored by default")
+
+	assert.False(t, dir(".github", IncludeHidden(true)))
+	assert.False(t, file(".editorconfig", IncludeHidden(true)))
+	assert.True(t, dir(".github", IncludeHidden(false)))
+
+	assert.True(t, dir(GitDir, IncludeHidden(true)), "the .git directory is never included")
+	assert.True(t, file(GitDir, IncludeHidden(true)), "nor a worktree's .git file")
+	assert.True(t, file(GlanceFilename, IncludeHidden(true)), "glance output is never included")
+	assert.True(t, dir(".secret", IncludeHidden(true)), "gitignore rules still apply")
+	assert.True(t, file(".env", IncludeHidden(true)), "gitignore rules still apply")
+	assert.True(t, dir(NodeModulesDir, IncludeHidden(true)))
+}
+
+func TestExcludeDir(t *testing.T) {
+	testDir := t.TempDir()
+	outRoot := filepath.Join(testDir, "docs", "glance")
+	require.NoError(t, os.MkdirAll(filepath.Join(outRoot, "api"), 0750))
+	require.NoError(t, os.MkdirAll(filepath.Join(testDir, "api"), 0750))
+
+	assert.True(t, ShouldIgnoreDir(outRoot, filepath.Dir(outRoot), nil, ExcludeDir(outRoot)))
+	assert.True(t, ShouldIgnoreDir(filepath.Join(outRoot, "api"), outRoot, nil, ExcludeDir(outRoot)), "descendants are excluded too")
+	assert.False(t, ShouldIgnoreDir(filepath.Join(testDir, "docs"), testDir, nil, ExcludeDir(outRoot)), "ancestors are not")
+	assert.False(t, ShouldIgnoreDir(outRoot, filepath.Dir(outRoot), nil, ExcludeDir("")))
+
+	dirs, _, err := ListDirsWithIgnores(testDir, WithExcludedDir(outRoot))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{testDir, filepath.Join(testDir, "api"), filepath.Join(testDir, "docs")}, dirs)
+}
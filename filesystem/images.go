@@ -0,0 +1,130 @@
+// Package filesystem provides functionality for scanning, reading, and managing
+// filesystem operations in the glance application.
+package filesystem
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// imageMIMETypes maps the image extensions GatherImages reads to their MIME types.
+var imageMIMETypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+}
+
+// Image is an image file read so a vision model can describe it.
+type Image struct {
+	// Name is the file name, relative to its directory
+	Name string
+
+	// MIMEType is derived from the file extension, e.g. "image/png"
+	MIMEType string
+
+	// Data is the file's content
+	Data []byte
+}
+
+// GatherImages reads the image files (PNG, JPEG, GIF, and WebP) directly inside
+// dir, in name order. Hidden and ignored files are skipped, as are images over
+// maxBytes; reading stops once maxCount images have been read.
+//
+// Parameters:
+//   - dir: The directory to read images from
+//   - ignoreChain: A chain of gitignore matchers to check for ignored files
+//   - maxCount: The maximum number of images to return (0 for no limit)
+//   - maxBytes: The size in bytes above which an image is skipped (0 for no limit)
+//   - opts: Adjustments to the built-in ignore rules, such as IncludeHidden
+//
+// Returns:
+//   - The images read, or nil if there are none
+//   - An error if dir is invalid or cannot be listed
+func GatherImages(dir string, ignoreChain IgnoreChain, maxCount int, maxBytes int64, opts ...IgnoreOption) ([]Image, error) {
+	validDir, err := ValidateDirPath(dir, dir, true, true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid directory for image gathering: %w", err)
+	}
+
+	entries, err := os.ReadDir(validDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", validDir, err)
+	}
+
+	var images []Image
+	for _, entry := range entries {
+		if maxCount > 0 && len(images) >= maxCount {
+			log.WithFields(logrus.Fields{
+				"directory":  validDir,
+				"max_images": maxCount,
+			}).Debug("Image limit reached; skipping remaining images")
+			break
+		}
+
+		name := entry.Name()
+		mimeType, ok := imageMIMETypes[strings.ToLower(filepath.Ext(name))]
+		if !ok || entry.IsDir() || isIgnoredHidden(name, opts) {
+			continue
+		}
+
+		validPath, err := ValidateFilePath(filepath.Join(validDir, name), validDir, false, true)
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"path":  name,
+				"error": err,
+			}).Debug("Skipping image that failed validation")
+			continue
+		}
+		if ShouldIgnoreFile(validPath, validDir, ignoreChain, opts...) {
+			continue
+		}
+
+		data, err := readImage(validPath, maxBytes)
+		if err != nil {
+			log.WithFields(logrus.Fields{
+				"path":  validPath,
+				"error": err,
+			}).Debug("Skipping unreadable image")
+			continue
+		}
+		if data == nil {
+			log.WithFields(logrus.Fields{
+				"path":      validPath,
+				"max_bytes": maxBytes,
+			}).Debug("Skipping image over the size limit")
+			continue
+		}
+		images = append(images, Image{Name: name, MIMEType: mimeType, Data: data})
+	}
+	return images, nil
+}
+
+// readImage reads an already-validated image, returning nil data without an
+// error when the file is larger than maxBytes (0 for no limit).
+func readImage(validatedPath string, maxBytes int64) ([]byte, error) {
+	f, err := openLimited(validatedPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var r io.Reader = f
+	if maxBytes > 0 {
+		r = io.LimitReader(f, maxBytes+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if maxBytes > 0 && int64(len(data)) > maxBytes {
+		return nil, nil
+	}
+	return data, nil
+}
@@ -0,0 +1,49 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatherImages(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, size int) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), make([]byte, size), 0600))
+	}
+	write("b.PNG", 10)
+	write("a.jpg", 20)
+	write("c.webp", 30)
+	write("huge.gif", 200)
+	write("notes.txt", 10)
+	write(".hidden.png", 10)
+	write("ignored.png", 10)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "dir.png"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("ignored.png\n"), 0600))
+
+	_, chains, err := ListDirsWithIgnores(dir)
+	require.NoError(t, err)
+
+	images, err := GatherImages(dir, chains[dir], 0, 100)
+	require.NoError(t, err)
+	var names, mimeTypes []string
+	for _, img := range images {
+		names = append(names, img.Name)
+		mimeTypes = append(mimeTypes, img.MIMEType)
+	}
+	assert.Equal(t, []string{"a.jpg", "b.PNG", "c.webp"}, names, "oversized, hidden, ignored, and non-image entries are skipped")
+	assert.Equal(t, []string{"image/jpeg", "image/png", "image/webp"}, mimeTypes)
+	assert.Len(t, images[0].Data, 20)
+
+	images, err = GatherImages(dir, chains[dir], 2, 0)
+	require.NoError(t, err)
+	require.Len(t, images, 2, "the count limit applies")
+	assert.Equal(t, "a.jpg", images[0].Name)
+
+	images, err = GatherImages(t.TempDir(), nil, 5, 0)
+	require.NoError(t, err)
+	assert.Empty(t, images)
+}
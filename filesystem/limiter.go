@@ -0,0 +1,80 @@
+// Package filesystem provides functionality for scanning, reading, and managing
+// filesystem operations in the glance application.
+package filesystem
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// DefaultMaxOpenFiles is the default number of files that may be open for reading
+// at once across every goroutine in the process.
+const DefaultMaxOpenFiles = 64
+
+var (
+	// openSlotsMu guards replacement of openSlots by SetMaxOpenFiles
+	openSlotsMu sync.Mutex
+
+	// openSlots is the global semaphore shared by all file reads; each open file
+	// holds one slot until it is closed
+	openSlots = make(chan struct{}, DefaultMaxOpenFiles)
+)
+
+// osOpen opens a file for reading. It is a variable so tests can instrument opens.
+var osOpen = func(path string) (io.ReadCloser, error) {
+	// #nosec G304 -- Every caller passes a path validated with filesystem.ValidateFilePath
+	return os.Open(path)
+}
+
+// SetMaxOpenFiles sets how many files may be open for reading at once, shared by
+// every concurrent GatherLocalFiles call. Reads already holding a slot finish
+// against the previous limit.
+//
+// Parameters:
+//   - n: The maximum number of open files; values below 1 restore DefaultMaxOpenFiles
+func SetMaxOpenFiles(n int) {
+	if n < 1 {
+		n = DefaultMaxOpenFiles
+	}
+	openSlotsMu.Lock()
+	defer openSlotsMu.Unlock()
+	openSlots = make(chan struct{}, n)
+}
+
+// MaxOpenFiles returns the current limit on files open for reading at once.
+func MaxOpenFiles() int {
+	openSlotsMu.Lock()
+	defer openSlotsMu.Unlock()
+	return cap(openSlots)
+}
+
+// openLimited opens an already-validated path once a global slot is free. The slot
+// is released when the returned file is closed, or immediately if the open fails.
+func openLimited(validatedPath string) (io.ReadCloser, error) {
+	openSlotsMu.Lock()
+	slots := openSlots
+	openSlotsMu.Unlock()
+
+	slots <- struct{}{}
+	f, err := osOpen(validatedPath)
+	if err != nil {
+		<-slots
+		return nil, err
+	}
+	return &limitedFile{ReadCloser: f, slots: slots}, nil
+}
+
+// limitedFile releases its semaphore slot exactly once when closed.
+type limitedFile struct {
+	io.ReadCloser
+	slots chan struct{}
+	once  sync.Once
+}
+
+// Close closes the underlying file and frees its slot.
+func (f *limitedFile) Close() error {
+	err := f.ReadCloser.Close()
+	f.once.Do(func() { <-f.slots })
+	return err
+}
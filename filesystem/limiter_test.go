@@ -0,0 +1,143 @@
+package filesystem
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openCounter is an instrumented opener that tracks how many files are open at once.
+type openCounter struct {
+	open atomic.Int32
+	peak atomic.Int32
+}
+
+func (c *openCounter) opener(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path) // #nosec G304 -- test paths come from t.TempDir
+	if err != nil {
+		return nil, err
+	}
+	n := c.open.Add(1)
+	for {
+		peak := c.peak.Load()
+		if n <= peak || c.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond) // widen the window so overlapping opens are observed
+	return &countedFile{ReadCloser: f, counter: c}, nil
+}
+
+type countedFile struct {
+	io.ReadCloser
+	counter *openCounter
+}
+
+func (f *countedFile) Close() error {
+	f.counter.open.Add(-1)
+	return f.ReadCloser.Close()
+}
+
+// instrumentOpens installs an openCounter and open-file limit for the duration of a test.
+func instrumentOpens(t *testing.T, limit int) *openCounter {
+	t.Helper()
+	counter := &openCounter{}
+	origOpen := osOpen
+	osOpen = counter.opener
+	SetMaxOpenFiles(limit)
+	t.Cleanup(func() {
+		osOpen = origOpen
+		SetMaxOpenFiles(DefaultMaxOpenFiles)
+	})
+	return counter
+}
+
+func writeTextFiles(t *testing.T, dir string, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		name := filepath.Join(dir, fmt.Sprintf("file%02d.txt", i))
+		require.NoError(t, os.WriteFile(name, []byte(fmt.Sprintf("content %d\n", i)), 0600))
+	}
+}
+
+func TestGatherLocalFilesRespectsMaxOpenFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTextFiles(t, dir, 20)
+	counter := instrumentOpens(t, 2)
+
+	files, err := GatherLocalFiles(dir, nil, 1024)
+	require.NoError(t, err)
+
+	assert.Len(t, files, 20)
+	assert.Equal(t, "content 7\n", files["file07.txt"])
+	assert.LessOrEqual(t, counter.peak.Load(), int32(2), "never more files open than the limit")
+	assert.Zero(t, counter.open.Load(), "every file is closed")
+}
+
+// TestGatherLocalFilesSharedLimitAcrossDirectories is meant to run under -race:
+// several directories gather in parallel against one global limit.
+func TestGatherLocalFilesSharedLimitAcrossDirectories(t *testing.T) {
+	const dirCount = 6
+	dirs := make([]string, dirCount)
+	for i := range dirs {
+		dirs[i] = t.TempDir()
+		writeTextFiles(t, dirs[i], 10)
+	}
+	counter := instrumentOpens(t, 3)
+
+	var wg sync.WaitGroup
+	results := make([]map[string]string, dirCount)
+	errs := make([]error, dirCount)
+	for i, dir := range dirs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = GatherLocalFiles(dir, nil, 1024)
+		}()
+	}
+	wg.Wait()
+
+	for i := range dirs {
+		require.NoError(t, errs[i])
+		assert.Len(t, results[i], 10)
+	}
+	assert.LessOrEqual(t, counter.peak.Load(), int32(3), "the limit is global, not per directory")
+	assert.Zero(t, counter.open.Load())
+}
+
+func TestSetMaxOpenFiles(t *testing.T) {
+	t.Cleanup(func() { SetMaxOpenFiles(DefaultMaxOpenFiles) })
+
+	assert.Equal(t, DefaultMaxOpenFiles, MaxOpenFiles())
+
+	SetMaxOpenFiles(8)
+	assert.Equal(t, 8, MaxOpenFiles())
+
+	SetMaxOpenFiles(0)
+	assert.Equal(t, DefaultMaxOpenFiles, MaxOpenFiles(), "values below 1 restore the default")
+}
+
+func TestOpenLimitedReleasesSlotOnError(t *testing.T) {
+	instrumentOpens(t, 1)
+
+	_, err := openLimited(filepath.Join(t.TempDir(), "missing.txt"))
+	require.Error(t, err)
+
+	// With a limit of one, a leaked slot would block this open forever
+	f, err := openLimited(os.DevNull)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	_ = f.Close() // a second close must not release a second slot
+
+	f, err = openLimited(os.DevNull)
+	require.NoError(t, err)
+	assert.NoError(t, f.Close())
+}
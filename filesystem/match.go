@@ -0,0 +1,78 @@
+// Package filesystem provides functionality for scanning, reading, and managing
+// filesystem operations in the glance application.
+package filesystem
+
+import (
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+// caseSensitive controls extension and filename matching. Its zero value keeps
+// matching case-insensitive, which is the package default.
+var caseSensitive atomic.Bool
+
+// SetCaseInsensitive sets whether extension and filename rules ignore case
+// (e.g. whether ".go" matches "MAIN.GO"). Matching is case-insensitive by default.
+//
+// Parameters:
+//   - enabled: true to ignore case, false to require an exact match
+func SetCaseInsensitive(enabled bool) {
+	caseSensitive.Store(!enabled)
+}
+
+// CaseInsensitive reports whether extension and filename rules currently ignore case.
+func CaseInsensitive() bool {
+	return !caseSensitive.Load()
+}
+
+// normalizeForMatch lowercases s when matching is case-insensitive.
+func normalizeForMatch(s string) string {
+	if CaseInsensitive() {
+		return strings.ToLower(s)
+	}
+	return s
+}
+
+// MatchesExtension reports whether the file name's extension is one of exts.
+// Extensions are compared including the leading dot (e.g. ".go"), honoring
+// the package case-sensitivity setting.
+//
+// Parameters:
+//   - name: The file name or path to check
+//   - exts: The extensions to match against
+//
+// Returns:
+//   - true if the extension matches one of exts
+func MatchesExtension(name string, exts []string) bool {
+	ext := filepath.Ext(name)
+	if ext == "" {
+		return false
+	}
+	ext = normalizeForMatch(ext)
+	for _, candidate := range exts {
+		if normalizeForMatch(candidate) == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesName reports whether the base name of a file equals one of names,
+// honoring the package case-sensitivity setting (e.g. "Dockerfile" and "dockerfile").
+//
+// Parameters:
+//   - name: The file name or path to check
+//   - names: The file names to match against
+//
+// Returns:
+//   - true if the base name matches one of names
+func MatchesName(name string, names ...string) bool {
+	base := normalizeForMatch(filepath.Base(name))
+	for _, candidate := range names {
+		if normalizeForMatch(candidate) == base {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withCaseInsensitive sets the package matching mode for the duration of a test.
+func withCaseInsensitive(t *testing.T, enabled bool) {
+	t.Helper()
+	orig := CaseInsensitive()
+	SetCaseInsensitive(enabled)
+	t.Cleanup(func() { SetCaseInsensitive(orig) })
+}
+
+func TestMatchesExtension(t *testing.T) {
+	assert.True(t, CaseInsensitive(), "matching should be case-insensitive by default")
+
+	t.Run("Case-insensitive", func(t *testing.T) {
+		withCaseInsensitive(t, true)
+		assert.True(t, MatchesExtension("MAIN.GO", []string{".go"}))
+		assert.True(t, MatchesExtension("docs/Readme.Md", []string{".md"}))
+		assert.True(t, MatchesExtension("main.go", []string{".GO"}))
+		assert.False(t, MatchesExtension("main.golang", []string{".go"}))
+		assert.False(t, MatchesExtension("Makefile", []string{".go"}))
+	})
+
+	t.Run("Case-sensitive opt-out", func(t *testing.T) {
+		withCaseInsensitive(t, false)
+		assert.False(t, MatchesExtension("MAIN.GO", []string{".go"}))
+		assert.True(t, MatchesExtension("main.go", []string{".go"}))
+	})
+}
+
+func TestMatchesName(t *testing.T) {
+	t.Run("Case-insensitive", func(t *testing.T) {
+		withCaseInsensitive(t, true)
+		assert.True(t, MatchesName("dockerfile", "Dockerfile"))
+		assert.True(t, MatchesName("build/DOCKERFILE", "Dockerfile"))
+		assert.True(t, MatchesName(".GLANCE.md", GlanceFilename, LegacyGlanceFilename))
+		assert.False(t, MatchesName("Dockerfile.dev", "Dockerfile"))
+	})
+
+	t.Run("Case-sensitive opt-out", func(t *testing.T) {
+		withCaseInsensitive(t, false)
+		assert.False(t, MatchesName("dockerfile", "Dockerfile"))
+		assert.True(t, MatchesName("Dockerfile", "Dockerfile"))
+	})
+}
+
+func TestHasFileWithExtensionMixedCase(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Server.GO"), []byte("package server"), 0600))
+
+	withCaseInsensitive(t, true)
+	found, err := HasFileWithExtension(dir, []string{".go"}, nil)
+	require.NoError(t, err)
+	assert.True(t, found, "upper-case extension should match its lowercase rule")
+
+	SetCaseInsensitive(false)
+	found, err = HasFileWithExtension(dir, []string{".go"}, nil)
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestGatherLocalFilesSkipsMixedCaseGlanceOutput(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "GLANCE.MD"), []byte("# old summary"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0600))
+
+	withCaseInsensitive(t, true)
+	files, err := GatherLocalFiles(dir, nil, 0)
+	require.NoError(t, err)
+	assert.Contains(t, files, "main.go")
+	assert.NotContains(t, files, "GLANCE.MD", "a glance output written on a case-insensitive filesystem must not be re-read")
+}
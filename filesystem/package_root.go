@@ -0,0 +1,34 @@
+// Package filesystem provides functionality for scanning, reading, and managing
+// filesystem operations in the glance application.
+package filesystem
+
+import (
+	"path/filepath"
+)
+
+// PackageManifests are the files that mark a directory as a package root, in the
+// order IsPackageRoot checks them.
+var PackageManifests = []string{
+	"package.json",
+	"go.mod",
+	"Cargo.toml",
+	"pyproject.toml",
+}
+
+// IsPackageRoot reports whether dir is the root of a package, such as one member
+// of a monorepo, by looking for a manifest file directly inside it.
+//
+// Parameters:
+//   - dir: The directory to inspect
+//
+// Returns:
+//   - true if dir contains one of PackageManifests as a regular file
+//   - The name of the first manifest found (e.g. "go.mod"), or "" if none
+func IsPackageRoot(dir string) (bool, string) {
+	for _, manifest := range PackageManifests {
+		if _, err := ValidateFilePath(filepath.Join(dir, manifest), dir, false, true); err == nil {
+			return true, manifest
+		}
+	}
+	return false, ""
+}
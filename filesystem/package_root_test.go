@@ -0,0 +1,58 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPackageRoot(t *testing.T) {
+	for _, manifest := range PackageManifests {
+		t.Run(manifest, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, manifest), []byte("{}\n"), 0600))
+
+			isRoot, found := IsPackageRoot(dir)
+			assert.True(t, isRoot)
+			assert.Equal(t, manifest, found)
+		})
+	}
+
+	t.Run("plain directory", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0600))
+
+		isRoot, found := IsPackageRoot(dir)
+		assert.False(t, isRoot)
+		assert.Empty(t, found)
+	})
+
+	t.Run("manifest must be a file", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "package.json"), 0755))
+
+		isRoot, _ := IsPackageRoot(dir)
+		assert.False(t, isRoot)
+	})
+
+	t.Run("manifest in a subdirectory does not count", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "web"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "web", "package.json"), []byte("{}\n"), 0600))
+
+		isRoot, _ := IsPackageRoot(dir)
+		assert.False(t, isRoot)
+	})
+
+	t.Run("first manifest wins", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "pyproject.toml"), []byte("[project]\n"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte("{}\n"), 0600))
+
+		_, found := IsPackageRoot(dir)
+		assert.Equal(t, "package.json", found)
+	})
+}
@@ -79,6 +79,13 @@ func TestValidatePathWithinBase(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, filepath.Clean(testFile), filepath.Clean(validPath))
 	})
+
+	t.Run("Filesystem root as base", func(t *testing.T) {
+		root := string(os.PathSeparator)
+		validPath, err := ValidatePathWithinBase(testFile, root, true)
+		assert.NoError(t, err, "every absolute path lies within the filesystem root")
+		assert.Equal(t, filepath.Clean(testFile), validPath)
+	})
 }
 
 func TestValidateFilePath(t *testing.T) {
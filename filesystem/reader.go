@@ -10,7 +10,11 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/sirupsen/logrus"
 )
@@ -30,36 +34,104 @@ const MaxDefaultFileSize = 5 * 1024 * 1024
 //   - The contents of the file as a string
 //   - An error, if any occurred during reading or validation
 func ReadTextFile(path string, maxBytes int64, baseDir string) (string, error) {
-	var validatedPath string
+	return readTextFile(path, maxBytes, baseDir, false)
+}
 
+// readTextFile implements ReadTextFile. With detectEncoding, content is
+// transcoded to UTF-8 by DecodeText before truncation instead of having
+// invalid UTF-8 replaced.
+func readTextFile(path string, maxBytes int64, baseDir string, detectEncoding bool) (string, error) {
+	content, validatedPath, err := readValidatedFile(path, baseDir)
+	if err != nil {
+		return "", err
+	}
+
+	var contentStr string
+	if detectEncoding {
+		var detected string
+		contentStr, detected = DecodeText(content)
+		if detected != EncodingUTF8 {
+			log.WithFields(logrus.Fields{
+				"file":     validatedPath,
+				"encoding": detected,
+			}).Debug("Transcoded file to UTF-8")
+		}
+	} else {
+		// Validate UTF-8 by replacing invalid sequences with the replacement character
+		contentStr = strings.ToValidUTF8(string(content), "�")
+	}
+
+	// Truncate if needed
+	if maxBytes > 0 && int64(len(contentStr)) > maxBytes {
+		contentStr = TruncateContent(contentStr, maxBytes)
+	}
+
+	return contentStr, nil
+}
+
+// ReadUTF8File reads a file like ReadTextFile, without a size limit, but
+// rejects invalid UTF-8 instead of replacing it.
+//
+// Parameters:
+//   - path: The absolute path to the file to read
+//   - baseDir: Base directory for path validation. Must be non-empty for proper security validation.
+//
+// Returns:
+//   - The contents of the file as a string
+//   - An error if the path is invalid, reading fails, or the content is not
+//     valid UTF-8, naming the byte offset of the first invalid sequence
+func ReadUTF8File(path string, baseDir string) (string, error) {
+	content, _, err := readValidatedFile(path, baseDir)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(content) {
+		return "", fmt.Errorf("content is not valid UTF-8 (invalid byte sequence at offset %d)", invalidUTF8Offset(content))
+	}
+	return string(content), nil
+}
+
+// readValidatedFile validates path against baseDir and reads it, holding a
+// global open-file slot. It returns the content and the validated path.
+func readValidatedFile(path string, baseDir string) ([]byte, string, error) {
 	// A non-empty baseDir is required for proper validation
 	if baseDir == "" {
-		return "", errors.New("baseDir cannot be empty for validation")
+		return nil, "", errors.New("baseDir cannot be empty for validation")
 	}
 
 	// Validate path with the provided baseDir
-	var err error
-	validatedPath, err = ValidateFilePath(path, baseDir, true, true)
+	validatedPath, err := ValidateFilePath(path, baseDir, true, true)
 	if err != nil {
-		return "", fmt.Errorf("path validation failed: %w", err)
+		return nil, "", fmt.Errorf("path validation failed: %w", err)
 	}
 
-	// Read the file with validated path
-	// #nosec G304 -- Path has been validated using filesystem.ValidateFilePath
-	content, err := os.ReadFile(validatedPath)
+	// Read the file with validated path, holding a global open-file slot
+	f, err := openLimited(validatedPath)
 	if err != nil {
-		return "", err
+		return nil, "", err
 	}
+	defer func() {
+		_ = f.Close() // explicitly ignore the error as we're in a read-only context
+	}()
 
-	// Validate UTF-8 by replacing invalid sequences with the replacement character
-	contentStr := strings.ToValidUTF8(string(content), "�")
-
-	// Truncate if needed
-	if maxBytes > 0 && int64(len(contentStr)) > maxBytes {
-		contentStr = TruncateContent(contentStr, maxBytes)
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, "", err
 	}
+	return content, validatedPath, nil
+}
 
-	return contentStr, nil
+// invalidUTF8Offset returns the byte offset of the first invalid UTF-8
+// sequence in content, or len(content) if there is none.
+func invalidUTF8Offset(content []byte) int {
+	for offset := 0; offset < len(content); {
+		r, size := utf8.DecodeRune(content[offset:])
+		if r == utf8.RuneError && size <= 1 {
+			return offset
+		}
+		offset += size
+	}
+	return len(content)
 }
 
 // TruncateContent truncates a string to a maximum size in bytes and
@@ -86,6 +158,68 @@ func TruncateContent(content string, maxBytes int64) string {
 	return content[:maxBytes] + "...(truncated)"
 }
 
+// omissionMarker separates the slices kept by SampleContent.
+const omissionMarker = "\n\n... [%d bytes omitted] ...\n\n"
+
+// SampleContent shortens content to at most budget bytes by keeping a head, a
+// middle sample, and a tail, separated by markers stating how many bytes were
+// omitted. Unlike TruncateContent it preserves the end of the file. Slices never
+// split a UTF-8 sequence.
+//
+// Parameters:
+//   - content: The string to sample
+//   - budget: The maximum number of bytes to return, markers included (0 or
+//     negative for no limit)
+//
+// Returns:
+//   - content unchanged if it fits the budget, otherwise the sampled content
+func SampleContent(content string, budget int64) string {
+	if budget <= 0 || int64(len(content)) <= budget {
+		return content
+	}
+
+	// Reserve room for two markers at their widest possible count
+	markerLen := int64(len(fmt.Sprintf(omissionMarker, len(content))))
+	available := budget - 2*markerLen
+	if available < 3 {
+		// Too small to sample meaningfully; keep as much of the head as fits
+		return content[:runeStartBefore(content, int(budget))]
+	}
+
+	headLen := available * 2 / 5
+	midLen := available / 5
+	tailLen := available - headLen - midLen
+
+	headEnd := runeStartBefore(content, int(headLen))
+	midStart := runeStartAfter(content, int((int64(len(content))-midLen)/2))
+	midEnd := runeStartBefore(content, midStart+int(midLen))
+	tailStart := runeStartAfter(content, len(content)-int(tailLen))
+
+	var b strings.Builder
+	b.WriteString(content[:headEnd])
+	fmt.Fprintf(&b, omissionMarker, midStart-headEnd)
+	b.WriteString(content[midStart:midEnd])
+	fmt.Fprintf(&b, omissionMarker, tailStart-midEnd)
+	b.WriteString(content[tailStart:])
+	return b.String()
+}
+
+// runeStartBefore returns the largest index <= i that begins a UTF-8 sequence.
+func runeStartBefore(s string, i int) int {
+	for i > 0 && i < len(s) && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return i
+}
+
+// runeStartAfter returns the smallest index >= i that begins a UTF-8 sequence.
+func runeStartAfter(s string, i int) int {
+	for i < len(s) && !utf8.RuneStart(s[i]) {
+		i++
+	}
+	return i
+}
+
 // IsTextFile checks if a file's content type indicates it is a text-based file
 // by reading its first 512 bytes.
 //
@@ -111,9 +245,8 @@ func IsTextFile(path string, baseDir string) (bool, error) {
 		return false, fmt.Errorf("path validation failed: %w", err)
 	}
 
-	// Open the file with validated path
-	// #nosec G304 -- Path has been validated using filesystem.ValidateFilePath
-	f, err := os.Open(validatedPath)
+	// Open the file with validated path, holding a global open-file slot
+	f, err := openLimited(validatedPath)
 	if err != nil {
 		return false, err
 	}
@@ -128,31 +261,137 @@ func IsTextFile(path string, baseDir string) (bool, error) {
 		return false, err
 	}
 
-	ctype := http.DetectContentType(buf[:n])
-	if strings.HasPrefix(ctype, "text/") ||
+	return isTextContentType(http.DetectContentType(buf[:n])), nil
+}
+
+// isTextContentType reports whether a sniffed MIME type denotes text-based content.
+func isTextContentType(ctype string) bool {
+	return strings.HasPrefix(ctype, "text/") ||
 		strings.HasPrefix(ctype, "application/json") ||
 		strings.HasPrefix(ctype, "application/xml") ||
-		strings.Contains(ctype, "yaml") {
-		return true, nil
+		strings.Contains(ctype, "yaml")
+}
+
+// GatherOption configures optional behavior of GatherLocalFiles.
+type GatherOption func(*gatherOptions)
+
+// gatherOptions holds the optional settings applied by GatherOption values.
+type gatherOptions struct {
+	readCompressed   bool
+	maxFileAge       time.Duration
+	sampleLargeFiles bool
+	skipGenerated    bool
+	detectEncoding   bool
+	includeHidden    bool
+	skipped          *[]SkippedFile
+}
+
+// SkippedFile is a file GatherLocalFiles could not validate or read. Files left
+// out on purpose (ignored, hidden, binary, or too old) are never reported.
+type SkippedFile struct {
+	Path string // path relative to the gathered directory, or absolute if that failed
+	Err  error
+}
+
+// Error describes the skipped file and why it was skipped.
+func (s SkippedFile) Error() string {
+	return fmt.Sprintf("%s: %v", s.Path, s.Err)
+}
+
+// Unwrap returns the underlying validation or read error.
+func (s SkippedFile) Unwrap() error {
+	return s.Err
+}
+
+// WithReadCompressed enables transparent decompression of gzip (.gz) files so that
+// compressed text is included alongside plain text files.
+func WithReadCompressed(enabled bool) GatherOption {
+	return func(o *gatherOptions) {
+		o.readCompressed = enabled
+	}
+}
+
+// WithMaxFileAge skips files whose modification time is older than maxAge
+// relative to the start of gathering. Zero (or a negative value) disables the filter.
+func WithMaxFileAge(maxAge time.Duration) GatherOption {
+	return func(o *gatherOptions) {
+		o.maxFileAge = maxAge
 	}
+}
 
-	return false, nil
+// WithSkippedFiles appends every file that could not be validated or read to
+// *skipped, sorted by path, so callers can escalate failures that are otherwise
+// only logged at debug level.
+func WithSkippedFiles(skipped *[]SkippedFile) GatherOption {
+	return func(o *gatherOptions) {
+		o.skipped = skipped
+	}
+}
+
+// WithSampleLargeFiles makes files over the size limit keep a head, middle, and
+// tail sample (see SampleContent) instead of being truncated. Compressed files
+// are still truncated, since their decompressed size is bounded for safety.
+func WithSampleLargeFiles(enabled bool) GatherOption {
+	return func(o *gatherOptions) {
+		o.sampleLargeFiles = enabled
+	}
+}
+
+// WithSkipGenerated leaves out files that IsLikelyGenerated flags as generated
+// or minified, since they spend prompt tokens without helping the summary.
+func WithSkipGenerated(enabled bool) GatherOption {
+	return func(o *gatherOptions) {
+		o.skipGenerated = enabled
+	}
+}
+
+// WithDetectEncoding transcodes files that are not UTF-8 (UTF-16 with a byte
+// order mark, or Latin-1) to UTF-8 instead of replacing their invalid bytes.
+// See DecodeText.
+func WithDetectEncoding(enabled bool) GatherOption {
+	return func(o *gatherOptions) {
+		o.detectEncoding = enabled
+	}
+}
+
+// WithHiddenFiles gathers hidden files too (see IncludeHidden). glance output
+// files and gitignored files are still left out.
+func WithHiddenFiles(enabled bool) GatherOption {
+	return func(o *gatherOptions) {
+		o.includeHidden = enabled
+	}
 }
 
 // GatherLocalFiles reads immediate files in a directory and returns a map of
 // relative path to file content for text-based files.
 // It includes path validation to prevent path traversal vulnerabilities.
+// Files are read concurrently, bounded by the process-wide SetMaxOpenFiles limit.
 //
 // Parameters:
 //   - dir: The directory to scan for files
 //   - ignoreChain: A chain of gitignore matchers to check for ignored files
 //   - maxFileBytes: The maximum number of bytes to read from each file
+//   - options: Optional behaviors such as WithReadCompressed, WithMaxFileAge, WithSampleLargeFiles,
+//     WithSkipGenerated, WithDetectEncoding, and WithSkippedFiles
 //
 // Returns:
 //   - A map of relative file paths to their contents as strings
 //   - An error, if any occurred during scanning or reading
-func GatherLocalFiles(dir string, ignoreChain IgnoreChain, maxFileBytes int64) (map[string]string, error) {
+func GatherLocalFiles(dir string, ignoreChain IgnoreChain, maxFileBytes int64, options ...GatherOption) (map[string]string, error) {
+	var opts gatherOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	ignoreOpts := []IgnoreOption{IncludeHidden(opts.includeHidden)}
+
 	files := make(map[string]string)
+	var candidates []gatherCandidate
+	var skipped []SkippedFile
+
+	var ageCutoff time.Time
+	if opts.maxFileAge > 0 {
+		ageCutoff = time.Now().Add(-opts.maxFileAge)
+	}
 
 	// Clean and normalize the directory path
 	cleanDir := filepath.Clean(dir)
@@ -184,8 +423,8 @@ func GatherLocalFiles(dir string, ignoreChain IgnoreChain, maxFileBytes int64) (
 			return fs.SkipDir
 		}
 
-		// Skip directories, glance output files, and hidden files
-		if d.IsDir() || d.Name() == GlanceFilename || d.Name() == LegacyGlanceFilename || strings.HasPrefix(d.Name(), ".") {
+		// Skip directories, glance output files, and hidden files (unless included)
+		if d.IsDir() || MatchesName(d.Name(), GlanceFilename, LegacyGlanceFilename) || isIgnoredHidden(d.Name(), ignoreOpts) {
 			return nil
 		}
 
@@ -198,6 +437,7 @@ func GatherLocalFiles(dir string, ignoreChain IgnoreChain, maxFileBytes int64) (
 				"path":  path,
 				"error": err,
 			}).Debug("Path validation failed")
+			skipped = append(skipped, SkippedFile{Path: d.Name(), Err: err})
 			return nil
 		}
 
@@ -209,46 +449,137 @@ func GatherLocalFiles(dir string, ignoreChain IgnoreChain, maxFileBytes int64) (
 				"base_dir": validDir,
 				"error":    err,
 			}).Debug("Error calculating relative path")
+			skipped = append(skipped, SkippedFile{Path: validPath, Err: err})
 			return nil
 		}
 
 		// Check if the file should be ignored using the standardized function
-		if ShouldIgnoreFile(validPath, validDir, ignoreChain) {
+		if ShouldIgnoreFile(validPath, validDir, ignoreChain, ignoreOpts...) {
 			log.WithField("file", relPath).Debug("Ignoring file")
 			return nil
 		}
 
-		// Check if file is text-based (pass base directory for validation)
-		isText, errCheck := IsTextFile(validPath, validDir)
-		if errCheck != nil {
-			log.WithFields(logrus.Fields{
-				"file":  validPath,
-				"error": errCheck,
-			}).Debug("Error checking if file is text")
+		// Skip stale files when a maximum age is set
+		if !ageCutoff.IsZero() {
+			fileInfo, infoErr := d.Info()
+			if infoErr != nil {
+				log.WithFields(logrus.Fields{
+					"file":  validPath,
+					"error": infoErr,
+				}).Debug("Error reading file info")
+				skipped = append(skipped, SkippedFile{Path: relPath, Err: infoErr})
+				return nil
+			}
+			if fileInfo.ModTime().Before(ageCutoff) {
+				log.WithFields(logrus.Fields{
+					"file":     relPath,
+					"mod_time": fileInfo.ModTime(),
+				}).Debug("Skipping file older than max file age")
+				return nil
+			}
 		}
 
-		if !isText {
-			log.WithField("file", validPath).Debug("Skipping binary/non-text file")
-			return nil
-		}
+		candidates = append(candidates, gatherCandidate{path: validPath, relPath: relPath})
+		return nil
+	})
 
-		// Read file content (pass base directory for validation)
-		content, err := ReadTextFile(validPath, maxFileBytes, validDir)
-		if err != nil {
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the candidates concurrently; the global open-file limit (see
+	// SetMaxOpenFiles) bounds how many are open at once across all directories.
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, c := range candidates {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			content, ok, readErr := readGatheredFile(c.path, validDir, maxFileBytes, opts)
+			if ok && opts.skipGenerated && IsLikelyGenerated(c.relPath, content) {
+				log.WithField("file", c.path).Debug("Skipping likely generated or minified file")
+				ok = false
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if readErr != nil {
+				skipped = append(skipped, SkippedFile{Path: c.relPath, Err: readErr})
+			}
+			if ok {
+				files[c.relPath] = content
+			}
+		}()
+	}
+	wg.Wait()
+
+	if opts.skipped != nil {
+		sort.Slice(skipped, func(i, j int) bool { return skipped[i].Path < skipped[j].Path })
+		*opts.skipped = append(*opts.skipped, skipped...)
+	}
+
+	return files, nil
+}
+
+// gatherCandidate is a file that passed GatherLocalFiles' name, ignore, and age filters.
+type gatherCandidate struct {
+	path    string
+	relPath string
+}
+
+// readGatheredFile reads one gathered file, returning false when it is binary or
+// unreadable. Failures are logged at debug level and returned for WithSkippedFiles
+// rather than failing the directory.
+func readGatheredFile(validPath, validDir string, maxFileBytes int64, opts gatherOptions) (string, bool, error) {
+	// Compressed files are sniffed after decompression, so they bypass IsTextFile
+	if opts.readCompressed && IsGzipFile(validPath) {
+		content, isText, gzErr := ReadGzipTextFile(validPath, maxFileBytes, validDir)
+		if gzErr != nil {
 			log.WithFields(logrus.Fields{
 				"file":  validPath,
-				"error": err,
-			}).Debug("Error reading file")
-			return nil
+				"error": gzErr,
+			}).Debug("Error reading compressed file")
+			return "", false, gzErr
 		}
+		if !isText {
+			log.WithField("file", validPath).Debug("Skipping compressed binary/non-text file")
+			return "", false, nil
+		}
+		return content, true, nil
+	}
 
-		files[relPath] = content
-		return nil
-	})
+	// Check if file is text-based (pass base directory for validation)
+	isText, errCheck := IsTextFile(validPath, validDir)
+	if errCheck != nil {
+		log.WithFields(logrus.Fields{
+			"file":  validPath,
+			"error": errCheck,
+		}).Debug("Error checking if file is text")
+		return "", false, errCheck
+	}
+
+	if !isText {
+		log.WithField("file", validPath).Debug("Skipping binary/non-text file")
+		return "", false, nil
+	}
 
+	// Read file content (pass base directory for validation)
+	readLimit := maxFileBytes
+	if opts.sampleLargeFiles {
+		readLimit = 0 // read everything, then sample down to maxFileBytes
+	}
+	content, err := readTextFile(validPath, readLimit, validDir, opts.detectEncoding)
 	if err != nil {
-		return nil, err
+		log.WithFields(logrus.Fields{
+			"file":  validPath,
+			"error": err,
+		}).Debug("Error reading file")
+		return "", false, err
+	}
+	if opts.sampleLargeFiles {
+		content = SampleContent(content, maxFileBytes)
 	}
 
-	return files, nil
+	return content, true, nil
 }
@@ -1,10 +1,19 @@
 package filesystem
 
 import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	gitignore "github.com/sabhiram/go-gitignore"
 	"github.com/stretchr/testify/assert"
@@ -346,3 +355,226 @@ func TestGatherLocalFiles(t *testing.T) {
 		assert.Empty(t, results, "Empty directory should return empty results map")
 	})
 }
+
+// writeGzip writes data to path as a gzip stream.
+func writeGzip(t *testing.T, path string, data []byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+}
+
+func TestGatherLocalFilesCompressed(t *testing.T) {
+	testDir := t.TempDir()
+
+	writeGzip(t, filepath.Join(testDir, "notes.md.gz"), []byte("# Notes\nCompressed reference text."))
+	writeGzip(t, filepath.Join(testDir, "blob.bin.gz"), []byte{0, 1, 2, 3, 0xff, 0xfe, 0, 0})
+	require.NoError(t, os.WriteFile(filepath.Join(testDir, "plain.txt"), []byte("plain"), 0644))
+
+	t.Run("Compressed files skipped by default", func(t *testing.T) {
+		results, err := GatherLocalFiles(testDir, nil, 0)
+		require.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Contains(t, results, "plain.txt")
+	})
+
+	t.Run("Compressed text included when enabled", func(t *testing.T) {
+		results, err := GatherLocalFiles(testDir, nil, 0, WithReadCompressed(true))
+		require.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.Equal(t, "# Notes\nCompressed reference text.", results["notes.md.gz"])
+		assert.NotContains(t, results, "blob.bin.gz", "compressed binary should still be skipped")
+	})
+
+	t.Run("Truncation applies to decompressed content", func(t *testing.T) {
+		results, err := GatherLocalFiles(testDir, nil, 7, WithReadCompressed(true))
+		require.NoError(t, err)
+		assert.Equal(t, "# Notes...(truncated)", results["notes.md.gz"])
+	})
+
+	t.Run("Corrupt gzip is skipped", func(t *testing.T) {
+		corruptDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(corruptDir, "bad.txt.gz"), []byte("not gzip"), 0644))
+
+		results, err := GatherLocalFiles(corruptDir, nil, 0, WithReadCompressed(true))
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
+
+func TestGatherLocalFilesMaxFileAge(t *testing.T) {
+	testDir := t.TempDir()
+	oldPath := filepath.Join(testDir, "stale.go")
+	require.NoError(t, os.WriteFile(oldPath, []byte("package stale"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(testDir, "fresh.go"), []byte("package fresh"), 0644))
+	backdated := time.Now().Add(-3 * 365 * 24 * time.Hour)
+	require.NoError(t, os.Chtimes(oldPath, backdated, backdated))
+
+	t.Run("Zero age includes every file", func(t *testing.T) {
+		results, err := GatherLocalFiles(testDir, nil, 0, WithMaxFileAge(0))
+		require.NoError(t, err)
+		assert.Len(t, results, 2)
+	})
+
+	t.Run("Old files are excluded", func(t *testing.T) {
+		results, err := GatherLocalFiles(testDir, nil, 0, WithMaxFileAge(365*24*time.Hour))
+		require.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Contains(t, results, "fresh.go")
+		assert.NotContains(t, results, "stale.go")
+	})
+
+	t.Run("Threshold older than every file keeps them all", func(t *testing.T) {
+		results, err := GatherLocalFiles(testDir, nil, 0, WithMaxFileAge(10*365*24*time.Hour))
+		require.NoError(t, err)
+		assert.Len(t, results, 2)
+	})
+}
+
+func TestGatherLocalFilesHiddenFiles(t *testing.T) {
+	testDir := t.TempDir()
+	for name, content := range map[string]string{
+		"main.go":        "package main",
+		".editorconfig":  "root = true",
+		".git":           "gitdir: ../.git/worktrees/x",
+		GlanceFilename:   "# summary",
+		".golangci.yml":  "linters: {}",
+		"notes.tmp":      "scratch",
+		".gitattributes": "* text=auto",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(testDir, name), []byte(content), 0600))
+	}
+
+	results, err := GatherLocalFiles(testDir, nil, 0)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"main.go", "notes.tmp"}, slices.Collect(maps.Keys(results)))
+
+	results, err = GatherLocalFiles(testDir, nil, 0, WithHiddenFiles(true))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"main.go", "notes.tmp", ".editorconfig", ".golangci.yml", ".gitattributes"}, slices.Collect(maps.Keys(results)),
+		"hidden files are gathered, but not .git or glance output")
+}
+
+func TestSampleContent(t *testing.T) {
+	t.Run("Content within budget is unchanged", func(t *testing.T) {
+		assert.Equal(t, "short", SampleContent("short", 10))
+		assert.Equal(t, "exact", SampleContent("exact", 5))
+		assert.Equal(t, "no limit", SampleContent("no limit", 0))
+	})
+
+	t.Run("Keeps head, middle, and tail within budget", func(t *testing.T) {
+		var b strings.Builder
+		b.WriteString("HEAD-MARKER\n")
+		for i := 0; i < 2000; i++ {
+			b.WriteString("line of filler text\n")
+		}
+		b.WriteString("TAIL-MARKER")
+		content := b.String()
+
+		for _, budget := range []int64{200, 1000, 4096} {
+			sampled := SampleContent(content, budget)
+			assert.LessOrEqual(t, int64(len(sampled)), budget)
+			assert.True(t, strings.HasPrefix(sampled, "HEAD-MARKER"), "head should be preserved")
+			assert.True(t, strings.HasSuffix(sampled, "TAIL-MARKER"), "tail should be preserved")
+			assert.Equal(t, 2, strings.Count(sampled, "bytes omitted"), "two omission markers expected")
+		}
+	})
+
+	t.Run("Omitted counts account for every byte", func(t *testing.T) {
+		content := strings.Repeat("abcdefghij", 100)
+		sampled := SampleContent(content, 300)
+
+		var omittedHead, omittedTail int
+		parts := strings.Split(sampled, "\n\n... [")
+		require.Len(t, parts, 3)
+		_, err := fmt.Sscanf(parts[1], "%d bytes omitted", &omittedHead)
+		require.NoError(t, err)
+		_, err = fmt.Sscanf(parts[2], "%d bytes omitted", &omittedTail)
+		require.NoError(t, err)
+
+		kept := len(parts[0]) + len(strings.SplitN(parts[1], "...\n\n", 2)[1]) + len(strings.SplitN(parts[2], "...\n\n", 2)[1])
+		assert.Equal(t, len(content), kept+omittedHead+omittedTail)
+	})
+
+	t.Run("Never splits multi-byte characters", func(t *testing.T) {
+		content := strings.Repeat("héllo wörld ✓ ", 500)
+		sampled := SampleContent(content, 257)
+		assert.True(t, utf8.ValidString(sampled))
+		assert.LessOrEqual(t, len(sampled), 257)
+	})
+
+	t.Run("Tiny budget falls back to the head", func(t *testing.T) {
+		sampled := SampleContent(strings.Repeat("x", 1000), 10)
+		assert.Equal(t, strings.Repeat("x", 10), sampled)
+	})
+}
+
+func TestGatherLocalFilesSampleLargeFiles(t *testing.T) {
+	testDir := t.TempDir()
+	content := "HEAD\n" + strings.Repeat("middle filler\n", 500) + "TAIL"
+	require.NoError(t, os.WriteFile(filepath.Join(testDir, "big.txt"), []byte(content), 0644))
+
+	results, err := GatherLocalFiles(testDir, nil, 512)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(results["big.txt"], "...(truncated)"), "truncation is the default")
+
+	results, err = GatherLocalFiles(testDir, nil, 512, WithSampleLargeFiles(true))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(results["big.txt"], "HEAD"))
+	assert.True(t, strings.HasSuffix(results["big.txt"], "TAIL"))
+	assert.LessOrEqual(t, len(results["big.txt"]), 512)
+}
+
+func TestGatherLocalFilesSkippedFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ok.txt"), []byte("fine\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("locked\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "image.bin"), []byte{0x00, 0x01, 0x02, 0xff}, 0600))
+	require.NoError(t, os.Symlink(filepath.Join(dir, "missing.txt"), filepath.Join(dir, "dangling.txt")))
+
+	// Simulate an unreadable file; permission bits are not enforced for root
+	origOpen := osOpen
+	osOpen = func(path string) (io.ReadCloser, error) {
+		if filepath.Base(path) == "secret.txt" {
+			return nil, fs.ErrPermission
+		}
+		return origOpen(path)
+	}
+	t.Cleanup(func() { osOpen = origOpen })
+
+	var skipped []SkippedFile
+	files, err := GatherLocalFiles(dir, nil, 1024, WithSkippedFiles(&skipped))
+	require.NoError(t, err, "unreadable files never fail gathering itself")
+
+	assert.Equal(t, map[string]string{"ok.txt": "fine\n"}, files)
+	require.Len(t, skipped, 2, "binary files are skipped on purpose and not reported")
+	assert.Equal(t, "dangling.txt", skipped[0].Path)
+	assert.Equal(t, "secret.txt", skipped[1].Path)
+	assert.ErrorIs(t, skipped[1], fs.ErrPermission)
+	assert.Contains(t, skipped[1].Error(), "secret.txt")
+}
+
+func TestReadUTF8File(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.md")
+	require.NoError(t, os.WriteFile(valid, []byte("caf\u00e9 \ufffd is a literal replacement character\n"), 0600))
+	invalid := filepath.Join(dir, "invalid.md")
+	require.NoError(t, os.WriteFile(invalid, []byte("caf\xc3 and more"), 0600))
+
+	content, err := ReadUTF8File(valid, dir)
+	require.NoError(t, err)
+	assert.Equal(t, "caf\u00e9 \ufffd is a literal replacement character\n", content)
+
+	_, err = ReadUTF8File(invalid, dir)
+	assert.EqualError(t, err, "content is not valid UTF-8 (invalid byte sequence at offset 3)")
+
+	lenient, err := ReadTextFile(invalid, 0, dir)
+	require.NoError(t, err, "ReadTextFile replaces what ReadUTF8File rejects")
+	assert.True(t, utf8.ValidString(lenient))
+
+	_, err = ReadUTF8File(filepath.Join(dir, "..", "outside.md"), dir)
+	assert.Error(t, err, "paths are validated against baseDir")
+}
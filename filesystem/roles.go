@@ -0,0 +1,101 @@
+// Package filesystem provides functionality for scanning, reading, and managing
+// filesystem operations in the glance application.
+package filesystem
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// File roles reported by ClassifyFileRole
+const (
+	// RoleEntrypoint marks a program or package entrypoint, such as main.go
+	RoleEntrypoint = "entrypoint"
+
+	// RoleConfig marks build, deployment, or tool configuration
+	RoleConfig = "config"
+
+	// RoleTest marks a test file
+	RoleTest = "test"
+
+	// RoleDocs marks documentation
+	RoleDocs = "docs"
+)
+
+// entrypointNames lists lowercase file names that conventionally start a
+// program or package, besides main.* in any language.
+var entrypointNames = map[string]bool{
+	"__main__.py": true, "app.py": true, "manage.py": true, "wsgi.py": true, "asgi.py": true,
+	"index.js": true, "index.jsx": true, "index.mjs": true, "index.cjs": true,
+	"index.ts": true, "index.tsx": true, "server.js": true, "server.ts": true, "lib.rs": true,
+}
+
+// configNames lists lowercase configuration file names whose extension alone
+// does not say so.
+var configNames = map[string]bool{
+	"dockerfile": true, "containerfile": true, "makefile": true, "procfile": true,
+	"jenkinsfile": true, "vagrantfile": true, "go.mod": true, "requirements.txt": true,
+	"cmakelists.txt": true, "build.gradle": true, "setup.py": true,
+}
+
+// docNames lists lowercase documentation file stems conventionally written
+// without an extension.
+var docNames = map[string]bool{
+	"readme": true, "license": true, "changelog": true, "contributing": true,
+	"notice": true, "authors": true,
+}
+
+// ClassifyFileRole guesses a file's role from its path alone, so the prompt
+// can point the model at entrypoints and configuration. Tests are recognized
+// first (main_test.go is a test, not an entrypoint), then entrypoints such as
+// main.go or index.ts, configuration such as *.yaml or Dockerfile, and
+// documentation. Names are compared case-insensitively, as in
+// DefaultFileScorer.
+//
+// Parameters:
+//   - path: The file's path, as a name or relative path
+//
+// Returns:
+//   - RoleTest, RoleEntrypoint, RoleConfig, or RoleDocs; "" for other files
+func ClassifyFileRole(path string) string {
+	base := filepath.Base(path)
+	name := strings.ToLower(base)
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+
+	switch {
+	case isTestFile(name) || isTestPath(path):
+		return RoleTest
+	case stem == "main" || entrypointNames[name]:
+		return RoleEntrypoint
+	case configNames[name] || configExtensions[ext] || strings.HasPrefix(name, "dockerfile.") ||
+		strings.HasSuffix(stem, ".config") || strings.HasPrefix(name, "."):
+		return RoleConfig
+	case docExtensions[ext] || docNames[stem]:
+		return RoleDocs
+	}
+	return ""
+}
+
+// isTestPath reports whether a file is a test by a convention isTestFile does
+// not cover: an RSpec-style models_spec.rb, a Java-style UserTest or
+// Swift-style UserTests name (compared with case, so "latest" does not count),
+// or a __tests__ directory.
+func isTestPath(path string) bool {
+	base := filepath.Base(path)
+	stem := strings.TrimSuffix(base, filepath.Ext(base))
+	if strings.HasSuffix(strings.ToLower(stem), "_spec") {
+		return true
+	}
+	for _, suffix := range []string{"Test", "Tests"} {
+		if len(stem) > len(suffix) && strings.HasSuffix(stem, suffix) {
+			return true
+		}
+	}
+	for _, part := range strings.Split(filepath.ToSlash(filepath.Dir(path)), "/") {
+		if part == "__tests__" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package filesystem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyFileRole(t *testing.T) {
+	tests := map[string]string{
+		// Tests win over every other role
+		"main_test.go":                 RoleTest,
+		"handler_test.go":              RoleTest,
+		"test_app.py":                  RoleTest,
+		"App.test.tsx":                 RoleTest,
+		"user.spec.ts":                 RoleTest,
+		"UserServiceTest.java":         RoleTest,
+		"ParserTests.swift":            RoleTest,
+		"__tests__/button.js":          RoleTest,
+		"models_spec.rb":               RoleTest,
+		"config_test.yaml":             RoleTest,
+		"main.go":                      RoleEntrypoint,
+		"MAIN.GO":                      RoleEntrypoint,
+		"main.rs":                      RoleEntrypoint,
+		"index.ts":                     RoleEntrypoint,
+		"index.jsx":                    RoleEntrypoint,
+		"__main__.py":                  RoleEntrypoint,
+		"lib.rs":                       RoleEntrypoint,
+		"settings.yaml":                RoleConfig,
+		"docker-compose.yml":           RoleConfig,
+		"Cargo.toml":                   RoleConfig,
+		"package.json":                 RoleConfig,
+		"Dockerfile":                   RoleConfig,
+		"Dockerfile.dev":               RoleConfig,
+		"Makefile":                     RoleConfig,
+		"go.mod":                       RoleConfig,
+		"vite.config.ts":               RoleConfig,
+		".eslintrc":                    RoleConfig,
+		"README.md":                    RoleDocs,
+		"LICENSE":                      RoleDocs,
+		"guide.rst":                    RoleDocs,
+		"handler.go":                   "",
+		"latest.go":                    "",
+		"contest.py":                   "",
+		"utils/strings.ts":             "",
+		"testdata_loader.go":           "",
+		"internal/mainframe/client.go": "",
+	}
+	for path, want := range tests {
+		assert.Equal(t, want, ClassifyFileRole(path), path)
+	}
+}
@@ -3,6 +3,8 @@
 package filesystem
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,6 +17,12 @@ import (
 type IgnoreRule struct {
 	OriginDir string // Absolute path to the directory containing the .gitignore file
 	Matcher   *gitignore.GitIgnore
+
+	// Reincluder holds the same patterns after one matching every path, so it
+	// reports whether a path that earlier rules in the chain ignore is still
+	// ignored once this file's "!pattern" lines are applied. Rules without
+	// one (see LoadIgnoreRule) cannot re-include paths.
+	Reincluder *gitignore.GitIgnore
 }
 
 // IgnoreChain represents the cumulative list of ignore rules applicable to a directory.
@@ -26,6 +34,63 @@ type queueItem struct {
 	ignoreChain IgnoreChain
 }
 
+// ScanOption configures optional behavior of ListDirsWithIgnores.
+type ScanOption func(*scanOptions)
+
+// scanOptions holds the optional settings applied by ScanOption values.
+type scanOptions struct {
+	rootMatchers  []*gitignore.GitIgnore
+	subtree       string
+	includeHidden bool
+	excludeDirs   []string
+}
+
+// WithRootIgnore seeds the root's ignore chain with matcher, as the first rule
+// and anchored at the scan root, ahead of any .gitignore files. This is how the
+// user's global git excludes file (see LoadGlobalGitignore) is applied. A nil
+// matcher is ignored.
+func WithRootIgnore(matcher *gitignore.GitIgnore) ScanOption {
+	return func(o *scanOptions) {
+		if matcher != nil {
+			o.rootMatchers = append(o.rootMatchers, matcher)
+		}
+	}
+}
+
+// WithSubtree limits the scan to dir and its descendants. The scan still starts
+// at the root and descends through dir's ancestors, so .gitignore rules from
+// those ancestors apply inside dir, but only directories within dir are listed.
+// If dir itself is ignored, nothing is listed.
+func WithSubtree(dir string) ScanOption {
+	return func(o *scanOptions) {
+		o.subtree = filepath.Clean(dir)
+	}
+}
+
+// WithIncludeHidden scans hidden directories too (see IncludeHidden). GitDir
+// and gitignored directories are still skipped.
+func WithIncludeHidden(enabled bool) ScanOption {
+	return func(o *scanOptions) {
+		o.includeHidden = enabled
+	}
+}
+
+// WithExcludedDir leaves dir and its descendants out of the scan (see
+// ExcludeDir). An empty dir is ignored.
+func WithExcludedDir(dir string) ScanOption {
+	return func(o *scanOptions) {
+		if dir != "" {
+			o.excludeDirs = append(o.excludeDirs, dir)
+		}
+	}
+}
+
+// withinDir reports whether path is dir or one of its descendants.
+func withinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
 // ListDirsWithIgnores performs a BFS from the root directory, collecting subdirectories
 // and merging each directory's .gitignore with its parent's chain.
 //
@@ -35,35 +100,73 @@ type queueItem struct {
 //
 // Parameters:
 //   - root: The starting directory for the BFS traversal
+//   - options: Optional behaviors such as WithRootIgnore and WithSubtree
 //
 // Returns:
 //   - A slice of directory paths
 //   - A map of directory path -> chain of ignore rules
 //   - An error, if any occurred during directory traversal
-func ListDirsWithIgnores(root string) ([]string, map[string]IgnoreChain, error) {
+func ListDirsWithIgnores(root string, options ...ScanOption) ([]string, map[string]IgnoreChain, error) {
+	var opts scanOptions
+	for _, option := range options {
+		option(&opts)
+	}
+	ignoreOpts := []IgnoreOption{IncludeHidden(opts.includeHidden)}
+	for _, dir := range opts.excludeDirs {
+		ignoreOpts = append(ignoreOpts, ExcludeDir(dir))
+	}
+
 	var dirsList []string
 
+	// Seed the root chain with any global rules
+	rootChain := IgnoreChain{}
+	for _, matcher := range opts.rootMatchers {
+		rootChain = append(rootChain, IgnoreRule{OriginDir: root, Matcher: matcher})
+	}
+
 	// BFS queue
 	queue := []queueItem{
-		{path: root, ignoreChain: IgnoreChain{}},
+		{path: root, ignoreChain: rootChain},
 	}
 
 	// map of directory -> chain of ignore rules
 	dirToChain := make(map[string]IgnoreChain)
-	dirToChain[root] = IgnoreChain{}
+	dirToChain[root] = rootChain
+
+	// Real paths of the directories already scanned, so a directory reachable
+	// under two paths (a symlink loop, a bind mount of an ancestor) is scanned once
+	visited := make(map[string]bool)
 
 	for len(queue) > 0 {
 		current := queue[0]
 		queue = queue[1:]
 
+		realPath := realDirPath(current.path)
+		if visited[realPath] {
+			log.WithFields(logrus.Fields{
+				"directory": current.path,
+				"real_path": realPath,
+			}).Warn("Skipping directory already scanned under another path (possible symlink loop)")
+			continue
+		}
+		visited[realPath] = true
+
+		// Ancestors of a WithSubtree directory are traversed for their ignore rules
+		// but not listed
+		listed := opts.subtree == "" || withinDir(current.path, opts.subtree)
+
 		// We always add the root directory
 		if current.path == root {
-			dirsList = append(dirsList, current.path)
+			if listed {
+				dirsList = append(dirsList, current.path)
+			}
 		} else {
 			// For non-root directories, use the shared ignore functions to check
 			// if the directory should be included
-			if !ShouldIgnoreDir(current.path, filepath.Dir(current.path), current.ignoreChain) {
-				dirsList = append(dirsList, current.path)
+			if !ShouldIgnoreDir(current.path, filepath.Dir(current.path), current.ignoreChain, ignoreOpts...) {
+				if listed {
+					dirsList = append(dirsList, current.path)
+				}
 			} else {
 				// Skip this directory - don't process its children
 				log.WithField("directory", current.path).Debug("Skipping directory matched by ignore rules")
@@ -72,7 +175,7 @@ func ListDirsWithIgnores(root string) ([]string, map[string]IgnoreChain, error)
 		}
 
 		// Load .gitignore in the current directory, if it exists
-		localIgnore, err := LoadGitignore(current.path)
+		localRule, err := LoadIgnoreRule(current.path)
 		if err != nil {
 			log.WithFields(logrus.Fields{
 				"directory": current.path,
@@ -86,12 +189,8 @@ func ListDirsWithIgnores(root string) ([]string, map[string]IgnoreChain, error)
 		copy(combinedChain, current.ignoreChain)
 
 		// Add the local .gitignore rule if one exists
-		if localIgnore != nil {
-			newRule := IgnoreRule{
-				OriginDir: current.path,
-				Matcher:   localIgnore,
-			}
-			combinedChain = append(combinedChain, newRule)
+		if localRule != nil {
+			combinedChain = append(combinedChain, *localRule)
 		}
 
 		// Store the applicable ignore chain for this directory
@@ -104,7 +203,8 @@ func ListDirsWithIgnores(root string) ([]string, map[string]IgnoreChain, error)
 		}
 
 		for _, e := range entries {
-			// Skip non-directories
+			// Skip non-directories. Symlinks report their own type rather than
+			// their target's, so symlinked directories are never followed.
 			if !e.IsDir() {
 				continue
 			}
@@ -112,14 +212,19 @@ func ListDirsWithIgnores(root string) ([]string, map[string]IgnoreChain, error)
 			name := e.Name()
 			fullChildPath := filepath.Join(current.path, name)
 
-			// Use the helper function to check for hidden dirs and node_modules
+			// Check for hidden dirs (unless included) and node_modules
 			// This is an optimization to avoid creating queue items for directories
 			// we know will be excluded
-			if strings.HasPrefix(name, ".") || name == NodeModulesDir {
+			if isIgnoredHidden(name, ignoreOpts) || name == NodeModulesDir {
 				log.WithField("directory", fullChildPath).Debug("Skipping hidden/node_modules directory")
 				continue
 			}
 
+			// Outside a WithSubtree scan, only the path down to the subtree is followed
+			if opts.subtree != "" && !withinDir(fullChildPath, opts.subtree) && !withinDir(opts.subtree, fullChildPath) {
+				continue
+			}
+
 			// Queue the directory for processing
 			// It will be checked against ignore rules in the next iteration
 			queue = append(queue, queueItem{
@@ -132,6 +237,16 @@ func ListDirsWithIgnores(root string) ([]string, map[string]IgnoreChain, error)
 	return dirsList, dirToChain, nil
 }
 
+// realDirPath resolves symlinks in dir, falling back to the cleaned path when
+// it cannot be resolved, so that every directory has an identity to track.
+func realDirPath(dir string) string {
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return filepath.Clean(dir)
+	}
+	return resolved
+}
+
 // LoadGitignore parses the .gitignore file in a directory and returns a GitIgnore object.
 // If no .gitignore file exists, it returns nil for both the GitIgnore object and the error.
 //
@@ -153,6 +268,40 @@ func LoadGitignore(dir string) (*gitignore.GitIgnore, error) {
 	return g, nil
 }
 
+// LoadIgnoreRule parses the .gitignore file in a directory into an IgnoreRule
+// whose negations can re-include paths ignored by earlier rules in a chain,
+// as git does when a nested .gitignore says "!keep.log".
+//
+// Parameters:
+//   - dir: The directory to check for a .gitignore file
+//
+// Returns:
+//   - The rule, anchored at dir, or nil if no .gitignore file exists
+//   - An error, if the file could not be read
+func LoadIgnoreRule(dir string) (*IgnoreRule, error) {
+	path := filepath.Join(dir, ".gitignore")
+	validPath, err := ValidateFilePath(path, dir, false, true)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// #nosec G304 -- validPath is validated above to be dir's own .gitignore
+	data, err := os.ReadFile(validPath)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(data), "\n")
+
+	return &IgnoreRule{
+		OriginDir:  dir,
+		Matcher:    gitignore.CompileIgnoreLines(lines...),
+		Reincluder: gitignore.CompileIgnoreLines(append([]string{"**"}, lines...)...),
+	}, nil
+}
+
 // The compatibility functions ExtractGitignoreMatchers and CreateIgnoreChain
 // have been removed as part of the migration to use IgnoreChain consistently
 // throughout the codebase.
@@ -3,7 +3,9 @@ package filesystem
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -201,6 +203,104 @@ func TestListDirsWithIgnores(t *testing.T) {
 	}
 }
 
+func TestListDirsWithIgnores_Subtree(t *testing.T) {
+	root := t.TempDir()
+	for _, d := range []string{"a/b/c", "a/b/build", "a/b/keep", "a/other", "z"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, d), 0755))
+	}
+	// An ancestor's rule must still apply inside the subtree
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("build/\n"), 0600))
+
+	subtree := filepath.Join(root, "a", "b")
+	dirs, chains, err := ListDirsWithIgnores(root, WithSubtree(subtree))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		subtree,
+		filepath.Join(subtree, "c"),
+		filepath.Join(subtree, "keep"),
+	}, dirs, "only the subtree is listed, without the ignored build directory")
+	assert.NotEmpty(t, chains[subtree], "the subtree inherits the root .gitignore")
+	assert.NotContains(t, chains, filepath.Join(root, "z"), "unrelated branches are not scanned")
+
+	dirs, _, err = ListDirsWithIgnores(root, WithSubtree(filepath.Join(subtree, "build")))
+	require.NoError(t, err)
+	assert.Empty(t, dirs, "an ignored subtree lists nothing")
+}
+
+func TestListDirsWithIgnores_IncludeHidden(t *testing.T) {
+	root := t.TempDir()
+	for _, d := range []string{".github/workflows", ".git/objects", ".cache", "src"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, d), 0755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte(".cache/\n"), 0600))
+
+	dirs, _, err := ListDirsWithIgnores(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{root, filepath.Join(root, "src")}, dirs, "hidden directories are skipped by default")
+
+	dirs, _, err = ListDirsWithIgnores(root, WithIncludeHidden(true))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		root,
+		filepath.Join(root, ".github"),
+		filepath.Join(root, ".github", "workflows"),
+		filepath.Join(root, "src"),
+	}, dirs, ".git and gitignored hidden directories are still skipped")
+}
+
+func TestListDirsWithIgnores_SymlinkLoop(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symlinks requires privileges on Windows")
+	}
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "a", "b"), 0755))
+	// Loops back to the root, to the parent, and to the directory itself
+	require.NoError(t, os.Symlink(root, filepath.Join(root, "a", "b", "to-root")))
+	require.NoError(t, os.Symlink(filepath.Join(root, "a"), filepath.Join(root, "a", "b", "to-parent")))
+	require.NoError(t, os.Symlink(".", filepath.Join(root, "a", "self")))
+
+	done := make(chan struct{})
+	var dirs []string
+	var err error
+	go func() {
+		defer close(done)
+		dirs, _, err = ListDirsWithIgnores(root)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("scan did not terminate on a symlink loop")
+	}
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{root, filepath.Join(root, "a"), filepath.Join(root, "a", "b")}, dirs,
+		"each directory is listed exactly once")
+
+	// A scan started through a symlink still sees the real tree once
+	link := filepath.Join(t.TempDir(), "link")
+	require.NoError(t, os.Symlink(root, link))
+	dirs, _, err = ListDirsWithIgnores(link)
+	require.NoError(t, err)
+	assert.Len(t, dirs, 3)
+}
+
+func TestRealDirPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symlinks requires privileges on Windows")
+	}
+
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	link := filepath.Join(root, "link")
+	require.NoError(t, os.Symlink(root, link))
+
+	assert.Equal(t, root, realDirPath(link))
+	assert.Equal(t, root, realDirPath(filepath.Join(link, "link", "link")), "nested loops resolve to one identity")
+	missing := filepath.Join(root, "missing", "..", "gone")
+	assert.Equal(t, filepath.Clean(missing), realDirPath(missing), "unresolvable paths fall back to the cleaned path")
+}
+
 func TestListDirsWithIgnores_ErrorHandling(t *testing.T) {
 	// Test with non-existent directory
 	_, _, err := ListDirsWithIgnores("/non/existent/directory")
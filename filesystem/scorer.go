@@ -0,0 +1,146 @@
+// Package filesystem provides functionality for scanning, reading, and managing
+// filesystem operations in the glance application.
+package filesystem
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FileScorer assigns a relevance score to a file so that callers can rank
+// files when deciding what to show the LLM first (or at all).
+// Higher scores indicate more relevant files.
+type FileScorer interface {
+	// Score returns the relevance of a file given its path and content.
+	Score(path string, content string) float64
+}
+
+// FileScorerFunc adapts an ordinary function to the FileScorer interface.
+type FileScorerFunc func(path string, content string) float64
+
+// Score calls f(path, content).
+func (f FileScorerFunc) Score(path string, content string) float64 {
+	return f(path, content)
+}
+
+// Relevance scores used by DefaultFileScorer.
+const (
+	scoreSource    = 1.0
+	scoreReadme    = 0.9
+	scoreDocs      = 0.7
+	scoreTest      = 0.6
+	scoreOther     = 0.5
+	scoreConfig    = 0.4
+	scoreGenerated = 0.2
+	scoreLockfile  = 0.1
+)
+
+// sourceExtensions lists file extensions treated as hand-written source code.
+var sourceExtensions = map[string]bool{
+	".go": true, ".py": true, ".js": true, ".jsx": true, ".ts": true, ".tsx": true,
+	".rs": true, ".java": true, ".kt": true, ".swift": true, ".c": true, ".h": true,
+	".cc": true, ".cpp": true, ".hpp": true, ".cs": true, ".rb": true, ".php": true,
+	".scala": true, ".sh": true, ".ex": true, ".exs": true, ".vue": true, ".svelte": true,
+}
+
+// docExtensions lists file extensions treated as prose documentation.
+var docExtensions = map[string]bool{
+	".md": true, ".rst": true, ".txt": true, ".adoc": true,
+}
+
+// configExtensions lists file extensions treated as configuration or data.
+var configExtensions = map[string]bool{
+	".json": true, ".yaml": true, ".yml": true, ".toml": true, ".ini": true,
+	".xml": true, ".cfg": true, ".conf": true, ".properties": true,
+}
+
+// lockfileNames lists dependency lock files, which are large and rarely informative.
+var lockfileNames = map[string]bool{
+	"go.sum": true, "package-lock.json": true, "yarn.lock": true, "pnpm-lock.yaml": true,
+	"cargo.lock": true, "poetry.lock": true, "gemfile.lock": true, "composer.lock": true,
+	"pipfile.lock": true,
+}
+
+// DefaultFileScorer favors hand-written source code over documentation,
+// configuration, generated artifacts, and dependency lock files.
+type DefaultFileScorer struct{}
+
+// Score implements FileScorer for DefaultFileScorer.
+func (DefaultFileScorer) Score(path string, content string) float64 {
+	name := strings.ToLower(filepath.Base(path))
+	ext := filepath.Ext(name)
+
+	switch {
+	case lockfileNames[name] || ext == ".lock":
+		return scoreLockfile
+	case isGeneratedFile(name, content):
+		return scoreGenerated
+	case strings.HasPrefix(name, "readme"):
+		return scoreReadme
+	case isTestFile(name):
+		return scoreTest
+	case sourceExtensions[ext]:
+		return scoreSource
+	case docExtensions[ext]:
+		return scoreDocs
+	case configExtensions[ext]:
+		return scoreConfig
+	default:
+		return scoreOther
+	}
+}
+
+// isGeneratedFile reports whether a file looks machine-generated, based on
+// common naming conventions and the standard "Code generated" header.
+func isGeneratedFile(name string, content string) bool {
+	if strings.Contains(name, ".min.") || strings.HasSuffix(name, ".pb.go") ||
+		strings.Contains(name, "_generated.") || strings.Contains(name, ".gen.") {
+		return true
+	}
+
+	header := content
+	if len(header) > 512 {
+		header = header[:512]
+	}
+	return strings.Contains(header, "Code generated") && strings.Contains(header, "DO NOT EDIT")
+}
+
+// isTestFile reports whether a file name follows a common test-file convention.
+func isTestFile(name string) bool {
+	stem := strings.TrimSuffix(name, filepath.Ext(name))
+	return strings.HasSuffix(stem, "_test") || strings.HasSuffix(stem, ".test") ||
+		strings.HasSuffix(stem, ".spec") || strings.HasPrefix(stem, "test_")
+}
+
+// RankFiles orders the files in a map from most to least relevant according to scorer.
+// Files with equal scores are ordered by path so the result is stable across runs.
+// A nil scorer falls back to DefaultFileScorer.
+//
+// Parameters:
+//   - files: A map of relative file paths to their contents
+//   - scorer: The FileScorer used to rank files
+//
+// Returns:
+//   - The file paths ordered by descending relevance
+func RankFiles(files map[string]string, scorer FileScorer) []string {
+	if scorer == nil {
+		scorer = DefaultFileScorer{}
+	}
+
+	scores := make(map[string]float64, len(files))
+	paths := make([]string, 0, len(files))
+	for path, content := range files {
+		scores[path] = scorer.Score(path, content)
+		paths = append(paths, path)
+	}
+
+	sort.Slice(paths, func(i, j int) bool {
+		if scores[paths[i]] != scores[paths[j]] {
+			return scores[paths[i]] > scores[paths[j]]
+		}
+		return paths[i] < paths[j]
+	})
+
+	return paths
+}
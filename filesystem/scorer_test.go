@@ -0,0 +1,88 @@
+package filesystem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultFileScorer(t *testing.T) {
+	scorer := DefaultFileScorer{}
+
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    float64
+	}{
+		{name: "Go source", path: "main.go", want: scoreSource},
+		{name: "TypeScript source", path: "src/app.tsx", want: scoreSource},
+		{name: "README", path: "README.md", want: scoreReadme},
+		{name: "Markdown docs", path: "guide.md", want: scoreDocs},
+		{name: "Go test", path: "main_test.go", want: scoreTest},
+		{name: "JS spec", path: "app.spec.js", want: scoreTest},
+		{name: "YAML config", path: "config.yaml", want: scoreConfig},
+		{name: "Unknown extension", path: "Makefile", want: scoreOther},
+		{name: "Minified bundle", path: "bundle.min.js", want: scoreGenerated},
+		{name: "Protobuf output", path: "api.pb.go", want: scoreGenerated},
+		{
+			name:    "Generated header",
+			path:    "zz_deepcopy.go",
+			content: "// Code generated by controller-gen. DO NOT EDIT.\npackage v1\n",
+			want:    scoreGenerated,
+		},
+		{name: "go.sum", path: "go.sum", want: scoreLockfile},
+		{name: "package-lock", path: "package-lock.json", want: scoreLockfile},
+		{name: "Cargo.lock", path: "Cargo.lock", want: scoreLockfile},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, scorer.Score(tc.path, tc.content))
+		})
+	}
+}
+
+func TestRankFiles(t *testing.T) {
+	files := map[string]string{
+		"go.sum":       "",
+		"config.yaml":  "",
+		"README.md":    "",
+		"util.go":      "",
+		"main.go":      "",
+		"main_test.go": "",
+		"gen.pb.go":    "",
+	}
+
+	t.Run("Default scorer orders source before config and lockfiles", func(t *testing.T) {
+		ranked := RankFiles(files, nil)
+		assert.Equal(t, []string{
+			"main.go",
+			"util.go",
+			"README.md",
+			"main_test.go",
+			"config.yaml",
+			"gen.pb.go",
+			"go.sum",
+		}, ranked)
+	})
+
+	t.Run("Ordering is stable across calls", func(t *testing.T) {
+		first := RankFiles(files, DefaultFileScorer{})
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, first, RankFiles(files, DefaultFileScorer{}))
+		}
+	})
+
+	t.Run("Custom scorer", func(t *testing.T) {
+		byLength := FileScorerFunc(func(path, content string) float64 {
+			return float64(len(content))
+		})
+		ranked := RankFiles(map[string]string{"a": "x", "b": "xxx", "c": "xx"}, byLength)
+		assert.Equal(t, []string{"b", "c", "a"}, ranked)
+	})
+
+	t.Run("Empty map", func(t *testing.T) {
+		assert.Empty(t, RankFiles(map[string]string{}, nil))
+	})
+}
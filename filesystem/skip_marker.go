@@ -0,0 +1,100 @@
+// Package filesystem provides functionality for scanning, reading, and managing
+// filesystem operations in the glance application.
+package filesystem
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SkipMarkerFilename marks a directory that should not be summarized. A marker
+// containing the line "recursive" also excludes everything beneath it.
+const SkipMarkerFilename = ".glanceskip"
+
+// maxSkipMarkerBytes bounds how much of a skip marker is read.
+const maxSkipMarkerBytes = 4096
+
+// SkipMarker is the skip setting a directory's SkipMarkerFilename declares.
+type SkipMarker int
+
+const (
+	// NoSkipMarker means the directory has no skip marker.
+	NoSkipMarker SkipMarker = iota
+
+	// SkipDirOnly skips the directory itself; its subdirectories are still
+	// summarized.
+	SkipDirOnly
+
+	// SkipRecursive skips the directory and all of its descendants.
+	SkipRecursive
+)
+
+// ReadSkipMarker reports the skip setting declared by dir's SkipMarkerFilename.
+// A marker that exists but cannot be read skips only dir, since its presence
+// is the user's intent even if its contents are unknown.
+//
+// Parameters:
+//   - dir: The directory to check
+//
+// Returns:
+//   - NoSkipMarker, SkipDirOnly, or SkipRecursive (a marker with a line
+//     reading "recursive", in any case)
+func ReadSkipMarker(dir string) SkipMarker {
+	markerPath, err := ValidateFilePath(filepath.Join(dir, SkipMarkerFilename), dir, false, true)
+	if errors.Is(err, fs.ErrNotExist) {
+		return NoSkipMarker
+	}
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"directory": dir,
+			"error":     err,
+		}).Debug("Invalid skip marker; skipping the directory only")
+		return SkipDirOnly
+	}
+
+	content, err := ReadTextFile(markerPath, maxSkipMarkerBytes, dir)
+	if err != nil {
+		log.WithFields(logrus.Fields{
+			"path":  markerPath,
+			"error": err,
+		}).Debug("Unreadable skip marker; skipping the directory only")
+		return SkipDirOnly
+	}
+	for _, line := range strings.Split(content, "\n") {
+		if strings.EqualFold(strings.TrimSpace(line), "recursive") {
+			return SkipRecursive
+		}
+	}
+	return SkipDirOnly
+}
+
+// SkippedByMarker reports whether dir should not be summarized because of a
+// skip marker: its own, or a recursive one in an ancestor up to and including
+// root. Ancestors above root are not consulted.
+//
+// Parameters:
+//   - dir: The directory to check
+//   - root: The scan root bounding the ancestor search
+//
+// Returns:
+//   - true if a skip marker excludes dir, false otherwise
+func SkippedByMarker(dir, root string) bool {
+	if ReadSkipMarker(dir) != NoSkipMarker {
+		return true
+	}
+	for current := dir; current != root; {
+		parent := filepath.Dir(current)
+		if parent == current {
+			return false // dir is not under root
+		}
+		current = parent
+		if ReadSkipMarker(current) == SkipRecursive {
+			return true
+		}
+	}
+	return false
+}
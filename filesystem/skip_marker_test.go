@@ -0,0 +1,57 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSkipMarker(t *testing.T) {
+	dir := t.TempDir()
+	assert.Equal(t, NoSkipMarker, ReadSkipMarker(dir))
+
+	marker := filepath.Join(dir, SkipMarkerFilename)
+	for content, want := range map[string]SkipMarker{
+		"":                              SkipDirOnly,
+		"generated fixtures\n":          SkipDirOnly,
+		"recursive\n":                   SkipRecursive,
+		"# vendored code\n  RECURSIVE ": SkipRecursive,
+	} {
+		require.NoError(t, os.WriteFile(marker, []byte(content), 0600))
+		assert.Equal(t, want, ReadSkipMarker(dir), "marker content %q", content)
+	}
+}
+
+func TestSkippedByMarker(t *testing.T) {
+	root := t.TempDir()
+	deep := filepath.Join(root, "a", "b", "c")
+	require.NoError(t, os.MkdirAll(deep, 0755))
+	assert.False(t, SkippedByMarker(deep, root))
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a", SkipMarkerFilename), nil, 0600))
+	assert.True(t, SkippedByMarker(filepath.Join(root, "a"), root))
+	assert.False(t, SkippedByMarker(deep, root), "a plain marker does not reach descendants")
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a", SkipMarkerFilename), []byte("recursive"), 0600))
+	assert.True(t, SkippedByMarker(deep, root))
+	assert.False(t, SkippedByMarker(deep, filepath.Join(root, "a", "b")), "ancestors above root are not consulted")
+}
+
+func TestSkipMarkerIgnoreRules(t *testing.T) {
+	root := t.TempDir()
+	marked := filepath.Join(root, "marked")
+	require.NoError(t, os.Mkdir(marked, 0755))
+	marker := filepath.Join(marked, SkipMarkerFilename)
+
+	require.NoError(t, os.WriteFile(marker, nil, 0600))
+	assert.False(t, ShouldIgnoreDir(marked, root, nil), "a plain marker keeps the directory in the scan")
+
+	require.NoError(t, os.WriteFile(marker, []byte("recursive"), 0600))
+	assert.True(t, ShouldIgnoreDir(marked, root, nil))
+
+	assert.True(t, ShouldIgnoreFile(marker, marked, nil, IncludeHidden(true)),
+		"markers are never summarized, even with hidden files included")
+}
@@ -0,0 +1,125 @@
+// Package filesystem provides functionality for scanning, reading, and managing
+// filesystem operations in the glance application.
+package filesystem
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// statsPattern matches a block written by DirStats.Render, including the blank
+// line that separates it from the summary.
+var statsPattern = regexp.MustCompile(`(?s)<!-- glance:stats -->\n.*?<!-- /glance:stats -->\n\n?`)
+
+// noExtensionLabel groups files without an extension in the language breakdown.
+const noExtensionLabel = "(none)"
+
+// LanguageStats counts the files and lines sharing one file extension.
+type LanguageStats struct {
+	// Extension is the lowercased extension including the dot (e.g. ".go"),
+	// or "(none)" for files without one
+	Extension string
+	Files     int
+	Lines     int
+}
+
+// DirStats summarizes the local files of a directory. It is computed by glance
+// itself rather than the LLM, so it is identical for identical inputs.
+type DirStats struct {
+	FileCount  int
+	TotalLines int
+	// Languages is sorted by line count, then file count, then extension
+	Languages []LanguageStats
+}
+
+// ComputeDirStats counts files, lines, and a per-extension breakdown for the
+// given file contents.
+//
+// Parameters:
+//   - fileContents: Map of file paths to their contents, as returned by GatherLocalFiles
+//
+// Returns:
+//   - The computed statistics; an empty map yields zero counts and no languages
+func ComputeDirStats(fileContents map[string]string) DirStats {
+	byExt := make(map[string]*LanguageStats)
+	stats := DirStats{FileCount: len(fileContents)}
+
+	for path, content := range fileContents {
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext == "" {
+			ext = noExtensionLabel
+		}
+		lang, ok := byExt[ext]
+		if !ok {
+			lang = &LanguageStats{Extension: ext}
+			byExt[ext] = lang
+		}
+		lines := countLines(content)
+		lang.Files++
+		lang.Lines += lines
+		stats.TotalLines += lines
+	}
+
+	for _, lang := range byExt {
+		stats.Languages = append(stats.Languages, *lang)
+	}
+	sort.Slice(stats.Languages, func(i, j int) bool {
+		a, b := stats.Languages[i], stats.Languages[j]
+		if a.Lines != b.Lines {
+			return a.Lines > b.Lines
+		}
+		if a.Files != b.Files {
+			return a.Files > b.Files
+		}
+		return a.Extension < b.Extension
+	})
+
+	return stats
+}
+
+// countLines returns the number of lines in content. A trailing line without
+// a final newline still counts; empty content has zero lines.
+func countLines(content string) int {
+	if content == "" {
+		return 0
+	}
+	lines := strings.Count(content, "\n")
+	if !strings.HasSuffix(content, "\n") {
+		lines++
+	}
+	return lines
+}
+
+// Render formats the statistics as a markdown block suitable for placing
+// above a generated summary. The block ends with a blank line.
+//
+// Returns:
+//   - The rendered markdown block
+func (s DirStats) Render() string {
+	var b strings.Builder
+	b.WriteString("<!-- glance:stats -->\n")
+	fmt.Fprintf(&b, "**Files:** %d · **Lines:** %d\n", s.FileCount, s.TotalLines)
+	if len(s.Languages) > 0 {
+		b.WriteString("\n| Extension | Files | Lines |\n|---|---:|---:|\n")
+		for _, lang := range s.Languages {
+			fmt.Fprintf(&b, "| %s | %d | %d |\n", lang.Extension, lang.Files, lang.Lines)
+		}
+	}
+	b.WriteString("<!-- /glance:stats -->\n\n")
+	return b.String()
+}
+
+// StripStats removes a block written by DirStats.Render, leaving the summary
+// the model wrote.
+//
+// Parameters:
+//   - content: A glance output file's content
+//
+// Returns:
+//   - The content without its stats block; content without one is returned unchanged
+func StripStats(content string) string {
+	return statsPattern.ReplaceAllString(content, "")
+}
@@ -0,0 +1,70 @@
+package filesystem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeDirStats(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		stats := ComputeDirStats(map[string]string{})
+		assert.Equal(t, DirStats{}, stats)
+	})
+
+	t.Run("Counts files, lines, and extensions", func(t *testing.T) {
+		stats := ComputeDirStats(map[string]string{
+			"main.go":    "package main\n\nfunc main() {}\n",
+			"util.GO":    "package main\nvar x = 1", // no trailing newline
+			"README.md":  "# Title\n",
+			"Makefile":   "all:\n\tgo build\n",
+			"empty.txt":  "",
+			"notes.txt":  "one\n",
+			"nested.txt": "a\nb\n",
+		})
+
+		assert.Equal(t, 7, stats.FileCount)
+		assert.Equal(t, 11, stats.TotalLines)
+		assert.Equal(t, []LanguageStats{
+			{Extension: ".go", Files: 2, Lines: 5},
+			{Extension: ".txt", Files: 3, Lines: 3},
+			{Extension: "(none)", Files: 1, Lines: 2},
+			{Extension: ".md", Files: 1, Lines: 1},
+		}, stats.Languages)
+	})
+
+	t.Run("Ties break by extension", func(t *testing.T) {
+		stats := ComputeDirStats(map[string]string{"b.py": "x\n", "a.js": "y\n"})
+		assert.Equal(t, ".js", stats.Languages[0].Extension)
+		assert.Equal(t, ".py", stats.Languages[1].Extension)
+	})
+}
+
+func TestDirStatsRender(t *testing.T) {
+	stats := DirStats{
+		FileCount:  3,
+		TotalLines: 42,
+		Languages: []LanguageStats{
+			{Extension: ".go", Files: 2, Lines: 40},
+			{Extension: ".md", Files: 1, Lines: 2},
+		},
+	}
+	expected := "<!-- glance:stats -->\n" +
+		"**Files:** 3 · **Lines:** 42\n" +
+		"\n| Extension | Files | Lines |\n|---|---:|---:|\n" +
+		"| .go | 2 | 40 |\n" +
+		"| .md | 1 | 2 |\n" +
+		"<!-- /glance:stats -->\n\n"
+	assert.Equal(t, expected, stats.Render())
+
+	empty := DirStats{}.Render()
+	assert.Equal(t, "<!-- glance:stats -->\n**Files:** 0 · **Lines:** 0\n<!-- /glance:stats -->\n\n", empty)
+}
+
+func TestStripStats(t *testing.T) {
+	summary := "## Purpose\nA package.\n"
+	stats := ComputeDirStats(map[string]string{"main.go": "package main\n"})
+
+	assert.Equal(t, summary, StripStats(stats.Render()+summary))
+	assert.Equal(t, summary, StripStats(summary), "content without stats is unchanged")
+}
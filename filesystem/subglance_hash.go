@@ -0,0 +1,72 @@
+// Package filesystem provides functionality for scanning, reading, and managing
+// filesystem operations in the glance application.
+package filesystem
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+)
+
+// subGlanceHashPattern matches the marker RenderSubGlanceHash writes.
+var subGlanceHashPattern = regexp.MustCompile(`<!-- glance:subglances sha256=([0-9a-f]{64}) -->`)
+
+// HashSubGlances returns the hex SHA-256 of a directory's combined subdirectory
+// summaries, as stored in its glance output by RenderSubGlanceHash.
+func HashSubGlances(subGlances string) string {
+	sum := sha256.Sum256([]byte(subGlances))
+	return hex.EncodeToString(sum[:])
+}
+
+// RenderSubGlanceHash formats hash as an HTML comment for the end of a glance
+// output file. Markdown renderers hide it; StoredSubGlanceHash reads it back.
+func RenderSubGlanceHash(hash string) string {
+	return "\n<!-- glance:subglances sha256=" + hash + " -->\n"
+}
+
+// StripSubGlanceHash removes markers written by RenderSubGlanceHash.
+//
+// Parameters:
+//   - content: A glance output file's content
+//
+// Returns:
+//   - The content without its marker; content without one is returned unchanged
+func StripSubGlanceHash(content string) string {
+	return subGlanceHashPattern.ReplaceAllString(content, "")
+}
+
+// StoredSubGlanceHash reads the subdirectory summary hash recorded in dir's
+// glance output file.
+//
+// Parameters:
+//   - dir: The directory whose glance output should be read
+//
+// Returns:
+//   - The stored hash
+//   - Whether a hash was found; a missing file or a file written without a hash
+//     (older versions, or a directory without child summaries) reports false
+//   - An error if the file exists but cannot be validated or read
+func StoredSubGlanceHash(dir string) (string, bool, error) {
+	validPath, err := ValidateFilePath(filepath.Join(dir, GlanceFilename), dir, false, true)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	content, err := ReadTextFile(validPath, 0, dir)
+	if err != nil {
+		return "", false, err
+	}
+
+	// The marker is written last; earlier matches could only come from quoted text
+	matches := subGlanceHashPattern.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		return "", false, nil
+	}
+	return matches[len(matches)-1][1], true, nil
+}
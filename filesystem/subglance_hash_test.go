@@ -0,0 +1,68 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashSubGlances(t *testing.T) {
+	assert.Len(t, HashSubGlances("a"), 64)
+	assert.Equal(t, HashSubGlances("a"), HashSubGlances("a"))
+	assert.NotEqual(t, HashSubGlances("a"), HashSubGlances("b"))
+}
+
+func TestStoredSubGlanceHash(t *testing.T) {
+	write := func(t *testing.T, content string) string {
+		t.Helper()
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, GlanceFilename), []byte(content), 0600))
+		return dir
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		hash := HashSubGlances("child summaries")
+		dir := write(t, "# Summary\n"+RenderSubGlanceHash(hash))
+
+		got, ok, err := StoredSubGlanceHash(dir)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, hash, got)
+	})
+
+	t.Run("last marker wins", func(t *testing.T) {
+		quoted, own := HashSubGlances("quoted"), HashSubGlances("own")
+		dir := write(t, "# Summary\n"+RenderSubGlanceHash(quoted)+"more\n"+RenderSubGlanceHash(own))
+
+		got, ok, err := StoredSubGlanceHash(dir)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, own, got)
+	})
+
+	t.Run("no marker", func(t *testing.T) {
+		_, ok, err := StoredSubGlanceHash(write(t, "# Summary\n"))
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("no glance output", func(t *testing.T) {
+		_, ok, err := StoredSubGlanceHash(t.TempDir())
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestStripSubGlanceHash(t *testing.T) {
+	summary := "## Purpose\nA package.\n"
+	marked := summary + RenderSubGlanceHash(HashSubGlances("child summaries"))
+
+	stripped := StripSubGlanceHash(marked)
+	assert.NotContains(t, stripped, "glance:subglances")
+	assert.Equal(t, summary, strings.TrimRight(stripped, "\n")+"\n")
+	assert.Equal(t, summary, StripSubGlanceHash(summary), "content without a marker is unchanged")
+}
@@ -27,12 +27,20 @@ const DefaultFileMode = 0o600
 // Parameters:
 //   - dir: The directory to search for the latest modification time
 //   - ignoreChain: A chain of gitignore matchers to check for ignored files/directories
+//   - opts: Adjustments to the built-in ignore rules, such as IncludeHidden
 //
 // Returns:
 //   - The most recent modification time found
 //   - An error, if any occurred during the search
-func LatestModTime(dir string, ignoreChain IgnoreChain) (time.Time, error) {
+func LatestModTime(dir string, ignoreChain IgnoreChain, opts ...IgnoreOption) (time.Time, error) {
+	latest, _, err := latestModEntry(dir, ignoreChain, opts...)
+	return latest, err
+}
+
+// latestModEntry is LatestModTime that also reports the path of the newest entry.
+func latestModEntry(dir string, ignoreChain IgnoreChain, opts ...IgnoreOption) (time.Time, string, error) {
 	var latest time.Time
+	var latestPath string
 
 	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, werr error) error {
 		if werr != nil {
@@ -42,7 +50,7 @@ func LatestModTime(dir string, ignoreChain IgnoreChain) (time.Time, error) {
 		// For directories (except the root dir), check if we should skip them
 		if d.IsDir() && path != dir {
 			// Check if the directory should be ignored
-			if ShouldIgnoreDir(path, dir, ignoreChain) {
+			if ShouldIgnoreDir(path, dir, ignoreChain, opts...) {
 				return fs.SkipDir
 			}
 		}
@@ -60,16 +68,117 @@ func LatestModTime(dir string, ignoreChain IgnoreChain) (time.Time, error) {
 		// Update latest time if this file/dir is newer
 		if info.ModTime().After(latest) {
 			latest = info.ModTime()
+			latestPath = path
 		}
 
 		return nil
 	})
 
-	return latest, err
+	return latest, latestPath, err
+}
+
+// DirInputSize estimates how much input a directory contributes to its prompt:
+// the total size in bytes of the regular files directly inside it that are not
+// hidden or ignored. Subdirectories are not counted.
+//
+// Parameters:
+//   - dir: The directory to measure
+//   - ignoreChain: A chain of gitignore matchers to check for ignored files
+//   - opts: Adjustments to the built-in ignore rules, such as IncludeHidden
+//
+// Returns:
+//   - The combined size of the directory's own files
+//   - An error, if the directory could not be read
+func DirInputSize(dir string, ignoreChain IgnoreChain, opts ...IgnoreOption) (int64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, e := range entries {
+		if !e.Type().IsRegular() || ShouldIgnoreFile(filepath.Join(dir, e.Name()), dir, ignoreChain, opts...) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue // removed since ReadDir; it no longer contributes
+		}
+		total += info.Size()
+	}
+	return total, nil
+}
+
+// RegenReason explains why a directory's glance output will or will not be regenerated.
+type RegenReason string
+
+// Regeneration reasons reported by CheckRegeneration and the processing loop.
+const (
+	// RegenReasonForced means regeneration was forced globally
+	RegenReasonForced RegenReason = "forced"
+
+	// RegenReasonMissing means the glance output file does not exist yet
+	RegenReasonMissing RegenReason = GlanceFilename + " missing"
+
+	// RegenReasonNewerFile means a file in the directory tree is newer than the glance output
+	RegenReasonNewerFile RegenReason = "newer file found"
+
+	// RegenReasonChildRegenerated means a descendant directory was regenerated
+	RegenReasonChildRegenerated RegenReason = "child regenerated"
+
+	// RegenReasonSubGlancesChanged means the combined subdirectory summaries no
+	// longer match the hash stored in the glance output, even though no file is newer
+	RegenReasonSubGlancesChanged RegenReason = "child summaries changed"
+
+	// RegenReasonUpToDate means the glance output is fresh and will be skipped
+	RegenReasonUpToDate RegenReason = "skipped (up-to-date)"
+)
+
+// RegenDecision is the structured result of a regeneration check.
+type RegenDecision struct {
+	// Reason is why the directory will or will not be regenerated
+	Reason RegenReason
+
+	// NewerFile is the path, relative to the directory, of the newest entry
+	// when Reason is RegenReasonNewerFile
+	NewerFile string
+}
+
+// Regenerate reports whether the decision calls for regeneration.
+func (d RegenDecision) Regenerate() bool {
+	return d.Reason != RegenReasonUpToDate
+}
+
+// String renders the decision as a short human-readable reason.
+func (d RegenDecision) String() string {
+	if d.Reason == RegenReasonNewerFile && d.NewerFile != "" {
+		return fmt.Sprintf("newer file %s found", d.NewerFile)
+	}
+	return string(d.Reason)
 }
 
 // ShouldRegenerate determines if the glance output file in a directory needs to be regenerated.
-// Regeneration is needed if:
+// It is a boolean convenience wrapper around CheckRegeneration.
+//
+// Parameters:
+//   - dir: The directory to check for regeneration need
+//   - globalForce: Whether regeneration is forced globally
+//   - ignoreChain: A chain of gitignore matchers to check for ignored files/directories
+//   - opts: Adjustments to the built-in ignore rules, such as IncludeHidden
+//
+// Returns:
+//   - true if regeneration is needed, false otherwise
+//   - an error, if any occurred during the check
+func ShouldRegenerate(dir string, globalForce bool, ignoreChain IgnoreChain, opts ...IgnoreOption) (bool, error) {
+	decision, err := CheckRegeneration(dir, globalForce, ignoreChain, opts...)
+	if err != nil {
+		return false, err
+	}
+	return decision.Regenerate(), nil
+}
+
+// CheckRegeneration determines whether the glance output file in a directory needs
+// to be regenerated, and why. Regeneration is needed if:
 // - Force is true
 // - GlanceFilename doesn't exist (including when only the legacy filename exists — forces migration)
 // - Any file in the directory is newer than GlanceFilename
@@ -78,48 +187,72 @@ func LatestModTime(dir string, ignoreChain IgnoreChain) (time.Time, error) {
 //   - dir: The directory to check for regeneration need
 //   - globalForce: Whether regeneration is forced globally
 //   - ignoreChain: A chain of gitignore matchers to check for ignored files/directories
+//   - opts: Adjustments to the built-in ignore rules, such as IncludeHidden
 //
 // Returns:
-//   - true if regeneration is needed, false otherwise
+//   - The regeneration decision and its reason
 //   - an error, if any occurred during the check
-func ShouldRegenerate(dir string, globalForce bool, ignoreChain IgnoreChain) (bool, error) {
+func CheckRegeneration(dir string, globalForce bool, ignoreChain IgnoreChain, opts ...IgnoreOption) (RegenDecision, error) {
+	return CheckRegenerationAt(dir, filepath.Join(dir, GlanceFilename), globalForce, ignoreChain, opts...)
+}
+
+// CheckRegenerationAt is CheckRegeneration for a glance output file kept at
+// glancePath, which may lie outside dir (see --output-dir). The legacy filename
+// is only looked for when glancePath is inside dir.
+//
+// Parameters:
+//   - dir: The directory to check for regeneration need
+//   - glancePath: The path of dir's glance output file
+//   - globalForce: Whether regeneration is forced globally
+//   - ignoreChain: A chain of gitignore matchers to check for ignored files/directories
+//   - opts: Adjustments to the built-in ignore rules, such as IncludeHidden
+//
+// Returns:
+//   - The regeneration decision and its reason
+//   - an error, if any occurred during the check
+func CheckRegenerationAt(dir, glancePath string, globalForce bool, ignoreChain IgnoreChain, opts ...IgnoreOption) (RegenDecision, error) {
 	// Always regenerate if force is true
 	if globalForce {
 		log.WithField("directory", dir).Debug("Force regeneration")
-		return true, nil
+		return RegenDecision{Reason: RegenReasonForced}, nil
 	}
 
 	// Check if the current glance output file exists.
 	// If only the legacy filename (glance.md) is present, force regeneration so that
 	// the directory migrates to the new filename (.glance.md) on the next run.
 	// This is a one-time cost per directory for users upgrading from v1.x.
-	glancePath := filepath.Join(dir, GlanceFilename)
 	glanceInfo, err := os.Stat(glancePath)
 	if err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
-			return false, fmt.Errorf("stat glance output %q: %w", glancePath, err)
+			return RegenDecision{}, fmt.Errorf("stat glance output %q: %w", glancePath, err)
 		}
 		legacyPath := filepath.Join(dir, LegacyGlanceFilename)
-		if _, legacyErr := os.Stat(legacyPath); legacyErr == nil {
+		if filepath.Dir(glancePath) != filepath.Clean(dir) {
+			log.WithField("directory", dir).Debug("glance output not found, will generate")
+		} else if _, legacyErr := os.Stat(legacyPath); legacyErr == nil {
 			log.WithField("directory", dir).Debug("Found legacy glance output, regenerating to migrate to new filename")
 		} else {
 			log.WithField("directory", dir).Debug("glance output not found, will generate")
 		}
-		return true, nil
+		return RegenDecision{Reason: RegenReasonMissing}, nil
 	}
 
 	// Check if any file is newer than the glance output
-	latest, err := LatestModTime(dir, ignoreChain)
+	latest, latestPath, err := latestModEntry(dir, ignoreChain, opts...)
 	if err != nil {
-		return false, err
+		return RegenDecision{}, err
 	}
 
 	if latest.After(glanceInfo.ModTime()) {
 		log.WithField("directory", dir).Debug("Found newer files, will regenerate glance output")
-		return true, nil
+		relPath, relErr := filepath.Rel(dir, latestPath)
+		if relErr != nil {
+			relPath = latestPath
+		}
+		return RegenDecision{Reason: RegenReasonNewerFile, NewerFile: relPath}, nil
 	}
 
-	return false, nil
+	return RegenDecision{Reason: RegenReasonUpToDate}, nil
 }
 
 // BubbleUpParents marks all parent directories of a given directory for regeneration,
@@ -199,8 +332,13 @@ func ValidatePathWithinBase(path, baseDir string, allowBaseDir bool) (string, er
 			ErrPathOutsideBase, path, baseDir)
 	}
 
-	// Check if the path starts with the base directory
-	if !strings.HasPrefix(absPath, absBaseDir+string(os.PathSeparator)) && absPath != absBaseDir {
+	// Check if the path starts with the base directory. A filesystem root such as
+	// "/" already ends in a separator, so none is appended.
+	basePrefix := absBaseDir
+	if !strings.HasSuffix(basePrefix, string(os.PathSeparator)) {
+		basePrefix += string(os.PathSeparator)
+	}
+	if !strings.HasPrefix(absPath, basePrefix) && absPath != absBaseDir {
 		return "", fmt.Errorf("%w: path %q is outside of allowed directory %q",
 			ErrPathOutsideBase, path, baseDir)
 	}
@@ -230,6 +230,71 @@ func TestShouldRegenerate(t *testing.T) {
 	})
 }
 
+func TestCheckRegeneration(t *testing.T) {
+	baseDir := t.TempDir()
+	glanceFile := filepath.Join(baseDir, GlanceFilename)
+	require.NoError(t, os.WriteFile(glanceFile, []byte("# Glance"), 0600))
+
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(glanceFile, old, old))
+	require.NoError(t, os.Chtimes(baseDir, old.Add(-time.Minute), old.Add(-time.Minute)))
+
+	t.Run("Forced", func(t *testing.T) {
+		decision, err := CheckRegeneration(baseDir, true, nil)
+		require.NoError(t, err)
+		assert.Equal(t, RegenReasonForced, decision.Reason)
+		assert.Equal(t, "forced", decision.String())
+		assert.True(t, decision.Regenerate())
+	})
+
+	t.Run("Up to date", func(t *testing.T) {
+		decision, err := CheckRegeneration(baseDir, false, nil)
+		require.NoError(t, err)
+		assert.Equal(t, RegenReasonUpToDate, decision.Reason)
+		assert.Equal(t, "skipped (up-to-date)", decision.String())
+		assert.False(t, decision.Regenerate())
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		decision, err := CheckRegeneration(t.TempDir(), false, nil)
+		require.NoError(t, err)
+		assert.Equal(t, RegenReasonMissing, decision.Reason)
+		assert.Equal(t, ".glance.md missing", decision.String())
+	})
+
+	t.Run("Newer file names the file", func(t *testing.T) {
+		subDir := filepath.Join(baseDir, "pkg")
+		require.NoError(t, os.Mkdir(subDir, 0755))
+		newer := filepath.Join(subDir, "main.go")
+		require.NoError(t, os.WriteFile(newer, []byte("package pkg"), 0600))
+		require.NoError(t, os.Chtimes(subDir, old, old))
+		require.NoError(t, os.Chtimes(baseDir, old, old))
+
+		decision, err := CheckRegeneration(baseDir, false, nil)
+		require.NoError(t, err)
+		assert.Equal(t, RegenReasonNewerFile, decision.Reason)
+		assert.Equal(t, filepath.Join("pkg", "main.go"), decision.NewerFile)
+		assert.Equal(t, "newer file "+filepath.Join("pkg", "main.go")+" found", decision.String())
+	})
+
+	t.Run("Output kept outside the directory", func(t *testing.T) {
+		srcDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, LegacyGlanceFilename), []byte("# Legacy"), 0600))
+		require.NoError(t, os.Chtimes(filepath.Join(srcDir, LegacyGlanceFilename), old, old))
+		require.NoError(t, os.Chtimes(srcDir, old, old))
+		mirrored := filepath.Join(t.TempDir(), GlanceFilename)
+
+		decision, err := CheckRegenerationAt(srcDir, mirrored, false, nil)
+		require.NoError(t, err)
+		assert.Equal(t, RegenReasonMissing, decision.Reason)
+
+		require.NoError(t, os.WriteFile(mirrored, []byte("# Glance"), 0600))
+		decision, err = CheckRegenerationAt(srcDir, mirrored, false, nil)
+		require.NoError(t, err)
+		assert.Equal(t, RegenReasonUpToDate, decision.Reason)
+	})
+}
+
 func TestBubbleUpParents(t *testing.T) {
 	// Test case 1: Standard case with multiple parent directories
 	t.Run("Standard case", func(t *testing.T) {
@@ -431,3 +496,22 @@ func TestLatestModTime_EdgeCases(t *testing.T) {
 
 // Skipping TestShouldRegenerate_EdgeCases for simplicity
 // These tests are too dependent on file system permissions that vary by platform
+
+func TestDirInputSize(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.go"), make([]byte, 100), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.go"), make([]byte, 50), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".hidden"), make([]byte, 1000), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "build.log"), make([]byte, 1000), 0600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "c.go"), make([]byte, 1000), 0600))
+
+	chain := IgnoreChain{{OriginDir: dir, Matcher: gitignore.CompileIgnoreLines("*.log")}}
+
+	size, err := DirInputSize(dir, chain)
+	require.NoError(t, err)
+	assert.Equal(t, int64(150), size, "only the directory's own visible, non-ignored files count")
+
+	_, err = DirInputSize(filepath.Join(dir, "missing"), nil)
+	assert.Error(t, err)
+}
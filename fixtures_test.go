@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/llm"
+)
+
+// writePackageDirs creates each named directory under root, holding a main.go
+// that declares a package of the same name, so every directory's prompt can be
+// told apart by its contents.
+func writePackageDirs(t *testing.T, root string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, name), 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(root, name, "main.go"), []byte("package "+name), 0600))
+	}
+}
+
+// processTree scans cfg.TargetDir, processes every directory with service, and
+// returns the results keyed by directory.
+func processTree(t *testing.T, cfg *config.Config, service *llm.Service) map[string]result {
+	t.Helper()
+	dirs, ignoreChains, err := scanDirectories(cfg)
+	require.NoError(t, err)
+
+	results, _ := processDirectories(context.Background(), dirs, ignoreChains, cfg, service, io.Discard)
+	byDir := make(map[string]result, len(results))
+	for _, r := range results {
+		byDir[r.dir] = r
+	}
+	return byDir
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"glance/filesystem"
+)
+
+// footerNow supplies the generation time recorded by --footer. Tests replace
+// it to simulate runs at different times.
+var footerNow = time.Now
+
+// appendFooter ends summary with the --footer provenance block for model.
+// filesystem.StripFooter on the result gives back summary, newline-terminated.
+func appendFooter(summary, model string) string {
+	if !strings.HasSuffix(summary, "\n") {
+		summary += "\n"
+	}
+	return summary + filesystem.RenderFooter(model, footerNow())
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/cache"
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// useFooterTime makes --footer record at for the rest of the test.
+func useFooterTime(t *testing.T, at time.Time) {
+	t.Helper()
+	original := footerNow
+	footerNow = func() time.Time { return at }
+	t.Cleanup(func() { footerNow = original })
+}
+
+func TestFooter(t *testing.T) {
+	root := t.TempDir()
+	child := filepath.Join(root, "pkg")
+	require.NoError(t, os.MkdirAll(child, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(child, "pkg.go"), []byte("package pkg\n"), 0600))
+
+	store, err := cache.NewFileStore(filepath.Join(t.TempDir(), "cache"))
+	require.NoError(t, err)
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).Return("## Purpose\nSummary.\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+		llm.WithServiceModelName("test-model"), llm.WithSummaryCache(store), llm.WithPromptTemplate(llm.DefaultTemplate()))
+	require.NoError(t, err)
+
+	dirs, chains, err := filesystem.ListDirsWithIgnores(root)
+	require.NoError(t, err)
+	reverseSlice(dirs)
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithFooter(true).WithForce(true)
+
+	readGlance := func(dir string) string {
+		content, err := os.ReadFile(filepath.Join(dir, filesystem.GlanceFilename))
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	first := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	useFooterTime(t, first)
+	results, _ := processDirectories(context.Background(), dirs, chains, cfg, service, io.Discard)
+	for _, r := range results {
+		require.True(t, r.success, "%s: %v", r.dir, r.err)
+	}
+	mockLLMClient.AssertNumberOfCalls(t, "Generate", 2)
+	assert.Contains(t, readGlance(child), "_Generated by glance on 2024-01-01T12:00:00Z with test-model._")
+	assert.Contains(t, readGlance(root), "_Generated by glance on 2024-01-01T12:00:00Z with test-model._")
+	storedHash, ok, err := filesystem.StoredSubGlanceHash(root)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// A later run over identical inputs only moves the footers: the parent's
+	// prompt, which includes the child summary, is unchanged, so nothing is
+	// sent to the LLM again and the recorded child hash still matches
+	useFooterTime(t, first.Add(24*time.Hour))
+	processDirectories(context.Background(), dirs, chains, cfg, service, io.Discard)
+	mockLLMClient.AssertNumberOfCalls(t, "Generate", 2)
+	assert.Contains(t, readGlance(child), "_Generated by glance on 2024-01-02T12:00:00Z with test-model._")
+	assert.False(t, subGlancesChanged(root, chains[root], cfg.WithForce(false)),
+		"a child whose footer alone changed does not make the parent stale")
+	newHash, _, err := filesystem.StoredSubGlanceHash(root)
+	require.NoError(t, err)
+	assert.Equal(t, storedHash, newHash)
+}
+
+func TestCompareIgnoresFooter(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0600))
+	written := appendFooter("## Purpose\nSummary.\n", "test-model")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, filesystem.GlanceFilename), []byte(written), 0600))
+
+	cfg := config.NewDefaultConfig().WithTargetDir(dir).WithCompare(true).WithFooter(true)
+	r := compareGlance(cfg, result{dir: dir}, "## Purpose\nSummary.\n", outcomeGenerated)
+	require.NoError(t, r.err)
+	assert.False(t, r.stale, "a footer on disk does not make the summary stale")
+}
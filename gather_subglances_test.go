@@ -1,8 +1,10 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -197,3 +199,86 @@ func TestGatherSubGlances(t *testing.T) {
 		assert.Empty(t, content)
 	})
 }
+
+func TestGatherSubGlancesLimited(t *testing.T) {
+	testDir := t.TempDir()
+
+	var subdirs []string
+	for i := 0; i < 12; i++ {
+		sub := filepath.Join(testDir, fmt.Sprintf("child%02d", i))
+		require.NoError(t, os.Mkdir(sub, 0755))
+		body := fmt.Sprintf("# child%02d\n", i) + strings.Repeat("detail line about this package\n", 200)
+		require.NoError(t, os.WriteFile(filepath.Join(sub, filesystem.GlanceFilename), []byte(body), 0644))
+		subdirs = append(subdirs, sub)
+	}
+	small := filepath.Join(testDir, "small")
+	require.NoError(t, os.Mkdir(small, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(small, filesystem.GlanceFilename), []byte("# small\nTiny summary."), 0644))
+	subdirs = append(subdirs, small)
+
+	unlimited, err := gatherSubGlances(testDir, subdirs)
+	require.NoError(t, err)
+	require.Greater(t, len(unlimited), 50000)
+
+	for _, limit := range []int64{8000, 2000} {
+		combined, err := gatherSubGlancesLimited(testDir, subdirs, limit, false)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, int64(len(combined)), limit, "combined subglances must respect the cap")
+		for i := 0; i < 12; i++ {
+			assert.Contains(t, combined, fmt.Sprintf("child%02d", i), "every child must still be referenced")
+		}
+		assert.Contains(t, combined, "# small\nTiny summary.", "short summaries stay whole")
+	}
+
+	t.Run("No limit or room to spare leaves output unchanged", func(t *testing.T) {
+		combined, err := gatherSubGlancesLimited(testDir, subdirs, 0, false)
+		require.NoError(t, err)
+		assert.Equal(t, unlimited, combined)
+
+		combined, err = gatherSubGlancesLimited(testDir, subdirs, int64(len(unlimited)), false)
+		require.NoError(t, err)
+		assert.Equal(t, unlimited, combined)
+	})
+}
+
+func TestLimitSubGlances(t *testing.T) {
+	long := subGlance{name: "api", content: strings.Repeat("x", 5000)}
+	short := subGlance{name: "cli", content: "short"}
+
+	t.Run("Excerpt ends with an omission reference", func(t *testing.T) {
+		out := limitSubGlances([]subGlance{long, short}, 1000)
+		assert.LessOrEqual(t, len(out), 1000)
+		assert.Regexp(t, `\[api: \d+ more bytes omitted\]\n\nshort$`, out)
+	})
+
+	t.Run("Tiny budget keeps references only", func(t *testing.T) {
+		out := limitSubGlances([]subGlance{long, {name: "db", content: strings.Repeat("y", 5000)}}, 120)
+		assert.Equal(t, "[api: summary omitted, 5000 bytes]\n\n[db: summary omitted, 5000 bytes]", out)
+	})
+}
+
+func TestGatherSubGlancesStrict(t *testing.T) {
+	testDir := t.TempDir()
+	good := filepath.Join(testDir, "good")
+	bad := filepath.Join(testDir, "bad")
+	pending := filepath.Join(testDir, "pending")
+	for _, d := range []string{good, bad, pending} {
+		require.NoError(t, os.Mkdir(d, 0755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(good, filesystem.GlanceFilename), []byte("good summary"), 0600))
+	// A directory where the glance output should be cannot be read as a file
+	require.NoError(t, os.Mkdir(filepath.Join(bad, filesystem.GlanceFilename), 0755))
+	subdirs := []string{good, bad, pending}
+
+	combined, err := gatherSubGlancesLimited(testDir, subdirs, 0, false)
+	require.NoError(t, err, "lenient mode skips the unreadable summary")
+	assert.Equal(t, "good summary", combined)
+
+	_, err = gatherSubGlancesLimited(testDir, subdirs, 0, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "strict mode")
+
+	combined, err = gatherSubGlancesLimited(testDir, []string{good, pending}, 0, true)
+	require.NoError(t, err, "a child without any glance output is not an error, even in strict mode")
+	assert.Equal(t, "good summary", combined)
+}
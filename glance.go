@@ -2,17 +2,21 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	_ "github.com/joho/godotenv" // Used by the config package for loading environment variables
-	progressbar "github.com/schollz/progressbar/v3"
 	"github.com/sirupsen/logrus"
 
+	"glance/cache"
 	"glance/config"
+	"glance/events"
 	"glance/filesystem"
 	"glance/llm"
 	"glance/ui"
@@ -28,6 +32,21 @@ type result struct {
 	attempts int
 	success  bool
 	err      error
+
+	// outcome says whether the directory was generated, stubbed, skipped, or failed
+	outcome dirOutcome
+
+	// stale marks, with --compare, a glance.md that differs from a fresh summary;
+	// diff describes the difference
+	stale bool
+	diff  string
+
+	// budgetSkipped marks a directory left unprocessed because the LLM budget ran out
+	budgetSkipped bool
+
+	// deadlineSkipped marks a directory left unprocessed, or cancelled mid-request,
+	// because the --max-runtime deadline passed
+	deadlineSkipped bool
 }
 
 // -----------------------------------------------------------------------------
@@ -35,6 +54,16 @@ type result struct {
 // -----------------------------------------------------------------------------
 
 func main() {
+	start := time.Now()
+
+	// Exit with exitCode only after every deferred cleanup below has run
+	exitCode := 0
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
+
 	// Load configuration from command-line flags, environment variables, etc.
 	cfg, err := config.LoadConfig(os.Args)
 	if err != nil {
@@ -45,6 +74,35 @@ func main() {
 	// Set up logging with debug level
 	setupLogging()
 
+	// Bound the whole run by --max-runtime, if set
+	ctx, cancel := runContext(cfg)
+	defer cancel()
+
+	// Keep the local target path out of every log line when requested
+	if cfg.AnonymizePaths {
+		logrus.AddHook(newPathAnonymizerHook(cfg.TargetDir))
+	}
+
+	// Apply case sensitivity to extension and filename rules
+	filesystem.SetCaseInsensitive(cfg.IgnoreCase)
+
+	// Bound open files across all concurrent reads
+	filesystem.SetMaxOpenFiles(cfg.MaxOpenFiles)
+
+	// Clean mode removes generated files and never needs the LLM
+	if cfg.Clean {
+		if err := runClean(cfg, os.Stdout); err != nil {
+			logrus.WithField("error", err).Fatal("Failed to clean glance files")
+		}
+		return
+	}
+
+	// Verify mode checks existing files for corruption and never needs the LLM
+	if cfg.Verify {
+		exitCode = verifyExitCode(cfg, os.Stdout)
+		return
+	}
+
 	// Set up the LLM client and service using the function variable
 	llmClient, llmService, err := setupLLMService(cfg)
 	if err != nil {
@@ -52,17 +110,86 @@ func main() {
 	}
 	defer llmClient.Close()
 
+	// Describe images with a vision model before each summary if requested
+	if cfg.DescribeImages {
+		closeDescriber, err := setupImageDescriber(cfg)
+		if err != nil {
+			logrus.WithField("error", err).Fatal("Failed to initialize image describer")
+		}
+		defer closeDescriber()
+	}
+
+	// Load shared repository context once, before any directory is processed
+	if err := loadRepoContext(cfg); err != nil {
+		logrus.WithField("error", err).Fatal("Failed to load context files")
+	}
+
+	// Serve summaries over HTTP instead of generating files
+	if cfg.Serve {
+		if err := runServer(cfg, llmService); err != nil {
+			logrus.WithField("error", err).Error("HTTP API stopped")
+			llmClient.Close()
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Print a single directory's prompt instead of generating anything
+	if cfg.DumpPrompt != "" {
+		if err := dumpPrompt(cfg, llmService, os.Stdout); err != nil {
+			logrus.WithField("error", err).Error("Failed to dump prompt")
+			llmClient.Close()
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Stream progress events for monitoring tools if requested
+	if cfg.EventsFile != "" {
+		emitter, closeEvents, err := openEventStream(cfg)
+		if err != nil {
+			logrus.WithField("error", err).Fatal("Failed to open events file")
+		}
+		runEvents = emitter
+		defer closeEvents()
+	}
+
 	// Scan directories and process them to generate glance.md files
 	dirs, ignoreChains, err := scanDirectories(cfg)
 	if err != nil {
 		logrus.WithField("error", err).Fatal("Directory scan failed - Check file permissions and disk space")
 	}
+	if cfg.Staged && len(dirs) == 0 {
+		logrus.Info("No staged changes under the target directory; nothing to do")
+		return
+	}
 
 	// Process directories and generate glance.md files
-	results, _ := processDirectories(dirs, ignoreChains, cfg, llmService, os.Stderr)
+	enableStdout(cfg, os.Stdout)
+	enableStreaming(cfg, dirs, os.Stdout)
+	results, _ := processDirectories(ctx, dirs, ignoreChains, cfg, llmService, os.Stderr)
 
 	// Print summary of results
-	printDebrief(results)
+	printDebrief(results, cfg.QuietSuccess)
+	emitRunCompleted(results, llmService.TokensCounted(), time.Since(start))
+
+	// Export run metrics for CI observability if requested
+	if cfg.MetricsFile != "" {
+		if err := exportMetrics(cfg.MetricsFile, results, llmService.TokensCounted(), time.Since(start)); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"path":  cfg.MetricsFile,
+				"error": err,
+			}).Error("Failed to write metrics file")
+		}
+	}
+
+	// Report stale summaries, failing the run if any differ
+	if cfg.Compare {
+		exitCode = printCompareReport(os.Stdout, cfg.TargetDir, results)
+	}
+	if cfg.FailOnEmptySummary && exitCode == 0 {
+		exitCode = emptySummaryExitCode(results)
+	}
 }
 
 // -----------------------------------------------------------------------------
@@ -118,52 +245,52 @@ type SetupLLMServiceFunc func(cfg *config.Config) (llm.Client, *llm.Service, err
 // The implementation to use - can be swapped in tests
 var setupLLMServiceFunc SetupLLMServiceFunc = createLLMService
 
+// explainOut is where --explain decisions are written - can be swapped in tests
+var explainOut io.Writer = os.Stdout
+
 // setupLLMService creates a client and service
 func setupLLMService(cfg *config.Config) (llm.Client, *llm.Service, error) {
 	return setupLLMServiceFunc(cfg)
 }
 
+// defaultMaxOutputTokens returns the recommended output token limit for a model.
+func defaultMaxOutputTokens(model string) int32 {
+	profile, _ := llm.LookupModelProfile(model)
+	if profile.DefaultMaxOutputTokens > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int32(profile.DefaultMaxOutputTokens)
+}
+
+// Models of the fixed failover chain, in order
+const (
+	primaryModel    = "gemini-3-flash-preview"
+	stableModel     = "gemini-2.5-flash"
+	openRouterModel = "x-ai/grok-4.1-fast"
+)
+
 // createLLMService is the actual implementation for initializing the LLM client and service
 func createLLMService(cfg *config.Config) (llm.Client, *llm.Service, error) {
-	primaryClient, err := llm.NewGeminiClient(
-		cfg.APIKey,
-		llm.WithModelName("gemini-3-flash-preview"),
-		llm.WithMaxRetries(0), // Single attempt per tier; FallbackClient handles retries.
-		llm.WithMaxOutputTokens(4096),
-		llm.WithTimeout(60),
-	)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create primary Gemini client: %w", err)
+	openRouterKey := strings.TrimSpace(os.Getenv("OPENROUTER_API_KEY"))
+	if err := checkChainAllowed(cfg, openRouterKey != ""); err != nil {
+		return nil, nil, err
 	}
 
-	stableClient, err := llm.NewGeminiClient(
-		cfg.APIKey,
-		llm.WithModelName("gemini-2.5-flash"),
-		llm.WithMaxRetries(0), // Single attempt per tier; FallbackClient handles retries.
-		llm.WithMaxOutputTokens(4096),
-		llm.WithTimeout(60),
-	)
+	primaryClient, stableClient, err := newGeminiTierClients(cfg)
 	if err != nil {
-		primaryClient.Close()
-		return nil, nil, fmt.Errorf("failed to create stable Gemini fallback client: %w", err)
+		return nil, nil, err
 	}
 
+	geminiThrottle := llm.NewThrottle()
 	tiers := []llm.FallbackTier{
-		{Name: "gemini-3-flash-preview", Client: primaryClient},
-		{Name: "gemini-2.5-flash", Client: stableClient},
+		{Name: primaryModel, Client: primaryClient, MaxConcurrent: cfg.ProviderConcurrency[config.ProviderGemini], Throttle: geminiThrottle},
+		{Name: stableModel, Client: stableClient, MaxConcurrent: cfg.ProviderConcurrency[config.ProviderGemini], Throttle: geminiThrottle},
 	}
 
-	openRouterKey := strings.TrimSpace(os.Getenv("OPENROUTER_API_KEY"))
 	if openRouterKey == "" {
 		logrus.Warn("OPENROUTER_API_KEY is not set; cross-provider fallback (x-ai/grok-4.1-fast) is disabled")
 	} else {
-		grokFallbackClient, grokErr := llm.NewOpenRouterClient(
-			openRouterKey,
-			llm.WithModelName("x-ai/grok-4.1-fast"),
-			llm.WithMaxRetries(0), // Single attempt per tier; FallbackClient handles retries.
-			llm.WithMaxOutputTokens(4096),
-			llm.WithTimeout(60),
-		)
+		grokFallbackClient, grokErr := llm.NewOpenRouterClient(openRouterKey, openRouterClientOptions(cfg, openRouterModel)...)
 		if grokErr != nil {
 			primaryClient.Close()
 			stableClient.Close()
@@ -171,12 +298,14 @@ func createLLMService(cfg *config.Config) (llm.Client, *llm.Service, error) {
 		}
 
 		tiers = append(tiers, llm.FallbackTier{
-			Name:   "x-ai/grok-4.1-fast",
-			Client: grokFallbackClient,
+			Name:          openRouterModel,
+			Client:        grokFallbackClient,
+			MaxConcurrent: cfg.ProviderConcurrency[config.ProviderOpenRouter],
+			Throttle:      llm.NewThrottle(),
 		})
 	}
 
-	client, err := llm.NewFallbackClient(tiers, cfg.MaxRetries)
+	client, err := llm.NewFallbackClient(tiers, cfg.MaxRetries, llm.WithResponseValidator(llm.ValidateMarkdown))
 	if err != nil {
 		for _, tier := range tiers {
 			tier.Client.Close()
@@ -191,11 +320,7 @@ func createLLMService(cfg *config.Config) (llm.Client, *llm.Service, error) {
 	compositeModelName := "fallback(" + strings.Join(tierNames, "->") + ")"
 
 	// Create the service with functional options
-	service, err := llm.NewService(
-		client,
-		llm.WithServiceModelName(compositeModelName),
-		llm.WithPromptTemplate(cfg.PromptTemplate),
-	)
+	service, err := llm.NewService(client, serviceOptions(cfg, compositeModelName)...)
 	if err != nil {
 		client.Close()
 		return nil, nil, fmt.Errorf("failed to create LLM service: %w", err)
@@ -204,30 +329,116 @@ func createLLMService(cfg *config.Config) (llm.Client, *llm.Service, error) {
 	return client, service, nil
 }
 
+// serviceOptions returns the Service options derived from the configuration.
+func serviceOptions(cfg *config.Config, modelName string) []func(*llm.ServiceConfig) {
+	options := []func(*llm.ServiceConfig){
+		llm.WithServiceModelName(modelName),
+		llm.WithPromptTemplate(cfg.PromptTemplate),
+		llm.WithFileScorer(filesystem.DefaultFileScorer{}),
+		llm.WithPromptVars(cfg.PromptVars),
+		llm.WithStrictTemplate(cfg.StrictTemplate),
+		llm.WithGenerationSettings(cfg.SystemInstructions, generationOptions(cfg)),
+	}
+	if cfg.MaxRequests > 0 || cfg.MaxTokens > 0 {
+		options = append(options, llm.WithBudget(llm.NewBudget(cfg.MaxRequests, cfg.MaxTokens)))
+	}
+	if limit := cfg.InflightLimit(); limit > 0 {
+		options = append(options, llm.WithMaxInflight(limit))
+	}
+	for _, name := range cfg.PostProcessors {
+		if processor, ok := llm.BuiltinPostProcessor(name); ok {
+			options = append(options, llm.WithOutputPostProcessor(processor))
+		}
+	}
+	if cfg.SplitLargeDirs > 0 {
+		options = append(options, llm.WithSplitLargeDirs(cfg.SplitLargeDirs))
+	}
+	if cfg.ChunkSummarizeBytes > 0 {
+		options = append(options, llm.WithChunkSummarize(cfg.ChunkSummarizeBytes))
+	}
+	if cfg.LinkSources {
+		options = append(options, llm.WithSourceLinks(sourceLinkBase(cfg.TargetDir)))
+	}
+	if cfg.FailOnEmptySummary {
+		options = append(options, llm.WithSummaryCheck(func(summary string) error { return checkSummaryLength(cfg, summary) }))
+	}
+	if !cfg.NoCache {
+		if store := openSummaryCache(); store != nil {
+			options = append(options, llm.WithSummaryCache(store))
+		}
+	}
+	return options
+}
+
+// openSummaryCache opens the per-user summary cache. Failures are logged and
+// disable caching rather than aborting the run.
+func openSummaryCache() cache.SummaryStore {
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		logrus.WithField("error", err).Warn("Summary cache unavailable; continuing without it")
+		return nil
+	}
+
+	store, err := cache.NewFileStore(dir)
+	if err != nil {
+		logrus.WithField("error", err).Warn("Summary cache unavailable; continuing without it")
+		return nil
+	}
+	return store
+}
+
 // scanDirectories performs BFS scanning and gathers .gitignore chain info per directory
 func scanDirectories(cfg *config.Config) ([]string, map[string]filesystem.IgnoreChain, error) {
 	logrus.Info("Scanning directories...")
+	runEvents.Emit(events.Event{Type: events.ScanStarted, Dir: cfg.TargetDir})
 
-	// Show a spinner while scanning
-	scanner := ui.NewScanner()
+	// Show a spinner while scanning, on stderr with the progress bar so --stdout stays clean
+	scanner := ui.NewScanner(ui.WithWriter(os.Stderr))
 	scanner.Start()
 	defer scanner.Stop()
 
 	// Perform BFS scanning and gather .gitignore chain info per directory
-	dirsList, dirToIgnoreChain, err := listAllDirsWithIgnores(cfg.TargetDir)
+	dirsList, dirToIgnoreChain, err := listAllDirsWithIgnores(cfg.TargetDir, scanOptions(cfg)...)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	// Keep only directories touched by staged changes, and their ancestors
+	if cfg.Staged {
+		if dirsList, err = restrictToStaged(cfg.TargetDir, dirsList); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Top-down mode keeps BFS order: parents first, without child summaries
+	if cfg.TopDown {
+		return dirsList, dirToIgnoreChain, nil
+	}
+
 	// Process from deepest subdirectories upward
 	reverseSlice(dirsList)
 
+	// Optionally reorder by size without processing any parent before its children
+	dirsList = orderDirectories(dirsList, dirToIgnoreChain, cfg.Order, ignoreOptions(cfg)...)
+
 	return dirsList, dirToIgnoreChain, nil
 }
 
+// displayDir returns dir relative to root for progress output, falling back to
+// the base name if the two are unrelated.
+func displayDir(root, dir string) string {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return filepath.Base(dir)
+	}
+	return rel
+}
+
 // processDirectories generates glance.md files for each directory in the list and returns the map of directories
 // needing regeneration. progressOut controls where progress bar output is written; pass io.Discard to suppress it.
+// Once ctx is done, the remaining directories are recorded as deadline-skipped without being processed.
 func processDirectories(
+	ctx context.Context,
 	dirsList []string,
 	dirToIgnoreChain map[string]filesystem.IgnoreChain,
 	cfg *config.Config,
@@ -236,38 +447,88 @@ func processDirectories(
 ) ([]result, map[string]bool) {
 	logrus.Info("Preparing to generate glance output files...")
 
-	// Set up options for the progress bar
-	options := []progressbar.Option{
-		progressbar.OptionSetDescription("Creating glance files"),
-		progressbar.OptionShowCount(),
-		progressbar.OptionSetWidth(40),
-		progressbar.OptionSetPredictTime(false),
-		progressbar.OptionSetWriter(progressOut),
-	}
-
-	// Create progress bar with the configured options
-	bar := progressbar.NewOptions(len(dirsList), options...)
+	// The progress bar also names the directory currently being generated
+	progress := ui.NewProgress(len(dirsList), "Creating glance files", progressOut)
 
 	// Create map to track directories needing regeneration due to child changes
 	needsRegen := make(map[string]bool)
 	var finalResults []result
+	dirConfigs := config.NewDirConfigResolver(cfg)
+	heights := dirHeights(dirsList)
+	siblings := siblingDirs(dirsList)
 
 	// Process each directory
 	for _, d := range dirsList {
 		ignoreChain := dirToIgnoreChain[d]
+		runEvents.Emit(events.Event{Type: events.DirStarted, Dir: displayDir(cfg.TargetDir, d)})
+
+		// Stop cleanly once --max-runtime has passed, recording what was left undone
+		if ctx.Err() != nil {
+			explainDecision(cfg, d, "skipped (--max-runtime reached)")
+			r := result{dir: d, err: ctx.Err(), outcome: outcomeSkipped, deadlineSkipped: true}
+			finalResults = append(finalResults, r)
+			emitDirCompleted(cfg, r, 0)
+			progress.Increment()
+			continue
+		}
+
+		// Resolve the directory's effective config from ancestor .glance.toml files.
+		// A malformed file fails only its own directory, never the whole run.
+		dirCfg, errCfg := dirConfigs.Resolve(d)
+		if errCfg != nil {
+			logrus.WithFields(logrus.Fields{
+				"directory": d,
+				"error":     errCfg,
+			}).Warn("Invalid per-directory config; skipping directory")
+			explainDecision(cfg, d, "skipped (invalid "+config.DirConfigFilename+")")
+			r := result{dir: d, err: errCfg}
+			finalResults = append(finalResults, r)
+			emitDirCompleted(cfg, r, 0)
+			progress.Increment()
+			continue
+		}
+		dirCfg = rollupConfig(dirCfg, d, heights)
+		dirCfg = siblingsConfig(dirCfg, d, siblings)
+
+		// Skip marked directories, and those without qualifying file types, entirely
+		if reason := skipReason(cfg, d, ignoreChain, needsRegen[d]); reason != "" {
+			explainDecision(cfg, d, "skipped ("+reason+")")
+			r := result{dir: d, success: true, outcome: outcomeSkipped}
+			finalResults = append(finalResults, r)
+			emitDirCompleted(cfg, r, 0)
+			progress.Increment()
+			continue
+		}
 
 		// Check if we need to regenerate the glance.md file based on local file changes
-		forceDir, errCheck := filesystem.ShouldRegenerate(d, cfg.Force, ignoreChain)
+		glancePath := filepath.Join(glanceOutputDir(cfg, d), filesystem.GlanceFilename)
+		decision, errCheck := filesystem.CheckRegenerationAt(d, glancePath, cfg.Force || cfg.Compare || cfg.Stdout, ignoreChain, ignoreOptions(cfg)...)
 		if errCheck != nil {
 			logrus.WithFields(logrus.Fields{
 				"directory": d,
 				"error":     errCheck,
 			}).Warn("Couldn't check modification time")
 		}
+		forceDir := errCheck == nil && decision.Regenerate()
 
 		// Also check if this directory needs regeneration due to child directory changes
+		if !forceDir && needsRegen[d] {
+			decision = filesystem.RegenDecision{Reason: filesystem.RegenReasonChildRegenerated}
+		}
 		forceDir = forceDir || needsRegen[d]
 
+		// Catch child summaries that changed without a newer mtime (clock skew, restored backups)
+		if !forceDir && errCheck == nil && subGlancesChanged(d, ignoreChain, dirCfg) {
+			decision = filesystem.RegenDecision{Reason: filesystem.RegenReasonSubGlancesChanged}
+			forceDir = true
+		}
+
+		explanation := decision.String()
+		if errCheck != nil && !forceDir {
+			explanation = "skipped (regeneration check failed)"
+		}
+		explainDecision(cfg, d, explanation)
+
 		if needsRegen[d] {
 			logrus.WithFields(logrus.Fields{
 				"directory": d,
@@ -275,35 +536,39 @@ func processDirectories(
 			}).Debug("Directory marked for regeneration due to child changes")
 		}
 
-		// Process the directory with retry logic
-		r := processDirectory(d, forceDir, ignoreChain, cfg, llmService)
+		// Process the directory with retry logic; a panic fails only this directory
+		progress.SetActive([]string{displayDir(cfg.TargetDir, d)})
+		tokensBefore := llmService.TokensCounted()
+		r := processDirectorySafely(ctx, d, forceDir, ignoreChain, dirCfg, llmService)
 		finalResults = append(finalResults, r)
+		emitDirCompleted(cfg, r, llmService.TokensCounted()-tokensBefore)
+		progress.SetActive(nil)
+		progress.Increment()
 
-		// Ignore error for non-critical UI
-		_ = bar.Add(1)
-
-		// Bubble up parent's regeneration flag if needed - only when regeneration was
-		// successful and actually attempted (not skipped)
-		if r.success && r.attempts > 0 && forceDir {
+		// Bubble up parent's regeneration flag if needed (see bubbleReason)
+		if reason := bubbleReason(cfg, d, r, forceDir); reason != "" {
 			logrus.WithFields(logrus.Fields{
 				"directory": d,
-				"reason":    "successfully regenerated",
+				"reason":    reason,
 			}).Debug("Marking parent directories for regeneration")
-			filesystem.BubbleUpParents(d, cfg.TargetDir, needsRegen)
+			filesystem.BubbleUpParents(d, scanRoot(cfg), needsRegen)
 		}
 	}
 
-	// Finish the progress bar (ignore errors for non-critical UI)
-	_ = bar.Finish()
+	progress.Finish()
 
-	logrus.WithField("target_dir", cfg.TargetDir).Info("All done! glance output files have been generated for your codebase")
+	if !cfg.QuietSuccess && !cfg.Compare && !cfg.Stdout {
+		logrus.WithField("target_dir", cfg.TargetDir).Info("All done! glance output files have been generated for your codebase")
+	}
 
 	return finalResults, needsRegen
 }
 
-// processDirectory processes a single directory with retry logic
-func processDirectory(dir string, forceDir bool, ignoreChain filesystem.IgnoreChain, cfg *config.Config, llmService *llm.Service) result {
+// processDirectory processes a single directory with retry logic. LLM requests
+// are made with ctx, so cancelling it abandons an in-flight request.
+func processDirectory(ctx context.Context, dir string, forceDir bool, ignoreChain filesystem.IgnoreChain, cfg *config.Config, llmService *llm.Service) result {
 	r := result{dir: dir}
+	cfg = packageRootConfig(cfg, dir)
 
 	// forceDir already indicates if regeneration is needed based on filesystem.ShouldRegenerate
 	// or parent propagation in processDirectories
@@ -314,6 +579,7 @@ func processDirectory(dir string, forceDir bool, ignoreChain filesystem.IgnoreCh
 			"action":    "skip",
 		}).Debug("Skipping directory - glance.md already exists and looks fresh, no child changes detected")
 		r.success = true
+		r.outcome = outcomeSkipped
 		r.attempts = 0 // Explicitly mark that we didn't attempt to regenerate
 		return r
 	}
@@ -341,15 +607,9 @@ func processDirectory(dir string, forceDir bool, ignoreChain filesystem.IgnoreCh
 		"stage":     "gather_subdirectories",
 	}).Debug("Reading subdirectories")
 
-	subdirs, err := readSubdirectories(dir, ignoreChain)
+	subdirs, err := readSubdirectories(dir, ignoreChain, ignoreOptions(cfg)...)
 	if err != nil {
-		logrus.WithFields(logrus.Fields{
-			"directory": dir,
-			"error":     err,
-			"stage":     "gather_subdirectories",
-		}).Error("Failed to read subdirectories")
-		r.err = err
-		return r
+		return gatherFailure(r, "gather_subdirectories", "Failed to read subdirectories", err)
 	}
 
 	logrus.WithFields(logrus.Fields{
@@ -358,15 +618,10 @@ func processDirectory(dir string, forceDir bool, ignoreChain filesystem.IgnoreCh
 		"stage":         "gather_subglances",
 	}).Debug("Gathering glance files from subdirectories")
 
-	subGlances, err := gatherSubGlances(dir, subdirs)
+	subGlances, err := subGlancesFor(cfg, dir, subdirs, ignoreChain)
 	if err != nil {
-		logrus.WithFields(logrus.Fields{
-			"directory": dir,
-			"error":     err,
-			"stage":     "gather_subglances",
-		}).Error("Failed to gather glance files from subdirectories")
-		r.err = fmt.Errorf("gatherSubGlances failed: %w", err)
-		return r
+		return gatherFailure(r, "gather_subglances", "Failed to gather glance files from subdirectories",
+			fmt.Errorf("gatherSubGlances failed: %w", err))
 	}
 
 	logrus.WithFields(logrus.Fields{
@@ -374,15 +629,10 @@ func processDirectory(dir string, forceDir bool, ignoreChain filesystem.IgnoreCh
 		"stage":     "gather_local_files",
 	}).Debug("Gathering local files")
 
-	fileContents, err := gatherLocalFiles(dir, ignoreChain, cfg.MaxFileBytes)
+	fileContents, err := localFilesFor(dir, ignoreChain, cfg)
 	if err != nil {
-		logrus.WithFields(logrus.Fields{
-			"directory": dir,
-			"error":     err,
-			"stage":     "gather_local_files",
-		}).Error("Failed to gather local files")
-		r.err = fmt.Errorf("gatherLocalFiles failed: %w", err)
-		return r
+		return gatherFailure(r, "gather_local_files", "Failed to gather local files",
+			fmt.Errorf("gatherLocalFiles failed: %w", err))
 	}
 
 	logrus.WithFields(logrus.Fields{
@@ -396,42 +646,22 @@ func processDirectory(dir string, forceDir bool, ignoreChain filesystem.IgnoreCh
 	// Directories with no analyzable content have nothing for the LLM to work with.
 	// Calling the LLM with an empty prompt causes hallucination based on the
 	// directory path name alone (e.g., inventing Rails framework details for
-	// a Next.js project's /lib/assets). Write a minimal stub instead.
+	// a Next.js project's /lib/assets). Write a minimal stub instead, or nothing
+	// at all with --no-empty-stubs.
 	if len(fileContents) == 0 && strings.TrimSpace(subGlances) == "" {
-		stubDesc := stubDescription(dir, subdirs)
-		logrus.WithField("directory", dir).Debug("Skipping LLM for directory with no analyzable content — writing minimal stub")
-		// Base(dir) is intentional: stub heading is a display label, not a path reference.
-		stub := fmt.Sprintf("# %s\n\n%s\n", filepath.Base(dir), stubDesc)
-		glancePath := filepath.Join(dir, filesystem.GlanceFilename)
-		validatedPath, pathErr := filesystem.ValidateFilePath(glancePath, dir, true, false)
-		if pathErr != nil {
-			r.err = fmt.Errorf("invalid glance.md path for %s: %w", dir, pathErr)
-			return r
-		}
-		// #nosec G306 -- Using filesystem.DefaultFileMode (0600) for security & path validated
-		if werr := os.WriteFile(validatedPath, []byte(stub), filesystem.DefaultFileMode); werr != nil {
-			r.err = fmt.Errorf("failed writing stub glance.md to %s: %w", dir, werr)
-			return r
-		}
-		r.success = true
-		r.attempts = 1 // Counts as processed: triggers BubbleUpParents for parent regen
-		return r
+		logrus.WithField("directory", dir).Debug("Skipping LLM for directory with no analyzable content")
+		return writeStub(cfg, r, stubDescription(dir, subdirs))
 	}
 
-	// Create context for LLM operations
-	ctx := context.Background()
-
-	// Use relative path in the LLM prompt to avoid leaking machine-specific paths.
-	// Both cfg.TargetDir and dir are absolute (enforced by LoadConfig + scanning),
-	// so Rel should never fail; the fallback is a safeguard, not an expected code path.
-	relDir, relErr := filepath.Rel(cfg.TargetDir, dir)
-	if relErr != nil {
+	// Directories with fewer than --min-files analyzable files rarely earn an LLM
+	// call; they are stubbed the same way unless child summaries give them substance.
+	if belowMinFiles(cfg, fileContents, subGlances) {
 		logrus.WithFields(logrus.Fields{
-			"root":  cfg.TargetDir,
-			"dir":   dir,
-			"error": relErr,
-		}).Warn("filepath.Rel failed; falling back to Base — absolute path may appear in LLM prompt")
-		relDir = filepath.Base(dir)
+			"directory":   dir,
+			"files_count": len(fileContents),
+			"min_files":   cfg.MinFiles,
+		}).Debug("Skipping LLM for directory below the --min-files threshold")
+		return writeStub(cfg, r, minFilesStubDescription(fileContents))
 	}
 
 	logrus.WithFields(logrus.Fields{
@@ -439,7 +669,36 @@ func processDirectory(dir string, forceDir bool, ignoreChain filesystem.IgnoreCh
 		"stage":     "llm_generation",
 	}).Debug("Generating markdown content using LLM service")
 
-	summary, llmErr := llmService.GenerateGlanceMarkdown(ctx, relDir, fileContents, subGlances)
+	options := append(promptOptions(cfg, dir), imagePromptOptions(ctx, cfg, dir, ignoreChain)...)
+	options = append(options, streamPromptOptions()...)
+	summary, llmErr := generateSummary(ctx, cfg, llmService, dir, fileContents, subGlances, options...)
+	if errors.Is(llmErr, llm.ErrBudgetExhausted) {
+		logrus.WithField("directory", dir).Debug("Skipping directory - LLM budget exhausted")
+		r.budgetSkipped = true
+		r.outcome = outcomeSkipped
+		r.err = llmErr
+		return r
+	}
+	if llmErr != nil && ctx.Err() != nil {
+		logrus.WithFields(logrus.Fields{
+			"directory": dir,
+			"error":     ctx.Err(),
+		}).Warn("Cancelled in-flight LLM request - run deadline reached")
+		r.deadlineSkipped = true
+		r.outcome = outcomeSkipped
+		r.err = ctx.Err()
+		return r
+	}
+	if errors.Is(llmErr, errEmptySummary) {
+		logrus.WithFields(logrus.Fields{
+			"directory": dir,
+			"error":     llmErr,
+			"stage":     "summary_check",
+		}).Error("Generated summary is too short; not writing glance.md")
+		r.attempts = 1
+		r.err = llmErr
+		return r
+	}
 	if llmErr != nil {
 		logrus.WithFields(logrus.Fields{
 			"directory": dir,
@@ -451,15 +710,33 @@ func processDirectory(dir string, forceDir bool, ignoreChain filesystem.IgnoreCh
 		return r
 	}
 
+	if cfg.IncludeStats {
+		summary = filesystem.ComputeDirStats(fileContents).Render() + summary
+	}
+	if subGlances != "" {
+		summary = strings.TrimRight(summary, "\n") + "\n" + filesystem.RenderSubGlanceHash(filesystem.HashSubGlances(subGlances))
+	}
+	if cfg.Footer && !cfg.Compare {
+		summary = appendFooter(summary, llmService.ModelName())
+	}
+
+	// With --compare, the summary is checked against the file on disk instead of written
+	if cfg.Compare {
+		return compareGlance(cfg, r, summary, outcomeGenerated)
+	}
+	if stdoutSink != nil {
+		return streamGlance(r, summary, outcomeGenerated)
+	}
+
 	// Validate the glance output path before writing
-	glancePath := filepath.Join(dir, filesystem.GlanceFilename)
+	glancePath := filepath.Join(glanceOutputDir(cfg, dir), filesystem.GlanceFilename)
 	logrus.WithFields(logrus.Fields{
 		"directory": dir,
 		"path":      glancePath,
 		"stage":     "path_validation",
 	}).Debug("Validating glance output path")
 
-	validatedPath, pathErr := filesystem.ValidateFilePath(glancePath, dir, true, false)
+	validatedPath, pathErr := glanceWritePath(cfg, dir)
 	if pathErr != nil {
 		logrus.WithFields(logrus.Fields{
 			"directory": dir,
@@ -471,9 +748,8 @@ func processDirectory(dir string, forceDir bool, ignoreChain filesystem.IgnoreCh
 		return r
 	}
 
-	// Write the generated content to file using the validated path
-	// #nosec G306 -- Using filesystem.DefaultFileMode (0600) for security & path validated
-	if werr := os.WriteFile(validatedPath, []byte(summary), filesystem.DefaultFileMode); werr != nil { // Path validated & using secure permissions
+	// Write the generated content atomically to the validated path
+	if werr := filesystem.WriteFileAtomic(validatedPath, []byte(summary), cfg.TempDir); werr != nil {
 		logrus.WithFields(logrus.Fields{
 			"directory": dir,
 			"path":      validatedPath,
@@ -494,11 +770,50 @@ func processDirectory(dir string, forceDir bool, ignoreChain filesystem.IgnoreCh
 	}).Debug("Successfully generated and wrote glance.md file")
 
 	r.success = true
+	r.outcome = outcomeGenerated
 	r.attempts = 1
 	r.err = nil
 	return r
 }
 
+// promptDir returns dir relative to the target directory for use in the LLM prompt.
+func promptDir(cfg *config.Config, dir string) string {
+	// Use relative path in the LLM prompt to avoid leaking machine-specific paths.
+	// Both cfg.TargetDir and dir are absolute (enforced by LoadConfig + scanning),
+	// so Rel should never fail; the fallback is a safeguard, not an expected code path.
+	relDir, relErr := filepath.Rel(cfg.TargetDir, dir)
+	if relErr != nil {
+		logrus.WithFields(logrus.Fields{
+			"root":  cfg.TargetDir,
+			"dir":   dir,
+			"error": relErr,
+		}).Warn("filepath.Rel failed; falling back to Base — absolute path may appear in LLM prompt")
+		relDir = filepath.Base(dir)
+	}
+	return relDir
+}
+
+// promptOptions returns the per-directory prompt data options implied by cfg.
+func promptOptions(cfg *config.Config, dir string) []llm.PromptDataOption {
+	var options []llm.PromptDataOption
+	if cfg.DirPromptTemplate != "" {
+		options = append(options, llm.WithTemplate(cfg.DirPromptTemplate))
+	}
+	if cfg.IncludeGitMetadata {
+		options = append(options, llm.WithGitHistory(gitHistory(dir)))
+	}
+	if cfg.IncludeSiblings && len(cfg.Siblings) > 0 {
+		options = append(options, llm.WithSiblings(cfg.Siblings))
+	}
+	if repoContext != "" {
+		options = append(options, llm.WithRepoContext(repoContext))
+	}
+	if cfg.AnonymizePaths {
+		options = append(options, llm.WithPromptRewrite(pathAnonymizer(cfg.TargetDir)))
+	}
+	return options
+}
+
 // -----------------------------------------------------------------------------
 // .gitignore scanning and BFS
 // -----------------------------------------------------------------------------
@@ -506,9 +821,9 @@ func processDirectory(dir string, forceDir bool, ignoreChain filesystem.IgnoreCh
 // listAllDirsWithIgnores performs a BFS from `root`, collecting subdirectories
 // and merging each directory's .gitignore with its parent's chain.
 // This function now uses filesystem.ListDirsWithIgnores directly, returning the native IgnoreChain type.
-func listAllDirsWithIgnores(root string) ([]string, map[string]filesystem.IgnoreChain, error) {
+func listAllDirsWithIgnores(root string, options ...filesystem.ScanOption) ([]string, map[string]filesystem.IgnoreChain, error) {
 	// Use the filesystem package function to get the directories and ignore chains
-	return filesystem.ListDirsWithIgnores(root)
+	return filesystem.ListDirsWithIgnores(root, options...)
 }
 
 // reverseSlice reverses a slice of directory paths in-place.
@@ -522,49 +837,10 @@ func reverseSlice(s []string) {
 // file collection and processing
 // -----------------------------------------------------------------------------
 
-// gatherSubGlances merges the contents of existing subdirectory glance output files.
-// Falls back to the legacy filename (glance.md) when the current filename (.glance.md)
-// is absent, so parent summaries remain complete during the upgrade migration window.
-// The baseDir parameter defines the security boundary for path validations within the function.
-func gatherSubGlances(baseDir string, subdirs []string) (string, error) {
-	var combined []string
-	for _, sd := range subdirs {
-		// Validate the subdirectory using the provided baseDir for consistent security boundary
-		validDir, err := filesystem.ValidateDirPath(sd, baseDir, true, true)
-		if err != nil {
-			logrus.Warnf("Skipping invalid subdirectory for glance output collection: %v", err)
-			continue
-		}
-
-		// Resolve the glance output path: prefer current filename, fall back to legacy.
-		candidateNames := []string{filesystem.GlanceFilename, filesystem.LegacyGlanceFilename}
-		var validPath string
-		for _, name := range candidateNames {
-			p := filepath.Join(validDir, name)
-			vp, vpErr := filesystem.ValidateFilePath(p, validDir, true, true)
-			if vpErr == nil {
-				validPath = vp
-				break
-			}
-		}
-		if validPath == "" {
-			logrus.Debugf("Skipping invalid glance output path for subdirectory: %s", validDir)
-			continue
-		}
-
-		// Use filesystem.ReadTextFile instead of os.ReadFile
-		// This provides better validation and UTF-8 handling
-		content, err := filesystem.ReadTextFile(validPath, 0, validDir)
-		if err == nil {
-			combined = append(combined, content)
-		}
-	}
-	return strings.Join(combined, "\n\n"), nil
-}
-
-// readSubdirectories lists immediate subdirectories in a directory, skipping hidden or ignored ones.
+// readSubdirectories lists immediate subdirectories in a directory, skipping hidden (unless
+// included by opts) or ignored ones.
 // This implementation uses filesystem package functions with appropriate filtering.
-func readSubdirectories(dir string, ignoreChain filesystem.IgnoreChain) ([]string, error) {
+func readSubdirectories(dir string, ignoreChain filesystem.IgnoreChain, opts ...filesystem.IgnoreOption) ([]string, error) {
 	// Get the parent directory to use as baseDir for validation
 	parentDir := filepath.Dir(dir)
 
@@ -591,7 +867,7 @@ func readSubdirectories(dir string, ignoreChain filesystem.IgnoreChain) ([]strin
 		fullPath := filepath.Join(validDir, name)
 
 		// Use the filesystem package for directory filtering
-		if filesystem.ShouldIgnoreDir(fullPath, validDir, ignoreChain) {
+		if filesystem.ShouldIgnoreDir(fullPath, validDir, ignoreChain, opts...) {
 			continue
 		}
 
@@ -630,44 +906,79 @@ func stubDescription(dir string, subdirs []string) string {
 }
 
 // gatherLocalFiles reads immediate files in a directory (excluding glance.md, hidden files, etc.).
-// This function now uses filesystem.GatherLocalFiles directly with the IgnoreChain.
-func gatherLocalFiles(dir string, ignoreChain filesystem.IgnoreChain, maxFileBytes int64) (map[string]string, error) {
-	// Use the filesystem package function that provides comprehensive validation and handling
-	return filesystem.GatherLocalFiles(dir, ignoreChain, maxFileBytes)
-}
+// This function uses filesystem.GatherLocalFiles with the IgnoreChain and the
+// file-reading options selected in cfg.
+func gatherLocalFiles(dir string, ignoreChain filesystem.IgnoreChain, cfg *config.Config) (map[string]string, error) {
+	var skipped []filesystem.SkippedFile
 
-// -----------------------------------------------------------------------------
-// results reporting
-// -----------------------------------------------------------------------------
+	// Use the filesystem package function that provides comprehensive validation and handling
+	files, err := filesystem.GatherLocalFiles(dir, ignoreChain, cfg.MaxFileBytes,
+		filesystem.WithReadCompressed(cfg.ReadCompressed),
+		filesystem.WithMaxFileAge(cfg.MaxFileAge),
+		filesystem.WithSampleLargeFiles(cfg.SampleLargeFiles),
+		filesystem.WithSkipGenerated(cfg.SkipGenerated),
+		filesystem.WithDetectEncoding(cfg.DetectEncoding),
+		filesystem.WithHiddenFiles(cfg.IncludeHidden),
+		filesystem.WithSkippedFiles(&skipped),
+	)
+	if err != nil {
+		return nil, err
+	}
 
-// printDebrief displays a summary of successes and failures.
-func printDebrief(results []result) {
-	var totalSuccess, totalFailed int
-	for _, r := range results {
-		if r.success {
-			totalSuccess++
-		} else {
-			totalFailed++
+	// In strict mode any file that could not be read fails the directory
+	if cfg.Strict && len(skipped) > 0 {
+		errs := make([]error, len(skipped))
+		for i, s := range skipped {
+			errs[i] = s
 		}
+		return nil, fmt.Errorf("strict mode: %d file(s) could not be read: %w", len(skipped), errors.Join(errs...))
 	}
-	logrus.Info("=== FINAL SUMMARY ===")
-	logrus.WithFields(logrus.Fields{
-		"total_dirs":    len(results),
-		"success_count": totalSuccess,
-		"failure_count": totalFailed,
-	}).Info("Directory processing summary")
+	return files, nil
+}
 
-	if totalFailed == 0 {
-		logrus.Info("Perfect run! No failures detected. Your codebase is now well-documented!")
+// dirQualifies reports whether dir directly contains a file with one of exts.
+// Unreadable directories are treated as non-qualifying.
+func dirQualifies(dir string, exts []string, ignoreChain filesystem.IgnoreChain, opts ...filesystem.IgnoreOption) bool {
+	qualifies, err := filesystem.HasFileWithExtension(dir, exts, ignoreChain, opts...)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"directory": dir,
+			"error":     err,
+		}).Warn("Couldn't inspect directory for qualifying file types")
+		return false
+	}
+	if !qualifies {
+		logrus.WithFields(logrus.Fields{
+			"directory":  dir,
+			"extensions": exts,
+			"action":     "skip",
+		}).Debug("Skipping directory - no files with qualifying extensions")
+	}
+	return qualifies
+}
+
+// explainDecision prints a directory's regeneration decision when --explain is set.
+func explainDecision(cfg *config.Config, dir string, reason string) {
+	if !cfg.Explain {
 		return
 	}
+	relDir, err := filepath.Rel(cfg.TargetDir, dir)
+	if err != nil {
+		relDir = dir
+	}
+	_, _ = fmt.Fprintf(explainOut, "%s: %s\n", relDir, reason)
+}
 
-	logrus.Info("Some directories couldn't be processed:")
-	for _, r := range results {
-		if !r.success {
-			// Use the UI error reporting
-			ui.ReportError(r.err, fmt.Sprintf("Failed to process %s (attempts: %d)", r.dir, r.attempts))
-		}
+// gitHistory returns the recent commit summaries for dir as a newline-separated block.
+// Failures are logged and yield an empty history so git metadata never blocks generation.
+func gitHistory(dir string) string {
+	commits, err := filesystem.RecentCommits(dir, config.DefaultGitHistoryCommits)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"directory": dir,
+			"error":     err,
+		}).Warn("Couldn't read git history")
+		return ""
 	}
-	logrus.Info("=====================")
+	return strings.Join(commits, "\n")
 }
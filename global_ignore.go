@@ -0,0 +1,56 @@
+package main
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"glance/config"
+	"glance/filesystem"
+)
+
+// scanOptions returns the directory scan options for cfg. With --only, the scan
+// lists just that subtree. With --include-hidden, hidden directories are listed
+// too. With --respect-global-gitignore, the user's global git excludes file
+// seeds the root ignore chain; a file that cannot be read is logged and skipped.
+func scanOptions(cfg *config.Config) []filesystem.ScanOption {
+	var options []filesystem.ScanOption
+	if cfg.IncludeHidden {
+		options = append(options, filesystem.WithIncludeHidden(true))
+	}
+	if cfg.Only != "" {
+		options = append(options, filesystem.WithSubtree(cfg.Only))
+	}
+	if cfg.OutputDir != "" {
+		options = append(options, filesystem.WithExcludedDir(cfg.OutputDir))
+	}
+	if !cfg.RespectGlobalGitignore {
+		return options
+	}
+
+	matcher, path, err := filesystem.LoadGlobalGitignore()
+	if err != nil {
+		logrus.WithField("error", err).Warn("Ignoring unreadable global git excludes file")
+		return options
+	}
+	if matcher == nil {
+		logrus.Debug("No global git excludes file found")
+		return options
+	}
+
+	logrus.WithField("path", path).Debug("Applying global git excludes file")
+	return append(options, filesystem.WithRootIgnore(matcher))
+}
+
+// ignoreOptions returns the adjustments cfg makes to the built-in ignore rules
+// when inspecting a single directory's files and subdirectories.
+func ignoreOptions(cfg *config.Config) []filesystem.IgnoreOption {
+	return []filesystem.IgnoreOption{filesystem.IncludeHidden(cfg.IncludeHidden), filesystem.ExcludeDir(cfg.OutputDir)}
+}
+
+// scanRoot returns the top of the tree being processed: the --only subtree, or
+// the target directory. Regeneration never bubbles up past it.
+func scanRoot(cfg *config.Config) string {
+	if cfg.Only != "" {
+		return cfg.Only
+	}
+	return cfg.TargetDir
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+)
+
+// TestScanOptionsGlobalGitignore verifies that --respect-global-gitignore applies the
+// default global excludes file ($XDG_CONFIG_HOME/git/ignore) to the scan.
+func TestScanOptionsGlobalGitignore(t *testing.T) {
+	// Isolate git from the real user and system config so the default location is used
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(home, "gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".config", "git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".config", "git", "ignore"), []byte("tmp/\n"), 0600))
+
+	root := t.TempDir()
+	t.Chdir(root)
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "tmp"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "src"), 0755))
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root)
+	assert.Nil(t, scanOptions(cfg), "global excludes are opt-in")
+	dirs, _, err := listAllDirsWithIgnores(root, scanOptions(cfg)...)
+	require.NoError(t, err)
+	assert.Len(t, dirs, 3)
+
+	cfg = cfg.WithRespectGlobalGitignore(true)
+	dirs, _, err = listAllDirsWithIgnores(root, scanOptions(cfg)...)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{root, filepath.Join(root, "src")}, dirs)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/llm"
+)
+
+// imageDescriptionModel is the vision model used by --describe-images.
+const imageDescriptionModel = stableModel
+
+// imageDescriber describes images for --describe-images. It stays nil unless
+// the flag is set; tests replace it with a mock.
+var imageDescriber llm.VisionDescriber
+
+// setupImageDescriber creates the vision model client for --describe-images
+// and installs it as imageDescriber. The returned function closes it.
+func setupImageDescriber(cfg *config.Config) (func(), error) {
+	if err := cfg.CheckModelAllowed(config.ProviderGemini, imageDescriptionModel); err != nil {
+		return nil, err
+	}
+	describer, err := llm.NewGeminiVisionDescriber(cfg.APIKey, geminiClientOptions(cfg, imageDescriptionModel)...)
+	if err != nil {
+		return nil, err
+	}
+	imageDescriber = describer
+	return describer.Close, nil
+}
+
+// imagePromptOptions returns the prompt option carrying descriptions of dir's
+// images, or none when image description is off, dir is a roll-up, or dir has
+// no images. An image that cannot be described is logged and left out rather
+// than failing dir.
+func imagePromptOptions(ctx context.Context, cfg *config.Config, dir string, ignoreChain filesystem.IgnoreChain) []llm.PromptDataOption {
+	if !cfg.DescribeImages || imageDescriber == nil || cfg.Rollup {
+		return nil
+	}
+
+	images, err := filesystem.GatherImages(dir, ignoreChain, cfg.MaxImages, cfg.MaxImageBytes, ignoreOptions(cfg)...)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"directory": dir,
+			"error":     err,
+		}).Warn("Failed to gather images; continuing without image descriptions")
+		return nil
+	}
+
+	descriptions := make(map[string]string, len(images))
+	for _, img := range images {
+		description, err := imageDescriber.DescribeImage(ctx, img.Name, img.MIMEType, img.Data)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"directory": dir,
+				"image":     img.Name,
+				"error":     err,
+			}).Warn("Failed to describe image; leaving it out of the prompt")
+			continue
+		}
+		descriptions[img.Name] = description
+	}
+	if len(descriptions) == 0 {
+		return nil
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"directory":   dir,
+		"image_count": len(descriptions),
+	}).Debug("Described images for prompt")
+	return []llm.PromptDataOption{llm.WithImageDescriptions(llm.FormatImageDescriptions(descriptions))}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// useImageDescriber installs describer as imageDescriber for the rest of the test.
+func useImageDescriber(t *testing.T, describer llm.VisionDescriber) {
+	t.Helper()
+	original := imageDescriber
+	imageDescriber = describer
+	t.Cleanup(func() { imageDescriber = original })
+}
+
+// imagePromptService returns a service whose prompts show only the image
+// descriptions, and the prompts it sends.
+func imagePromptService(t *testing.T) (*llm.Service, *[]string) {
+	t.Helper()
+	var prompts []string
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { prompts = append(prompts, args.String(1)) }).
+		Return("# summary\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+		llm.WithPromptTemplate("images [{{.ImageDescriptions}}]"))
+	require.NoError(t, err)
+	return service, &prompts
+}
+
+func TestDescribeImages(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "arch.png"), []byte("PNGDATA"), 0600))
+	cfg := config.NewDefaultConfig().WithTargetDir(dir).WithDescribeImages(true)
+
+	describer := new(mocks.VisionDescriber)
+	describer.On("DescribeImage", mock.Anything, "arch.png", "image/png", []byte("PNGDATA")).
+		Return("A box-and-arrow architecture diagram.", nil).Once()
+	useImageDescriber(t, describer)
+
+	service, prompts := imagePromptService(t)
+	r := processDirectory(context.Background(), dir, true, nil, cfg, service)
+	require.True(t, r.success, "processing failed: %v", r.err)
+
+	require.Len(t, *prompts, 1)
+	assert.Equal(t, "images [=== image: arch.png ===\nA box-and-arrow architecture diagram.\n\n]", (*prompts)[0])
+	describer.AssertExpectations(t)
+}
+
+func TestDescribeImagesNoOp(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "logo.png"), []byte("PNGDATA"), 0600))
+
+	t.Run("no images", func(t *testing.T) {
+		noImages := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(noImages, "main.go"), []byte("package main\n"), 0600))
+		describer := new(mocks.VisionDescriber)
+		useImageDescriber(t, describer)
+
+		service, prompts := imagePromptService(t)
+		cfg := config.NewDefaultConfig().WithTargetDir(noImages).WithDescribeImages(true)
+		r := processDirectory(context.Background(), noImages, true, nil, cfg, service)
+		require.True(t, r.success, "processing failed: %v", r.err)
+
+		assert.Equal(t, []string{"images []"}, *prompts)
+		describer.AssertNotCalled(t, "DescribeImage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("flag off", func(t *testing.T) {
+		describer := new(mocks.VisionDescriber)
+		useImageDescriber(t, describer)
+
+		service, prompts := imagePromptService(t)
+		r := processDirectory(context.Background(), dir, true, nil, config.NewDefaultConfig().WithTargetDir(dir), service)
+		require.True(t, r.success, "processing failed: %v", r.err)
+
+		assert.Equal(t, []string{"images []"}, *prompts)
+		describer.AssertNotCalled(t, "DescribeImage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("failed description is left out", func(t *testing.T) {
+		describer := new(mocks.VisionDescriber)
+		describer.On("DescribeImage", mock.Anything, "logo.png", "image/png", mock.Anything).
+			Return("", assert.AnError).Once()
+		useImageDescriber(t, describer)
+
+		service, prompts := imagePromptService(t)
+		cfg := config.NewDefaultConfig().WithTargetDir(dir).WithDescribeImages(true)
+		r := processDirectory(context.Background(), dir, true, nil, cfg, service)
+		require.True(t, r.success, "processing failed: %v", r.err)
+
+		assert.Equal(t, []string{"images []"}, *prompts)
+		describer.AssertExpectations(t)
+	})
+}
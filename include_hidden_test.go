@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestIncludeHidden verifies that --include-hidden summarizes dot-directories
+// and dotfiles, but never the .git directory.
+func TestIncludeHidden(t *testing.T) {
+	root := t.TempDir()
+	workflows := filepath.Join(root, ".github", "workflows")
+	gitDir := filepath.Join(root, ".git")
+	require.NoError(t, os.MkdirAll(workflows, 0755))
+	require.NoError(t, os.MkdirAll(gitDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(workflows, "ci.yml"), []byte("on: push\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "config"), []byte("[core]\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".editorconfig"), []byte("root = true\n"), 0600))
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithForce(true).WithIncludeHidden(true)
+
+	dirs, ignoreChains, err := scanDirectories(cfg)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{root, filepath.Join(root, ".github"), workflows}, dirs)
+
+	var prompts []string
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { prompts = append(prompts, args.String(1)) }).
+		Return("# summary\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+		llm.WithPromptTemplate("dir {{.Directory}}\n{{.FileContents}}"))
+	require.NoError(t, err)
+
+	results, _ := processDirectories(context.Background(), dirs, ignoreChains, cfg, service, io.Discard)
+	for _, r := range results {
+		assert.True(t, r.success, "processing %s failed: %v", r.dir, r.err)
+	}
+
+	assert.FileExists(t, filepath.Join(workflows, filesystem.GlanceFilename))
+	assert.NoFileExists(t, filepath.Join(gitDir, filesystem.GlanceFilename))
+	require.Len(t, prompts, 3)
+	assert.Contains(t, prompts[0], "on: push", "hidden directories' files are summarized")
+	assert.Contains(t, prompts[2], "root = true", "dotfiles are summarized")
+}
+
+// TestHiddenGlanceFile verifies end to end that the summary is written as the
+// hidden .glance.md, is never fed back to the model even when hidden files are
+// included, and is regenerated only once a file beside it changes.
+func TestHiddenGlanceFile(t *testing.T) {
+	root := t.TempDir()
+	source := filepath.Join(root, "main.go")
+	require.NoError(t, os.WriteFile(source, []byte("package main\n"), 0600))
+
+	var prompts []string
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { prompts = append(prompts, args.String(1)) }).
+		Return("# hidden summary\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+		llm.WithPromptTemplate("dir {{.Directory}}\n{{.FileContents}}"))
+	require.NoError(t, err)
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithIncludeHidden(true)
+	run := func() {
+		dirs, ignoreChains, err := scanDirectories(cfg)
+		require.NoError(t, err)
+		results, _ := processDirectories(context.Background(), dirs, ignoreChains, cfg, service, io.Discard)
+		for _, r := range results {
+			require.True(t, r.success, "processing %s failed: %v", r.dir, r.err)
+		}
+	}
+
+	run()
+	glancePath := filepath.Join(root, filesystem.GlanceFilename)
+	assert.Equal(t, ".glance.md", filepath.Base(glancePath))
+	assert.FileExists(t, glancePath)
+	assert.NoFileExists(t, filepath.Join(root, filesystem.LegacyGlanceFilename))
+	require.Len(t, prompts, 1)
+
+	run()
+	assert.Len(t, prompts, 1, "an up-to-date .glance.md is not regenerated")
+
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(source, later, later))
+	run()
+	require.Len(t, prompts, 2, "a changed file regenerates .glance.md")
+	assert.Contains(t, prompts[1], "package main")
+	assert.NotContains(t, prompts[1], "hidden summary", ".glance.md is never summarized as a file")
+}
+
+// TestIncludeHiddenSkipsDirConfig verifies that a directory's .glance.toml is
+// read as configuration but never included in the prompt, even with
+// --include-hidden.
+func TestIncludeHiddenSkipsDirConfig(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, config.DirConfigFilename),
+		[]byte("include_git_metadata = false # dirconfig-marker\n"), 0600))
+
+	var prompts []string
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { prompts = append(prompts, args.String(1)) }).
+		Return("# summary\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+		llm.WithPromptTemplate("dir {{.Directory}}\n{{.FileContents}}"))
+	require.NoError(t, err)
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithIncludeHidden(true)
+	results := processTree(t, cfg, service)
+	require.True(t, results[root].success, "processing failed: %v", results[root].err)
+
+	require.Len(t, prompts, 1)
+	assert.Contains(t, prompts[0], "package main")
+	assert.NotContains(t, prompts[0], "dirconfig-marker", config.DirConfigFilename+" is never summarized")
+}
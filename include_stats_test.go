@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestIncludeStatsBlock verifies that --include-stats places the computed stats
+// block above the LLM content and that the block is absent by default.
+func TestIncludeStatsBlock(t *testing.T) {
+	for _, includeStats := range []bool{true, false} {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Readme\n"), 0600))
+
+		mockLLMClient := new(mocks.LLMClient)
+		mockLLMClient.On("Generate", mock.Anything, mock.Anything).Return("# Summary\n\nGenerated content.\n", nil)
+		mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+		service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient}, llm.WithPromptTemplate("{{.Directory}}"))
+		require.NoError(t, err)
+
+		cfg := config.NewDefaultConfig().WithTargetDir(dir).WithIncludeStats(includeStats)
+		r := processDirectory(context.Background(), dir, true, nil, cfg, service)
+		require.NoError(t, r.err)
+
+		content, err := os.ReadFile(filepath.Join(dir, filesystem.GlanceFilename))
+		require.NoError(t, err)
+
+		if includeStats {
+			expectedBlock := filesystem.ComputeDirStats(map[string]string{
+				"main.go":   "package main\n\nfunc main() {}\n",
+				"README.md": "# Readme\n",
+			}).Render()
+			assert.True(t, strings.HasPrefix(string(content), expectedBlock), "stats block should come first")
+			assert.Contains(t, string(content), "**Files:** 2 · **Lines:** 4")
+			assert.True(t, strings.HasSuffix(string(content), "# Summary\n\nGenerated content.\n"))
+		} else {
+			assert.Equal(t, "# Summary\n\nGenerated content.\n", string(content))
+		}
+	}
+}
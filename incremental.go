@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/llm"
+)
+
+// generateSummary asks llmService for dir's summary. With --incremental-prompt
+// it updates the existing summary from a diff when one is usable (see
+// incrementalInputs), and otherwise summarizes the full file contents.
+func generateSummary(
+	ctx context.Context,
+	cfg *config.Config,
+	llmService *llm.Service,
+	dir string,
+	fileContents map[string]string,
+	subGlances string,
+	options ...llm.PromptDataOption,
+) (string, error) {
+	if previous, diff, ok := incrementalInputs(cfg, dir, fileContents); ok {
+		return llmService.UpdateGlanceMarkdown(ctx, promptDir(cfg, dir), fileContents, subGlances, previous, diff, options...)
+	}
+	return llmService.GenerateGlanceMarkdown(ctx, promptDir(cfg, dir), fileContents, subGlances, options...)
+}
+
+// incrementalInputs returns dir's existing summary, without the blocks glance
+// adds around it, and the git diff of its files since that summary was
+// committed, for --incremental-prompt. It reports false, so the directory is
+// summarized from its full contents, when the option is off or overridden by
+// --force, --compare, or --stdout; when there is no existing summary; and when
+// the diff is empty, unavailable, or no smaller than the files themselves.
+func incrementalInputs(cfg *config.Config, dir string, fileContents map[string]string) (string, string, bool) {
+	if !cfg.IncrementalPrompt || cfg.Force || cfg.Compare || cfg.Stdout {
+		return "", "", false
+	}
+
+	outputDir := glanceOutputDir(cfg, dir)
+	summaryPath, err := filesystem.ValidateFilePath(filepath.Join(outputDir, filesystem.GlanceFilename), outputDir, false, true)
+	if err != nil {
+		return "", "", false
+	}
+	// #nosec G304 -- path validated against the directory's output location above
+	existing, err := os.ReadFile(summaryPath)
+	if err != nil {
+		return "", "", false
+	}
+	previous := strings.TrimSpace(filesystem.StripSubGlanceHash(filesystem.StripStats(filesystem.StripFooter(string(existing)))))
+	if previous == "" {
+		return "", "", false
+	}
+
+	diff, err := filesystem.DiffSinceSummary(dir, summaryPath)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"directory": dir,
+			"error":     err,
+		}).Warn("Couldn't diff directory; summarizing full contents")
+		return "", "", false
+	}
+
+	var contentBytes int
+	for _, content := range fileContents {
+		contentBytes += len(content)
+	}
+	if diff == "" || len(diff) >= contentBytes {
+		logrus.WithFields(logrus.Fields{
+			"directory":     dir,
+			"diff_bytes":    len(diff),
+			"content_bytes": contentBytes,
+		}).Debug("No usable diff; summarizing full contents")
+		return "", "", false
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"directory":     dir,
+		"diff_bytes":    len(diff),
+		"content_bytes": contentBytes,
+	}).Debug("Updating existing summary from diff")
+	return previous, diff, true
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestIncrementalPrompt verifies that --incremental-prompt sends the existing
+// summary and the diff of changed files instead of their full contents.
+func TestIncrementalPrompt(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v failed: %s", args, out)
+	}
+
+	root := t.TempDir()
+	source := filepath.Join(root, "server.go")
+	unchanged := "// handleHealth reports liveness\nfunc handleHealth() {}\n"
+	original := "package server\n\n" + strings.Repeat(unchanged, 20) + "func Serve() error { return nil }\n"
+	require.NoError(t, os.WriteFile(source, []byte(original), 0600))
+	glancePath := filepath.Join(root, filesystem.GlanceFilename)
+	previous := "## Purpose\nAn HTTP server exposing Serve.\n"
+	require.NoError(t, os.WriteFile(glancePath, []byte(previous), 0600))
+	git(root, "init", "-q")
+	git(root, "add", "-A")
+	git(root, "commit", "-q", "-m", "init")
+
+	// Change the source after the summary so the directory is regenerated
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(glancePath, past, past))
+	changed := strings.Replace(original, "func Serve() error { return nil }", "func Serve(addr string) error { return listen(addr) }", 1)
+	require.NoError(t, os.WriteFile(source, []byte(changed), 0600))
+
+	run := func(cfg *config.Config) string {
+		t.Helper()
+		var prompts []string
+		mockLLMClient := new(mocks.LLMClient)
+		mockLLMClient.On("Generate", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { prompts = append(prompts, args.String(1)) }).
+			Return(previous, nil)
+		mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+		service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+			llm.WithPromptTemplate("dir {{.Directory}}\n{{.FileContents}}"))
+		require.NoError(t, err)
+
+		dirs, ignoreChains, err := scanDirectories(cfg)
+		require.NoError(t, err)
+		results, _ := processDirectories(context.Background(), dirs, ignoreChains, cfg, service, io.Discard)
+		for _, r := range results {
+			require.True(t, r.success, "processing %s failed: %v", r.dir, r.err)
+		}
+		require.Len(t, prompts, 1)
+		return prompts[0]
+	}
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithIncrementalPrompt(true)
+
+	prompt := run(cfg)
+	assert.Contains(t, prompt, "existing summary:\n"+strings.TrimSpace(previous))
+	assert.Contains(t, prompt, "-func Serve() error { return nil }")
+	assert.Contains(t, prompt, "+func Serve(addr string) error { return listen(addr) }")
+	assert.Contains(t, prompt, "- server.go", "the prompt still names the directory's files")
+	assert.Less(t, strings.Count(prompt, "handleHealth"), 20, "unchanged lines are not sent in full")
+
+	require.NoError(t, os.Chtimes(glancePath, past, past))
+	prompt = run(cfg.WithIncrementalPrompt(false))
+	assert.NotContains(t, prompt, "existing summary:")
+	assert.Contains(t, prompt, "func Serve(addr string) error { return listen(addr) }")
+	assert.Equal(t, 20, strings.Count(prompt, "handleHealth reports liveness"), "without the option the full contents are sent")
+
+	require.NoError(t, os.Chtimes(glancePath, past, past))
+	prompt = run(cfg.WithForce(true))
+	assert.NotContains(t, prompt, "existing summary:", "--force always summarizes full contents")
+}
@@ -0,0 +1,23 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// VisionDescriber is a mock implementation of llm.VisionDescriber.
+type VisionDescriber struct {
+	mock.Mock
+}
+
+// DescribeImage mocks the method that describes one image.
+func (m *VisionDescriber) DescribeImage(ctx context.Context, name, mimeType string, data []byte) (string, error) {
+	args := m.Called(ctx, name, mimeType, data)
+	return args.String(0), args.Error(1)
+}
+
+// Close mocks the method that releases resources.
+func (m *VisionDescriber) Close() {
+	m.Called()
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+
+	"glance/filesystem"
+	"glance/llm"
+)
+
+// sourceLinkBase returns the permalink base for --link-sources: the GitHub blob
+// URL of HEAD, extended with targetDir's path within the repository so that
+// directories relative to targetDir resolve correctly. It returns "" (relative
+// links) when targetDir is not in a git repository with a GitHub origin.
+func sourceLinkBase(targetDir string) string {
+	remote, commit, ok := filesystem.GitOrigin(targetDir)
+	if !ok {
+		return ""
+	}
+	base, ok := llm.GitHubPermalinkBase(remote, commit)
+	if !ok {
+		logrus.WithField("remote", remote).Debug("Origin is not a GitHub repository; using relative source links")
+		return ""
+	}
+
+	root, ok := filesystem.FindRepoRoot(targetDir)
+	if !ok {
+		return ""
+	}
+	rel, err := filepath.Rel(root, targetDir)
+	if err != nil {
+		return ""
+	}
+	if rel == "." {
+		return base
+	}
+	return base + "/" + filepath.ToSlash(rel)
+}
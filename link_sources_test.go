@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceLinkBase(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+
+	git := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v failed: %s", args, out)
+		return strings.TrimSpace(string(out))
+	}
+
+	repo := t.TempDir()
+	sub := filepath.Join(repo, "services", "api")
+	require.NoError(t, os.MkdirAll(sub, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(sub, "main.go"), []byte("package main\n"), 0600))
+	git(repo, "init", "-q")
+	git(repo, "add", "-A")
+	git(repo, "commit", "-q", "-m", "init")
+
+	assert.Empty(t, sourceLinkBase(repo), "no origin means relative links")
+
+	git(repo, "remote", "add", "origin", "https://gitlab.com/acme/widgets.git")
+	assert.Empty(t, sourceLinkBase(repo), "non-GitHub origin means relative links")
+
+	git(repo, "remote", "set-url", "origin", "git@github.com:acme/widgets.git")
+	sha := git(repo, "rev-parse", "HEAD")
+	assert.Equal(t, "https://github.com/acme/widgets/blob/"+sha, sourceLinkBase(repo))
+	assert.Equal(t, "https://github.com/acme/widgets/blob/"+sha+"/services/api", sourceLinkBase(sub),
+		"a target below the repository root is part of the base")
+}
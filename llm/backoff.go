@@ -1,14 +1,27 @@
 package llm
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/binary"
 	"math"
 	"time"
+
+	"glance/clock"
 )
 
 const jitterRatio = 0.20
 
+// backoffClock is the clock every retry backoff in this package waits on.
+// Tests replace it with a clock.Fake to run retries without real delays.
+var backoffClock = clock.Real()
+
+// sleepWithContext waits d on backoffClock, returning early with ctx.Err() if
+// ctx is done first.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	return backoffClock.Sleep(ctx, d)
+}
+
 // ExponentialBackoff returns wait time for the given 1-based attempt number.
 // It uses base*2^(attempt-1), caps at maxWait, and applies up to 20% jitter.
 func ExponentialBackoff(attempt int, base, maxWait time.Duration) time.Duration {
@@ -1,12 +1,30 @@
 package llm
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/clock"
+	"glance/internal/mocks"
 )
 
+// useFakeClock makes every backoff in the package wait on a fake clock for the
+// rest of the test.
+func useFakeClock(t *testing.T) *clock.Fake {
+	t.Helper()
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	original := backoffClock
+	backoffClock = fake
+	t.Cleanup(func() { backoffClock = original })
+	return fake
+}
+
 func TestExponentialBackoffJitter(t *testing.T) {
 	const samples = 100
 	base := 100 * time.Millisecond
@@ -90,3 +108,32 @@ func TestExponentialBackoff(t *testing.T) {
 		assert.LessOrEqual(t, wait, maxWait)
 	})
 }
+
+func TestFallbackClientBackoffWaitsOnClock(t *testing.T) {
+	fake := useFakeClock(t)
+	start := fake.Now()
+
+	primaryMock := new(mocks.LLMClient)
+	primaryMock.On("Generate", mock.Anything, "prompt").Return("", errors.New("transient")).Twice()
+	primaryMock.On("Generate", mock.Anything, "prompt").Return("ok", nil).Once()
+
+	// Backoffs long enough that a real clock would make the test crawl
+	client, err := NewFallbackClientWithBackoff(
+		[]FallbackTier{{Name: "primary", Client: NewMockClientAdapter(primaryMock)}},
+		2,
+		10*time.Second,
+		time.Minute,
+	)
+	require.NoError(t, err)
+
+	got, err := client.Generate(context.Background(), "prompt")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", got)
+
+	sleeps := fake.Sleeps()
+	require.Len(t, sleeps, 2, "one backoff before each retry")
+	assert.InDelta(t, float64(10*time.Second), float64(sleeps[0]), float64(2*time.Second))
+	assert.InDelta(t, float64(20*time.Second), float64(sleeps[1]), float64(4*time.Second))
+	assert.Equal(t, sleeps[0]+sleeps[1], fake.Now().Sub(start))
+	primaryMock.AssertExpectations(t)
+}
@@ -0,0 +1,67 @@
+// Package llm provides abstractions and implementations for interacting with
+// Large Language Model APIs in the glance application.
+package llm
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrBudgetExhausted is returned when a run's LLM request or token budget has been used up.
+var ErrBudgetExhausted = errors.New("LLM budget exhausted")
+
+// Budget caps the number of LLM requests and prompt tokens spent during a run.
+// It is safe for concurrent use. Once a request would exceed either cap the
+// budget is marked exhausted and every later request is refused, so that a run
+// stops making LLM calls rather than squeezing in occasional small prompts.
+type Budget struct {
+	mu          sync.Mutex
+	maxRequests int64
+	maxTokens   int64
+	requests    int64
+	tokens      int64
+	exhausted   bool
+}
+
+// NewBudget creates a Budget. A limit of zero or less disables that cap.
+func NewBudget(maxRequests, maxTokens int64) *Budget {
+	return &Budget{
+		maxRequests: maxRequests,
+		maxTokens:   maxTokens,
+	}
+}
+
+// Acquire records one request consuming the given number of prompt tokens.
+// It returns ErrBudgetExhausted, without recording anything, if the request
+// would exceed either cap or the budget is already exhausted.
+func (b *Budget) Acquire(tokens int64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.exhausted {
+		return ErrBudgetExhausted
+	}
+	if (b.maxRequests > 0 && b.requests+1 > b.maxRequests) ||
+		(b.maxTokens > 0 && b.tokens+tokens > b.maxTokens) {
+		b.exhausted = true
+		return ErrBudgetExhausted
+	}
+
+	b.requests++
+	b.tokens += tokens
+	return nil
+}
+
+// Exhausted reports whether a request has been refused for exceeding the budget.
+func (b *Budget) Exhausted() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.exhausted
+}
+
+// Usage returns the requests and prompt tokens recorded so far.
+func (b *Budget) Usage() (requests int64, tokens int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.requests, b.tokens
+}
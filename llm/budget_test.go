@@ -0,0 +1,101 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/internal/mocks"
+)
+
+func TestBudget(t *testing.T) {
+	t.Run("Request cap", func(t *testing.T) {
+		budget := NewBudget(2, 0)
+		assert.NoError(t, budget.Acquire(100))
+		assert.NoError(t, budget.Acquire(100))
+		assert.ErrorIs(t, budget.Acquire(1), ErrBudgetExhausted)
+		assert.True(t, budget.Exhausted())
+
+		requests, tokens := budget.Usage()
+		assert.Equal(t, int64(2), requests)
+		assert.Equal(t, int64(200), tokens)
+	})
+
+	t.Run("Token cap stays exhausted", func(t *testing.T) {
+		budget := NewBudget(0, 150)
+		assert.NoError(t, budget.Acquire(100))
+		assert.ErrorIs(t, budget.Acquire(100), ErrBudgetExhausted)
+		assert.ErrorIs(t, budget.Acquire(10), ErrBudgetExhausted, "a smaller request must not slip in after exhaustion")
+	})
+
+	t.Run("Unlimited", func(t *testing.T) {
+		budget := NewBudget(0, 0)
+		for i := 0; i < 100; i++ {
+			require.NoError(t, budget.Acquire(1000))
+		}
+		assert.False(t, budget.Exhausted())
+	})
+
+	t.Run("Concurrent acquisition never overspends", func(t *testing.T) {
+		budget := NewBudget(25, 0)
+		var granted sync.WaitGroup
+		var mu sync.Mutex
+		succeeded := 0
+		for i := 0; i < 100; i++ {
+			granted.Add(1)
+			go func() {
+				defer granted.Done()
+				if budget.Acquire(1) == nil {
+					mu.Lock()
+					succeeded++
+					mu.Unlock()
+				}
+			}()
+		}
+		granted.Wait()
+		assert.Equal(t, 25, succeeded)
+	})
+}
+
+func TestServiceStopsAtBudget(t *testing.T) {
+	mockClient := new(mocks.LLMClient)
+	mockClient.On("CountTokens", mock.Anything, mock.Anything).Return(40, nil)
+	mockClient.On("Generate", mock.Anything, mock.Anything).Return("# summary", nil)
+
+	service, err := NewService(
+		NewMockClientAdapter(mockClient),
+		WithPromptTemplate("{{.Directory}}"),
+		WithBudget(NewBudget(0, 100)),
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err := service.GenerateGlanceMarkdown(context.Background(), "dir", map[string]string{}, "")
+		require.NoError(t, err)
+	}
+
+	_, err = service.GenerateGlanceMarkdown(context.Background(), "dir", map[string]string{}, "")
+	assert.True(t, errors.Is(err, ErrBudgetExhausted))
+	mockClient.AssertNumberOfCalls(t, "Generate", 2)
+}
+
+func TestServiceBudgetEstimatesUncountedTokens(t *testing.T) {
+	mockClient := new(mocks.LLMClient)
+	mockClient.On("CountTokens", mock.Anything, mock.Anything).Return(0, errors.New("unsupported"))
+	mockClient.On("Generate", mock.Anything, mock.Anything).Return("# summary", nil)
+
+	budget := NewBudget(0, 1000)
+	service, err := NewService(NewMockClientAdapter(mockClient), WithPromptTemplate("0123456789abcdef"), WithBudget(budget))
+	require.NoError(t, err)
+
+	_, err = service.GenerateGlanceMarkdown(context.Background(), "dir", map[string]string{}, "")
+	require.NoError(t, err)
+
+	_, tokens := budget.Usage()
+	assert.Equal(t, int64(4), tokens)
+}
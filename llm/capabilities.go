@@ -0,0 +1,72 @@
+// Package llm provides abstractions and implementations for interacting with
+// Large Language Model APIs in the glance application.
+package llm
+
+// ClientCapabilities describes which optional features a client supports natively.
+type ClientCapabilities struct {
+	// SupportsStreaming is true when GenerateStream yields incremental chunks
+	// rather than a single chunk produced by a blocking Generate call
+	SupportsStreaming bool
+
+	// SupportsTokenCount is true when CountTokens returns real counts
+	SupportsTokenCount bool
+
+	// SupportsMultimodal is true when the provider accepts non-text input
+	SupportsMultimodal bool
+}
+
+// CapabilityReporter is implemented by clients that can report their capabilities.
+// It is separate from Client so that existing clients and test doubles keep working.
+type CapabilityReporter interface {
+	Capabilities() ClientCapabilities
+}
+
+// CapabilitiesOf returns the capabilities reported by client. Clients that do not
+// implement CapabilityReporter are assumed to support streaming and token counting,
+// matching the Client contract, but not multimodal input.
+func CapabilitiesOf(client Client) ClientCapabilities {
+	if reporter, ok := client.(CapabilityReporter); ok {
+		return reporter.Capabilities()
+	}
+	return ClientCapabilities{SupportsStreaming: true, SupportsTokenCount: true}
+}
+
+// Capabilities reports Gemini's native streaming, token counting, and multimodal input.
+func (c *GeminiClient) Capabilities() ClientCapabilities {
+	return ClientCapabilities{SupportsStreaming: true, SupportsTokenCount: true, SupportsMultimodal: true}
+}
+
+// Capabilities reports that chat-completions clients fake streaming with a single
+// chunk, cannot count tokens, and send text only.
+func (c *chatCompletionsClient) Capabilities() ClientCapabilities {
+	return ClientCapabilities{}
+}
+
+// Capabilities combines the tiers' capabilities. Streaming and token counting try
+// each tier in turn, so any supporting tier suffices. Multimodal input must work
+// whichever tier ends up serving the request, so every tier must support it.
+func (c *FallbackClient) Capabilities() ClientCapabilities {
+	caps := ClientCapabilities{SupportsMultimodal: len(c.tiers) > 0}
+	for _, tier := range c.tiers {
+		tierCaps := CapabilitiesOf(tier.Client)
+		caps.SupportsStreaming = caps.SupportsStreaming || tierCaps.SupportsStreaming
+		caps.SupportsTokenCount = caps.SupportsTokenCount || tierCaps.SupportsTokenCount
+		caps.SupportsMultimodal = caps.SupportsMultimodal && tierCaps.SupportsMultimodal
+	}
+	return caps
+}
+
+// ResponseValidator is implemented by clients that vet each response before
+// returning it, such as a FallbackClient with WithResponseValidator. Callers
+// that assemble a response themselves, from a stream, apply the same check.
+type ResponseValidator interface {
+	ValidateResponse(response string) error
+}
+
+// validateResponse checks response with client's ValidateResponse, if it has one.
+func validateResponse(client Client, response string) error {
+	if validator, ok := client.(ResponseValidator); ok {
+		return validator.ValidateResponse(response)
+	}
+	return nil
+}
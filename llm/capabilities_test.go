@@ -0,0 +1,90 @@
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/internal/mocks"
+)
+
+// textOnlyClient wraps a Client and reports no optional capabilities.
+type textOnlyClient struct {
+	Client
+}
+
+func (textOnlyClient) Capabilities() ClientCapabilities { return ClientCapabilities{} }
+
+func TestClientCapabilities(t *testing.T) {
+	t.Run("Gemini", func(t *testing.T) {
+		caps := CapabilitiesOf(&GeminiClient{})
+		assert.Equal(t, ClientCapabilities{SupportsStreaming: true, SupportsTokenCount: true, SupportsMultimodal: true}, caps)
+	})
+
+	t.Run("OpenRouter", func(t *testing.T) {
+		client, err := NewOpenRouterClient("test-key", WithModelName("x-ai/grok-4.1-fast"))
+		require.NoError(t, err)
+		assert.Equal(t, ClientCapabilities{}, CapabilitiesOf(client))
+	})
+
+	t.Run("OpenAI-compatible", func(t *testing.T) {
+		client, err := NewOpenAICompatibleClient("http://localhost:11434/v1", "", WithModelName("llama3.2"))
+		require.NoError(t, err)
+		assert.Equal(t, ClientCapabilities{}, CapabilitiesOf(client))
+	})
+
+	t.Run("Clients without a report get the interface defaults", func(t *testing.T) {
+		caps := CapabilitiesOf(NewMockClientAdapter(new(mocks.LLMClient)))
+		assert.Equal(t, ClientCapabilities{SupportsStreaming: true, SupportsTokenCount: true}, caps)
+	})
+}
+
+func TestFallbackClientCapabilities(t *testing.T) {
+	gemini := &GeminiClient{}
+	openRouter, err := NewOpenRouterClient("test-key", WithModelName("x-ai/grok-4.1-fast"))
+	require.NoError(t, err)
+
+	t.Run("Streaming and token counting are a union", func(t *testing.T) {
+		client, err := NewFallbackClient([]FallbackTier{{Client: gemini}, {Client: openRouter}}, 0)
+		require.NoError(t, err)
+		caps := CapabilitiesOf(client)
+		assert.True(t, caps.SupportsStreaming)
+		assert.True(t, caps.SupportsTokenCount)
+	})
+
+	t.Run("Multimodal is an intersection", func(t *testing.T) {
+		mixed, err := NewFallbackClient([]FallbackTier{{Client: gemini}, {Client: openRouter}}, 0)
+		require.NoError(t, err)
+		assert.False(t, CapabilitiesOf(mixed).SupportsMultimodal)
+
+		geminiOnly, err := NewFallbackClient([]FallbackTier{{Client: gemini}, {Client: &GeminiClient{}}}, 0)
+		require.NoError(t, err)
+		assert.True(t, CapabilitiesOf(geminiOnly).SupportsMultimodal)
+	})
+
+	t.Run("No supporting tier", func(t *testing.T) {
+		client, err := NewFallbackClient([]FallbackTier{{Client: openRouter}}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, ClientCapabilities{}, CapabilitiesOf(client))
+	})
+}
+
+func TestServiceSkipsTokenCountWhenUnsupported(t *testing.T) {
+	mockClient := new(mocks.LLMClient)
+	mockClient.On("Generate", mock.Anything, mock.Anything).Return("# summary", nil)
+
+	budget := NewBudget(0, 1000)
+	service, err := NewService(textOnlyClient{NewMockClientAdapter(mockClient)},
+		WithPromptTemplate("{{.Directory}}"), WithBudget(budget))
+	require.NoError(t, err)
+
+	_, genErr := service.GenerateGlanceMarkdown(context.Background(), "pkg", nil, "")
+	require.NoError(t, genErr)
+	mockClient.AssertNotCalled(t, "CountTokens", mock.Anything, mock.Anything)
+
+	_, tokens := budget.Usage()
+	assert.Equal(t, estimateTokens("pkg"), tokens, "the budget is charged an estimate instead")
+}
@@ -0,0 +1,240 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"glance/filesystem"
+)
+
+// ErrNotRecorded is returned by a ReplayClient for a prompt its cassette has no
+// response for.
+var ErrNotRecorded = errors.New("prompt not recorded in cassette")
+
+// cassette is the JSON file a RecordingClient writes and a ReplayClient reads:
+// every prompt the recorded client answered, with its response, in order.
+type cassette struct {
+	Interactions []cassetteInteraction `json:"interactions"`
+}
+
+// cassetteInteraction is one recorded prompt and the response it received.
+type cassetteInteraction struct {
+	Prompt   string `json:"prompt"`
+	Response string `json:"response"`
+}
+
+// RecordingClient proxies another Client and records every successful
+// generation to a cassette file that a ReplayClient can serve later, for
+// reproducible tests and demos that need no API access.
+type RecordingClient struct {
+	inner Client
+	path  string
+
+	mu       sync.Mutex
+	cassette cassette
+	index    map[string]int // prompt -> position in cassette.Interactions
+}
+
+// ReplayClient is a Client that answers prompts from a cassette recorded by a
+// RecordingClient instead of calling an API. Prompts missing from the cassette
+// fail with ErrNotRecorded.
+type ReplayClient struct {
+	responses map[string]string
+}
+
+// NewRecordingClient wraps inner so that every response it generates is
+// recorded to the cassette at path. The cassette is rewritten after each new
+// response, so it is complete even if the run is interrupted; an existing file
+// at path is replaced.
+//
+// Parameters:
+//   - inner: The client that answers the prompts
+//   - path: The cassette file to write
+//
+// Returns:
+//   - A new RecordingClient
+//   - An error if path is invalid
+func NewRecordingClient(inner Client, path string) (Client, error) {
+	if inner == nil {
+		return nil, errors.New("recording client requires a client to record")
+	}
+	validPath, err := cassettePath(path, false)
+	if err != nil {
+		return nil, err
+	}
+	return &RecordingClient{inner: inner, path: validPath, index: make(map[string]int)}, nil
+}
+
+// NewReplayClient returns a Client serving the responses in the cassette at
+// path, which a RecordingClient wrote.
+//
+// Parameters:
+//   - path: The cassette file to read
+//
+// Returns:
+//   - A new ReplayClient
+//   - An error if the cassette cannot be read or parsed
+func NewReplayClient(path string) (Client, error) {
+	validPath, err := cassettePath(path, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// #nosec G304 -- Path has been validated using filesystem.ValidateFilePath
+	data, err := os.ReadFile(validPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", validPath, err)
+	}
+
+	responses := make(map[string]string, len(c.Interactions))
+	for _, interaction := range c.Interactions {
+		responses[interaction.Prompt] = interaction.Response
+	}
+	return &ReplayClient{responses: responses}, nil
+}
+
+// cassettePath absolutizes and validates a cassette path.
+func cassettePath(path string, mustExist bool) (string, error) {
+	if strings.TrimSpace(path) == "" {
+		return "", errors.New("cassette path cannot be empty")
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid cassette path: %w", err)
+	}
+	validPath, err := filesystem.ValidateFilePath(absPath, filepath.Dir(absPath), false, mustExist)
+	if err != nil {
+		return "", fmt.Errorf("invalid cassette path: %w", err)
+	}
+	return validPath, nil
+}
+
+// Generate asks the inner client and records a successful response.
+func (c *RecordingClient) Generate(ctx context.Context, prompt string) (string, error) {
+	response, err := c.inner.Generate(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+	if err := c.record(prompt, response); err != nil {
+		return "", err
+	}
+	return response, nil
+}
+
+// GenerateStream streams from the inner client and records the complete
+// response once the stream finishes without an error.
+func (c *RecordingClient) GenerateStream(ctx context.Context, prompt string) (<-chan StreamChunk, error) {
+	innerChan, err := c.inner.GenerateStream(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan StreamChunk)
+	go func() {
+		defer close(out)
+		var response strings.Builder
+		failed := false
+		for chunk := range innerChan {
+			if chunk.Error != nil {
+				failed = true
+			}
+			response.WriteString(chunk.Text)
+			if chunk.Done && !failed {
+				if recErr := c.record(prompt, response.String()); recErr != nil {
+					chunk = StreamChunk{Error: recErr, Done: true}
+				}
+			}
+			out <- chunk
+		}
+	}()
+	return out, nil
+}
+
+// CountTokens delegates to the inner client; token counts are not recorded.
+func (c *RecordingClient) CountTokens(ctx context.Context, prompt string) (int, error) {
+	return c.inner.CountTokens(ctx, prompt)
+}
+
+// Close closes the inner client.
+func (c *RecordingClient) Close() {
+	c.inner.Close()
+}
+
+// record adds or replaces the response for prompt and rewrites the cassette.
+func (c *RecordingClient) record(prompt, response string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	interaction := cassetteInteraction{Prompt: prompt, Response: response}
+	if i, ok := c.index[prompt]; ok {
+		c.cassette.Interactions[i] = interaction
+	} else {
+		c.index[prompt] = len(c.cassette.Interactions)
+		c.cassette.Interactions = append(c.cassette.Interactions, interaction)
+	}
+
+	data, err := json.MarshalIndent(c.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+	if err := filesystem.WriteFileAtomic(c.path, data, ""); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// Generate returns the recorded response for prompt.
+func (c *ReplayClient) Generate(ctx context.Context, prompt string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	response, ok := c.responses[prompt]
+	if !ok {
+		return "", fmt.Errorf("%w (%d-byte prompt starting %q)", ErrNotRecorded, len(prompt), promptPreview(prompt))
+	}
+	return response, nil
+}
+
+// GenerateStream sends the recorded response for prompt as a single chunk.
+func (c *ReplayClient) GenerateStream(ctx context.Context, prompt string) (<-chan StreamChunk, error) {
+	response, err := c.Generate(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan StreamChunk, 1)
+	out <- StreamChunk{Text: response, Done: true}
+	close(out)
+	return out, nil
+}
+
+// CountTokens estimates the prompt's tokens, since no API is available.
+func (c *ReplayClient) CountTokens(_ context.Context, prompt string) (int, error) {
+	return int(estimateTokens(prompt)), nil
+}
+
+// Close implements Client; a ReplayClient holds no resources.
+func (c *ReplayClient) Close() {}
+
+// promptPreview returns the start of prompt for error messages.
+func promptPreview(prompt string) string {
+	const maxPreview = 60
+	if len(prompt) <= maxPreview {
+		return prompt
+	}
+	cut := maxPreview
+	for cut > 0 && !utf8.RuneStart(prompt[cut]) {
+		cut--
+	}
+	return prompt[:cut] + "..."
+}
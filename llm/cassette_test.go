@@ -0,0 +1,119 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/internal/mocks"
+)
+
+func TestRecordThenReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	responses := map[string]string{
+		"summarize cmd":     "# cmd\n\nThe CLI entrypoint.\n",
+		"summarize llm":     "# llm\n\nClients for Gemini and OpenRouter.\n",
+		"summarize ünïcödé": "# docs\n\nNon-ASCII survives the round trip: ✓\n",
+	}
+
+	mockClient := new(mocks.LLMClient)
+	for prompt, response := range responses {
+		mockClient.On("Generate", mock.Anything, prompt).Return(response, nil).Once()
+	}
+	mockClient.On("Generate", mock.Anything, "failing prompt").Return("", errors.New("API down")).Once()
+	mockClient.On("Close").Return().Once()
+
+	recorder, err := NewRecordingClient(NewMockClientAdapter(mockClient), path)
+	require.NoError(t, err)
+	for prompt, want := range responses {
+		got, genErr := recorder.Generate(context.Background(), prompt)
+		require.NoError(t, genErr)
+		assert.Equal(t, want, got)
+	}
+	_, err = recorder.Generate(context.Background(), "failing prompt")
+	assert.EqualError(t, err, "API down", "inner errors pass through unchanged")
+	recorder.Close()
+	mockClient.AssertExpectations(t)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	replay, err := NewReplayClient(path)
+	require.NoError(t, err)
+	defer replay.Close()
+	for prompt, want := range responses {
+		got, genErr := replay.Generate(context.Background(), prompt)
+		require.NoError(t, genErr)
+		assert.Equal(t, want, got, "replay must return exactly what was recorded")
+	}
+
+	_, err = replay.Generate(context.Background(), "failing prompt")
+	assert.ErrorIs(t, err, ErrNotRecorded, "failed generations are not recorded")
+	_, err = replay.Generate(context.Background(), strings.Repeat("unknown ", 20))
+	assert.ErrorIs(t, err, ErrNotRecorded)
+	assert.Contains(t, err.Error(), "160-byte prompt starting \"unknown unknown")
+
+	chunks, err := replay.GenerateStream(context.Background(), "summarize cmd")
+	require.NoError(t, err)
+	var streamed strings.Builder
+	for chunk := range chunks {
+		require.NoError(t, chunk.Error)
+		streamed.WriteString(chunk.Text)
+	}
+	assert.Equal(t, responses["summarize cmd"], streamed.String())
+}
+
+func TestRecordingClientStream(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	innerChan := make(chan mocks.StreamChunk, 3)
+	innerChan <- mocks.StreamChunk{Text: "# streamed"}
+	innerChan <- mocks.StreamChunk{Text: " summary\n"}
+	innerChan <- mocks.StreamChunk{Done: true}
+	close(innerChan)
+
+	mockClient := new(mocks.LLMClient)
+	mockClient.On("GenerateStream", mock.Anything, "stream prompt").Return((<-chan mocks.StreamChunk)(innerChan), nil)
+
+	recorder, err := NewRecordingClient(NewMockClientAdapter(mockClient), path)
+	require.NoError(t, err)
+	chunks, err := recorder.GenerateStream(context.Background(), "stream prompt")
+	require.NoError(t, err)
+	var streamed strings.Builder
+	for chunk := range chunks {
+		require.NoError(t, chunk.Error)
+		streamed.WriteString(chunk.Text)
+	}
+	assert.Equal(t, "# streamed summary\n", streamed.String())
+
+	replay, err := NewReplayClient(path)
+	require.NoError(t, err)
+	got, err := replay.Generate(context.Background(), "stream prompt")
+	require.NoError(t, err)
+	assert.Equal(t, "# streamed summary\n", got)
+}
+
+func TestCassetteErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := NewReplayClient(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+
+	corrupt := filepath.Join(dir, "corrupt.json")
+	require.NoError(t, os.WriteFile(corrupt, []byte("{not json"), 0600))
+	_, err = NewReplayClient(corrupt)
+	assert.ErrorContains(t, err, "failed to parse cassette")
+
+	_, err = NewRecordingClient(nil, filepath.Join(dir, "cassette.json"))
+	assert.Error(t, err)
+	_, err = NewRecordingClient(NewMockClientAdapter(new(mocks.LLMClient)), "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,147 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+// chunkSummaryPrompt asks for the short per-file summary that stands in for a
+// large file's contents in its directory's prompt.
+const chunkSummaryPrompt = `Summarize this file for someone who will describe the directory it belongs to.
+Keep the names of exported types and functions, entrypoints, configuration keys,
+and anything unusual. Answer in a few short paragraphs or bullet points.
+
+file: %s%s
+
+%s`
+
+// compressLargeFiles returns fileMap with every file over the chunk-summarize
+// threshold replaced by a summary of it, so large files inform the directory
+// summary without crowding everything else out of the prompt ("map-reduce"
+// summarization). Each such file is cut into chunks of at most the threshold,
+// every chunk is summarized with its own call, and the chunk summaries stand
+// in for the file. Smaller files are kept verbatim; fileMap is not modified.
+func (s *Service) compressLargeFiles(ctx context.Context, dir string, fileMap map[string]string) (map[string]string, error) {
+	if s.chunkBytes <= 0 {
+		return fileMap, nil
+	}
+
+	names := make([]string, 0, len(fileMap))
+	for name, content := range fileMap {
+		if len(content) > s.chunkBytes {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return fileMap, nil
+	}
+	sort.Strings(names)
+
+	compressed := make(map[string]string, len(fileMap))
+	for name, content := range fileMap {
+		compressed[name] = content
+	}
+	for _, name := range names {
+		summary, err := s.summarizeFile(ctx, dir, name, fileMap[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to pre-summarize %s: %w", name, err)
+		}
+		compressed[name] = summary
+	}
+	return compressed, nil
+}
+
+// summarizeFile summarizes one large file chunk by chunk.
+func (s *Service) summarizeFile(ctx context.Context, dir, name, content string) (string, error) {
+	chunks := splitChunks(content, s.chunkBytes)
+	parts := make([]string, 0, len(chunks))
+	for i, chunk := range chunks {
+		part := ""
+		if len(chunks) > 1 {
+			part = fmt.Sprintf(" (part %d of %d)", i+1, len(chunks))
+		}
+		summary, err := s.preSummarize(ctx, dir, fmt.Sprintf(chunkSummaryPrompt, name, part, chunk))
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, strings.TrimSpace(summary))
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"directory": dir,
+		"file":      name,
+		"bytes":     len(content),
+		"chunks":    len(chunks),
+		"operation": "chunk_summarize",
+	}).Debug("Replaced large file with its summary")
+
+	return fmt.Sprintf("[Summary of a %d-byte file too large to include verbatim]\n\n%s\n",
+		len(content), strings.Join(parts, "\n\n")), nil
+}
+
+// preSummarize makes one pre-summary call, served from the summary cache when
+// possible and charged against the budget like any other request.
+func (s *Service) preSummarize(ctx context.Context, dir, prompt string) (string, error) {
+	var cacheKey string
+	if s.summaryCache != nil {
+		cacheKey = s.summaryCacheKey(prompt)
+		if cached, ok := s.summaryCache.Get(cacheKey); ok {
+			return cached, nil
+		}
+	}
+
+	if s.budget != nil {
+		if err := s.budget.Acquire(estimateTokens(prompt)); err != nil {
+			return "", fmt.Errorf("skipping %s: %w", dir, err)
+		}
+	}
+
+	client := s.chunkClient
+	if client == nil {
+		client = s.client
+	}
+	result, err := s.generateWith(ctx, client, prompt, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if s.summaryCache != nil {
+		if putErr := s.summaryCache.Put(cacheKey, result); putErr != nil {
+			s.log.WithFields(logrus.Fields{
+				"directory": dir,
+				"operation": "summary_cache",
+				"error":     putErr,
+			}).Warn("Failed to store file summary in cache")
+		}
+	}
+	return result, nil
+}
+
+// splitChunks cuts content into pieces of at most maxBytes, preferring to end
+// each piece at a line break and never splitting a UTF-8 character.
+func splitChunks(content string, maxBytes int) []string {
+	var chunks []string
+	for len(content) > maxBytes {
+		cut := strings.LastIndexByte(content[:maxBytes], '\n') + 1
+		if cut == 0 {
+			cut = maxBytes
+			for cut > 0 && !utf8.RuneStart(content[cut]) {
+				cut--
+			}
+			if cut == 0 {
+				cut = maxBytes
+			}
+		}
+		chunks = append(chunks, content[:cut])
+		content = content[cut:]
+	}
+	if content != "" {
+		chunks = append(chunks, content)
+	}
+	return chunks
+}
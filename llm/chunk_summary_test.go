@@ -0,0 +1,79 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/internal/mocks"
+)
+
+func TestGenerateGlanceMarkdownChunkSummarize(t *testing.T) {
+	mockClient := new(mocks.LLMClient)
+	service, err := NewService(NewMockClientAdapter(mockClient),
+		WithPromptTemplate("{{.FileContents}}"),
+		WithChunkSummarize(50),
+	)
+	require.NoError(t, err)
+
+	var prompts []string
+	mockClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil)
+	mockClient.On("Generate", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { prompts = append(prompts, args.String(1)) }).
+		Return("summary text", nil)
+
+	large := strings.Repeat("func big() {} // line\n", 3) // 66 bytes, two chunks
+	files := map[string]string{"large.go": large, "small.go": "package small"}
+	_, err = service.GenerateGlanceMarkdown(context.Background(), "pkg", files, "")
+	require.NoError(t, err)
+
+	require.Len(t, prompts, 3, "one pre-summary per chunk of the large file, then the directory")
+	assert.Contains(t, prompts[0], "file: large.go (part 1 of 2)")
+	assert.Contains(t, prompts[1], "file: large.go (part 2 of 2)")
+	for _, prompt := range prompts[:2] {
+		assert.NotContains(t, prompt, "small.go", "small files get no pre-summary")
+	}
+
+	directoryPrompt := prompts[2]
+	assert.Contains(t, directoryPrompt, "package small", "small files are included verbatim")
+	assert.Contains(t, directoryPrompt, "[Summary of a 66-byte file too large to include verbatim]")
+	assert.Contains(t, directoryPrompt, "summary text\n\nsummary text")
+	assert.NotContains(t, directoryPrompt, "func big()")
+	assert.Equal(t, large, files["large.go"], "the caller's file map is left alone")
+}
+
+func TestChunkSummaryClient(t *testing.T) {
+	mainClient := new(mocks.LLMClient)
+	chunkClient := new(mocks.LLMClient)
+	service, err := NewService(NewMockClientAdapter(mainClient),
+		WithPromptTemplate("{{.FileContents}}"),
+		WithChunkSummarize(10),
+		WithChunkSummaryClient(NewMockClientAdapter(chunkClient)),
+	)
+	require.NoError(t, err)
+
+	mainClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil)
+	mainClient.On("Generate", mock.Anything, mock.Anything).Return("# pkg summary", nil).Once()
+	chunkClient.On("Generate", mock.Anything, mock.MatchedBy(func(prompt string) bool {
+		return strings.Contains(prompt, "file: large.go")
+	})).Return("cheap summary", nil).Twice()
+
+	result, err := service.GenerateGlanceMarkdown(context.Background(), "pkg",
+		map[string]string{"large.go": "0123456789abcdef"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "# pkg summary", result)
+	mainClient.AssertExpectations(t)
+	chunkClient.AssertExpectations(t)
+}
+
+func TestSplitChunks(t *testing.T) {
+	assert.Equal(t, []string{"ab\n", "cd\n", "ef"}, splitChunks("ab\ncd\nef", 4), "chunks end at line breaks")
+	assert.Equal(t, []string{"abcd", "efgh", "ij"}, splitChunks("abcdefghij", 4), "long lines are cut")
+	assert.Equal(t, []string{"a", "é", "é"}, splitChunks("aéé", 2), "characters are never split")
+	assert.Equal(t, []string{"small"}, splitChunks("small", 10))
+	assert.Empty(t, splitChunks("", 10))
+}
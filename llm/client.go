@@ -124,6 +124,10 @@ type ClientOptions struct {
 	// MaxOutputTokens limits the length of the generated content
 	MaxOutputTokens int32
 
+	// Deterministic requests greedy decoding. Temperature 0 is then sent
+	// explicitly instead of being treated as unset.
+	Deterministic bool
+
 	// CandidateCount is the number of response alternatives to generate
 	CandidateCount int32
 
@@ -135,6 +139,46 @@ type ClientOptions struct {
 
 	// SystemInstructions provide context or persona to the model
 	SystemInstructions string
+
+	// ResponseMIMEType pins the format of the model's reply, such as
+	// "text/plain" (Gemini only); empty leaves it to the model
+	ResponseMIMEType string
+
+	// Endpoint configuration (Gemini only)
+	// Backend selects the Gemini Developer API or Vertex AI; empty means the Gemini API
+	Backend Backend
+
+	// BaseURL overrides the backend's default endpoint; empty keeps the default
+	BaseURL string
+
+	// Request metadata
+	// ExtraHeaders are added to every HTTP request; reserved headers are ignored
+	ExtraHeaders map[string]string
+
+	// Conversation shaping (chat-completions clients only)
+	// MessageShaper builds the request messages; nil uses DefaultMessageShaper
+	MessageShaper MessageShaper
+
+	// ModelFallbacks are models OpenRouter tries, in order, when ModelName is
+	// unavailable (OpenRouter only); empty sends no "models" list
+	ModelFallbacks []string
+
+	// Retry backoff by failure class (chat-completions clients only)
+	// ServerErrorBackoff is the first wait before retrying an HTTP 5xx response,
+	// doubled per attempt; zero uses 100ms
+	ServerErrorBackoff time.Duration
+
+	// RateLimitBackoff is the first wait before retrying an HTTP 429 response
+	// without a Retry-After header, doubled per attempt; zero uses 1s
+	RateLimitBackoff time.Duration
+
+	// Observability
+	// OnRetry is called before each retry; nil disables it (see WithOnRetry)
+	OnRetry RetryFunc
+
+	// Logging
+	// Logger receives the client's log output; nil uses the standard logrus logger
+	Logger logrus.FieldLogger
 }
 
 // DefaultClientOptions returns a ClientOptions instance with sensible defaults.
@@ -211,6 +255,18 @@ func WithTopK(topK float32) ClientOption {
 	}
 }
 
+// WithDeterministic configures greedy decoding for reproducible output:
+// temperature 0 and top-k 1, with top-p left wide open so it never interferes.
+// Apply it after any individual sampling options it should override.
+func WithDeterministic() ClientOption {
+	return func(o *ClientOptions) {
+		o.Deterministic = true
+		o.Temperature = 0
+		o.TopP = 1
+		o.TopK = 1
+	}
+}
+
 // WithMaxOutputTokens sets the maximum number of tokens to generate.
 // This limits the length of the response.
 func WithMaxOutputTokens(maxOutputTokens int32) ClientOption {
@@ -254,6 +310,39 @@ func WithSystemInstructions(instructions string) ClientOption {
 	}
 }
 
+// WithModelFallbacks sets the models OpenRouter routes to, in order, when the
+// primary model is unavailable. Other clients ignore it.
+func WithModelFallbacks(models []string) ClientOption {
+	return func(o *ClientOptions) {
+		o.ModelFallbacks = models
+	}
+}
+
+// WithRetryBackoff sets the first waits before a chat-completions client
+// retries an HTTP 5xx response and an HTTP 429 response without Retry-After;
+// each doubles per attempt. Zero keeps the default for that class. 429s with
+// Retry-After wait as long as the provider asks, and other 4xx responses are
+// never retried. The Gemini client ignores it.
+func WithRetryBackoff(serverError, rateLimit time.Duration) ClientOption {
+	return func(o *ClientOptions) {
+		o.ServerErrorBackoff = serverError
+		o.RateLimitBackoff = rateLimit
+	}
+}
+
+// WithResponseMIMEType pins the MIME type of the model's reply, for example
+// "text/plain", so it does not answer in JSON or another structured format.
+// Only the Gemini client sends it; empty leaves the choice to the model.
+func WithResponseMIMEType(mimeType string) ClientOption {
+	return func(o *ClientOptions) {
+		o.ResponseMIMEType = mimeType
+	}
+}
+
+// TruncationNote is appended to content returned after generation stopped at
+// the output token limit, so readers know the summary is incomplete.
+const TruncationNote = "\n\n> _Note: this summary was truncated because the model reached its output token limit._\n"
+
 // GeminiClient is a Client implementation that uses Google's Gemini API.
 type GeminiClient struct {
 	client  *genai.Client
@@ -286,12 +375,6 @@ func NewGeminiClient(apiKey string, options ...ClientOption) (Client, error) {
 //   - A new GeminiClient instance
 //   - An error if client creation fails
 func newGeminiClient(apiKey string, options ...ClientOption) (*GeminiClient, error) {
-	if apiKey == "" {
-		return nil, customerrors.NewValidationError("API key is required", nil).
-			WithCode("GENAI-001").
-			WithSuggestion("Provide a valid API key either through environment variable or configuration")
-	}
-
 	// Start with default options
 	opts := DefaultClientOptions()
 
@@ -300,16 +383,9 @@ func newGeminiClient(apiKey string, options ...ClientOption) (*GeminiClient, err
 		option(&opts)
 	}
 
-	ctx := context.Background()
-	// #nosec G101 -- API key is provided by the user and not hardcoded // pragma: allowlist secret
-	client, err := genai.NewClient(ctx, &genai.ClientConfig{
-		APIKey:  apiKey, // pragma: allowlist secret
-		Backend: genai.BackendGeminiAPI,
-	})
+	client, err := newGenaiClientFromOptions(apiKey, opts)
 	if err != nil {
-		return nil, customerrors.WrapAPIError(err, "failed to create Gemini client").
-			WithCode("GENAI-002").
-			WithSuggestion("Check API key validity and network connectivity")
+		return nil, err
 	}
 
 	return &GeminiClient{
@@ -348,7 +424,7 @@ func (c *GeminiClient) Generate(ctx context.Context, prompt string) (string, err
 	genConfig := &genai.GenerateContentConfig{}
 
 	// Apply generation parameters if they have non-zero values
-	if c.options.Temperature > 0 {
+	if c.options.Temperature > 0 || c.options.Deterministic {
 		genConfig.Temperature = &c.options.Temperature
 	}
 
@@ -372,6 +448,10 @@ func (c *GeminiClient) Generate(ctx context.Context, prompt string) (string, err
 		genConfig.StopSequences = c.options.StopSequences
 	}
 
+	if c.options.ResponseMIMEType != "" {
+		genConfig.ResponseMIMEType = c.options.ResponseMIMEType
+	}
+
 	// Apply safety settings if any are defined
 	if len(c.options.SafetySettings) > 0 {
 		genConfig.SafetySettings = make([]*genai.SafetySetting, 0, len(c.options.SafetySettings))
@@ -387,11 +467,9 @@ func (c *GeminiClient) Generate(ctx context.Context, prompt string) (string, err
 		}
 	}
 
-	// Prepare contents with system instructions if provided
+	// Send system instructions in their own field; contents only accept user and model roles
 	if c.options.SystemInstructions != "" {
-		// Add system instructions as the first content item
-		systemContent := genai.NewContentFromText(c.options.SystemInstructions, "system")
-		contents = append([]*genai.Content{systemContent}, contents...)
+		genConfig.SystemInstruction = genai.NewContentFromText(c.options.SystemInstructions, genai.RoleUser)
 	}
 
 	// Use non-streaming API with our configured generation options.
@@ -404,8 +482,7 @@ func (c *GeminiClient) Generate(ctx context.Context, prompt string) (string, err
 				WithSuggestion("Consider increasing the timeout value")
 		}
 
-		return "", customerrors.WrapAPIError(err, "failed to generate content").
-			WithCode("GENAI-004")
+		return "", wrapGeminiError(err, "failed to generate content", "GENAI-004")
 	}
 
 	// Check if we have valid candidates.
@@ -415,17 +492,23 @@ func (c *GeminiClient) Generate(ctx context.Context, prompt string) (string, err
 			WithSuggestion("Check if the prompt contains content that may be filtered")
 	}
 
-	// Check for finish reason issues.
+	// Check for finish reason issues. Hitting the output token limit is not
+	// retried, since a retry would just hit the limit again; the partial
+	// content is returned with a trailing note instead.
+	truncated := false
 	if resp.Candidates[0].FinishReason != genai.FinishReasonStop {
 		reason := resp.Candidates[0].FinishReason
-		if reason == genai.FinishReasonSafety {
+		switch reason {
+		case genai.FinishReasonMaxTokens:
+			truncated = true
+		case genai.FinishReasonSafety:
 			return "", customerrors.NewAPIError("content blocked by safety settings", nil).
 				WithCode("GENAI-007").
 				WithSuggestion("Modify the prompt to avoid potentially harmful content")
+		default:
+			return "", customerrors.NewAPIError(fmt.Sprintf("generation incomplete: %s", reason), nil).
+				WithCode("GENAI-008")
 		}
-
-		return "", customerrors.NewAPIError(fmt.Sprintf("generation incomplete: %s", reason), nil).
-			WithCode("GENAI-008")
 	}
 
 	// Guard against nil Content even on successful finish reasons.
@@ -443,6 +526,24 @@ func (c *GeminiClient) Generate(ctx context.Context, prompt string) (string, err
 		}
 	}
 
+	// A finished response can still carry no text; fail it so the caller's
+	// retry policy applies instead of a blank summary being written.
+	if strings.TrimSpace(result.String()) == "" {
+		return "", customerrors.NewAPIError("response content was empty", nil).
+			WithCode("GENAI-029").
+			WithSuggestion("This is usually transient; the request can be retried")
+	}
+
+	if truncated {
+		c.options.log().WithFields(logrus.Fields{
+			"model":             c.model,
+			"max_output_tokens": c.options.MaxOutputTokens,
+		}).Warn("Generation stopped at the output token limit; returning partial content")
+		// Close a code block the limit cut off, so the note renders outside it
+		// and the response passes fence validation instead of being retried
+		return closeCodeFence(result.String()) + TruncationNote, nil
+	}
+
 	return result.String(), nil
 }
 
@@ -480,10 +581,10 @@ func (c *GeminiClient) CountTokens(ctx context.Context, prompt string) (int, err
 		genai.NewContentFromText(prompt, "user"),
 	}
 
-	// Add system instructions to content if provided
+	// Count system instructions as leading text, since the Gemini API's token
+	// count doesn't accept a system instruction
 	if c.options.SystemInstructions != "" {
-		// Add system instructions as the first content item
-		systemContent := genai.NewContentFromText(c.options.SystemInstructions, "system")
+		systemContent := genai.NewContentFromText(c.options.SystemInstructions, genai.RoleUser)
 		contents = append([]*genai.Content{systemContent}, contents...)
 	}
 
@@ -494,7 +595,7 @@ func (c *GeminiClient) CountTokens(ctx context.Context, prompt string) (int, err
 	maxAttempts := c.options.MaxRetries + 1
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
 		if attempt > 1 {
-			logrus.WithFields(logrus.Fields{
+			c.options.log().WithFields(logrus.Fields{
 				"attempt":     attempt,
 				"max_retries": c.options.MaxRetries,
 			}).Debug("Retry attempt for counting tokens")
@@ -517,14 +618,20 @@ func (c *GeminiClient) CountTokens(ctx context.Context, prompt string) (int, err
 				WithCode("GENAI-012").
 				WithSuggestion("Consider increasing the timeout value")
 		} else {
-			lastError = customerrors.WrapAPIError(err, "failed to count tokens").
-				WithCode("GENAI-013")
+			lastError = wrapGeminiError(err, "failed to count tokens", "GENAI-013")
 		}
 
 		// Simple backoff before retry
-		if attempt < c.options.MaxRetries {
-			backoffMs := 100 * attempt * attempt // Exponential backoff
-			time.Sleep(time.Duration(backoffMs) * time.Millisecond)
+		if attempt < maxAttempts {
+			backoff := geminiRetryBackoff(attempt, c.options.MaxRetries)
+			notifyRetry(c.options.OnRetry, attempt, lastError, backoff)
+			if backoff == 0 {
+				continue
+			}
+			if sleepErr := sleepWithContext(tokenCtx, backoff); sleepErr != nil {
+				lastError = sleepErr
+				break
+			}
 		}
 	}
 
@@ -566,7 +673,7 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string) (<-cha
 	genConfig := &genai.GenerateContentConfig{}
 
 	// Apply generation parameters if they have non-zero values
-	if c.options.Temperature > 0 {
+	if c.options.Temperature > 0 || c.options.Deterministic {
 		genConfig.Temperature = &c.options.Temperature
 	}
 
@@ -588,6 +695,10 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string) (<-cha
 		genConfig.StopSequences = c.options.StopSequences
 	}
 
+	if c.options.ResponseMIMEType != "" {
+		genConfig.ResponseMIMEType = c.options.ResponseMIMEType
+	}
+
 	// Apply safety settings if any are defined
 	if len(c.options.SafetySettings) > 0 {
 		genConfig.SafetySettings = make([]*genai.SafetySetting, 0, len(c.options.SafetySettings))
@@ -603,11 +714,9 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string) (<-cha
 		}
 	}
 
-	// Prepare contents with system instructions if provided
+	// Send system instructions in their own field; contents only accept user and model roles
 	if c.options.SystemInstructions != "" {
-		// Add system instructions as the first content item
-		systemContent := genai.NewContentFromText(c.options.SystemInstructions, "system")
-		contents = append([]*genai.Content{systemContent}, contents...)
+		genConfig.SystemInstruction = genai.NewContentFromText(c.options.SystemInstructions, genai.RoleUser)
 	}
 
 	// Start a goroutine to handle the streaming response
@@ -622,7 +731,7 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string) (<-cha
 		maxAttempts := c.options.MaxRetries + 1
 		for attempt := 1; attempt <= maxAttempts; attempt++ {
 			if attempt > 1 {
-				logrus.WithFields(logrus.Fields{
+				c.options.log().WithFields(logrus.Fields{
 					"attempt":     attempt,
 					"max_retries": c.options.MaxRetries,
 				}).Debug("Retry attempt for streaming content")
@@ -635,7 +744,7 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string) (<-cha
 			chunkReceived := false
 			responseFinished := false
 
-			for resp := range streamChan {
+			for resp, streamErr := range streamChan {
 				// Check for context canceled or deadline exceeded
 				if errors.Is(genCtx.Err(), context.Canceled) {
 					lastError = customerrors.WrapAPIError(genCtx.Err(), "context was canceled during stream").
@@ -653,6 +762,10 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string) (<-cha
 				}
 
 				// If the response has an error, break and retry
+				if streamErr != nil {
+					lastError = wrapGeminiError(streamErr, "streaming content generation failed", "GENAI-032")
+					break
+				}
 				if resp == nil {
 					lastError = customerrors.NewAPIError("received nil response", nil).
 						WithCode("GENAI-018").
@@ -699,9 +812,16 @@ func (c *GeminiClient) GenerateStream(ctx context.Context, prompt string) (<-cha
 			}
 
 			// Simple backoff before retry
-			if attempt < c.options.MaxRetries {
-				backoffMs := 100 * attempt * attempt // Exponential backoff
-				time.Sleep(time.Duration(backoffMs) * time.Millisecond)
+			if attempt < maxAttempts {
+				backoff := geminiRetryBackoff(attempt, c.options.MaxRetries)
+				notifyRetry(c.options.OnRetry, attempt, lastError, backoff)
+				if backoff == 0 {
+					continue
+				}
+				if sleepErr := sleepWithContext(genCtx, backoff); sleepErr != nil {
+					lastError = sleepErr
+					break
+				}
 			}
 		}
 
@@ -746,3 +866,12 @@ func (c *GeminiClient) Close() {
 		c.model = ""
 	}
 }
+
+// geminiRetryBackoff returns the wait after a failed Gemini attempt: 100ms
+// times the attempt number squared, or none before the final retry.
+func geminiRetryBackoff(attempt, maxRetries int) time.Duration {
+	if attempt >= maxRetries {
+		return 0
+	}
+	return time.Duration(100*attempt*attempt) * time.Millisecond
+}
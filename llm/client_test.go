@@ -2,11 +2,15 @@ package llm
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/api/iterator"
 	"google.golang.org/genai"
 
@@ -246,6 +250,16 @@ func TestClientOptions(t *testing.T) {
 	assert.Equal(t, float32(0.9), testOpts.TopP)
 	assert.Equal(t, int32(500), testOpts.MaxOutputTokens)
 	assert.Equal(t, "Be concise", testOpts.SystemInstructions)
+
+	// Test deterministic mode overrides earlier sampling options
+	testOpts = DefaultClientOptions()
+	WithTemperature(0.9)(&testOpts)
+	WithDeterministic()(&testOpts)
+
+	assert.True(t, testOpts.Deterministic)
+	assert.Equal(t, float32(0), testOpts.Temperature)
+	assert.Equal(t, float32(1), testOpts.TopK)
+	assert.Equal(t, float32(1), testOpts.TopP)
 }
 
 // TestNewGeminiClient tests the client creation functionality
@@ -317,6 +331,92 @@ func TestGeminiClientGenerate(t *testing.T) {
 		assert.Empty(t, result)
 		assert.Contains(t, err.Error(), "not properly initialized")
 	})
+
+	t.Run("MAX_TOKENS returns partial content with a note", func(t *testing.T) {
+		client, requests := newFinishReasonGeminiClient(t, "MAX_TOKENS", "# Partial summary")
+
+		result, err := client.Generate(context.Background(), "test prompt")
+		require.NoError(t, err)
+		assert.Equal(t, "# Partial summary"+TruncationNote, result)
+		assert.Equal(t, 1, *requests, "a truncated response must not be retried")
+	})
+
+	t.Run("MAX_TOKENS mid-fence closes the fence before the note", func(t *testing.T) {
+		partial := "# Partial summary\n\nThe entry point starts the server:\n\n```go\nfunc main() {"
+		client, requests := newFinishReasonGeminiClient(t, "MAX_TOKENS", partial)
+		fallback, err := NewFallbackClientWithBackoff(
+			[]FallbackTier{{Name: "primary", Client: client}},
+			1,
+			time.Millisecond,
+			time.Millisecond,
+			WithResponseValidator(ValidateMarkdown),
+		)
+		require.NoError(t, err)
+
+		result, err := fallback.Generate(context.Background(), "test prompt")
+		require.NoError(t, err)
+		assert.Equal(t, partial+"\n```\n"+TruncationNote, result)
+		assert.Equal(t, 1, *requests, "a truncated code block must not fail validation and be retried")
+	})
+
+	t.Run("SAFETY is an error", func(t *testing.T) {
+		client, _ := newFinishReasonGeminiClient(t, "SAFETY", "# Partial summary")
+
+		result, err := client.Generate(context.Background(), "test prompt")
+		require.Error(t, err)
+		assert.Empty(t, result)
+		assert.Contains(t, err.Error(), "safety settings")
+	})
+
+	t.Run("Other finish reasons are errors", func(t *testing.T) {
+		client, _ := newFinishReasonGeminiClient(t, "RECITATION", "# Partial summary")
+
+		result, err := client.Generate(context.Background(), "test prompt")
+		require.Error(t, err)
+		assert.Empty(t, result)
+		assert.Contains(t, err.Error(), "generation incomplete: RECITATION")
+	})
+
+	t.Run("Empty finished response is retried, then fails", func(t *testing.T) {
+		useFakeClock(t)
+		client, requests := newFinishReasonGeminiClient(t, "STOP", " \n\t")
+
+		_, err := client.Generate(context.Background(), "test prompt")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "response content was empty")
+
+		fallback, err := NewFallbackClient([]FallbackTier{{Name: "gemini", Client: client}}, 2)
+		require.NoError(t, err)
+		*requests = 0
+		_, err = fallback.Generate(context.Background(), "test prompt")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "response content was empty")
+		assert.Equal(t, 3, *requests, "every attempt is made before giving up")
+	})
+}
+
+// newFinishReasonGeminiClient returns a GeminiClient backed by a local server
+// that answers every generateContent call with text and finishReason, along
+// with a pointer to the number of requests served.
+func newFinishReasonGeminiClient(t *testing.T, finishReason, text string) (*GeminiClient, *int) {
+	t.Helper()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []map[string]any{{
+				"content":      map[string]any{"role": "model", "parts": []map[string]any{{"text": text}}},
+				"finishReason": finishReason,
+			}},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := newGeminiClient("test-key", WithBaseURL(server.URL), WithMaxRetries(0))
+	require.NoError(t, err)
+	return client, &requests
 }
 
 // TestGeminiClientGenerateStream tests the GenerateStream method of GeminiClient
@@ -455,3 +555,38 @@ func TestGeminiClient_Integration(t *testing.T) {
 	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, emptyTokens, 0) // Should be 0 or more tokens
 }
+
+// TestGeminiClientSystemInstructions verifies that system instructions are
+// sent in the request's systemInstruction field rather than as a content item,
+// which the API only accepts with user or model roles.
+func TestGeminiClientSystemInstructions(t *testing.T) {
+	var body struct {
+		Contents          []map[string]any `json:"contents"`
+		SystemInstruction map[string]any   `json:"systemInstruction"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []map[string]any{{
+				"content":      map[string]any{"role": "model", "parts": []map[string]any{{"text": "summary"}}},
+				"finishReason": "STOP",
+			}},
+		})
+	}))
+	defer server.Close()
+
+	client, err := newGeminiClient("test-key", WithBaseURL(server.URL), WithSystemInstructions("Be concise"))
+	require.NoError(t, err)
+	_, err = client.Generate(context.Background(), "prompt")
+	require.NoError(t, err)
+
+	require.NotNil(t, body.SystemInstruction)
+	parts, ok := body.SystemInstruction["parts"].([]any)
+	require.True(t, ok)
+	require.Len(t, parts, 1)
+	assert.Equal(t, "Be concise", parts[0].(map[string]any)["text"])
+
+	require.Len(t, body.Contents, 1, "only the prompt is sent as content")
+	assert.Equal(t, "user", body.Contents[0]["role"])
+}
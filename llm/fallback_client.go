@@ -6,6 +6,8 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -14,32 +16,79 @@ import (
 )
 
 const (
-	defaultFallbackBackoff    = 200 * time.Millisecond
-	defaultFallbackMaxBackoff = 30 * time.Second
+	defaultFallbackBackoff      = 200 * time.Millisecond
+	defaultFallbackMaxBackoff   = 30 * time.Second
+	defaultFallbackCloseTimeout = 5 * time.Second
 )
 
 // FallbackTier defines a model/provider tier in a failover chain.
 type FallbackTier struct {
 	Name   string
 	Client Client
+
+	// MaxConcurrent limits in-flight requests to this tier, so providers with
+	// different rate limits can be throttled independently. Zero means unlimited.
+	MaxConcurrent int
+
+	// Throttle, when set, is consulted before every request to this tier and
+	// extended whenever the tier reports a rate limit. Tiers on the same
+	// provider share one Throttle so a 429 seen by any caller backs off all.
+	Throttle *Throttle
 }
 
 // FallbackClient tries generation with retries on each tier, then falls back
 // to the next tier when a tier is exhausted.
 type FallbackClient struct {
 	tiers          []FallbackTier
+	slots          []chan struct{} // per-tier semaphores; nil entries are unlimited
 	retriesPerTier int
 	baseBackoff    time.Duration
 	maxBackoff     time.Duration
+	closeTimeout   time.Duration
+	validate       func(string) error
+	attemptBudget  int // total attempts per Generate call across all tiers; zero is unlimited
+	onRetry        RetryFunc
+	log            logrus.FieldLogger
+}
+
+// FallbackOption configures optional FallbackClient behavior.
+type FallbackOption func(*FallbackClient)
+
+// WithCloseTimeout bounds how long Close waits for all tiers to close.
+// Tiers still closing after the timeout are logged and abandoned.
+func WithCloseTimeout(timeout time.Duration) FallbackOption {
+	return func(c *FallbackClient) {
+		c.closeTimeout = timeout
+	}
+}
+
+// WithResponseValidator checks every generated response before it is returned.
+// A response that fails validation counts as a failed attempt, so it is retried
+// and failed over exactly like a transport error.
+func WithResponseValidator(validate func(string) error) FallbackOption {
+	return func(c *FallbackClient) {
+		c.validate = validate
+	}
+}
+
+// WithTotalAttemptBudget caps the attempts a single Generate call may make across
+// all tiers. Per-tier retries still apply but stop once the budget is spent, so
+// an outage affecting every tier fails fast instead of running the whole chain.
+// Zero (the default) leaves attempts bounded only by the per-tier retries.
+func WithTotalAttemptBudget(n int) FallbackOption {
+	return func(c *FallbackClient) {
+		c.attemptBudget = n
+	}
 }
 
 // NewFallbackClient creates a fallback client with sensible backoff defaults.
-func NewFallbackClient(tiers []FallbackTier, retriesPerTier int) (Client, error) {
+func NewFallbackClient(tiers []FallbackTier, retriesPerTier int, options ...FallbackOption) (Client, error) {
 	return NewFallbackClientWithBackoff(
 		tiers,
 		retriesPerTier,
 		defaultFallbackBackoff,
 		defaultFallbackMaxBackoff,
+		options...,
 	)
 }
 
@@ -49,6 +98,7 @@ func NewFallbackClientWithBackoff(
 	retriesPerTier int,
 	baseBackoff time.Duration,
 	maxBackoff time.Duration,
+	options ...FallbackOption,
 ) (Client, error) {
 	if len(tiers) == 0 {
 		return nil, customerrors.NewValidationError("at least one fallback tier is required", nil).
@@ -68,6 +118,7 @@ func NewFallbackClientWithBackoff(
 	}
 
 	cleanTiers := make([]FallbackTier, 0, len(tiers))
+	slots := make([]chan struct{}, len(tiers))
 	for i, tier := range tiers {
 		if tier.Client == nil {
 			return nil, customerrors.NewValidationError(
@@ -75,6 +126,15 @@ func NewFallbackClientWithBackoff(
 				nil,
 			).WithCode("LLM-005")
 		}
+		if tier.MaxConcurrent < 0 {
+			return nil, customerrors.NewValidationError(
+				fmt.Sprintf("fallback tier %d has negative max concurrency", i),
+				nil,
+			).WithCode("LLM-011")
+		}
+		if tier.MaxConcurrent > 0 {
+			slots[i] = make(chan struct{}, tier.MaxConcurrent)
+		}
 
 		name := strings.TrimSpace(tier.Name)
 		if name == "" {
@@ -82,34 +142,71 @@ func NewFallbackClientWithBackoff(
 		}
 
 		cleanTiers = append(cleanTiers, FallbackTier{
-			Name:   name,
-			Client: tier.Client,
+			Name:          name,
+			Client:        tier.Client,
+			MaxConcurrent: tier.MaxConcurrent,
+			Throttle:      tier.Throttle,
 		})
 	}
 
-	return &FallbackClient{
+	client := &FallbackClient{
 		tiers:          cleanTiers,
+		slots:          slots,
 		retriesPerTier: retriesPerTier,
 		baseBackoff:    baseBackoff,
 		maxBackoff:     maxBackoff,
-	}, nil
+		closeTimeout:   defaultFallbackCloseTimeout,
+		log:            logrus.StandardLogger(),
+	}
+	for _, option := range options {
+		option(client)
+	}
+
+	if client.closeTimeout <= 0 {
+		return nil, customerrors.NewValidationError("close timeout must be greater than zero", nil).
+			WithCode("LLM-009")
+	}
+	if client.attemptBudget < 0 {
+		return nil, customerrors.NewValidationError("total attempt budget cannot be negative", nil).
+			WithCode("LLM-012")
+	}
+
+	return client, nil
 }
 
 // Generate tries each fallback tier with exponential backoff retries.
 func (c *FallbackClient) Generate(ctx context.Context, prompt string) (string, error) {
 	var lastErr error
 	maxAttempts := c.retriesPerTier + 1
+	totalAttempts := 0
 
+tiers:
 	for tierIdx, tier := range c.tiers {
 		for attempt := 1; attempt <= maxAttempts; attempt++ {
 			if ctx.Err() != nil {
 				return "", ctx.Err()
 			}
 
+			if tier.Throttle != nil {
+				if err := tier.Throttle.Wait(ctx); err != nil {
+					return "", err
+				}
+			}
+			release, err := c.acquire(ctx, tierIdx)
+			if err != nil {
+				return "", err
+			}
 			result, err := tier.Client.Generate(ctx, prompt)
+			release()
+			if err == nil && c.validate != nil {
+				if validationErr := c.validate(result); validationErr != nil {
+					err = customerrors.WrapValidationError(validationErr, "LLM response failed validation").
+						WithCode("LLM-010")
+				}
+			}
 			if err == nil {
 				if tierIdx > 0 || attempt > 1 {
-					logrus.WithFields(logrus.Fields{
+					c.log.WithFields(logrus.Fields{
 						"tier_name":       tier.Name,
 						"tier_index":      tierIdx + 1,
 						"tier_count":      len(c.tiers),
@@ -118,12 +215,15 @@ func (c *FallbackClient) Generate(ctx context.Context, prompt string) (string, e
 						"retries_tier":    c.retriesPerTier,
 						"failover_used":   tierIdx > 0,
 						"tier_retry_used": attempt > 1,
-					}).Info("LLM generation succeeded after retry/failover")
+					}).Debug("LLM generation succeeded after retry/failover")
 				}
 				return result, nil
 			}
 
 			lastErr = err
+			totalAttempts++
+			budgetSpent := c.attemptBudget > 0 && totalAttempts >= c.attemptBudget
+			rejected := rejectedStatus(err)
 
 			logFields := logrus.Fields{
 				"tier_name":       tier.Name,
@@ -133,14 +233,41 @@ func (c *FallbackClient) Generate(ctx context.Context, prompt string) (string, e
 				"attempts_tier":   maxAttempts,
 				"retries_tier":    c.retriesPerTier,
 				"error":           err,
-				"will_failover":   attempt == maxAttempts && tierIdx < len(c.tiers)-1,
-				"will_retry_tier": attempt < maxAttempts,
+				"attempts_total":  totalAttempts,
+				"attempt_budget":  c.attemptBudget,
+				"will_failover":   !budgetSpent && (rejected || attempt == maxAttempts) && tierIdx < len(c.tiers)-1,
+				"will_retry_tier": !budgetSpent && !rejected && attempt < maxAttempts,
 			}
 
-			if attempt < maxAttempts {
+			if budgetSpent {
+				c.log.WithFields(logFields).Warn("LLM attempt budget exhausted, giving up")
+				break tiers
+			}
+
+			throttled := false
+			retryAfter, limited := rateLimitDelay(err)
+			if limited && tier.Throttle != nil {
+				// Share the backoff: the next attempt on this tier, from this
+				// caller or any other, waits on the throttle instead of sleeping.
+				if retryAfter <= 0 {
+					retryAfter = ExponentialBackoff(attempt, c.baseBackoff, c.maxBackoff)
+				}
+				tier.Throttle.Observe(retryAfter)
+				throttled = true
+				logFields["throttle_ms"] = retryAfter.Milliseconds()
+			}
+
+			if attempt < maxAttempts && !rejected {
+				if throttled {
+					c.log.WithFields(logFields).Warn("LLM tier rate limited, retrying tier after shared backoff")
+					notifyRetry(c.onRetry, totalAttempts, err, retryAfter)
+					continue
+				}
+
 				wait := ExponentialBackoff(attempt, c.baseBackoff, c.maxBackoff)
 				logFields["backoff_ms"] = wait.Milliseconds()
-				logrus.WithFields(logFields).Warn("LLM tier attempt failed, retrying tier")
+				c.log.WithFields(logFields).Warn("LLM tier attempt failed, retrying tier")
+				notifyRetry(c.onRetry, totalAttempts, err, wait)
 
 				if sleepErr := sleepWithContext(ctx, wait); sleepErr != nil {
 					return "", sleepErr
@@ -148,7 +275,17 @@ func (c *FallbackClient) Generate(ctx context.Context, prompt string) (string, e
 				continue
 			}
 
-			logrus.WithFields(logFields).Warn("LLM tier exhausted, trying fallback tier")
+			// A client error fails the same way on every attempt, so it skips
+			// straight to the next tier
+			if rejected {
+				c.log.WithFields(logFields).Warn("LLM tier rejected the request, trying fallback tier")
+			} else {
+				c.log.WithFields(logFields).Warn("LLM tier exhausted, trying fallback tier")
+			}
+			if tierIdx < len(c.tiers)-1 {
+				notifyRetry(c.onRetry, totalAttempts, err, 0)
+			}
+			continue tiers
 		}
 	}
 
@@ -157,15 +294,66 @@ func (c *FallbackClient) Generate(ctx context.Context, prompt string) (string, e
 		WithSuggestion("Check provider connectivity, API keys, or reduce prompt size")
 }
 
-// CountTokens attempts token counting across tiers until one succeeds.
+// acquire takes a concurrency slot on the given tier, waiting until one frees up
+// or the context is done. The returned function releases the slot.
+func (c *FallbackClient) acquire(ctx context.Context, tierIdx int) (func(), error) {
+	slots := c.slots[tierIdx]
+	if slots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// CountTokens counts tokens on each tier that supports counting, retrying
+// failed counts with the same per-tier retries and backoff as Generate before
+// moving to the next tier. Tiers that report no token counting support are skipped.
 func (c *FallbackClient) CountTokens(ctx context.Context, prompt string) (int, error) {
-	var lastErr error
-	for _, tier := range c.tiers {
-		tokens, err := tier.Client.CountTokens(ctx, prompt)
-		if err == nil {
-			return tokens, nil
+	lastErr := errTokenCountUnsupported
+	maxAttempts := c.retriesPerTier + 1
+	totalAttempts := 0
+
+	for tierIdx, tier := range c.tiers {
+		if !CapabilitiesOf(tier.Client).SupportsTokenCount {
+			continue
+		}
+		for attempt := 1; attempt <= maxAttempts; attempt++ {
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+
+			release, err := c.acquire(ctx, tierIdx)
+			if err != nil {
+				return 0, err
+			}
+			tokens, err := tier.Client.CountTokens(ctx, prompt)
+			release()
+			if err == nil {
+				return tokens, nil
+			}
+			lastErr = err
+			totalAttempts++
+
+			if attempt < maxAttempts {
+				wait := ExponentialBackoff(attempt, c.baseBackoff, c.maxBackoff)
+				c.log.WithFields(logrus.Fields{
+					"tier_name":  tier.Name,
+					"attempt":    attempt,
+					"error":      err,
+					"backoff_ms": wait.Milliseconds(),
+				}).Debug("Token count failed, retrying tier")
+				notifyRetry(c.onRetry, totalAttempts, err, wait)
+
+				if sleepErr := sleepWithContext(ctx, wait); sleepErr != nil {
+					return 0, sleepErr
+				}
+			}
 		}
-		lastErr = err
 	}
 
 	return 0, customerrors.WrapAPIError(lastErr, "failed to count tokens across fallback tiers").
@@ -173,12 +361,29 @@ func (c *FallbackClient) CountTokens(ctx context.Context, prompt string) (int, e
 }
 
 // GenerateStream attempts streaming from each tier until one starts successfully.
+// Like Generate, it waits on the tier's throttle and holds one of its
+// concurrency slots, here until the stream ends. Each tier gets one attempt:
+// the streamed text is not validated here, so callers check the assembled
+// response with ValidateResponse and fall back to Generate, which retries.
 func (c *FallbackClient) GenerateStream(ctx context.Context, prompt string) (<-chan StreamChunk, error) {
 	var lastErr error
-	for _, tier := range c.tiers {
+	for tierIdx, tier := range c.tiers {
+		if tier.Throttle != nil {
+			if err := tier.Throttle.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+		release, err := c.acquire(ctx, tierIdx)
+		if err != nil {
+			return nil, err
+		}
 		stream, err := tier.Client.GenerateStream(ctx, prompt)
 		if err == nil {
-			return stream, nil
+			return releaseAfterStream(stream, release), nil
+		}
+		release()
+		if retryAfter, limited := rateLimitDelay(err); limited && tier.Throttle != nil && retryAfter > 0 {
+			tier.Throttle.Observe(retryAfter)
 		}
 		lastErr = err
 	}
@@ -187,21 +392,69 @@ func (c *FallbackClient) GenerateStream(ctx context.Context, prompt string) (<-c
 		WithCode("LLM-008")
 }
 
-// Close closes all underlying clients.
-func (c *FallbackClient) Close() {
-	for _, tier := range c.tiers {
-		tier.Client.Close()
+// releaseAfterStream forwards stream's chunks and calls release once the
+// stream has ended, so a tier's concurrency slot is held for its whole length.
+func releaseAfterStream(stream <-chan StreamChunk, release func()) <-chan StreamChunk {
+	out := make(chan StreamChunk)
+	go func() {
+		defer close(out)
+		defer release()
+		for chunk := range stream {
+			out <- chunk
+		}
+	}()
+	return out
+}
+
+// ValidateResponse applies the WithResponseValidator check to a response
+// assembled outside Generate, such as from GenerateStream. It returns nil when
+// no validator is configured.
+func (c *FallbackClient) ValidateResponse(response string) error {
+	if c.validate == nil {
+		return nil
+	}
+	if err := c.validate(response); err != nil {
+		return customerrors.WrapValidationError(err, "LLM response failed validation").
+			WithCode("LLM-010")
 	}
+	return nil
 }
 
-func sleepWithContext(ctx context.Context, d time.Duration) error {
-	timer := time.NewTimer(d)
+// Close closes all underlying clients concurrently and returns once they have all
+// closed or the close timeout elapses, so a single blocking tier cannot hang shutdown.
+// Tiers that have not finished closing in time are logged by name.
+func (c *FallbackClient) Close() {
+	closed := make([]atomic.Bool, len(c.tiers))
+	var wg sync.WaitGroup
+	for i, tier := range c.tiers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tier.Client.Close()
+			closed[i].Store(true)
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(c.closeTimeout)
 	defer timer.Stop()
 
 	select {
-	case <-ctx.Done():
-		return ctx.Err()
+	case <-done:
 	case <-timer.C:
-		return nil
+		for i, tier := range c.tiers {
+			if !closed[i].Load() {
+				c.log.WithFields(logrus.Fields{
+					"tier_name":  tier.Name,
+					"tier_index": i + 1,
+					"timeout_ms": c.closeTimeout.Milliseconds(),
+				}).Warn("LLM tier did not close in time; abandoning")
+			}
+		}
 	}
 }
@@ -3,9 +3,12 @@ package llm
 import (
 	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
@@ -28,11 +31,29 @@ func TestNewFallbackClientValidation(t *testing.T) {
 		assert.Nil(t, client)
 	})
 
+	t.Run("rejects negative tier concurrency", func(t *testing.T) {
+		client, err := NewFallbackClient([]FallbackTier{{Name: "t1", Client: adapter, MaxConcurrent: -1}}, 1)
+		assert.Error(t, err)
+		assert.Nil(t, client)
+	})
+
 	t.Run("rejects nil tier client", func(t *testing.T) {
 		client, err := NewFallbackClient([]FallbackTier{{Name: "t1", Client: nil}}, 1)
 		assert.Error(t, err)
 		assert.Nil(t, client)
 	})
+
+	t.Run("rejects non-positive close timeout", func(t *testing.T) {
+		client, err := NewFallbackClient([]FallbackTier{{Name: "t1", Client: adapter}}, 1, WithCloseTimeout(0))
+		assert.Error(t, err)
+		assert.Nil(t, client)
+	})
+
+	t.Run("rejects negative attempt budget", func(t *testing.T) {
+		client, err := NewFallbackClient([]FallbackTier{{Name: "t1", Client: adapter}}, 1, WithTotalAttemptBudget(-1))
+		assert.Error(t, err)
+		assert.Nil(t, client)
+	})
 }
 
 func TestFallbackClientGenerate(t *testing.T) {
@@ -194,10 +215,11 @@ func TestFallbackClientCountTokens(t *testing.T) {
 	primary := NewMockClientAdapter(primaryMock)
 	secondary := NewMockClientAdapter(secondaryMock)
 
+	// The primary tier's retry fails too before the secondary is tried
 	primaryMock.
 		On("CountTokens", ctx, prompt).
 		Return(0, errors.New("count failed")).
-		Once()
+		Twice()
 	secondaryMock.
 		On("CountTokens", ctx, prompt).
 		Return(77, nil).
@@ -291,33 +313,28 @@ func TestSleepWithContextCanceled(t *testing.T) {
 
 func TestFallbackClientContextCancelDuringRetry(t *testing.T) {
 	prompt := "test prompt"
+	useFakeClock(t)
+	ctx, cancel := context.WithCancel(context.Background())
 
 	primaryMock := new(mocks.LLMClient)
 	primary := NewMockClientAdapter(primaryMock)
 
-	// First attempt fails, triggering a backoff sleep
+	// First attempt fails and cancels, so the backoff sleep is interrupted
 	primaryMock.
 		On("Generate", mock.Anything, prompt).
+		Run(func(mock.Arguments) { cancel() }).
 		Return("", errors.New("transient")).
 		Once()
 	primaryMock.On("Close").Return().Once()
 
 	client, err := NewFallbackClientWithBackoff(
 		[]FallbackTier{{Name: "primary", Client: primary}},
-		2,           // 2 retries = 3 attempts
-		time.Second, // long backoff so cancel fires during sleep
+		2, // 2 retries = 3 attempts
+		time.Second,
 		5*time.Second,
 	)
 	assert.NoError(t, err)
 
-	ctx, cancel := context.WithCancel(context.Background())
-
-	// Cancel after a short delay — during the backoff sleep
-	go func() {
-		time.Sleep(50 * time.Millisecond)
-		cancel()
-	}()
-
 	_, genErr := client.Generate(ctx, prompt)
 	assert.Error(t, genErr)
 	assert.ErrorIs(t, genErr, context.Canceled)
@@ -346,3 +363,264 @@ func TestFallbackClientBackoffCap(t *testing.T) {
 	assert.GreaterOrEqual(t, capped, 2400*time.Microsecond)
 	assert.LessOrEqual(t, capped, 3*time.Millisecond)
 }
+
+func TestFallbackClientCloseTimeout(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	stuckMock := new(mocks.LLMClient)
+	stuckMock.On("Close").Run(func(mock.Arguments) { <-release }).Return()
+	healthyMock := new(mocks.LLMClient)
+	healthyMock.On("Close").Return().Once()
+
+	client, err := NewFallbackClient(
+		[]FallbackTier{
+			{Name: "healthy", Client: NewMockClientAdapter(healthyMock)},
+			{Name: "stuck", Client: NewMockClientAdapter(stuckMock)},
+		},
+		0,
+		WithCloseTimeout(50*time.Millisecond),
+	)
+	assert.NoError(t, err)
+
+	start := time.Now()
+	client.Close()
+	elapsed := time.Since(start)
+
+	assert.Less(t, elapsed, time.Second, "Close should return once the timeout elapses")
+	healthyMock.AssertExpectations(t)
+
+	var offenders []string
+	for _, entry := range hook.AllEntries() {
+		if entry.Message == "LLM tier did not close in time; abandoning" {
+			offenders = append(offenders, entry.Data["tier_name"].(string))
+		}
+	}
+	assert.Equal(t, []string{"stuck"}, offenders)
+}
+
+func TestFallbackClientCloseConcurrent(t *testing.T) {
+	// Each tier blocks until every tier has started closing, which only
+	// completes if tiers are closed concurrently rather than serially.
+	const tierCount = 3
+	started := make(chan struct{}, tierCount)
+	allStarted := make(chan struct{})
+
+	tiers := make([]FallbackTier, 0, tierCount)
+	for i := 0; i < tierCount; i++ {
+		m := new(mocks.LLMClient)
+		m.On("Close").Run(func(mock.Arguments) {
+			started <- struct{}{}
+			<-allStarted
+		}).Return().Once()
+		tiers = append(tiers, FallbackTier{Client: NewMockClientAdapter(m)})
+	}
+
+	client, err := NewFallbackClient(tiers, 0, WithCloseTimeout(2*time.Second))
+	assert.NoError(t, err)
+
+	go func() {
+		for i := 0; i < tierCount; i++ {
+			<-started
+		}
+		close(allStarted)
+	}()
+
+	start := time.Now()
+	client.Close()
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestFallbackClientResponseValidator(t *testing.T) {
+	ctx := context.Background()
+	prompt := "prompt"
+	valid := "# pkg\n\nThis package does something useful for the tests.\n"
+
+	t.Run("retries a malformed response", func(t *testing.T) {
+		primaryMock := new(mocks.LLMClient)
+		primaryMock.On("Generate", ctx, prompt).Return("```go\ntruncated", nil).Once()
+		primaryMock.On("Generate", ctx, prompt).Return(valid, nil).Once()
+
+		client, err := NewFallbackClientWithBackoff(
+			[]FallbackTier{{Name: "primary", Client: NewMockClientAdapter(primaryMock)}},
+			1,
+			time.Millisecond,
+			time.Millisecond,
+			WithResponseValidator(ValidateMarkdown),
+		)
+		assert.NoError(t, err)
+
+		out, genErr := client.Generate(ctx, prompt)
+		assert.NoError(t, genErr)
+		assert.Equal(t, valid, out)
+		primaryMock.AssertExpectations(t)
+	})
+
+	t.Run("fails when every response is malformed", func(t *testing.T) {
+		primaryMock := new(mocks.LLMClient)
+		primaryMock.On("Generate", ctx, prompt).Return("short", nil).Times(2)
+
+		client, err := NewFallbackClientWithBackoff(
+			[]FallbackTier{{Name: "primary", Client: NewMockClientAdapter(primaryMock)}},
+			1,
+			time.Millisecond,
+			time.Millisecond,
+			WithResponseValidator(ValidateMarkdown),
+		)
+		assert.NoError(t, err)
+
+		_, genErr := client.Generate(ctx, prompt)
+		assert.Error(t, genErr)
+		assert.Contains(t, genErr.Error(), "too short")
+		primaryMock.AssertExpectations(t)
+	})
+}
+
+func TestFallbackClientTotalAttemptBudget(t *testing.T) {
+	ctx := context.Background()
+	prompt := "prompt"
+	apiErr := errors.New("provider unavailable")
+
+	newTiers := func() ([]*mocks.LLMClient, []FallbackTier) {
+		var mockClients []*mocks.LLMClient
+		var tiers []FallbackTier
+		for _, name := range []string{"primary", "secondary", "tertiary"} {
+			m := new(mocks.LLMClient)
+			m.On("Generate", ctx, prompt).Return("", apiErr)
+			mockClients = append(mockClients, m)
+			tiers = append(tiers, FallbackTier{Name: name, Client: NewMockClientAdapter(m)})
+		}
+		return mockClients, tiers
+	}
+	totalCalls := func(mockClients []*mocks.LLMClient) int {
+		n := 0
+		for _, m := range mockClients {
+			n += len(m.Calls)
+		}
+		return n
+	}
+
+	t.Run("caps attempts across failing tiers", func(t *testing.T) {
+		mockClients, tiers := newTiers()
+		client, err := NewFallbackClientWithBackoff(tiers, 2, time.Millisecond, time.Millisecond, WithTotalAttemptBudget(3))
+		assert.NoError(t, err)
+
+		_, genErr := client.Generate(ctx, prompt)
+		assert.Error(t, genErr)
+		assert.ErrorIs(t, genErr, apiErr)
+		assert.Equal(t, 3, totalCalls(mockClients), "budget of 3 should stop after exactly 3 attempts")
+		mockClients[0].AssertNumberOfCalls(t, "Generate", 3)
+		mockClients[1].AssertNotCalled(t, "Generate", ctx, prompt)
+		mockClients[2].AssertNotCalled(t, "Generate", ctx, prompt)
+	})
+
+	t.Run("budget spans tiers when per-tier retries are lower", func(t *testing.T) {
+		mockClients, tiers := newTiers()
+		client, err := NewFallbackClientWithBackoff(tiers, 0, time.Millisecond, time.Millisecond, WithTotalAttemptBudget(3))
+		assert.NoError(t, err)
+
+		_, genErr := client.Generate(ctx, prompt)
+		assert.Error(t, genErr)
+		for _, m := range mockClients {
+			m.AssertNumberOfCalls(t, "Generate", 1)
+		}
+	})
+
+	t.Run("zero budget leaves per-tier retries unbounded", func(t *testing.T) {
+		mockClients, tiers := newTiers()
+		client, err := NewFallbackClientWithBackoff(tiers, 2, time.Millisecond, time.Millisecond)
+		assert.NoError(t, err)
+
+		_, genErr := client.Generate(ctx, prompt)
+		assert.Error(t, genErr)
+		assert.Equal(t, 9, totalCalls(mockClients))
+	})
+}
+
+// concurrencyProbe is a Client that records the peak number of overlapping Generate calls.
+type concurrencyProbe struct {
+	inFlight atomic.Int32
+	peak     atomic.Int32
+	err      error
+}
+
+func (p *concurrencyProbe) Generate(context.Context, string) (string, error) {
+	current := p.inFlight.Add(1)
+	defer p.inFlight.Add(-1)
+	for {
+		peak := p.peak.Load()
+		if current <= peak || p.peak.CompareAndSwap(peak, current) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	return "ok", p.err
+}
+
+func (p *concurrencyProbe) GenerateStream(context.Context, string) (<-chan StreamChunk, error) {
+	return nil, errors.New("not supported")
+}
+
+func (p *concurrencyProbe) CountTokens(context.Context, string) (int, error) { return 0, nil }
+
+func (p *concurrencyProbe) Close() {}
+
+func TestFallbackClientPerTierConcurrency(t *testing.T) {
+	primary := &concurrencyProbe{err: errors.New("primary down")}
+	secondary := &concurrencyProbe{}
+
+	client, err := NewFallbackClientWithBackoff(
+		[]FallbackTier{
+			{Name: "primary", Client: primary, MaxConcurrent: 4},
+			{Name: "secondary", Client: secondary, MaxConcurrent: 2},
+		},
+		0,
+		time.Millisecond,
+		time.Millisecond,
+	)
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out, genErr := client.Generate(context.Background(), "prompt")
+			assert.NoError(t, genErr)
+			assert.Equal(t, "ok", out)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, primary.peak.Load(), int32(4), "primary tier exceeded its concurrency bound")
+	assert.LessOrEqual(t, secondary.peak.Load(), int32(2), "secondary tier exceeded its concurrency bound")
+	assert.Equal(t, int32(2), secondary.peak.Load(), "secondary tier should be saturated")
+}
+
+func TestFallbackClientConcurrencyRespectsContext(t *testing.T) {
+	started := make(chan struct{})
+	blocked := make(chan struct{})
+	holder := new(mocks.LLMClient)
+	holder.On("Generate", mock.Anything, "prompt").Run(func(mock.Arguments) {
+		close(started)
+		<-blocked
+	}).Return("ok", nil).Once()
+
+	client, err := NewFallbackClient(
+		[]FallbackTier{{Name: "only", Client: NewMockClientAdapter(holder), MaxConcurrent: 1}},
+		0,
+	)
+	assert.NoError(t, err)
+
+	go func() { _, _ = client.Generate(context.Background(), "prompt") }()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, genErr := client.Generate(ctx, "prompt")
+	assert.ErrorIs(t, genErr, context.DeadlineExceeded)
+	close(blocked)
+}
@@ -0,0 +1,133 @@
+// Package llm provides abstractions and implementations for interacting with
+// Large Language Model APIs in the glance application.
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+
+	"google.golang.org/genai"
+
+	customerrors "glance/errors"
+)
+
+// Backend selects which Google API serves Gemini requests.
+type Backend string
+
+const (
+	// BackendGeminiAPI is the public Gemini Developer API, authenticated by API key (the default)
+	BackendGeminiAPI Backend = "gemini"
+
+	// BackendVertexAI is Vertex AI, authenticated by Application Default Credentials.
+	// The project and location are read from GOOGLE_CLOUD_PROJECT and GOOGLE_CLOUD_LOCATION.
+	BackendVertexAI Backend = "vertex"
+)
+
+// ParseBackend converts a user-supplied backend name into a Backend.
+// An empty name selects BackendGeminiAPI.
+func ParseBackend(name string) (Backend, error) {
+	switch Backend(name) {
+	case "", BackendGeminiAPI:
+		return BackendGeminiAPI, nil
+	case BackendVertexAI:
+		return BackendVertexAI, nil
+	default:
+		return "", customerrors.NewValidationError(fmt.Sprintf("unknown Gemini backend %q", name), nil).
+			WithCode("GENAI-023").
+			WithSuggestion(fmt.Sprintf("Use %q or %q", BackendGeminiAPI, BackendVertexAI))
+	}
+}
+
+// WithBackend selects the API backend used by the Gemini client.
+func WithBackend(backend Backend) ClientOption {
+	return func(o *ClientOptions) {
+		o.Backend = backend
+	}
+}
+
+// WithBaseURL overrides the endpoint used by the Gemini client, e.g. to route
+// requests through a proxy. An empty URL keeps the backend's default endpoint.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(o *ClientOptions) {
+		o.BaseURL = baseURL
+	}
+}
+
+// newGenaiClient constructs the underlying genai client - can be swapped in tests
+// to inspect the configuration without contacting the API.
+var newGenaiClient = func(ctx context.Context, cc *genai.ClientConfig) (*genai.Client, error) {
+	return genai.NewClient(ctx, cc)
+}
+
+// buildGenaiClientConfig translates ClientOptions into a genai.ClientConfig.
+//
+// Parameters:
+//   - apiKey: The API key, used only by the Gemini API backend // pragma: allowlist secret
+//   - opts: The resolved client options
+//
+// Returns:
+//   - The genai client configuration
+//   - A validation error if the options are incompatible
+func buildGenaiClientConfig(apiKey string, opts ClientOptions) (*genai.ClientConfig, error) {
+	backend, err := ParseBackend(string(opts.Backend))
+	if err != nil {
+		return nil, err
+	}
+
+	cc := &genai.ClientConfig{}
+	switch backend {
+	case BackendVertexAI:
+		// Vertex AI authenticates with Application Default Credentials; genai rejects
+		// an API key combined with the project and location Vertex requires.
+		cc.Backend = genai.BackendVertexAI
+	default:
+		if apiKey == "" {
+			return nil, customerrors.NewValidationError("API key is required", nil).
+				WithCode("GENAI-001").
+				WithSuggestion("Provide a valid API key either through environment variable or configuration")
+		}
+		cc.Backend = genai.BackendGeminiAPI
+		cc.APIKey = apiKey // pragma: allowlist secret
+	}
+
+	if opts.BaseURL != "" {
+		if err := validateBaseURL(opts.BaseURL, cc.APIKey != ""); err != nil {
+			return nil, err
+		}
+		cc.HTTPOptions.BaseURL = opts.BaseURL
+	}
+	cc.HTTPOptions.Headers = extraHTTPHeader(opts.ExtraHeaders, opts.log())
+
+	return cc, nil
+}
+
+// validateBaseURL ensures a base URL override is absolute and, when an API key
+// will be sent, uses HTTPS unless it points at the local machine.
+func validateBaseURL(baseURL string, sendsAPIKey bool) error {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return customerrors.NewValidationError(fmt.Sprintf("invalid Gemini base URL %q", baseURL), err).
+			WithCode("GENAI-024").
+			WithSuggestion("Use an absolute http(s) URL such as https://proxy.example.com/")
+	}
+
+	if sendsAPIKey && parsed.Scheme == "http" && !isLoopbackHost(parsed.Hostname()) {
+		return customerrors.NewValidationError(
+			fmt.Sprintf("refusing to send the API key over plain HTTP to %q", parsed.Host), nil).
+			WithCode("GENAI-025").
+			WithSuggestion("Use an https:// base URL, or a proxy on localhost")
+	}
+
+	return nil
+}
+
+// isLoopbackHost reports whether host names the local machine.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
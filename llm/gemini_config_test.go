@@ -0,0 +1,106 @@
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+)
+
+func TestBuildGenaiClientConfig(t *testing.T) {
+	t.Run("Default options are unchanged", func(t *testing.T) {
+		cc, err := buildGenaiClientConfig("test-key", DefaultClientOptions())
+		require.NoError(t, err)
+		assert.Equal(t, &genai.ClientConfig{
+			APIKey:  "test-key", // pragma: allowlist secret
+			Backend: genai.BackendGeminiAPI,
+		}, cc)
+	})
+
+	t.Run("Vertex AI backend omits the API key", func(t *testing.T) {
+		opts := DefaultClientOptions()
+		WithBackend(BackendVertexAI)(&opts)
+
+		cc, err := buildGenaiClientConfig("test-key", opts)
+		require.NoError(t, err)
+		assert.Equal(t, genai.BackendVertexAI, cc.Backend)
+		assert.Empty(t, cc.APIKey)
+	})
+
+	t.Run("Base URL override", func(t *testing.T) {
+		opts := DefaultClientOptions()
+		WithBaseURL("https://proxy.example.com/")(&opts)
+
+		cc, err := buildGenaiClientConfig("test-key", opts)
+		require.NoError(t, err)
+		assert.Equal(t, "https://proxy.example.com/", cc.HTTPOptions.BaseURL)
+		assert.Equal(t, genai.BackendGeminiAPI, cc.Backend)
+	})
+
+	t.Run("Plain HTTP allowed for a local proxy", func(t *testing.T) {
+		opts := DefaultClientOptions()
+		WithBaseURL("http://127.0.0.1:8080/")(&opts)
+
+		cc, err := buildGenaiClientConfig("test-key", opts)
+		require.NoError(t, err)
+		assert.Equal(t, "http://127.0.0.1:8080/", cc.HTTPOptions.BaseURL)
+	})
+
+	invalid := []struct {
+		name    string
+		apiKey  string
+		options []ClientOption
+	}{
+		{"Missing API key for Gemini API", "", nil},
+		{"Unknown backend", "test-key", []ClientOption{WithBackend("bedrock")}},
+		{"Relative base URL", "test-key", []ClientOption{WithBaseURL("proxy/v1")}},
+		{"Unsupported scheme", "test-key", []ClientOption{WithBaseURL("ftp://proxy.example.com/")}},
+		{"API key over plain HTTP", "test-key", []ClientOption{WithBaseURL("http://proxy.example.com/")}},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := DefaultClientOptions()
+			for _, option := range tc.options {
+				option(&opts)
+			}
+			_, err := buildGenaiClientConfig(tc.apiKey, opts)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewGeminiClientUsesBuiltConfig(t *testing.T) {
+	var captured *genai.ClientConfig
+	origNew := newGenaiClient
+	newGenaiClient = func(_ context.Context, cc *genai.ClientConfig) (*genai.Client, error) {
+		captured = cc
+		return &genai.Client{}, nil
+	}
+	defer func() { newGenaiClient = origNew }()
+
+	_, err := newGeminiClient("test-key", WithBaseURL("https://proxy.example.com/"))
+	require.NoError(t, err)
+	require.NotNil(t, captured)
+	assert.Equal(t, "https://proxy.example.com/", captured.HTTPOptions.BaseURL)
+	assert.Equal(t, "test-key", captured.APIKey)
+
+	captured = nil
+	_, err = newGeminiClient("test-key", WithBackend("bogus"))
+	assert.Error(t, err)
+	assert.Nil(t, captured, "invalid options must be rejected before constructing the client")
+}
+
+func TestParseBackend(t *testing.T) {
+	backend, err := ParseBackend("")
+	require.NoError(t, err)
+	assert.Equal(t, BackendGeminiAPI, backend)
+
+	backend, err = ParseBackend("vertex")
+	require.NoError(t, err)
+	assert.Equal(t, BackendVertexAI, backend)
+
+	_, err = ParseBackend("Vertex")
+	assert.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package llm
+
+import (
+	"errors"
+	"net/http"
+
+	"google.golang.org/genai"
+
+	customerrors "glance/errors"
+)
+
+// wrapGeminiError wraps a failed Gemini API call in a coded APIError, with a
+// suggestion that fits the HTTP status the API reported. Rate-limit rejections
+// are additionally wrapped in a RateLimitError carrying the delay from the
+// response's RetryInfo, as the OpenRouter client does for its 429s, so callers
+// can classify both providers' errors the same way.
+func wrapGeminiError(err error, message, code string) error {
+	apiErr := customerrors.WrapAPIError(err, message).WithCode(code)
+
+	status, ok := geminiStatus(err)
+	if !ok {
+		return apiErr
+	}
+	switch {
+	case status == http.StatusTooManyRequests:
+		delay, _ := rateLimitDelay(err)
+		return &RateLimitError{
+			RetryAfter: delay,
+			Err:        apiErr.WithSuggestion("Rate limited by provider. Retry after backoff"),
+		}
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return apiErr.WithSuggestion("Check that GEMINI_API_KEY is valid and has access to the model")
+	case status == http.StatusNotFound:
+		return apiErr.WithSuggestion("Check that the model name is correct and available to your API key")
+	case status == http.StatusBadRequest:
+		return apiErr.WithSuggestion("The request was rejected as invalid; try a smaller prompt or check the generation options")
+	case status >= http.StatusInternalServerError:
+		return apiErr.WithSuggestion("The Gemini API is having a temporary problem, retry later")
+	}
+	return apiErr
+}
+
+// geminiStatus returns the HTTP status code of a genai.APIError in err's chain.
+func geminiStatus(err error) (int, bool) {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code, true
+	}
+	var apiErrPtr *genai.APIError
+	if errors.As(err, &apiErrPtr) {
+		return apiErrPtr.Code, true
+	}
+	return 0, false
+}
@@ -0,0 +1,95 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+
+	customerrors "glance/errors"
+)
+
+// newFailingGeminiClient returns a GeminiClient backed by a local server that
+// rejects every request with status and the given error details.
+func newFailingGeminiClient(t *testing.T, status int, details ...map[string]any) *GeminiClient {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"error": map[string]any{"code": status, "message": http.StatusText(status), "details": details},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := newGeminiClient("test-key", WithBaseURL(server.URL), WithMaxRetries(0))
+	require.NoError(t, err)
+	return client
+}
+
+func TestGeminiClientErrorClassification(t *testing.T) {
+	t.Run("Rate limited", func(t *testing.T) {
+		client := newFailingGeminiClient(t, http.StatusTooManyRequests,
+			map[string]any{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "7s"})
+
+		_, err := client.Generate(context.Background(), "prompt")
+		require.Error(t, err)
+
+		var rateErr *RateLimitError
+		require.ErrorAs(t, err, &rateErr)
+		assert.Equal(t, 7*time.Second, rateErr.RetryAfter)
+
+		var glanceErr customerrors.GlanceError
+		require.ErrorAs(t, err, &glanceErr)
+		assert.Equal(t, "GENAI-004", glanceErr.Code())
+		assert.Contains(t, glanceErr.Suggestion(), "Rate limited")
+		assert.True(t, customerrors.IsAPIError(err))
+
+		var apiErr genai.APIError
+		require.ErrorAs(t, err, &apiErr, "the provider's own error stays in the chain")
+		assert.Equal(t, http.StatusTooManyRequests, apiErr.Code)
+	})
+
+	t.Run("Permission denied", func(t *testing.T) {
+		client := newFailingGeminiClient(t, http.StatusForbidden)
+
+		_, err := client.Generate(context.Background(), "prompt")
+		require.Error(t, err)
+
+		var rateErr *RateLimitError
+		assert.False(t, errors.As(err, &rateErr))
+		var glanceErr customerrors.GlanceError
+		require.ErrorAs(t, err, &glanceErr)
+		assert.Equal(t, "GENAI-004", glanceErr.Code())
+		assert.Contains(t, glanceErr.Suggestion(), "GEMINI_API_KEY")
+		assert.True(t, customerrors.IsAPIError(err))
+	})
+
+	t.Run("Stream error", func(t *testing.T) {
+		client := newFailingGeminiClient(t, http.StatusServiceUnavailable)
+
+		chunks, err := client.GenerateStream(context.Background(), "prompt")
+		require.NoError(t, err)
+		var streamErr error
+		for chunk := range chunks {
+			if chunk.Error != nil {
+				streamErr = chunk.Error
+			}
+		}
+		require.Error(t, streamErr)
+		assert.Contains(t, streamErr.Error(), "[GENAI-032]", "the API's error is reported, not dropped")
+		assert.True(t, customerrors.IsAPIError(streamErr))
+
+		var apiErr genai.APIError
+		require.ErrorAs(t, streamErr, &apiErr)
+		assert.Equal(t, http.StatusServiceUnavailable, apiErr.Code)
+	})
+}
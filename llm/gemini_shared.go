@@ -0,0 +1,85 @@
+package llm
+
+import (
+	"context"
+
+	"google.golang.org/genai"
+
+	customerrors "glance/errors"
+)
+
+// NewGeminiClientWithGenaiFunc is a function type for creating Gemini clients
+// on an existing genai client. This enables mocking in tests.
+type NewGeminiClientWithGenaiFunc func(client *genai.Client, options ...ClientOption) (Client, error)
+
+// The actual implementation function - can be swapped in tests
+var createGeminiClientWithGenai NewGeminiClientWithGenaiFunc = func(client *genai.Client, options ...ClientOption) (Client, error) {
+	return newGeminiClientWithGenai(client, options...)
+}
+
+// NewGenaiClient creates the underlying genai client for the connection settings
+// in options: the API key, WithBackend, WithBaseURL, and WithExtraHeaders. Model
+// and generation options are ignored. Pass the result to NewGeminiClientWithGenai
+// to share one connection across GeminiClients that differ only in model, such
+// as two Gemini tiers of a fallback chain.
+//
+// Parameters:
+//   - apiKey: The API key, used only by the Gemini API backend // pragma: allowlist secret
+//   - options: Zero or more functional options; only connection settings apply
+//
+// Returns:
+//   - The genai client
+//   - An error if the options are invalid or client creation fails
+func NewGenaiClient(apiKey string, options ...ClientOption) (*genai.Client, error) {
+	opts := DefaultClientOptions()
+	for _, option := range options {
+		option(&opts)
+	}
+	return newGenaiClientFromOptions(apiKey, opts)
+}
+
+// NewGeminiClientWithGenai creates a Gemini client that sends its requests
+// through client, typically one made by NewGenaiClient and shared with other
+// tiers. The connection settings among options are ignored, since client
+// already carries them; the model and generation options apply as usual.
+// Closing the returned client leaves client usable by the others.
+func NewGeminiClientWithGenai(client *genai.Client, options ...ClientOption) (Client, error) {
+	return createGeminiClientWithGenai(client, options...)
+}
+
+// newGeminiClientWithGenai is the actual implementation of NewGeminiClientWithGenai.
+func newGeminiClientWithGenai(client *genai.Client, options ...ClientOption) (*GeminiClient, error) {
+	if client == nil {
+		return nil, customerrors.NewValidationError("shared genai client is required", nil).
+			WithCode("GENAI-033")
+	}
+
+	opts := DefaultClientOptions()
+	for _, option := range options {
+		option(&opts)
+	}
+
+	return &GeminiClient{
+		client:  client,
+		model:   opts.ModelName,
+		options: &opts,
+	}, nil
+}
+
+// newGenaiClientFromOptions validates the connection settings in opts and
+// creates the genai client for them.
+func newGenaiClientFromOptions(apiKey string, opts ClientOptions) (*genai.Client, error) {
+	// Validates the API key requirement and backend/endpoint combination
+	clientConfig, err := buildGenaiClientConfig(apiKey, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newGenaiClient(context.Background(), clientConfig)
+	if err != nil {
+		return nil, customerrors.WrapAPIError(err, "failed to create Gemini client").
+			WithCode("GENAI-002").
+			WithSuggestion("Check API key validity and network connectivity")
+	}
+	return client, nil
+}
@@ -0,0 +1,58 @@
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+)
+
+func TestGeminiClientsShareGenaiClient(t *testing.T) {
+	var created int
+	origNew := newGenaiClient
+	newGenaiClient = func(_ context.Context, cc *genai.ClientConfig) (*genai.Client, error) {
+		created++
+		return &genai.Client{}, nil
+	}
+	defer func() { newGenaiClient = origNew }()
+
+	shared, err := NewGenaiClient("test-key", WithBaseURL("https://proxy.example.com/"))
+	require.NoError(t, err)
+
+	primary, err := NewGeminiClientWithGenai(shared, WithModelName("gemini-3-flash-preview"))
+	require.NoError(t, err)
+	stable, err := NewGeminiClientWithGenai(shared, WithModelName("gemini-2.5-flash"), WithMaxOutputTokens(1024))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, created, "tiers on a shared client must not create their own")
+	primaryGemini, stableGemini := primary.(*GeminiClient), stable.(*GeminiClient)
+	assert.Same(t, shared, primaryGemini.client)
+	assert.Same(t, shared, stableGemini.client)
+	assert.Equal(t, "gemini-3-flash-preview", primaryGemini.model)
+	assert.Equal(t, "gemini-2.5-flash", stableGemini.model)
+	assert.Equal(t, int32(1024), stableGemini.options.MaxOutputTokens)
+
+	primary.Close()
+	assert.Same(t, shared, stableGemini.client, "closing one tier leaves the shared client to the others")
+
+	separate, err := NewGeminiClient("test-key", WithModelName("gemini-2.5-flash"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, created, "NewGeminiClient still creates its own genai client")
+	assert.NotSame(t, shared, separate.(*GeminiClient).client)
+}
+
+func TestNewGenaiClientValidatesOptions(t *testing.T) {
+	_, err := NewGenaiClient("", WithModelName("gemini-2.5-flash"))
+	assert.Error(t, err, "the Gemini API backend requires an API key")
+
+	_, err = NewGenaiClient("test-key", WithBackend("bogus"))
+	assert.Error(t, err)
+}
+
+func TestNewGeminiClientWithGenaiRequiresClient(t *testing.T) {
+	client, err := NewGeminiClientWithGenai(nil, WithModelName("gemini-2.5-flash"))
+	assert.Error(t, err)
+	assert.Nil(t, client)
+}
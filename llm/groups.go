@@ -0,0 +1,120 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"glance/filesystem"
+)
+
+// subdirectoriesSection heads the section summarizing a split directory's
+// subdirectories.
+const subdirectoriesSection = "Subdirectories"
+
+// generateGrouped summarizes a directory too large for one prompt: each group
+// of related files (see filesystem.GroupFiles) is summarized on its own, and
+// the child summaries, if any, in a final section. The sections are combined
+// into one document, which is post-processed as a whole. Each section's prompt
+// is cached separately, so a change to one group regenerates only its section.
+func (s *Service) generateGrouped(
+	ctx context.Context,
+	dir string,
+	fileMap map[string]string,
+	subGlances string,
+	promptOptions ...PromptDataOption,
+) (string, error) {
+	groups := filesystem.GroupFiles(fileMap, s.splitMaxFiles)
+	s.log.WithFields(logrus.Fields{
+		"directory":   dir,
+		"file_count":  len(fileMap),
+		"group_count": len(groups),
+		"operation":   "split_large_dir",
+	}).Debug("Summarizing large directory in groups")
+
+	// Sections are streamed once the document is complete, headings included
+	var probe PromptData
+	for _, option := range promptOptions {
+		option(&probe)
+	}
+	sectionOptions := append(promptOptions[:len(promptOptions):len(promptOptions)], func(d *PromptData) {
+		d.stream = nil
+	})
+
+	sections := make([]string, 0, len(groups)+1)
+	for _, group := range groups {
+		summary, err := s.summarize(ctx, dir, group.Files, "", sectionOptions...)
+		if err != nil {
+			return "", fmt.Errorf("group %q: %w", group.Name, err)
+		}
+		sections = append(sections, renderGroupSection(group.Name, sortedFileNames(group.Files), summary))
+	}
+	if strings.TrimSpace(subGlances) != "" {
+		summary, err := s.summarize(ctx, dir, map[string]string{}, subGlances, sectionOptions...)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", strings.ToLower(subdirectoriesSection), err)
+		}
+		sections = append(sections, renderGroupSection(subdirectoriesSection, nil, summary))
+	}
+
+	combined := strings.Join(sections, "\n")
+	if probe.stream != nil {
+		writeStreamed(probe.stream, combined)
+	}
+	return s.finish(combined, dir, fileMap)
+}
+
+// renderGroupSection renders one section of a split directory's summary: a
+// heading, the files it covers, and the summary with its headings demoted to
+// sit below the section heading.
+func renderGroupSection(name string, files []string, summary string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", name)
+	if len(files) > 0 {
+		quoted := make([]string, len(files))
+		for i, file := range files {
+			quoted[i] = "`" + file + "`"
+		}
+		fmt.Fprintf(&b, "_Files: %s_\n\n", strings.Join(quoted, ", "))
+	}
+	b.WriteString(strings.TrimSpace(demoteHeadings(summary, 2)))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// demoteHeadings adds levels to every ATX heading outside code fences, capping
+// them at level 6, so a summary can be nested under a section heading.
+func demoteHeadings(markdown string, levels int) string {
+	lines := strings.Split(markdown, "\n")
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || !strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+		rest := trimmed[level:]
+		if level > 6 || (rest != "" && !strings.HasPrefix(rest, " ")) {
+			continue // not a heading, e.g. "#hashtag"
+		}
+		lines[i] = strings.Repeat("#", min(level+levels, 6)) + rest
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sortedFileNames returns the names in fileMap in alphabetical order.
+func sortedFileNames(fileMap map[string]string) []string {
+	names := make([]string, 0, len(fileMap))
+	for name := range fileMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
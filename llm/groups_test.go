@@ -0,0 +1,76 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/internal/mocks"
+)
+
+func TestGenerateGlanceMarkdownSplitLargeDirs(t *testing.T) {
+	mockClient := new(mocks.LLMClient)
+	service, err := NewService(NewMockClientAdapter(mockClient),
+		WithPromptTemplate("{{.FileContents}}|{{.SubGlances}}"),
+		WithSplitLargeDirs(2),
+	)
+	require.NoError(t, err)
+
+	var prompts []string
+	mockClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil)
+	mockClient.On("Generate", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { prompts = append(prompts, args.String(1)) }).
+		Return("# Overview\n\nDetails.\n\n```sh\n# not a heading\n```", nil)
+
+	files := map[string]string{
+		"api_user.go": "U", "api_order.go": "O",
+		"db_user.go": "D", "db_order.go": "E",
+	}
+	var streamed strings.Builder
+	result, err := service.GenerateGlanceMarkdown(context.Background(), "big", files,
+		"sub summary", WithStreamWriter(&streamed))
+	require.NoError(t, err)
+
+	require.Len(t, prompts, 3, "one generation per group plus one for subdirectories")
+	assert.Contains(t, prompts[0], "api_order.go")
+	assert.NotContains(t, prompts[0], "db_user.go")
+	assert.NotContains(t, prompts[0], "sub summary", "group prompts leave out subdirectory summaries")
+	assert.Contains(t, prompts[2], "sub summary")
+	assert.NotContains(t, prompts[2], "api_user.go")
+
+	assert.Contains(t, result, "## api\n\n_Files: `api_order.go`, `api_user.go`_\n\n### Overview")
+	assert.Contains(t, result, "## db\n\n_Files: `db_order.go`, `db_user.go`_")
+	assert.Contains(t, result, "## Subdirectories\n\n### Overview")
+	assert.Contains(t, result, "# not a heading", "headings in code fences are left alone")
+	assert.Less(t, strings.Index(result, "## api"), strings.Index(result, "## db"))
+	assert.Less(t, strings.Index(result, "## db"), strings.Index(result, "## Subdirectories"))
+	assert.Equal(t, result, streamed.String(), "the combined document is streamed once")
+}
+
+func TestGenerateGlanceMarkdownSplitSmallDir(t *testing.T) {
+	mockClient := new(mocks.LLMClient)
+	service, err := NewService(NewMockClientAdapter(mockClient),
+		WithPromptTemplate("{{.FileContents}}"),
+		WithSplitLargeDirs(5),
+	)
+	require.NoError(t, err)
+
+	mockClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil)
+	mockClient.On("Generate", mock.Anything, mock.Anything).Return("# Summary", nil).Once()
+
+	result, err := service.GenerateGlanceMarkdown(context.Background(), "small",
+		map[string]string{"a.go": "A", "b.go": "B"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "# Summary", result, "directories within the limit are summarized whole")
+	mockClient.AssertExpectations(t)
+}
+
+func TestDemoteHeadings(t *testing.T) {
+	input := "# One\n#### Four\n###### Six\n#tag\n```\n# code\n```\ntext"
+	assert.Equal(t, "### One\n###### Four\n###### Six\n#tag\n```\n# code\n```\ntext",
+		demoteHeadings(input, 2))
+}
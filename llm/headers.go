@@ -0,0 +1,59 @@
+// Package llm provides abstractions and implementations for interacting with
+// Large Language Model APIs in the glance application.
+package llm
+
+import (
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+// reservedHeaders are set by the clients themselves and cannot be overridden by
+// extra headers, so a gateway tag can never replace credentials or framing.
+var reservedHeaders = map[string]bool{
+	"Authorization":  true,
+	"Content-Type":   true,
+	"Content-Length": true,
+	"Host":           true,
+	"X-Goog-Api-Key": true,
+}
+
+// IsReservedHeader reports whether name (in any case) is a header the clients
+// manage themselves and that WithExtraHeaders will not send.
+func IsReservedHeader(name string) bool {
+	return reservedHeaders[http.CanonicalHeaderKey(name)]
+}
+
+// WithExtraHeaders adds HTTP headers, such as gateway cost-center tags or request
+// IDs, to every request the client sends. Repeated calls merge, with later values
+// winning. Reserved headers (see IsReservedHeader) are ignored.
+func WithExtraHeaders(headers map[string]string) ClientOption {
+	return func(o *ClientOptions) {
+		if len(headers) == 0 {
+			return
+		}
+		if o.ExtraHeaders == nil {
+			o.ExtraHeaders = make(map[string]string, len(headers))
+		}
+		for name, value := range headers {
+			o.ExtraHeaders[name] = value
+		}
+	}
+}
+
+// extraHTTPHeader converts extra headers to an http.Header, dropping reserved ones
+// with a warning to logger. It returns nil when there is nothing to send.
+func extraHTTPHeader(extra map[string]string, logger logrus.FieldLogger) http.Header {
+	var header http.Header
+	for name, value := range extra {
+		if IsReservedHeader(name) {
+			logger.WithField("header", http.CanonicalHeaderKey(name)).Warn("Ignoring extra header that would override a reserved header")
+			continue
+		}
+		if header == nil {
+			header = make(http.Header, len(extra))
+		}
+		header.Set(name, value)
+	}
+	return header
+}
@@ -0,0 +1,73 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsReservedHeader(t *testing.T) {
+	assert.True(t, IsReservedHeader("Authorization"))
+	assert.True(t, IsReservedHeader("authorization"))
+	assert.True(t, IsReservedHeader("content-type"))
+	assert.True(t, IsReservedHeader("x-goog-api-key"))
+	assert.False(t, IsReservedHeader("X-Cost-Center"))
+}
+
+func TestWithExtraHeadersMerges(t *testing.T) {
+	opts := DefaultClientOptions()
+	WithExtraHeaders(map[string]string{"X-A": "1", "X-B": "2"})(&opts)
+	WithExtraHeaders(map[string]string{"X-B": "3"})(&opts)
+	assert.Equal(t, map[string]string{"X-A": "1", "X-B": "3"}, opts.ExtraHeaders)
+}
+
+func TestExtraHeadersOnChatCompletionsRequest(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]any{"content": "ok"}}},
+		})
+	}))
+	defer server.Close()
+
+	clientIface, err := NewOpenRouterClient("test-key",
+		WithModelName("x-ai/grok-4.1-fast"),
+		WithExtraHeaders(map[string]string{
+			"X-Cost-Center": "platform",
+			"x-request-id":  "abc-123",
+			"Authorization": "Bearer stolen",
+			"content-type":  "text/plain",
+		}),
+	)
+	require.NoError(t, err)
+	client := clientIface.(*OpenRouterClient)
+	client.baseURL = server.URL
+
+	_, genErr := client.Generate(context.Background(), "prompt")
+	require.NoError(t, genErr)
+
+	assert.Equal(t, "platform", got.Get("X-Cost-Center"))
+	assert.Equal(t, "abc-123", got.Get("X-Request-Id"))
+	assert.Equal(t, []string{"Bearer test-key"}, got.Values("Authorization"), "reserved header must not be clobbered")
+	assert.Equal(t, []string{"application/json"}, got.Values("Content-Type"))
+}
+
+func TestExtraHeadersOnGenaiConfig(t *testing.T) {
+	cc, err := buildGenaiClientConfig("test-key", ClientOptions{
+		ExtraHeaders: map[string]string{"X-Cost-Center": "platform", "X-Goog-Api-Key": "other"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "platform", cc.HTTPOptions.Headers.Get("X-Cost-Center"))
+	assert.Empty(t, cc.HTTPOptions.Headers.Get("X-Goog-Api-Key"))
+	assert.Equal(t, "test-key", cc.APIKey)
+
+	cc, err = buildGenaiClientConfig("test-key", ClientOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, cc.HTTPOptions.Headers)
+}
@@ -0,0 +1,82 @@
+package llm
+
+import (
+	"context"
+)
+
+// IncrementalTemplate returns the prompt template used to update an existing
+// summary from a diff of the directory's files instead of their full contents.
+func IncrementalTemplate() string {
+	return `you are an expert code reviewer and technical writer.
+below is the existing technical summary of a directory, followed by a diff of
+the changes made to the directory's files since it was written.
+update the summary so it is accurate for the changed files.
+
+Hard constraints:
+- keep every part of the existing summary that the diff does not contradict, and keep its sections, order, and style.
+- change only what the diff shows has changed: added, removed, or renamed files, and changed responsibilities or dependencies.
+- do NOT speculate beyond what the existing summary, the diff, and the subdirectory summaries show.
+- do NOT mention the diff, the update, or what changed; the result must read as a fresh summary.
+
+respond with ONLY the updated summary.
+
+directory: {{.Directory}}
+
+existing summary:
+{{.PreviousSummary}}
+
+changes to the directory's files since then:
+{{.Diff}}
+{{- with .Files}}
+
+files now in the directory:
+{{- range .}}
+- {{.Name}}
+{{- end}}
+{{- end}}
+
+subdirectory summaries:
+{{.SubGlances}}
+`
+}
+
+// UpdateGlanceMarkdown is GenerateGlanceMarkdown for a directory that already
+// has a summary: instead of the files' full contents, the prompt holds the
+// previous summary and diff, the changes since it was written, and asks the
+// model to update it (see IncrementalTemplate). fileMap still names the files
+// in the prompt and is used for post-processing, but is never split into
+// groups or pre-summarized, since its contents are not sent.
+//
+// Parameters:
+//   - ctx: The context for the request
+//   - dir: The directory being summarized
+//   - fileMap: The directory's current files
+//   - subGlances: The subdirectory summaries
+//   - previousSummary: The existing summary, without any footer
+//   - diff: The changes to the directory's files since previousSummary
+//   - promptOptions: Further prompt data; any template they set is replaced
+//
+// Returns:
+//   - The updated summary
+//   - An error if generation fails
+func (s *Service) UpdateGlanceMarkdown(
+	ctx context.Context,
+	dir string,
+	fileMap map[string]string,
+	subGlances string,
+	previousSummary string,
+	diff string,
+	promptOptions ...PromptDataOption,
+) (string, error) {
+	options := append(append([]PromptDataOption{}, promptOptions...), func(d *PromptData) {
+		d.PreviousSummary = previousSummary
+		d.Diff = diff
+		d.template = IncrementalTemplate()
+	})
+
+	summary, err := s.summarize(ctx, dir, fileMap, subGlances, options...)
+	if err != nil {
+		return "", err
+	}
+	return s.finish(summary, dir, fileMap)
+}
@@ -0,0 +1,43 @@
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/internal/mocks"
+)
+
+func TestUpdateGlanceMarkdown(t *testing.T) {
+	mockClient := new(mocks.LLMClient)
+	service, err := NewService(NewMockClientAdapter(mockClient),
+		WithPromptTemplate("{{.FileContents}}"),
+		WithChunkSummarize(10),
+	)
+	require.NoError(t, err)
+
+	var prompts []string
+	mockClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil)
+	mockClient.On("Generate", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { prompts = append(prompts, args.String(1)) }).
+		Return("## Purpose\nupdated", nil)
+
+	files := map[string]string{"server.go": "package server // far longer than the chunk threshold"}
+	diff := "--- a/server.go\n+++ b/server.go\n@@ -1 +1 @@\n-old\n+new\n"
+	summary, err := service.UpdateGlanceMarkdown(context.Background(), "pkg", files, "sub summary",
+		"## Purpose\nprevious", diff)
+	require.NoError(t, err)
+	assert.Contains(t, summary, "updated")
+
+	require.Len(t, prompts, 1, "file contents are never pre-summarized")
+	prompt := prompts[0]
+	assert.Contains(t, prompt, "directory: pkg")
+	assert.Contains(t, prompt, "existing summary:\n## Purpose\nprevious")
+	assert.Contains(t, prompt, diff)
+	assert.Contains(t, prompt, "- server.go")
+	assert.Contains(t, prompt, "sub summary")
+	assert.NotContains(t, prompt, "package server", "the configured template and file contents are not used")
+}
@@ -0,0 +1,48 @@
+// Package llm provides abstractions and implementations for interacting with
+// Large Language Model APIs in the glance application.
+package llm
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// loggerOrStandard returns logger, or the standard logrus logger when logger is
+// nil, so every component logs somewhere without requiring an option.
+func loggerOrStandard(logger logrus.FieldLogger) logrus.FieldLogger {
+	if logger == nil {
+		return logrus.StandardLogger()
+	}
+	return logger
+}
+
+// WithLogger sends the client's retry, truncation, and configuration warnings to
+// logger instead of the standard logrus logger, so a program embedding glance can
+// merge them into its own logs. This parallels filesystem.SetLogger, but is
+// scoped to one client.
+func WithLogger(logger logrus.FieldLogger) ClientOption {
+	return func(o *ClientOptions) {
+		o.Logger = logger
+	}
+}
+
+// WithServiceLogger sends the service's logging to logger instead of the
+// standard logrus logger.
+func WithServiceLogger(logger logrus.FieldLogger) func(*ServiceConfig) {
+	return func(c *ServiceConfig) {
+		c.Logger = logger
+	}
+}
+
+// WithFallbackLogger sends the fallback client's retry and failover logging to
+// logger instead of the standard logrus logger. Each tier keeps the logger it
+// was created with.
+func WithFallbackLogger(logger logrus.FieldLogger) FallbackOption {
+	return func(c *FallbackClient) {
+		c.log = loggerOrStandard(logger)
+	}
+}
+
+// log returns the logger configured with WithLogger.
+func (o *ClientOptions) log() logrus.FieldLogger {
+	return loggerOrStandard(o.Logger)
+}
@@ -0,0 +1,105 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/internal/mocks"
+)
+
+// messages returns the messages of the entries a test hook captured.
+func messages(hook *test.Hook) []string {
+	var out []string
+	for _, entry := range hook.AllEntries() {
+		out = append(out, entry.Message)
+	}
+	return out
+}
+
+func TestInjectedLoggers(t *testing.T) {
+	global := test.NewGlobal()
+
+	t.Run("service", func(t *testing.T) {
+		global.Reset()
+		logger, hook := test.NewNullLogger()
+
+		mockClient := new(mocks.LLMClient)
+		mockClient.On("CountTokens", mock.Anything, mock.Anything).Return(0, errors.New("count failed"))
+		service, err := NewService(NewMockClientAdapter(mockClient),
+			WithPromptTemplate("{{.Directory}}"),
+			WithTokenCountOptional(false),
+			WithServiceLogger(logger),
+		)
+		require.NoError(t, err)
+
+		_, err = service.GenerateGlanceMarkdown(context.Background(), "pkg", nil, "")
+		require.Error(t, err)
+		assert.Contains(t, messages(hook), "Failed to count tokens - token counting is required")
+		assert.Empty(t, global.AllEntries(), "nothing reaches the global logger")
+	})
+
+	t.Run("fallback client", func(t *testing.T) {
+		global.Reset()
+		useFakeClock(t)
+		logger, hook := test.NewNullLogger()
+
+		primaryMock := new(mocks.LLMClient)
+		primaryMock.On("Generate", mock.Anything, "prompt").Return("", errors.New("transient")).Once()
+		primaryMock.On("Generate", mock.Anything, "prompt").Return("ok", nil).Once()
+		client, err := NewFallbackClientWithBackoff(
+			[]FallbackTier{{Name: "primary", Client: NewMockClientAdapter(primaryMock)}},
+			1, time.Second, time.Second,
+			WithFallbackLogger(logger),
+		)
+		require.NoError(t, err)
+
+		_, err = client.Generate(context.Background(), "prompt")
+		require.NoError(t, err)
+		assert.Contains(t, messages(hook), "LLM tier attempt failed, retrying tier")
+		assert.Empty(t, global.AllEntries(), "nothing reaches the global logger")
+	})
+
+	t.Run("gemini client", func(t *testing.T) {
+		global.Reset()
+		logger, hook := test.NewNullLogger()
+
+		client, _ := newFinishReasonGeminiClient(t, "MAX_TOKENS", "partial")
+		WithLogger(logger)(client.options)
+
+		_, err := client.Generate(context.Background(), "prompt")
+		require.NoError(t, err)
+		assert.Contains(t, messages(hook), "Generation stopped at the output token limit; returning partial content")
+		assert.Empty(t, global.AllEntries(), "nothing reaches the global logger")
+	})
+
+	t.Run("chat completions client", func(t *testing.T) {
+		global.Reset()
+		logger, hook := test.NewNullLogger()
+
+		_, err := NewOpenRouterClient("test-key",
+			WithLogger(logger),
+			WithExtraHeaders(map[string]string{"Authorization": "Bearer other"}),
+		)
+		require.NoError(t, err)
+		assert.Contains(t, messages(hook), "Ignoring extra header that would override a reserved header")
+		assert.Empty(t, global.AllEntries(), "nothing reaches the global logger")
+	})
+
+	t.Run("defaults to the standard logger", func(t *testing.T) {
+		global.Reset()
+		_, err := NewOpenRouterClient("test-key",
+			WithExtraHeaders(map[string]string{"Authorization": "Bearer other"}),
+		)
+		require.NoError(t, err)
+		require.NotEmpty(t, global.AllEntries())
+		assert.Equal(t, logrus.WarnLevel, global.LastEntry().Level)
+	})
+}
@@ -0,0 +1,115 @@
+// Package llm provides abstractions and implementations for interacting with
+// Large Language Model APIs in the glance application.
+package llm
+
+import (
+	"fmt"
+	"strings"
+
+	customerrors "glance/errors"
+)
+
+// MinMarkdownLength is the shortest generated summary, in bytes after trimming
+// whitespace, that ValidateMarkdown accepts as a plausible directory summary.
+const MinMarkdownLength = 40
+
+// ValidateMarkdown checks generated markdown for signs of a malformed or
+// truncated response: output that is too short, or a code fence that is
+// opened but never closed.
+//
+// Parameters:
+//   - s: The generated markdown
+//
+// Returns:
+//   - A validation error describing the first problem found, or nil if the markdown looks sound
+func ValidateMarkdown(s string) error {
+	trimmed := strings.TrimSpace(s)
+	if len(trimmed) < MinMarkdownLength {
+		return customerrors.NewValidationError(
+			fmt.Sprintf("generated markdown is too short (%d bytes, minimum %d)", len(trimmed), MinMarkdownLength),
+			nil,
+		).WithCode("MARKDOWN-001")
+	}
+
+	return CheckCodeFences(s)
+}
+
+// CheckCodeFences checks markdown for a code fence that is opened but never
+// closed, a sign that it was truncated. Unlike ValidateMarkdown it accepts
+// short markdown, such as the stubs written for trivial directories.
+//
+// Parameters:
+//   - s: The markdown to check
+//
+// Returns:
+//   - A validation error naming the line of the unclosed fence, or nil
+func CheckCodeFences(s string) error {
+	if line, _, open := unclosedCodeFence(s); open {
+		return customerrors.NewValidationError(
+			fmt.Sprintf("generated markdown has an unclosed code fence opened on line %d", line),
+			nil,
+		).WithCode("MARKDOWN-002").
+			WithSuggestion("The response was probably truncated; consider raising the output token limit")
+	}
+	return nil
+}
+
+// closeCodeFence appends a closing fence to markdown that ends inside a code
+// block, such as a response cut off at the output token limit, so that text
+// added after it is not swallowed by the block. Other markdown is unchanged.
+func closeCodeFence(s string) string {
+	_, fence, open := unclosedCodeFence(s)
+	if !open {
+		return s
+	}
+	if !strings.HasSuffix(s, "\n") {
+		s += "\n"
+	}
+	return s + fence + "\n"
+}
+
+// unclosedCodeFence scans for fenced code blocks (``` or ~~~) following the
+// CommonMark rules: a fence is closed only by a run of the same character at
+// least as long as the opener, with nothing but whitespace after it.
+// It returns the 1-based line of an unclosed opener, if any, and a fence that
+// closes it.
+func unclosedCodeFence(s string) (int, string, bool) {
+	var openChar byte
+	var openLen, openLine int
+
+	for i, line := range strings.Split(s, "\n") {
+		// Fences may be indented by up to three spaces
+		trimmed := strings.TrimLeft(line, " ")
+		if len(line)-len(trimmed) > 3 || len(trimmed) < 3 {
+			continue
+		}
+
+		char := trimmed[0]
+		if char != '`' && char != '~' {
+			continue
+		}
+		run := len(trimmed) - len(strings.TrimLeft(trimmed, string(char)))
+		if run < 3 {
+			continue
+		}
+
+		if openChar == 0 {
+			info := trimmed[run:]
+			// Backtick fences may not contain backticks in their info string
+			if char == '`' && strings.Contains(info, "`") {
+				continue
+			}
+			openChar, openLen, openLine = char, run, i+1
+			continue
+		}
+
+		if char == openChar && run >= openLen && strings.TrimSpace(trimmed[run:]) == "" {
+			openChar = 0
+		}
+	}
+
+	if openChar == 0 {
+		return 0, "", false
+	}
+	return openLine, strings.Repeat(string(openChar), openLen), true
+}
@@ -0,0 +1,59 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMarkdown(t *testing.T) {
+	const body = "# pkg\n\nThis package parses configuration files and validates them.\n"
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "Plain markdown", input: body},
+		{name: "Balanced backtick fence", input: body + "\n```go\nfunc main() {}\n```\n"},
+		{name: "Balanced tilde fence", input: body + "\n~~~\ncode\n~~~\n"},
+		{name: "Longer closing fence", input: body + "\n```\ncode\n`````\n"},
+		{name: "Inline backticks are not fences", input: body + "\nUse `go test` to run ``tests``.\n"},
+		{name: "Indented code is not a fence", input: body + "\n    ```\n"},
+		{name: "Empty", input: "   \n", wantErr: "too short"},
+		{name: "Too short", input: "# pkg\n\nParses.", wantErr: "too short"},
+		{name: "Unclosed fence", input: body + "\n```go\nfunc main() {", wantErr: "unclosed code fence opened on line 5"},
+		{name: "Mismatched closing character", input: body + "\n```\ncode\n~~~\n", wantErr: "unclosed code fence"},
+		{name: "Shorter closing fence", input: body + "\n````\ncode\n```\n", wantErr: "unclosed code fence"},
+		{name: "Closing fence with info string", input: body + "\n```\ncode\n```go\n", wantErr: "unclosed code fence"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateMarkdown(tc.input)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
+
+func TestCheckCodeFences(t *testing.T) {
+	assert.NoError(t, CheckCodeFences(""), "empty markdown has no open fence")
+	assert.NoError(t, CheckCodeFences("# pkg\n\nShort stub.\n"), "short markdown is accepted")
+	assert.NoError(t, CheckCodeFences("```\ncode\n```\n"))
+
+	err := CheckCodeFences("# pkg\n\n~~~\ncode\n")
+	assert.ErrorContains(t, err, "unclosed code fence opened on line 3")
+}
+
+func TestCloseCodeFence(t *testing.T) {
+	assert.Equal(t, "# pkg\n\nNo code.\n", closeCodeFence("# pkg\n\nNo code.\n"), "balanced markdown is unchanged")
+	assert.Equal(t, "```go\nx\n```\n", closeCodeFence("```go\nx\n```\n"))
+	assert.Equal(t, "````go\nfunc main() {\n````\n", closeCodeFence("````go\nfunc main() {"), "the closer matches the opener")
+	assert.Equal(t, "~~~\ncode\n~~~\n", closeCodeFence("~~~\ncode\n"))
+	assert.NoError(t, CheckCodeFences(closeCodeFence("# pkg\n\n```go\nfunc main() {")))
+}
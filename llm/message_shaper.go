@@ -0,0 +1,43 @@
+// Package llm provides abstractions and implementations for interacting with
+// Large Language Model APIs in the glance application.
+package llm
+
+import "strings"
+
+// ChatMessage is one message of a chat-completions request.
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// MessageShaper builds the conversation sent to a chat-completions endpoint from
+// the client's system instructions (possibly empty) and the rendered prompt.
+// A shaper may, for example, split the prompt across several user messages or
+// end with an assistant message that primes the reply.
+type MessageShaper func(systemInstructions, prompt string) []ChatMessage
+
+// DefaultMessageShaper sends the system instructions, when present, followed by
+// the prompt as a single user message.
+func DefaultMessageShaper(systemInstructions, prompt string) []ChatMessage {
+	messages := make([]ChatMessage, 0, 2)
+	if strings.TrimSpace(systemInstructions) != "" {
+		messages = append(messages, ChatMessage{
+			Role:    "system",
+			Content: systemInstructions,
+		})
+	}
+	messages = append(messages, ChatMessage{
+		Role:    "user",
+		Content: prompt,
+	})
+	return messages
+}
+
+// WithMessageShaper customizes the conversation structure sent by the
+// OpenRouter and OpenAI-compatible clients. A shaper that returns no messages
+// falls back to DefaultMessageShaper. The Gemini client ignores this option.
+func WithMessageShaper(shaper MessageShaper) ClientOption {
+	return func(o *ClientOptions) {
+		o.MessageShaper = shaper
+	}
+}
@@ -0,0 +1,84 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureMessages starts a chat-completions server that records the request messages.
+func captureMessages(t *testing.T) (*httptest.Server, *[]ChatMessage) {
+	t.Helper()
+	var got []ChatMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openRouterChatRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		got = req.Messages
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]any{"content": "ok"}}},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &got
+}
+
+func TestMessageShaper(t *testing.T) {
+	primed := func(system, prompt string) []ChatMessage {
+		return []ChatMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: prompt},
+			{Role: "assistant", Content: "# "},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		options []ClientOption
+		want    []ChatMessage
+	}{
+		{
+			name:    "default shape",
+			options: []ClientOption{WithSystemInstructions("be brief")},
+			want:    []ChatMessage{{Role: "system", Content: "be brief"}, {Role: "user", Content: "summarize"}},
+		},
+		{
+			name:    "default shape without system instructions",
+			options: nil,
+			want:    []ChatMessage{{Role: "user", Content: "summarize"}},
+		},
+		{
+			name:    "custom shaper",
+			options: []ClientOption{WithSystemInstructions("be brief"), WithMessageShaper(primed)},
+			want: []ChatMessage{
+				{Role: "system", Content: "be brief"},
+				{Role: "user", Content: "summarize"},
+				{Role: "assistant", Content: "# "},
+			},
+		},
+		{
+			name: "empty shaper output falls back to the default",
+			options: []ClientOption{WithMessageShaper(func(string, string) []ChatMessage {
+				return nil
+			})},
+			want: []ChatMessage{{Role: "user", Content: "summarize"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, got := captureMessages(t)
+			options := append([]ClientOption{WithModelName("llama3.2")}, tt.options...)
+			client, err := NewOpenAICompatibleClient(server.URL, "", options...)
+			require.NoError(t, err)
+
+			_, err = client.Generate(context.Background(), "summarize")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *got)
+		})
+	}
+}
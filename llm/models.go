@@ -0,0 +1,47 @@
+// Package llm provides abstractions and implementations for interacting with
+// Large Language Model APIs in the glance application.
+package llm
+
+import "strings"
+
+// ModelProfile describes the sizing characteristics of a specific model.
+type ModelProfile struct {
+	// ContextWindow is the maximum number of input tokens the model accepts
+	ContextWindow int
+
+	// DefaultMaxOutputTokens is the recommended output token limit for summaries
+	DefaultMaxOutputTokens int
+}
+
+// conservativeModelProfile is used for models that are not in the registry.
+// Its context window is deliberately small so budgeting errs on the side of caution.
+var conservativeModelProfile = ModelProfile{
+	ContextWindow:          32768,
+	DefaultMaxOutputTokens: 2048,
+}
+
+// modelProfiles maps known model names to their profiles.
+// Keys are lowercase and carry no provider-specific "models/" prefix.
+var modelProfiles = map[string]ModelProfile{
+	"gemini-3-flash-preview": {ContextWindow: 1048576, DefaultMaxOutputTokens: 4096},
+	"gemini-3-pro-preview":   {ContextWindow: 1048576, DefaultMaxOutputTokens: 4096},
+	"gemini-2.5-flash":       {ContextWindow: 1048576, DefaultMaxOutputTokens: 4096},
+	"gemini-2.5-flash-lite":  {ContextWindow: 1048576, DefaultMaxOutputTokens: 4096},
+	"gemini-2.5-pro":         {ContextWindow: 1048576, DefaultMaxOutputTokens: 4096},
+	"gemini-2.0-flash":       {ContextWindow: 1048576, DefaultMaxOutputTokens: 4096},
+	"x-ai/grok-4.1-fast":     {ContextWindow: 2000000, DefaultMaxOutputTokens: 4096},
+}
+
+// LookupModelProfile returns the profile for a model name.
+// Lookup is case-insensitive and ignores a leading "models/" prefix.
+// The boolean result reports whether the model is known; unknown models
+// receive a conservative fallback profile.
+func LookupModelProfile(name string) (ModelProfile, bool) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	key = strings.TrimPrefix(key, "models/")
+
+	if profile, ok := modelProfiles[key]; ok {
+		return profile, true
+	}
+	return conservativeModelProfile, false
+}
@@ -0,0 +1,39 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupModelProfile(t *testing.T) {
+	t.Run("Known models", func(t *testing.T) {
+		for _, name := range []string{"gemini-3-flash-preview", "gemini-2.5-flash", "x-ai/grok-4.1-fast"} {
+			profile, ok := LookupModelProfile(name)
+			assert.True(t, ok, "%s should be a known model", name)
+			assert.Greater(t, profile.ContextWindow, conservativeModelProfile.ContextWindow)
+			assert.Positive(t, profile.DefaultMaxOutputTokens)
+		}
+	})
+
+	t.Run("Specific values", func(t *testing.T) {
+		profile, ok := LookupModelProfile("gemini-2.5-flash")
+		assert.True(t, ok)
+		assert.Equal(t, ModelProfile{ContextWindow: 1048576, DefaultMaxOutputTokens: 4096}, profile)
+	})
+
+	t.Run("Normalizes case and models/ prefix", func(t *testing.T) {
+		profile, ok := LookupModelProfile(" models/Gemini-2.5-Flash ")
+		assert.True(t, ok)
+		assert.Equal(t, 1048576, profile.ContextWindow)
+	})
+
+	t.Run("Unknown model falls back to conservative defaults", func(t *testing.T) {
+		profile, ok := LookupModelProfile("some-future-model")
+		assert.False(t, ok)
+		assert.Equal(t, conservativeModelProfile, profile)
+
+		_, ok = LookupModelProfile("")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,93 @@
+// Package llm provides abstractions and implementations for interacting with
+// Large Language Model APIs in the glance application.
+package llm
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	customerrors "glance/errors"
+)
+
+const openAICompatibleCodeBase = "OPENAI"
+
+// OpenAICompatibleClient is a Client for any server exposing an OpenAI-compatible
+// /chat/completions API, such as Ollama, LM Studio, or Azure OpenAI.
+type OpenAICompatibleClient struct {
+	chatCompletionsClient
+}
+
+// NewOpenAICompatibleClientFunc is a function type for creating OpenAI-compatible clients.
+// This enables mocking in tests.
+type NewOpenAICompatibleClientFunc func(baseURL, apiKey string, options ...ClientOption) (Client, error)
+
+// The actual implementation function - can be swapped in tests
+var createOpenAICompatibleClient NewOpenAICompatibleClientFunc = func(baseURL, apiKey string, options ...ClientOption) (Client, error) {
+	return newOpenAICompatibleClient(baseURL, apiKey, options...)
+}
+
+// NewOpenAICompatibleClient creates a client for an OpenAI-compatible endpoint.
+// baseURL is the API root that /chat/completions is appended to
+// (e.g. "http://localhost:11434/v1"). apiKey may be empty for local servers
+// that need no auth, in which case no Authorization header is sent.
+func NewOpenAICompatibleClient(baseURL, apiKey string, options ...ClientOption) (Client, error) {
+	return createOpenAICompatibleClient(baseURL, apiKey, options...)
+}
+
+// newOpenAICompatibleClient is the actual implementation for creating an OpenAI-compatible client.
+func newOpenAICompatibleClient(baseURL, apiKey string, options ...ClientOption) (*OpenAICompatibleClient, error) {
+	apiKey = strings.TrimSpace(apiKey)
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	if err := validateOpenAIBaseURL(baseURL, apiKey != ""); err != nil {
+		return nil, err
+	}
+
+	opts := DefaultClientOptions()
+	for _, option := range options {
+		option(&opts)
+	}
+
+	if strings.TrimSpace(opts.ModelName) == "" {
+		return nil, customerrors.NewValidationError("OpenAI-compatible model name is required", nil).
+			WithCode(openAICompatibleCodeBase + "-002")
+	}
+
+	timeout := time.Duration(opts.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 60 * time.Second
+	}
+
+	return &OpenAICompatibleClient{chatCompletionsClient{
+		httpClient:   &http.Client{Timeout: timeout},
+		apiKey:       apiKey, // pragma: allowlist secret
+		baseURL:      baseURL,
+		model:        opts.ModelName,
+		options:      &opts,
+		provider:     "OpenAI-compatible endpoint",
+		codeBase:     openAICompatibleCodeBase,
+		extraHeaders: extraHTTPHeader(opts.ExtraHeaders, opts.log()),
+	}}, nil
+}
+
+// validateOpenAIBaseURL ensures the base URL is an absolute http(s) URL and,
+// when an API key will be sent, uses HTTPS unless it points at the local machine.
+func validateOpenAIBaseURL(baseURL string, sendsAPIKey bool) error {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return customerrors.NewValidationError(fmt.Sprintf("invalid OpenAI-compatible base URL %q", baseURL), err).
+			WithCode(openAICompatibleCodeBase + "-001").
+			WithSuggestion("Use an absolute http(s) URL such as http://localhost:11434/v1")
+	}
+
+	if sendsAPIKey && parsed.Scheme == "http" && !isLoopbackHost(parsed.Hostname()) {
+		return customerrors.NewValidationError(
+			fmt.Sprintf("refusing to send the API key over plain HTTP to %q", parsed.Host), nil).
+			WithCode(openAICompatibleCodeBase + "-014").
+			WithSuggestion("Use an https:// base URL, or a server on localhost")
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newOpenAIServer mimics an OpenAI-compatible /v1/chat/completions endpoint and
+// records the Authorization header and decoded request of the last call.
+func newOpenAIServer(t *testing.T, gotAuth *string, gotReq *openRouterChatRequest) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		*gotAuth = r.Header.Get("Authorization")
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(gotReq))
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":     "chatcmpl-1",
+			"object": "chat.completion",
+			"choices": []map[string]any{
+				{
+					"index":         0,
+					"finish_reason": "stop",
+					"message": map[string]any{
+						"role":    "assistant",
+						"content": "local model output",
+					},
+				},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOpenAICompatibleClientGenerate(t *testing.T) {
+	t.Run("Local server without auth", func(t *testing.T) {
+		var auth string
+		var req openRouterChatRequest
+		server := newOpenAIServer(t, &auth, &req)
+
+		client, err := NewOpenAICompatibleClient(server.URL+"/v1/", "", WithModelName("llama3.2"))
+		require.NoError(t, err)
+
+		out, genErr := client.Generate(context.Background(), "summarize")
+		require.NoError(t, genErr)
+		assert.Equal(t, "local model output", out)
+		assert.Empty(t, auth, "no Authorization header without an API key")
+		assert.Equal(t, "llama3.2", req.Model)
+		require.Len(t, req.Messages, 1)
+		assert.Equal(t, "user", req.Messages[0].Role)
+		assert.Equal(t, "summarize", req.Messages[0].Content)
+	})
+
+	t.Run("Bearer auth when a key is given", func(t *testing.T) {
+		var auth string
+		var req openRouterChatRequest
+		server := newOpenAIServer(t, &auth, &req)
+
+		client, err := NewOpenAICompatibleClient(server.URL+"/v1", "sk-test", WithModelName("gpt-4o-mini"))
+		require.NoError(t, err)
+
+		_, genErr := client.Generate(context.Background(), "summarize")
+		require.NoError(t, genErr)
+		assert.Equal(t, "Bearer sk-test", auth)
+	})
+
+	t.Run("Errors name the provider", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"message": "model not found"}})
+		}))
+		defer server.Close()
+
+		client, err := NewOpenAICompatibleClient(server.URL, "", WithModelName("missing"), WithMaxRetries(0))
+		require.NoError(t, err)
+
+		_, genErr := client.Generate(context.Background(), "summarize")
+		require.Error(t, genErr)
+		assert.Contains(t, genErr.Error(), "OpenAI-compatible endpoint returned status 404: model not found")
+	})
+}
+
+func TestNewOpenAICompatibleClientValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		apiKey  string
+		opts    []ClientOption
+	}{
+		{name: "Empty base URL", baseURL: "", opts: []ClientOption{WithModelName("m")}},
+		{name: "Relative base URL", baseURL: "localhost:11434/v1", opts: []ClientOption{WithModelName("m")}},
+		{name: "Unsupported scheme", baseURL: "ftp://example.com/v1", opts: []ClientOption{WithModelName("m")}},
+		{name: "Key over plain HTTP to a remote host", baseURL: "http://example.com/v1", apiKey: "sk-test", opts: []ClientOption{WithModelName("m")}},
+		{name: "Missing model", baseURL: "http://localhost:11434/v1", opts: []ClientOption{WithModelName("")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewOpenAICompatibleClient(tt.baseURL, tt.apiKey, tt.opts...)
+			assert.Error(t, err)
+			assert.Nil(t, client)
+		})
+	}
+
+	t.Run("Plain HTTP without a key is allowed", func(t *testing.T) {
+		client, err := NewOpenAICompatibleClient("http://gpu-box.lan:1234/v1", "", WithModelName("m"))
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+	})
+}
+
+func TestOpenAICompatibleClientCountTokensUnsupported(t *testing.T) {
+	client, err := NewOpenAICompatibleClient("http://localhost:11434/v1", "", WithModelName("m"))
+	require.NoError(t, err)
+
+	_, countErr := client.CountTokens(context.Background(), "prompt")
+	assert.Error(t, countErr)
+	assert.Contains(t, countErr.Error(), "OpenAI-compatible endpoint")
+}
@@ -22,19 +22,15 @@ const (
 	openRouterDefaultTitle = "failed to generate content"
 )
 
-type openRouterMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
 type openRouterChatRequest struct {
-	Model       string              `json:"model"`
-	Messages    []openRouterMessage `json:"messages"`
-	MaxTokens   int32               `json:"max_tokens,omitempty"`
-	Temperature *float32            `json:"temperature,omitempty"`
-	TopP        *float32            `json:"top_p,omitempty"`
-	TopK        *int32              `json:"top_k,omitempty"`
-	Stop        []string            `json:"stop,omitempty"`
+	Model       string        `json:"model"`
+	Models      []string      `json:"models,omitempty"`
+	Messages    []ChatMessage `json:"messages"`
+	MaxTokens   int32         `json:"max_tokens,omitempty"`
+	Temperature *float32      `json:"temperature,omitempty"`
+	TopP        *float32      `json:"top_p,omitempty"`
+	TopK        *int32        `json:"top_k,omitempty"`
+	Stop        []string      `json:"stop,omitempty"`
 }
 
 type openRouterError struct {
@@ -54,13 +50,24 @@ type openRouterChatResponse struct {
 	Error   *openRouterError   `json:"error"`
 }
 
+// chatCompletionsClient implements Client against an OpenAI-style
+// /chat/completions endpoint. OpenRouterClient and OpenAICompatibleClient
+// differ only in how they are constructed.
+type chatCompletionsClient struct {
+	httpClient   *http.Client
+	apiKey       string // empty for servers that need no auth
+	baseURL      string
+	model        string
+	options      *ClientOptions
+	provider     string      // display name used in error messages
+	codeBase     string      // error code prefix
+	extraHeaders http.Header // caller-supplied headers, reserved ones removed
+	fallbacks    []string    // OpenRouter "models" fallback list; nil elsewhere
+}
+
 // OpenRouterClient is a Client implementation that uses OpenRouter's chat API.
 type OpenRouterClient struct {
-	httpClient *http.Client
-	apiKey     string
-	baseURL    string
-	model      string
-	options    *ClientOptions
+	chatCompletionsClient
 }
 
 // NewOpenRouterClientFunc is a function type for creating OpenRouter clients.
@@ -100,20 +107,24 @@ func newOpenRouterClient(apiKey string, options ...ClientOption) (*OpenRouterCli
 		timeout = 60 * time.Second
 	}
 
-	return &OpenRouterClient{
-		httpClient: &http.Client{Timeout: timeout},
-		apiKey:     apiKey, // pragma: allowlist secret
-		baseURL:    openRouterBaseURL,
-		model:      opts.ModelName,
-		options:    &opts,
-	}, nil
+	return &OpenRouterClient{chatCompletionsClient{
+		httpClient:   &http.Client{Timeout: timeout},
+		apiKey:       apiKey, // pragma: allowlist secret
+		baseURL:      openRouterBaseURL,
+		model:        opts.ModelName,
+		options:      &opts,
+		provider:     "OpenRouter",
+		codeBase:     openRouterCodeBase,
+		extraHeaders: extraHTTPHeader(opts.ExtraHeaders, opts.log()),
+		fallbacks:    opts.ModelFallbacks,
+	}}, nil
 }
 
-// Generate sends the prompt to OpenRouter and returns the generated text.
-func (c *OpenRouterClient) Generate(ctx context.Context, prompt string) (string, error) {
+// Generate sends the prompt to the chat completions endpoint and returns the generated text.
+func (c *chatCompletionsClient) Generate(ctx context.Context, prompt string) (string, error) {
 	if c.httpClient == nil || c.model == "" {
-		return "", customerrors.NewValidationError("OpenRouter client is not properly initialized", nil).
-			WithCode(openRouterCodeBase + "-003")
+		return "", customerrors.NewValidationError(c.provider+" client is not properly initialized", nil).
+			WithCode(c.codeBase + "-003")
 	}
 
 	maxAttempts := c.options.MaxRetries + 1
@@ -122,35 +133,40 @@ func (c *OpenRouterClient) Generate(ctx context.Context, prompt string) (string,
 	}
 
 	var lastErr error
-	for attempt := 1; attempt <= maxAttempts; attempt++ {
+	attempt := 1
+	for ; ; attempt++ {
 		content, err := c.generateOnce(ctx, prompt)
 		if err == nil {
 			return content, nil
 		}
 		lastErr = err
 
-		if attempt < maxAttempts {
-			backoff := time.Duration(100*attempt*attempt) * time.Millisecond
-			if sleepErr := sleepWithContext(ctx, backoff); sleepErr != nil {
-				return "", sleepErr
-			}
+		// Each failure class has its own backoff; client errors are not retried
+		backoff, retryable := c.retryDelay(attempt, err)
+		if !retryable || attempt >= maxAttempts {
+			break
+		}
+		notifyRetry(c.options.OnRetry, attempt, err, backoff)
+		if sleepErr := sleepWithContext(ctx, backoff); sleepErr != nil {
+			return "", sleepErr
 		}
 	}
 
-	return "", customerrors.WrapAPIError(lastErr, fmt.Sprintf("%s after %d attempts", openRouterDefaultTitle, maxAttempts)).
-		WithCode(openRouterCodeBase + "-004")
+	return "", customerrors.WrapAPIError(lastErr, openRouterDefaultTitle+" "+attemptsSummary(attempt)).
+		WithCode(c.codeBase + "-004")
 }
 
-func (c *OpenRouterClient) generateOnce(ctx context.Context, prompt string) (string, error) {
+func (c *chatCompletionsClient) generateOnce(ctx context.Context, prompt string) (string, error) {
 	reqBody := openRouterChatRequest{
 		Model:    c.model,
+		Models:   c.fallbacks,
 		Messages: c.buildMessages(prompt),
 	}
 
 	if c.options.MaxOutputTokens > 0 {
 		reqBody.MaxTokens = c.options.MaxOutputTokens
 	}
-	if c.options.Temperature > 0 {
+	if c.options.Temperature > 0 || c.options.Deterministic {
 		temp := c.options.Temperature
 		reqBody.Temperature = &temp
 	}
@@ -168,8 +184,8 @@ func (c *OpenRouterClient) generateOnce(ctx context.Context, prompt string) (str
 
 	payload, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", customerrors.WrapAPIError(err, "failed to encode OpenRouter request").
-			WithCode(openRouterCodeBase + "-005")
+		return "", customerrors.WrapAPIError(err, fmt.Sprintf("failed to encode %s request", c.provider)).
+			WithCode(c.codeBase + "-005")
 	}
 
 	req, err := http.NewRequestWithContext(
@@ -179,17 +195,23 @@ func (c *OpenRouterClient) generateOnce(ctx context.Context, prompt string) (str
 		bytes.NewReader(payload),
 	)
 	if err != nil {
-		return "", customerrors.WrapAPIError(err, "failed to build OpenRouter request").
-			WithCode(openRouterCodeBase + "-006")
+		return "", customerrors.WrapAPIError(err, fmt.Sprintf("failed to build %s request", c.provider)).
+			WithCode(c.codeBase + "-006")
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.apiKey) // pragma: allowlist secret
+	// Extra headers go first so the client's own headers always win
+	for name, values := range c.extraHeaders {
+		req.Header[name] = values
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey) // pragma: allowlist secret
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", customerrors.WrapAPIError(err, "OpenRouter request failed").
-			WithCode(openRouterCodeBase + "-007")
+		return "", customerrors.WrapAPIError(err, c.provider+" request failed").
+			WithCode(c.codeBase + "-007")
 	}
 	defer func() {
 		_ = resp.Body.Close()
@@ -197,8 +219,8 @@ func (c *OpenRouterClient) generateOnce(ctx context.Context, prompt string) (str
 
 	bodyBytes, err := io.ReadAll(io.LimitReader(resp.Body, openRouterBodyLimit))
 	if err != nil {
-		return "", customerrors.WrapAPIError(err, "failed reading OpenRouter response").
-			WithCode(openRouterCodeBase + "-008")
+		return "", customerrors.WrapAPIError(err, fmt.Sprintf("failed reading %s response", c.provider)).
+			WithCode(c.codeBase + "-008")
 	}
 
 	var parsed openRouterChatResponse
@@ -216,46 +238,50 @@ func (c *OpenRouterClient) generateOnce(ctx context.Context, prompt string) (str
 		}
 
 		apiErr := customerrors.NewAPIError(
-			fmt.Sprintf("OpenRouter returned status %d: %s", resp.StatusCode, msg),
+			fmt.Sprintf("%s returned status %d: %s", c.provider, resp.StatusCode, msg),
 			nil,
-		).WithCode(openRouterCodeBase + "-009")
+		).WithCode(c.codeBase + "-009")
 
 		if resp.StatusCode == http.StatusTooManyRequests {
 			apiErr = apiErr.WithSuggestion("Rate limited by provider. Retry after backoff")
+			return "", &RateLimitError{
+				RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+				Err:        apiErr,
+			}
 		}
 
-		return "", apiErr
+		return "", &StatusError{StatusCode: resp.StatusCode, Err: apiErr}
 	}
 
 	if parsed.Error != nil && strings.TrimSpace(parsed.Error.Message) != "" {
 		return "", customerrors.NewAPIError(parsed.Error.Message, nil).
-			WithCode(openRouterCodeBase + "-010")
+			WithCode(c.codeBase + "-010")
 	}
 
 	if len(parsed.Choices) == 0 {
-		return "", customerrors.NewAPIError("OpenRouter response had no choices", nil).
-			WithCode(openRouterCodeBase + "-011")
+		return "", customerrors.NewAPIError(c.provider+" response had no choices", nil).
+			WithCode(c.codeBase + "-011")
 	}
 
 	content := extractOpenRouterContent(parsed.Choices[0].Message.Content)
 	if strings.TrimSpace(content) == "" {
-		return "", customerrors.NewAPIError("OpenRouter response content was empty", nil).
-			WithCode(openRouterCodeBase + "-012")
+		return "", customerrors.NewAPIError(c.provider+" response content was empty", nil).
+			WithCode(c.codeBase + "-012")
 	}
 
 	return content, nil
 }
 
-// CountTokens is not currently implemented for OpenRouter's generic client API.
-func (c *OpenRouterClient) CountTokens(ctx context.Context, prompt string) (int, error) {
+// CountTokens is not supported: the chat completions API has no token counting endpoint.
+func (c *chatCompletionsClient) CountTokens(ctx context.Context, prompt string) (int, error) {
 	_ = ctx
 	_ = prompt
-	return 0, customerrors.NewAPIError("token counting is not supported for OpenRouter client", nil).
-		WithCode(openRouterCodeBase + "-013")
+	return 0, customerrors.NewAPIError("token counting is not supported for "+c.provider+" client", nil).
+		WithCode(c.codeBase + "-013")
 }
 
 // GenerateStream uses non-streaming generation and returns one final chunk.
-func (c *OpenRouterClient) GenerateStream(ctx context.Context, prompt string) (<-chan StreamChunk, error) {
+func (c *chatCompletionsClient) GenerateStream(ctx context.Context, prompt string) (<-chan StreamChunk, error) {
 	ch := make(chan StreamChunk, 2)
 	go func() {
 		defer close(ch)
@@ -273,22 +299,19 @@ func (c *OpenRouterClient) GenerateStream(ctx context.Context, prompt string) (<
 	return ch, nil
 }
 
-// Close is a no-op because OpenRouterClient currently has no persistent resources.
-func (c *OpenRouterClient) Close() {}
+// Close is a no-op because the client currently has no persistent resources.
+func (c *chatCompletionsClient) Close() {}
 
-func (c *OpenRouterClient) buildMessages(prompt string) []openRouterMessage {
-	messages := make([]openRouterMessage, 0, 2)
-	if strings.TrimSpace(c.options.SystemInstructions) != "" {
-		messages = append(messages, openRouterMessage{
-			Role:    "system",
-			Content: c.options.SystemInstructions,
-		})
+// buildMessages shapes the conversation for prompt with the configured
+// MessageShaper, falling back to DefaultMessageShaper.
+func (c *chatCompletionsClient) buildMessages(prompt string) []ChatMessage {
+	if c.options.MessageShaper != nil {
+		if messages := c.options.MessageShaper(c.options.SystemInstructions, prompt); len(messages) > 0 {
+			return messages
+		}
+		c.options.log().WithField("provider", c.provider).Warn("Message shaper returned no messages; using the default shape")
 	}
-	messages = append(messages, openRouterMessage{
-		Role:    "user",
-		Content: prompt,
-	})
-	return messages
+	return DefaultMessageShaper(c.options.SystemInstructions, prompt)
 }
 
 func (r *openRouterChatResponse) errorMessage() string {
@@ -84,6 +84,7 @@ func TestOpenRouterClientGenerateSuccessArrayContent(t *testing.T) {
 }
 
 func TestOpenRouterClientGenerateHTTPError(t *testing.T) {
+	useFakeClock(t)
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusTooManyRequests)
 		_ = json.NewEncoder(w).Encode(map[string]any{
@@ -196,3 +197,80 @@ func TestOpenRouterClientRespectsTimeout(t *testing.T) {
 	_, genErr := client.Generate(ctx, "test prompt")
 	assert.Error(t, genErr)
 }
+
+func TestOpenRouterClientDeterministicSendsZeroTemperature(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{
+				{"message": map[string]any{"content": "ok"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	clientIface, err := NewOpenRouterClient(
+		"test-key",
+		WithModelName("x-ai/grok-4.1-fast"),
+		WithDeterministic(),
+	)
+	assert.NoError(t, err)
+
+	client := clientIface.(*OpenRouterClient)
+	client.baseURL = server.URL
+
+	_, genErr := client.Generate(context.Background(), "test prompt")
+	assert.NoError(t, genErr)
+
+	temperature, present := body["temperature"]
+	assert.True(t, present, "temperature 0 must be sent explicitly, not omitted")
+	assert.Equal(t, float64(0), temperature)
+	assert.Equal(t, float64(1), body["top_k"])
+}
+
+func TestOpenRouterClientModelFallbacks(t *testing.T) {
+	tests := []struct {
+		name      string
+		fallbacks []string
+	}{
+		{name: "sent when configured", fallbacks: []string{"openai/gpt-4o-mini", "anthropic/claude-3.5-haiku"}},
+		{name: "omitted otherwise"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"choices": []map[string]any{
+						{"message": map[string]any{"content": "ok"}},
+					},
+				})
+			}))
+			defer server.Close()
+
+			clientIface, err := NewOpenRouterClient(
+				"test-key",
+				WithModelName("x-ai/grok-4.1-fast"),
+				WithModelFallbacks(tt.fallbacks),
+			)
+			assert.NoError(t, err)
+
+			client := clientIface.(*OpenRouterClient)
+			client.baseURL = server.URL
+
+			_, genErr := client.Generate(context.Background(), "test prompt")
+			assert.NoError(t, genErr)
+
+			assert.Equal(t, "x-ai/grok-4.1-fast", body["model"])
+			models, present := body["models"]
+			if tt.fallbacks == nil {
+				assert.False(t, present, "models must be omitted when no fallbacks are configured")
+				return
+			}
+			assert.Equal(t, []any{"openai/gpt-4o-mini", "anthropic/claude-3.5-haiku"}, models)
+		})
+	}
+}
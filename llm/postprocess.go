@@ -0,0 +1,110 @@
+// Package llm provides abstractions and implementations for interacting with
+// Large Language Model APIs in the glance application.
+package llm
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PostProcessor transforms a generated summary before it is returned for writing.
+// An error fails the directory like a generation error.
+type PostProcessor func(summary string) (string, error)
+
+// Names of the built-in post-processors, as accepted by BuiltinPostProcessor.
+const (
+	PostProcessStripPreamble   = "strip-preamble"
+	PostProcessNormalizeFences = "normalize-fences"
+)
+
+// builtinPostProcessors maps names to the built-in post-processors.
+var builtinPostProcessors = map[string]PostProcessor{
+	PostProcessStripPreamble:   StripPreamble,
+	PostProcessNormalizeFences: NormalizeFenceLanguages,
+}
+
+// BuiltinPostProcessor returns the built-in post-processor with the given name.
+func BuiltinPostProcessor(name string) (PostProcessor, bool) {
+	p, ok := builtinPostProcessors[name]
+	return p, ok
+}
+
+// BuiltinPostProcessorNames returns the names of the built-in post-processors, sorted.
+func BuiltinPostProcessorNames() []string {
+	names := make([]string, 0, len(builtinPostProcessors))
+	for name := range builtinPostProcessors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// preamblePattern matches the conversational lead-in models sometimes put
+// before the requested output, e.g. "Here is a summary of the directory:".
+var preamblePattern = regexp.MustCompile(`(?i)^(here(?:'s| is| are)|sure|certainly|of course|okay|absolutely)\b.*[:.!]$`)
+
+// StripPreamble removes conversational lead-in lines, and the blank lines after
+// them, from the start of summary. Everything from the first other line on is
+// kept unchanged.
+func StripPreamble(summary string) (string, error) {
+	lines := strings.Split(summary, "\n")
+	start := 0
+	for start < len(lines) {
+		line := strings.TrimSpace(lines[start])
+		if line != "" && !preamblePattern.MatchString(line) {
+			break
+		}
+		start++
+	}
+	if start == len(lines) {
+		// Nothing but preamble; leave it for the caller to judge
+		return summary, nil
+	}
+	return strings.Join(lines[start:], "\n"), nil
+}
+
+// fenceLanguageAliases maps common alternative names of fenced code block
+// languages to the name NormalizeFenceLanguages uses.
+var fenceLanguageAliases = map[string]string{
+	"golang": "go",
+	"js":     "javascript",
+	"ts":     "typescript",
+	"py":     "python",
+	"sh":     "bash",
+	"shell":  "bash",
+	"zsh":    "bash",
+	"yml":    "yaml",
+	"md":     "markdown",
+}
+
+// NormalizeFenceLanguages rewrites the language of each fenced code block
+// opening line to its lowercase canonical name, e.g. "``` Golang" becomes
+// "```go". Closing fences and block contents are left alone.
+func NormalizeFenceLanguages(summary string) (string, error) {
+	lines := strings.Split(summary, "\n")
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if !strings.HasPrefix(trimmed, "```") {
+			continue
+		}
+		if inFence {
+			inFence = false
+			continue
+		}
+		inFence = true
+
+		indent := line[:len(line)-len(trimmed)]
+		info := strings.Fields(strings.TrimPrefix(trimmed, "```"))
+		if len(info) == 0 {
+			continue
+		}
+		lang := strings.ToLower(info[0])
+		if alias, ok := fenceLanguageAliases[lang]; ok {
+			lang = alias
+		}
+		lines[i] = indent + "```" + strings.Join(append([]string{lang}, info[1:]...), " ")
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,139 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/cache"
+	"glance/internal/mocks"
+)
+
+func TestStripPreamble(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Preamble and blank lines removed", "Here is a summary of the directory:\n\n## Purpose\nParses input.\n", "## Purpose\nParses input.\n"},
+		{"Several lead-in lines", "Sure!\nHere's the summary.\n## Purpose\n", "## Purpose\n"},
+		{"No preamble is unchanged", "## Purpose\nHere is how it works:\n", "## Purpose\nHere is how it works:\n"},
+		{"Ordinary first sentence is kept", "This package parses input.\n", "This package parses input.\n"},
+		{"Only preamble is left alone", "Sure, here you go:\n", "Sure, here you go:\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := StripPreamble(tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestNormalizeFenceLanguages(t *testing.T) {
+	input := strings.Join([]string{
+		"## Example",
+		"``` Golang",
+		"func main() {}",
+		"```",
+		"  ```YML title=config",
+		"key: value",
+		"  ```",
+		"```",
+		"plain",
+		"```",
+		"```rust",
+		"```",
+	}, "\n")
+	expected := strings.Join([]string{
+		"## Example",
+		"```go",
+		"func main() {}",
+		"```",
+		"  ```yaml title=config",
+		"key: value",
+		"  ```",
+		"```",
+		"plain",
+		"```",
+		"```rust",
+		"```",
+	}, "\n")
+
+	got, err := NormalizeFenceLanguages(input)
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
+}
+
+func TestBuiltinPostProcessor(t *testing.T) {
+	for _, name := range BuiltinPostProcessorNames() {
+		p, ok := BuiltinPostProcessor(name)
+		assert.True(t, ok, name)
+		assert.NotNil(t, p, name)
+	}
+	_, ok := BuiltinPostProcessor("unknown")
+	assert.False(t, ok)
+}
+
+func TestServiceOutputPostProcessors(t *testing.T) {
+	newService := func(t *testing.T, options ...func(*ServiceConfig)) (*Service, *mocks.LLMClient) {
+		mockClient := new(mocks.LLMClient)
+		mockClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil)
+		mockClient.On("Generate", mock.Anything, mock.Anything).Return("Here is the summary:\n# Summary\n", nil)
+		service, err := NewService(NewMockClientAdapter(mockClient), append([]func(*ServiceConfig){WithPromptTemplate("{{.Directory}}")}, options...)...)
+		require.NoError(t, err)
+		return service, mockClient
+	}
+
+	t.Run("Post-processors run in order", func(t *testing.T) {
+		service, _ := newService(t,
+			WithOutputPostProcessor(StripPreamble),
+			WithOutputPostProcessor(func(s string) (string, error) { return s + "<!-- reviewed -->\n", nil }),
+		)
+
+		got, err := service.GenerateGlanceMarkdown(context.Background(), "pkg", nil, "")
+		require.NoError(t, err)
+		assert.Equal(t, "# Summary\n<!-- reviewed -->\n", got)
+	})
+
+	t.Run("Failing post-processor fails the directory", func(t *testing.T) {
+		boom := errors.New("boom")
+		service, _ := newService(t, WithOutputPostProcessor(func(string) (string, error) { return "", boom }))
+
+		_, err := service.GenerateGlanceMarkdown(context.Background(), "pkg", nil, "")
+		assert.ErrorIs(t, err, boom)
+	})
+
+	t.Run("Blank post-processed output is rejected", func(t *testing.T) {
+		service, _ := newService(t, WithOutputPostProcessor(func(string) (string, error) { return " \n", nil }))
+
+		_, err := service.GenerateGlanceMarkdown(context.Background(), "pkg", nil, "")
+		assert.ErrorIs(t, err, ErrEmptyResponse)
+	})
+
+	t.Run("Cached summaries are stored unprocessed", func(t *testing.T) {
+		store, err := cache.NewFileStore(filepath.Join(t.TempDir(), "cache"))
+		require.NoError(t, err)
+		service, mockClient := newService(t, WithSummaryCache(store), WithOutputPostProcessor(StripPreamble))
+
+		first, err := service.GenerateGlanceMarkdown(context.Background(), "pkg", nil, "")
+		require.NoError(t, err)
+		second, err := service.GenerateGlanceMarkdown(context.Background(), "pkg", nil, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, "# Summary\n", first)
+		assert.Equal(t, first, second, "a cache hit is post-processed too")
+		mockClient.AssertNumberOfCalls(t, "Generate", 1)
+
+		cached, ok := store.Get(service.summaryCacheKey("pkg"))
+		require.True(t, ok)
+		assert.Equal(t, "Here is the summary:\n# Summary\n", cached)
+	})
+}
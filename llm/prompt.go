@@ -5,9 +5,12 @@ package llm
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 	"text/template"
+
+	"glance/filesystem"
 )
 
 // PromptData holds the content used to generate prompts for LLM requests.
@@ -22,6 +25,135 @@ type PromptData struct {
 
 	// FileContents contains the formatted contents of files in the directory
 	FileContents string
+
+	// Files holds the same files as FileContents, in the same order, as
+	// structured entries for templates that lay files out themselves or use
+	// their roles
+	Files []PromptFile
+
+	// GitHistory contains recent one-line commit summaries touching the directory.
+	// Empty unless git metadata was requested.
+	GitHistory string
+
+	// ImageDescriptions contains vision-model descriptions of the directory's
+	// images. Empty unless image description was requested.
+	ImageDescriptions string
+
+	// RepoContext contains repository-level files, such as the top-level README,
+	// shared by every directory's prompt. Empty unless context files were requested.
+	RepoContext string
+
+	// Siblings names the directory's peers, the other scanned directories
+	// under the same parent. Empty unless sibling names were requested.
+	Siblings []string
+
+	// PreviousSummary is the directory's existing summary, and Diff the changes
+	// to its files since then. Both are empty unless the summary is being
+	// updated incrementally (see Service.UpdateGlanceMarkdown).
+	PreviousSummary string
+	Diff            string
+
+	// Vars holds user-supplied template variables (--prompt-var), referenced in
+	// a custom template as {{.Vars.key}}
+	Vars map[string]string
+
+	// strictVars makes a template reference to a missing Vars key an error
+	// instead of rendering it empty
+	strictVars bool
+
+	// template, when set, replaces the service's prompt template for this prompt
+	template string
+
+	// rewrite, when set, is applied to the fully rendered prompt
+	rewrite func(string) string
+
+	// stream, when set, receives the generated text as it arrives
+	stream io.Writer
+}
+
+// PromptFile is one file in a prompt.
+type PromptFile struct {
+	// Name is the file's path relative to the directory
+	Name string
+
+	// Role is the file's heuristic role (see filesystem.ClassifyFileRole):
+	// "entrypoint", "config", "test", "docs", or "" for other files
+	Role string
+
+	// Content is the file's text
+	Content string
+}
+
+// RoleFiles returns the files with a known role, in prompt order. Templates
+// can use it to point the model at entrypoints and configuration:
+// {{range .RoleFiles}}{{.Name}}: {{.Role}}{{end}}.
+func (d *PromptData) RoleFiles() []PromptFile {
+	var files []PromptFile
+	for _, file := range d.Files {
+		if file.Role != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// PromptDataOption customizes PromptData beyond the core directory inputs.
+type PromptDataOption func(*PromptData)
+
+// WithGitHistory adds recent commit summaries to the prompt data.
+func WithGitHistory(history string) PromptDataOption {
+	return func(d *PromptData) {
+		d.GitHistory = history
+	}
+}
+
+// WithImageDescriptions adds image descriptions, as formatted by
+// FormatImageDescriptions, to the prompt data.
+func WithImageDescriptions(descriptions string) PromptDataOption {
+	return func(d *PromptData) {
+		d.ImageDescriptions = descriptions
+	}
+}
+
+// WithRepoContext adds repository-level context files, as formatted by
+// FormatFileContents, to the prompt data.
+func WithRepoContext(context string) PromptDataOption {
+	return func(d *PromptData) {
+		d.RepoContext = context
+	}
+}
+
+// WithSiblings adds the names of the directory's sibling directories to the
+// prompt data.
+func WithSiblings(siblings []string) PromptDataOption {
+	return func(d *PromptData) {
+		d.Siblings = siblings
+	}
+}
+
+// WithTemplate renders this prompt with the given template instead of the
+// service's configured one, e.g. for a directory with its own prompt file.
+func WithTemplate(template string) PromptDataOption {
+	return func(d *PromptData) {
+		d.template = template
+	}
+}
+
+// WithPromptRewrite applies rewrite to the rendered prompt before it is sent,
+// e.g. to redact machine-specific paths from file contents and summaries.
+func WithPromptRewrite(rewrite func(string) string) PromptDataOption {
+	return func(d *PromptData) {
+		d.rewrite = rewrite
+	}
+}
+
+// WithStreamWriter writes the summary to w as it is generated, for clients that
+// support streaming; otherwise the whole summary is written once it is ready.
+// The text written is the raw model output, before post-processing.
+func WithStreamWriter(w io.Writer) PromptDataOption {
+	return func(d *PromptData) {
+		d.stream = w
+	}
 }
 
 // DefaultTemplate returns the default prompt template used for generating directory summaries.
@@ -61,10 +193,41 @@ subdirectory summaries:
 
 local file contents:
 {{.FileContents}}
+{{- with .RoleFiles}}
+
+file roles (guessed from file names):
+{{- range .}}
+- {{.Name}}: {{.Role}}
+{{- end}}
+{{- end}}
+{{- if .ImageDescriptions}}
+
+image descriptions (generated by a vision model from the directory's images):
+{{.ImageDescriptions}}
+{{- end}}
+{{- if .GitHistory}}
+
+recent git history for this directory:
+{{.GitHistory}}
+{{- end}}
+{{- with .Siblings}}
+
+sibling directories (peers under the same parent; for context only):
+{{- range .}}
+- {{.}}
+{{- end}}
+{{- end}}
+{{- if .RepoContext}}
+
+repository context (background only; summarize this directory, not the repository):
+{{.RepoContext}}
+{{- end}}
 `
 }
 
 // GeneratePrompt generates a prompt by filling the template with the provided data.
+// A reference to a Vars key that was not supplied renders empty, or fails when
+// the data was built by a service with a strict template.
 //
 // Parameters:
 //   - data: The PromptData to use for template variables
@@ -75,7 +238,11 @@ local file contents:
 //   - An error if template parsing or execution fails
 func GeneratePrompt(data *PromptData, templateStr string) (string, error) {
 	// Parse the template
-	tmpl, err := template.New("prompt").Parse(templateStr)
+	missingKey := "missingkey=zero"
+	if data != nil && data.strictVars {
+		missingKey = "missingkey=error"
+	}
+	tmpl, err := template.New("prompt").Option(missingKey).Parse(templateStr)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse prompt template: %w", err)
 	}
@@ -104,10 +271,26 @@ func FormatFileContents(fileMap map[string]string) string {
 	}
 	sort.Strings(keys)
 
+	return FormatFileContentsInOrder(fileMap, keys)
+}
+
+// FormatFileContentsInOrder formats the files in fileMap in the order given by keys,
+// using the same layout as FormatFileContents. Keys missing from fileMap are skipped.
+//
+// Parameters:
+//   - fileMap: A map of filenames to their content
+//   - keys: The filenames in the order they should appear
+//
+// Returns:
+//   - A formatted string containing the selected file contents
+func FormatFileContentsInOrder(fileMap map[string]string, keys []string) string {
 	var builder strings.Builder
 
 	for _, filename := range keys {
-		content := fileMap[filename]
+		content, ok := fileMap[filename]
+		if !ok {
+			continue
+		}
 		builder.WriteString(fmt.Sprintf("=== file: %s ===\n%s\n\n", filename, content))
 	}
 
@@ -115,7 +298,8 @@ func FormatFileContents(fileMap map[string]string) string {
 }
 
 // BuildPromptData creates a PromptData structure with the provided information.
-// It formats the file contents using FormatFileContents.
+// It formats the file contents using FormatFileContents, and lists the same
+// files, with their roles, in Files.
 //
 // Parameters:
 //   - dir: The directory path
@@ -129,5 +313,28 @@ func BuildPromptData(dir string, subGlances string, fileMap map[string]string) *
 		Directory:    dir,
 		SubGlances:   subGlances,
 		FileContents: FormatFileContents(fileMap),
+		Files:        BuildPromptFiles(fileMap, sortedFileNames(fileMap)),
+	}
+}
+
+// BuildPromptFiles lists the files in fileMap in the order given by keys, each
+// with its role from filesystem.ClassifyFileRole. Keys missing from fileMap are
+// skipped, as in FormatFileContentsInOrder.
+//
+// Parameters:
+//   - fileMap: A map of filenames to their content
+//   - keys: The filenames in the order they should appear
+//
+// Returns:
+//   - The files as PromptFile entries
+func BuildPromptFiles(fileMap map[string]string, keys []string) []PromptFile {
+	files := make([]PromptFile, 0, len(keys))
+	for _, name := range keys {
+		content, ok := fileMap[name]
+		if !ok {
+			continue
+		}
+		files = append(files, PromptFile{Name: name, Role: filesystem.ClassifyFileRole(name), Content: content})
 	}
+	return files
 }
@@ -35,6 +35,66 @@ func TestDefaultTemplate(t *testing.T) {
 	assert.Contains(t, template, "400 words")
 }
 
+func TestDefaultTemplateGitHistory(t *testing.T) {
+	t.Run("Omitted when empty", func(t *testing.T) {
+		prompt, err := GeneratePrompt(BuildPromptData("dir", "", nil), DefaultTemplate())
+		assert.NoError(t, err)
+		assert.NotContains(t, prompt, "recent git history")
+	})
+
+	t.Run("Rendered when present", func(t *testing.T) {
+		data := BuildPromptData("dir", "", nil)
+		WithGitHistory("abc1234 add parser")(data)
+
+		prompt, err := GeneratePrompt(data, DefaultTemplate())
+		assert.NoError(t, err)
+		assert.Contains(t, prompt, "recent git history for this directory:\nabc1234 add parser")
+	})
+
+	t.Run("Custom templates without the variable are unaffected", func(t *testing.T) {
+		data := BuildPromptData("dir", "", nil)
+		WithGitHistory("abc1234 add parser")(data)
+
+		prompt, err := GeneratePrompt(data, "dir: {{.Directory}}")
+		assert.NoError(t, err)
+		assert.Equal(t, "dir: dir", prompt)
+	})
+}
+
+func TestDefaultTemplateRepoContext(t *testing.T) {
+	t.Run("Omitted when empty", func(t *testing.T) {
+		prompt, err := GeneratePrompt(BuildPromptData("dir", "", nil), DefaultTemplate())
+		assert.NoError(t, err)
+		assert.NotContains(t, prompt, "repository context")
+	})
+
+	t.Run("Rendered when present", func(t *testing.T) {
+		data := BuildPromptData("dir", "", nil)
+		WithRepoContext("=== file: README.md ===\n# Project\n\n")(data)
+
+		prompt, err := GeneratePrompt(data, DefaultTemplate())
+		assert.NoError(t, err)
+		assert.Contains(t, prompt, "repository context (background only; summarize this directory, not the repository):\n=== file: README.md ===\n# Project")
+	})
+}
+
+func TestDefaultTemplateSiblings(t *testing.T) {
+	t.Run("Omitted when empty", func(t *testing.T) {
+		prompt, err := GeneratePrompt(BuildPromptData("dir", "", nil), DefaultTemplate())
+		assert.NoError(t, err)
+		assert.NotContains(t, prompt, "sibling directories")
+	})
+
+	t.Run("Rendered when present", func(t *testing.T) {
+		data := BuildPromptData("dir", "", nil)
+		WithSiblings([]string{"api", "web"})(data)
+
+		prompt, err := GeneratePrompt(data, DefaultTemplate())
+		assert.NoError(t, err)
+		assert.Contains(t, prompt, "sibling directories (peers under the same parent; for context only):\n- api\n- web")
+	})
+}
+
 func TestGeneratePrompt(t *testing.T) {
 	// Test data
 	data := &PromptData{
@@ -252,3 +312,55 @@ func TestBuildPromptData(t *testing.T) {
 		assert.True(t, strings.Count(data.FileContents, "Large content line") > 100)
 	})
 }
+
+func TestPromptFiles(t *testing.T) {
+	fileMap := map[string]string{
+		"main.go":      "package main",
+		"handler.go":   "package main // handler",
+		"main_test.go": "package main // test",
+		"config.yaml":  "port: 80",
+	}
+
+	t.Run("Files mirror FileContents with roles", func(t *testing.T) {
+		data := BuildPromptData("dir", "", fileMap)
+		assert.Equal(t, []PromptFile{
+			{Name: "config.yaml", Role: "config", Content: "port: 80"},
+			{Name: "handler.go", Role: "", Content: "package main // handler"},
+			{Name: "main.go", Role: "entrypoint", Content: "package main"},
+			{Name: "main_test.go", Role: "test", Content: "package main // test"},
+		}, data.Files)
+		assert.Equal(t, []string{"config.yaml", "main.go", "main_test.go"}, promptFileNames(data.RoleFiles()))
+	})
+
+	t.Run("Templates can range over Files", func(t *testing.T) {
+		prompt, err := GeneratePrompt(BuildPromptData("dir", "", fileMap),
+			"{{range .Files}}[{{.Name}}|{{.Role}}]{{end}}")
+		assert.NoError(t, err)
+		assert.Equal(t, "[config.yaml|config][handler.go|][main.go|entrypoint][main_test.go|test]", prompt)
+	})
+
+	t.Run("Default template lists roles only when some are known", func(t *testing.T) {
+		prompt, err := GeneratePrompt(BuildPromptData("dir", "", fileMap), DefaultTemplate())
+		assert.NoError(t, err)
+		assert.Contains(t, prompt, "file roles (guessed from file names):\n- config.yaml: config\n- main.go: entrypoint\n- main_test.go: test")
+		assert.NotContains(t, prompt, "- handler.go:")
+
+		prompt, err = GeneratePrompt(BuildPromptData("dir", "", map[string]string{"handler.go": "x"}), DefaultTemplate())
+		assert.NoError(t, err)
+		assert.NotContains(t, prompt, "file roles (guessed")
+	})
+
+	t.Run("BuildPromptFiles follows the given order", func(t *testing.T) {
+		files := BuildPromptFiles(fileMap, []string{"main.go", "missing.go", "config.yaml"})
+		assert.Equal(t, []string{"main.go", "config.yaml"}, promptFileNames(files))
+	})
+}
+
+// promptFileNames returns the names of files, in order.
+func promptFileNames(files []PromptFile) []string {
+	names := make([]string, len(files))
+	for i, file := range files {
+		names[i] = file.Name
+	}
+	return names
+}
@@ -0,0 +1,59 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureGenerationConfig returns a Gemini client backed by a local server that
+// records the generationConfig of the last generateContent request.
+func captureGenerationConfig(t *testing.T, opts ...ClientOption) (*GeminiClient, *map[string]any) {
+	t.Helper()
+
+	var generationConfig map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			GenerationConfig map[string]any `json:"generationConfig"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		generationConfig = body.GenerationConfig
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []map[string]any{{
+				"content":      map[string]any{"role": "model", "parts": []map[string]any{{"text": "summary"}}},
+				"finishReason": "STOP",
+			}},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := newGeminiClient("test-key", append([]ClientOption{WithBaseURL(server.URL)}, opts...)...)
+	require.NoError(t, err)
+	return client, &generationConfig
+}
+
+func TestGeminiClientResponseMIMEType(t *testing.T) {
+	t.Run("sent when configured", func(t *testing.T) {
+		client, generationConfig := captureGenerationConfig(t, WithResponseMIMEType("text/plain"))
+
+		_, err := client.Generate(context.Background(), "prompt")
+		require.NoError(t, err)
+		assert.Equal(t, "text/plain", (*generationConfig)["responseMimeType"])
+	})
+
+	t.Run("unset by default", func(t *testing.T) {
+		client, generationConfig := captureGenerationConfig(t)
+
+		_, err := client.Generate(context.Background(), "prompt")
+		require.NoError(t, err)
+		require.NotNil(t, *generationConfig)
+		assert.NotContains(t, *generationConfig, "responseMimeType")
+	})
+}
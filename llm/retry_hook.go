@@ -0,0 +1,35 @@
+package llm
+
+import "time"
+
+// RetryFunc observes a retry. attempt is the number of the attempt that just
+// failed, starting at 1 and counted across the whole call; err is its error;
+// nextBackoff is how long the client waits before the next attempt, zero when
+// it moves straight on (for example to a fallback tier). The callback runs on
+// the calling goroutine and should return quickly.
+type RetryFunc func(attempt int, err error, nextBackoff time.Duration)
+
+// WithOnRetry registers fn to be called before each retry a client makes, so
+// embedders can count or log retries without parsing log output. Clients that
+// never retry, such as the Gemini client's Generate, never call it.
+func WithOnRetry(fn RetryFunc) ClientOption {
+	return func(o *ClientOptions) {
+		o.OnRetry = fn
+	}
+}
+
+// WithFallbackOnRetry registers fn to be called before each retry a
+// FallbackClient makes within a tier, for Generate and CountTokens alike, and
+// before Generate fails over to the next tier.
+func WithFallbackOnRetry(fn RetryFunc) FallbackOption {
+	return func(c *FallbackClient) {
+		c.onRetry = fn
+	}
+}
+
+// notifyRetry calls fn, if set.
+func notifyRetry(fn RetryFunc, attempt int, err error, nextBackoff time.Duration) {
+	if fn != nil {
+		fn(attempt, err, nextBackoff)
+	}
+}
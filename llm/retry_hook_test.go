@@ -0,0 +1,117 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"glance/internal/mocks"
+)
+
+// retryCall records one RetryFunc invocation.
+type retryCall struct {
+	attempt     int
+	err         error
+	nextBackoff time.Duration
+}
+
+// recordRetries returns a RetryFunc appending to the returned slice.
+func recordRetries() (RetryFunc, *[]retryCall) {
+	var calls []retryCall
+	return func(attempt int, err error, nextBackoff time.Duration) {
+		calls = append(calls, retryCall{attempt, err, nextBackoff})
+	}, &calls
+}
+
+func TestFallbackClientOnRetry(t *testing.T) {
+	useFakeClock(t)
+	ctx := context.Background()
+	errOne, errTwo, errThree := errors.New("first"), errors.New("second"), errors.New("third")
+
+	primaryMock := new(mocks.LLMClient)
+	primaryMock.On("Generate", ctx, "prompt").Return("", errOne).Once()
+	primaryMock.On("Generate", ctx, "prompt").Return("", errTwo).Once()
+	secondaryMock := new(mocks.LLMClient)
+	secondaryMock.On("Generate", ctx, "prompt").Return("", errThree).Once()
+	secondaryMock.On("Generate", ctx, "prompt").Return("ok", nil).Once()
+
+	onRetry, calls := recordRetries()
+	client, err := NewFallbackClientWithBackoff([]FallbackTier{
+		{Name: "primary", Client: NewMockClientAdapter(primaryMock)},
+		{Name: "secondary", Client: NewMockClientAdapter(secondaryMock)},
+	}, 1, 10*time.Millisecond, time.Second, WithFallbackOnRetry(onRetry))
+	require.NoError(t, err)
+
+	result, err := client.Generate(ctx, "prompt")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", result)
+
+	require.Len(t, *calls, 3)
+	assert.Equal(t, 1, (*calls)[0].attempt)
+	assert.ErrorIs(t, (*calls)[0].err, errOne)
+	assert.Greater(t, (*calls)[0].nextBackoff, time.Duration(0), "retrying the tier waits")
+	assert.Equal(t, 2, (*calls)[1].attempt)
+	assert.ErrorIs(t, (*calls)[1].err, errTwo)
+	assert.Equal(t, time.Duration(0), (*calls)[1].nextBackoff, "failing over does not wait")
+	assert.Equal(t, 3, (*calls)[2].attempt)
+	assert.ErrorIs(t, (*calls)[2].err, errThree)
+	assert.Greater(t, (*calls)[2].nextBackoff, time.Duration(0))
+}
+
+func TestFallbackClientOnRetryNotCalledOnSuccess(t *testing.T) {
+	mockClient := new(mocks.LLMClient)
+	mockClient.On("Generate", context.Background(), "prompt").Return("ok", nil).Once()
+
+	onRetry, calls := recordRetries()
+	client, err := NewFallbackClient([]FallbackTier{{Name: "only", Client: NewMockClientAdapter(mockClient)}}, 2,
+		WithFallbackOnRetry(onRetry))
+	require.NoError(t, err)
+
+	_, err = client.Generate(context.Background(), "prompt")
+	require.NoError(t, err)
+	assert.Empty(t, *calls)
+}
+
+func TestOpenRouterClientOnRetry(t *testing.T) {
+	fake := useFakeClock(t)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"message": "unavailable"}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]any{"content": "ok"}}},
+		})
+	}))
+	defer server.Close()
+
+	onRetry, calls := recordRetries()
+	client, err := newOpenRouterClient("test-key", WithMaxRetries(2), WithOnRetry(onRetry))
+	require.NoError(t, err)
+	client.baseURL = server.URL
+
+	result, err := client.Generate(context.Background(), "prompt")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", result)
+
+	require.Len(t, *calls, 2)
+	for i, call := range *calls {
+		assert.Equal(t, i+1, call.attempt)
+		assert.ErrorContains(t, call.err, "500")
+	}
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		[]time.Duration{(*calls)[0].nextBackoff, (*calls)[1].nextBackoff}, "server errors use the doubling server-error backoff")
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, fake.Sleeps(),
+		"the reported backoff is the one waited")
+}
@@ -0,0 +1,78 @@
+package llm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	customerrors "glance/errors"
+)
+
+// safetyCategories maps the short names accepted on the command line to
+// harm categories. The full HARM_CATEGORY_* constants are accepted as well.
+var safetyCategories = map[string]string{
+	"harassment":        HarmCategoryHarassment,
+	"hate-speech":       HarmCategoryHateSpeech,
+	"dangerous-content": HarmCategoryDangerousContent,
+	"sexually-explicit": HarmCategorySexuallyExplicit,
+	"derogatory":        HarmCategoryDerogatory,
+}
+
+// safetyThresholds maps the short names accepted on the command line to
+// blocking thresholds. The full HARM_BLOCK_* constants are accepted as well.
+var safetyThresholds = map[string]string{
+	"none":        HarmBlockNone,
+	"low":         HarmBlockLowAndAbove,
+	"medium":      HarmBlockMediumAndAbove,
+	"high":        HarmBlockHighAndAbove,
+	"unspecified": HarmBlockUnspecified,
+}
+
+// ParseSafetySetting converts user-supplied category and threshold names into
+// a SafetySetting. Names are case-insensitive and may be short ("hate-speech",
+// "medium") or the full constants ("HARM_CATEGORY_HATE_SPEECH",
+// "HARM_BLOCK_MEDIUM_AND_ABOVE"); underscores and hyphens are interchangeable.
+func ParseSafetySetting(category, threshold string) (SafetySetting, error) {
+	cat, ok := lookupSafetyName(category, "HARM_CATEGORY_", safetyCategories)
+	if !ok {
+		return SafetySetting{}, customerrors.NewValidationError(fmt.Sprintf("unknown safety category %q", category), nil).
+			WithCode("GENAI-030").
+			WithSuggestion("Use one of: " + strings.Join(sortedKeys(safetyCategories), ", "))
+	}
+	thr, ok := lookupSafetyName(threshold, "HARM_BLOCK_", safetyThresholds)
+	if !ok {
+		return SafetySetting{}, customerrors.NewValidationError(fmt.Sprintf("unknown safety threshold %q", threshold), nil).
+			WithCode("GENAI-031").
+			WithSuggestion("Use one of: " + strings.Join(sortedKeys(safetyThresholds), ", "))
+	}
+	return SafetySetting{Category: cat, Threshold: thr}, nil
+}
+
+// lookupSafetyName resolves name against the short names in names, or against
+// their full constant values (which all start with prefix).
+func lookupSafetyName(name, prefix string, names map[string]string) (string, bool) {
+	short := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "_", "-"))
+	if value, ok := names[short]; ok {
+		return value, true
+	}
+	full := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(name), "-", "_"))
+	if !strings.HasPrefix(full, prefix) {
+		return "", false
+	}
+	for _, value := range names {
+		if value == full {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// sortedKeys returns the keys of m in alphabetical order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
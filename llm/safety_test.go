@@ -0,0 +1,40 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	customerrors "glance/errors"
+)
+
+func TestParseSafetySetting(t *testing.T) {
+	tests := []struct {
+		category, threshold string
+		want                SafetySetting
+	}{
+		{"dangerous-content", "none", SafetySetting{HarmCategoryDangerousContent, HarmBlockNone}},
+		{"Hate_Speech", "HIGH", SafetySetting{HarmCategoryHateSpeech, HarmBlockHighAndAbove}},
+		{"HARM_CATEGORY_HARASSMENT", "HARM_BLOCK_LOW_AND_ABOVE", SafetySetting{HarmCategoryHarassment, HarmBlockLowAndAbove}},
+		{" sexually-explicit ", "medium", SafetySetting{HarmCategorySexuallyExplicit, HarmBlockMediumAndAbove}},
+	}
+	for _, tt := range tests {
+		got, err := ParseSafetySetting(tt.category, tt.threshold)
+		require.NoError(t, err, tt.category)
+		assert.Equal(t, tt.want, got)
+	}
+
+	_, err := ParseSafetySetting("violence", "none")
+	require.Error(t, err)
+	var glanceErr customerrors.GlanceError
+	require.ErrorAs(t, err, &glanceErr)
+	assert.Equal(t, "GENAI-030", glanceErr.Code())
+
+	_, err = ParseSafetySetting("harassment", "HARM_BLOCK_EVERYTHING")
+	require.ErrorAs(t, err, &glanceErr)
+	assert.Equal(t, "GENAI-031", glanceErr.Code())
+
+	_, err = ParseSafetySetting("HARM_BLOCK_NONE", "none")
+	assert.Error(t, err, "a threshold is not a category")
+}
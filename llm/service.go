@@ -4,9 +4,16 @@ package llm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
 
 	"github.com/sirupsen/logrus"
+
+	"glance/cache"
+	"glance/filesystem"
 )
 
 // Service provides high-level LLM operations for the Glance application.
@@ -16,6 +23,51 @@ type Service struct {
 	client         Client
 	modelName      string
 	promptTemplate string
+	fileScorer     filesystem.FileScorer
+	budget         *Budget
+	summaryCache   cache.SummaryStore
+
+	// systemInstructions and generationOptions are hashed into the summary
+	// cache key alongside the model and prompt
+	systemInstructions string
+	generationOptions  string
+
+	// sourceLinks appends a Sources section after each summary; sourceLinkBase
+	// makes its links permalinks instead of relative links
+	sourceLinks    bool
+	sourceLinkBase string
+
+	// tokenCountOptional lets generation proceed when the prompt cannot be counted
+	tokenCountOptional bool
+
+	// summaryCheck, when set, vets the model's text before post-processing
+	summaryCheck func(string) error
+
+	// postProcessors transform each summary, in order, before it is returned
+	postProcessors []PostProcessor
+
+	// promptVars fill {{.Vars.key}} in the template; strictTemplate makes a
+	// missing key an error
+	promptVars     map[string]string
+	strictTemplate bool
+
+	// inflight bounds concurrent Generate calls; nil means unlimited
+	inflight chan struct{}
+
+	// splitMaxFiles, when positive, summarizes directories with more files
+	// than this in groups of at most this many files
+	splitMaxFiles int
+
+	// chunkBytes, when positive, pre-summarizes files larger than this with
+	// chunkClient (or client when nil) before building the directory prompt
+	chunkBytes  int
+	chunkClient Client
+
+	// log receives the service's log output
+	log logrus.FieldLogger
+
+	// tokensCounted accumulates prompt tokens reported by CountTokens across calls
+	tokensCounted atomic.Int64
 }
 
 // ServiceConfig contains configuration for creating a new Service.
@@ -26,14 +78,88 @@ type ServiceConfig struct {
 
 	// PromptTemplate is the template string to use for generating prompts
 	PromptTemplate string
+
+	// FileScorer ranks local files so the most relevant appear first in the prompt.
+	// When nil, files are listed alphabetically.
+	FileScorer filesystem.FileScorer
+
+	// Budget caps the requests and prompt tokens the service may spend.
+	// When nil, spending is unlimited.
+	Budget *Budget
+
+	// SummaryCache stores generated summaries keyed by a hash of the model, the
+	// generation settings (see SystemInstructions), and the prompt,
+	// so identical input is never sent to the LLM twice. When nil, caching is disabled.
+	SummaryCache cache.SummaryStore
+
+	// SystemInstructions and GenerationOptions describe client settings that
+	// change a summary without appearing in its prompt, such as the system
+	// instruction, temperature, and safety settings. Both are part of the
+	// summary cache key, so changing them never serves a stale summary.
+	SystemInstructions string
+	GenerationOptions  string
+
+	// SourceLinks appends a list of links to the summarized files after each summary.
+	SourceLinks bool
+
+	// SourceLinkBase is the permalink base the links resolve against (see
+	// GitHubPermalinkBase). When empty, links are relative to the summary file.
+	SourceLinkBase string
+
+	// TokenCountOptional treats token counting as advisory: when the prompt cannot
+	// be counted, generation proceeds with an estimate. When false, a directory
+	// whose prompt cannot be counted fails before any generation request.
+	TokenCountOptional bool
+
+	// MaxInflight caps how many generation requests may be in flight at once
+	// across all concurrent callers of the service. Zero means unlimited.
+	MaxInflight int
+
+	// PostProcessors transform each summary, in order, after generation (or a
+	// cache hit) and before source links are added. Summaries are cached
+	// unprocessed, so changing post-processors takes effect without regenerating.
+	PostProcessors []PostProcessor
+
+	// SummaryCheck, when set, vets each directory's summary as the model wrote
+	// it, after generation or a cache hit and before post-processors and source
+	// links. An error fails the directory and is wrapped, so errors.Is still
+	// matches it. A streamed summary has already been written by then.
+	SummaryCheck func(string) error
+
+	// PromptVars are user-supplied variables available to the template as
+	// {{.Vars.key}}, e.g. a project name or intended audience.
+	PromptVars map[string]string
+
+	// StrictTemplate fails prompt rendering when the template references a
+	// Vars key that was not supplied. When false, such a key renders empty.
+	StrictTemplate bool
+
+	// SplitMaxFiles, when positive, summarizes a directory with more files than
+	// this as groups of related files (see filesystem.GroupFiles), one summary
+	// section per group, combined into a single document. Zero disables it.
+	SplitMaxFiles int
+
+	// ChunkSummarizeBytes, when positive, replaces every file larger than this
+	// in a directory's prompt with a summary made from chunks of at most this
+	// many bytes, one call per chunk. Zero includes files verbatim.
+	ChunkSummarizeBytes int
+
+	// ChunkSummaryClient makes the per-chunk calls, e.g. with a cheaper model.
+	// When nil, the service's own client is used.
+	ChunkSummaryClient Client
+
+	// Logger receives the service's log output. When nil, the standard logrus
+	// logger is used.
+	Logger logrus.FieldLogger
 }
 
 // DefaultServiceConfig returns a ServiceConfig with sensible defaults.
 // It uses the same default model as the client configuration.
 func DefaultServiceConfig() ServiceConfig {
 	return ServiceConfig{
-		ModelName:      "gemini-3-flash-preview", // Make sure this matches the client default
-		PromptTemplate: "",
+		ModelName:          "gemini-3-flash-preview", // Make sure this matches the client default
+		PromptTemplate:     "",
+		TokenCountOptional: true,
 	}
 }
 
@@ -51,6 +177,122 @@ func WithPromptTemplate(template string) func(*ServiceConfig) {
 	}
 }
 
+// WithFileScorer configures the scorer used to order local files in the prompt.
+func WithFileScorer(scorer filesystem.FileScorer) func(*ServiceConfig) {
+	return func(c *ServiceConfig) {
+		c.FileScorer = scorer
+	}
+}
+
+// WithBudget configures a spending cap shared by all generation requests.
+func WithBudget(budget *Budget) func(*ServiceConfig) {
+	return func(c *ServiceConfig) {
+		c.Budget = budget
+	}
+}
+
+// WithSummaryCache configures a persistent cache consulted before each generation.
+func WithSummaryCache(store cache.SummaryStore) func(*ServiceConfig) {
+	return func(c *ServiceConfig) {
+		c.SummaryCache = store
+	}
+}
+
+// WithGenerationSettings records the client's system instruction and a
+// description of its generation options, so summaries generated under
+// different settings are cached separately.
+func WithGenerationSettings(systemInstructions, generationOptions string) func(*ServiceConfig) {
+	return func(c *ServiceConfig) {
+		c.SystemInstructions = systemInstructions
+		c.GenerationOptions = generationOptions
+	}
+}
+
+// WithSourceLinks appends a Sources section linking to each summarized file.
+// permalinkBase, when non-empty, is the URL that directory paths are resolved
+// against; otherwise links are relative.
+func WithSourceLinks(permalinkBase string) func(*ServiceConfig) {
+	return func(c *ServiceConfig) {
+		c.SourceLinks = true
+		c.SourceLinkBase = permalinkBase
+	}
+}
+
+// WithTokenCountOptional configures whether a failed token count is tolerated
+// (the default) or fails the request. Retrying transient count failures is left
+// to the client, like every other retry (see FallbackClient.CountTokens).
+func WithTokenCountOptional(optional bool) func(*ServiceConfig) {
+	return func(c *ServiceConfig) {
+		c.TokenCountOptional = optional
+	}
+}
+
+// WithMaxInflight caps concurrent generation requests at n, independently of
+// how many goroutines call GenerateGlanceMarkdown. Callers beyond the cap wait
+// for a slot; prompt rendering, caching, and token counting are not limited.
+func WithMaxInflight(n int) func(*ServiceConfig) {
+	return func(c *ServiceConfig) {
+		c.MaxInflight = n
+	}
+}
+
+// WithOutputPostProcessor adds a post-processor that runs on each summary after
+// those already configured. If it returns an error, the directory fails.
+func WithOutputPostProcessor(processor func(string) (string, error)) func(*ServiceConfig) {
+	return func(c *ServiceConfig) {
+		c.PostProcessors = append(c.PostProcessors, processor)
+	}
+}
+
+// WithSummaryCheck vets each summary as the model wrote it, before any
+// post-processing, failing the directory when check returns an error.
+func WithSummaryCheck(check func(string) error) func(*ServiceConfig) {
+	return func(c *ServiceConfig) {
+		c.SummaryCheck = check
+	}
+}
+
+// WithPromptVars configures the variables available to the template as
+// {{.Vars.key}}.
+func WithPromptVars(vars map[string]string) func(*ServiceConfig) {
+	return func(c *ServiceConfig) {
+		c.PromptVars = vars
+	}
+}
+
+// WithStrictTemplate makes a template reference to a missing {{.Vars.key}} an
+// error instead of rendering it empty.
+func WithStrictTemplate(strict bool) func(*ServiceConfig) {
+	return func(c *ServiceConfig) {
+		c.StrictTemplate = strict
+	}
+}
+
+// WithSplitLargeDirs summarizes directories with more than maxPerGroup files
+// as groups of at most maxPerGroup related files. Zero disables splitting.
+func WithSplitLargeDirs(maxPerGroup int) func(*ServiceConfig) {
+	return func(c *ServiceConfig) {
+		c.SplitMaxFiles = maxPerGroup
+	}
+}
+
+// WithChunkSummarize pre-summarizes files larger than thresholdBytes, in
+// chunks of at most thresholdBytes, and includes the summaries in the
+// directory prompt instead of the files. Zero disables it.
+func WithChunkSummarize(thresholdBytes int) func(*ServiceConfig) {
+	return func(c *ServiceConfig) {
+		c.ChunkSummarizeBytes = thresholdBytes
+	}
+}
+
+// WithChunkSummaryClient makes chunk pre-summaries with client instead of the
+// service's own client, typically one for a quicker, cheaper model.
+func WithChunkSummaryClient(client Client) func(*ServiceConfig) {
+	return func(c *ServiceConfig) {
+		c.ChunkSummaryClient = client
+	}
+}
+
 // NewService creates a new LLM Service with the specified client and options.
 //
 // Parameters:
@@ -73,10 +315,33 @@ func NewService(client Client, options ...func(*ServiceConfig)) (*Service, error
 		option(&config)
 	}
 
+	var inflight chan struct{}
+	if config.MaxInflight > 0 {
+		inflight = make(chan struct{}, config.MaxInflight)
+	}
+
 	return &Service{
 		client:         client,
 		modelName:      config.ModelName,
 		promptTemplate: config.PromptTemplate,
+		fileScorer:     config.FileScorer,
+		budget:         config.Budget,
+		summaryCache:   config.SummaryCache,
+		sourceLinks:    config.SourceLinks,
+		sourceLinkBase: config.SourceLinkBase,
+
+		systemInstructions: config.SystemInstructions,
+		generationOptions:  config.GenerationOptions,
+		tokenCountOptional: config.TokenCountOptional,
+		inflight:           inflight,
+		summaryCheck:       config.SummaryCheck,
+		postProcessors:     config.PostProcessors,
+		promptVars:         config.PromptVars,
+		strictTemplate:     config.StrictTemplate,
+		splitMaxFiles:      config.SplitMaxFiles,
+		chunkBytes:         config.ChunkSummarizeBytes,
+		chunkClient:        config.ChunkSummaryClient,
+		log:                loggerOrStandard(config.Logger),
 	}, nil
 }
 
@@ -89,72 +354,150 @@ func NewService(client Client, options ...func(*ServiceConfig)) (*Service, error
 //   - dir: The directory path being processed
 //   - fileMap: A map of file names to their contents
 //   - subGlances: The combined contents of subdirectory glance.md files
+//   - promptOptions: Optional extra prompt data such as git history
 //
 // Returns:
 //   - The generated markdown content
 //   - An error if generation fails
-func (s *Service) GenerateGlanceMarkdown(ctx context.Context, dir string, fileMap map[string]string, subGlances string) (string, error) {
-	// Build prompt data
-	promptData := BuildPromptData(dir, subGlances, fileMap)
+func (s *Service) GenerateGlanceMarkdown(
+	ctx context.Context,
+	dir string,
+	fileMap map[string]string,
+	subGlances string,
+	promptOptions ...PromptDataOption,
+) (string, error) {
+	promptFiles, err := s.compressLargeFiles(ctx, dir, fileMap)
+	if err != nil {
+		return "", err
+	}
 
-	// Log start of prompt generation with structured fields
-	logrus.WithFields(logrus.Fields{
-		"directory":  dir,
-		"model":      s.modelName,
-		"operation":  "generate_prompt",
-		"file_count": len(fileMap),
-	}).Debug("Generating prompt from template")
+	if s.splitMaxFiles > 0 && len(fileMap) > s.splitMaxFiles {
+		return s.generateGrouped(ctx, dir, promptFiles, subGlances, promptOptions...)
+	}
 
-	// Use template from the service
-	prompt, err := GeneratePrompt(promptData, s.promptTemplate)
+	summary, err := s.summarize(ctx, dir, promptFiles, subGlances, promptOptions...)
 	if err != nil {
-		// Log prompt generation error with structured fields
-		logrus.WithFields(logrus.Fields{
-			"directory": dir,
-			"model":     s.modelName,
-			"operation": "generate_prompt",
-			"error":     err,
-			"status":    "failed",
-		}).Error("Failed to generate prompt from template")
-		return "", fmt.Errorf("failed to generate prompt: %w", err)
+		return "", err
+	}
+	return s.finish(summary, dir, fileMap)
+}
+
+// summaryCacheKey returns the summary cache key for prompt: a hash of
+// everything sent to the model that can change its response.
+func (s *Service) summaryCacheKey(prompt string) string {
+	return cache.HashInput(s.modelName, s.systemInstructions, s.generationOptions, prompt)
+}
+
+// summarize generates the summary for one prompt, serving it from the summary
+// cache when possible, before any post-processing.
+func (s *Service) summarize(
+	ctx context.Context,
+	dir string,
+	fileMap map[string]string,
+	subGlances string,
+	promptOptions ...PromptDataOption,
+) (string, error) {
+	prompt, promptData, err := s.renderPrompt(dir, fileMap, subGlances, promptOptions...)
+	if err != nil {
+		return "", err
+	}
+
+	// Serve identical input from the summary cache without an LLM call
+	var cacheKey string
+	if s.summaryCache != nil {
+		cacheKey = s.summaryCacheKey(prompt)
+		if cached, ok := s.summaryCache.Get(cacheKey); ok {
+			s.log.WithFields(logrus.Fields{
+				"directory": dir,
+				"model":     s.modelName,
+				"operation": "summary_cache",
+				"status":    "hit",
+			}).Debug("Serving summary from cache")
+			if promptData.stream != nil {
+				writeStreamed(promptData.stream, cached)
+			}
+			return cached, nil
+		}
 	}
 
 	// Optional token counting for debugging
-	tokens, tokenErr := s.client.CountTokens(ctx, prompt)
+	// Clients without token counting get an estimate instead of a failing call
+	var tokens int
+	tokenErr := errTokenCountUnsupported
+	if CapabilitiesOf(s.client).SupportsTokenCount {
+		tokens, tokenErr = s.client.CountTokens(ctx, prompt)
+	}
 	if tokenErr == nil {
-		logrus.WithFields(logrus.Fields{
+		s.tokensCounted.Add(int64(tokens))
+		s.log.WithFields(logrus.Fields{
 			"directory":   dir,
 			"token_count": tokens,
 			"model":       s.modelName,
 			"operation":   "count_tokens",
 		}).Debug("Token count for prompt")
+	} else if !s.tokenCountOptional {
+		s.log.WithFields(logrus.Fields{
+			"directory": dir,
+			"model":     s.modelName,
+			"operation": "count_tokens",
+			"error":     tokenErr,
+			"status":    "failed",
+		}).Error("Failed to count tokens - token counting is required")
+		return "", fmt.Errorf("failed to count tokens: %w", tokenErr)
 	} else {
-		logrus.WithFields(logrus.Fields{
+		s.log.WithFields(logrus.Fields{
 			"directory": dir,
 			"model":     s.modelName,
 			"operation": "count_tokens",
 			"error":     tokenErr,
-		}).Debug("Failed to count tokens")
+		}).Debug("Failed to count tokens - proceeding without an exact count")
+	}
+
+	// Charge the request against the budget before spending anything on generation
+	if s.budget != nil {
+		charged := int64(tokens)
+		if tokenErr != nil {
+			charged = estimateTokens(prompt)
+		}
+		if err := s.budget.Acquire(charged); err != nil {
+			s.log.WithFields(logrus.Fields{
+				"directory": dir,
+				"model":     s.modelName,
+				"operation": "generate_content",
+				"status":    "budget_exhausted",
+			}).Debug("Skipping generation - LLM budget exhausted")
+			return "", fmt.Errorf("skipping %s: %w", dir, err)
+		}
 	}
 
-	logrus.WithFields(logrus.Fields{
+	s.log.WithFields(logrus.Fields{
 		"directory": dir,
 		"model":     s.modelName,
 		"operation": "generate_content",
 	}).Debug("Generating content")
 
-	result, err := s.client.Generate(ctx, prompt)
+	result, err := s.generate(ctx, prompt, promptData.stream)
 	if err == nil {
-		logrus.WithFields(logrus.Fields{
+		s.log.WithFields(logrus.Fields{
 			"directory": dir,
 			"model":     s.modelName,
 			"operation": "generate_content",
 			"status":    "success",
 		}).Debug("Content generation successful")
+
+		if s.summaryCache != nil {
+			if putErr := s.summaryCache.Put(cacheKey, result); putErr != nil {
+				s.log.WithFields(logrus.Fields{
+					"directory": dir,
+					"operation": "summary_cache",
+					"error":     putErr,
+				}).Warn("Failed to store summary in cache")
+			}
+		}
 		return result, nil
 	}
 
-	logrus.WithFields(logrus.Fields{
+	s.log.WithFields(logrus.Fields{
 		"directory": dir,
 		"model":     s.modelName,
 		"operation": "generate_content",
@@ -164,3 +507,179 @@ func (s *Service) GenerateGlanceMarkdown(ctx context.Context, dir string, fileMa
 
 	return "", fmt.Errorf("failed to generate content: %w", err)
 }
+
+// RenderPrompt builds the exact prompt GenerateGlanceMarkdown would send for a
+// directory, without calling the LLM.
+//
+// Parameters:
+//   - dir: The directory path being processed
+//   - fileMap: A map of file names to their contents
+//   - subGlances: The combined contents of subdirectory glance.md files
+//   - promptOptions: Optional extra prompt data such as git history
+//
+// Returns:
+//   - The rendered prompt
+//   - An error if the template cannot be rendered
+func (s *Service) RenderPrompt(
+	dir string,
+	fileMap map[string]string,
+	subGlances string,
+	promptOptions ...PromptDataOption,
+) (string, error) {
+	prompt, _, err := s.renderPrompt(dir, fileMap, subGlances, promptOptions...)
+	return prompt, err
+}
+
+// renderPrompt implements RenderPrompt, also returning the prompt data so
+// callers can honor per-call options such as WithStreamWriter.
+func (s *Service) renderPrompt(
+	dir string,
+	fileMap map[string]string,
+	subGlances string,
+	promptOptions ...PromptDataOption,
+) (string, *PromptData, error) {
+	// Build prompt data, ranking files by relevance when a scorer is configured
+	promptData := BuildPromptData(dir, subGlances, fileMap)
+	if s.fileScorer != nil {
+		ranked := filesystem.RankFiles(fileMap, s.fileScorer)
+		promptData.FileContents = FormatFileContentsInOrder(fileMap, ranked)
+		promptData.Files = BuildPromptFiles(fileMap, ranked)
+	}
+	promptData.Vars = s.promptVars
+	promptData.strictVars = s.strictTemplate
+	for _, option := range promptOptions {
+		option(promptData)
+	}
+
+	// Log start of prompt generation with structured fields
+	s.log.WithFields(logrus.Fields{
+		"directory":  dir,
+		"model":      s.modelName,
+		"operation":  "generate_prompt",
+		"file_count": len(fileMap),
+	}).Debug("Generating prompt from template")
+
+	// Use template from the service
+	template := s.promptTemplate
+	if promptData.template != "" {
+		template = promptData.template
+	}
+	prompt, err := GeneratePrompt(promptData, template)
+	if err != nil {
+		// Log prompt generation error with structured fields
+		s.log.WithFields(logrus.Fields{
+			"directory": dir,
+			"model":     s.modelName,
+			"operation": "generate_prompt",
+			"error":     err,
+			"status":    "failed",
+		}).Error("Failed to generate prompt from template")
+		return "", nil, fmt.Errorf("failed to generate prompt: %w", err)
+	}
+
+	if promptData.rewrite != nil {
+		prompt = promptData.rewrite(prompt)
+	}
+
+	return prompt, promptData, nil
+}
+
+// generate calls the client once, holding an in-flight slot when the service
+// has a MaxInflight limit. When stream is set, the text is also written to it,
+// chunk by chunk if the client supports streaming.
+func (s *Service) generate(ctx context.Context, prompt string, stream io.Writer) (string, error) {
+	return s.generateWith(ctx, s.client, prompt, stream)
+}
+
+// generateWith is generate with the given client, such as the chunk summary
+// client. Streaming always goes through the service's own client.
+func (s *Service) generateWith(ctx context.Context, client Client, prompt string, stream io.Writer) (string, error) {
+	if s.inflight != nil {
+		select {
+		case s.inflight <- struct{}{}:
+			defer func() { <-s.inflight }()
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	var result string
+	var err error
+	if stream != nil {
+		result, err = s.generateStreamed(ctx, prompt, stream)
+	} else {
+		result, err = client.Generate(ctx, prompt)
+	}
+	if err == nil && strings.TrimSpace(result) == "" {
+		// Safety net for clients that return blank output as a success, so
+		// nothing blank is cached or written
+		return "", ErrEmptyResponse
+	}
+	return result, err
+}
+
+// finish applies the post-processors to a generated or cached summary and then
+// adds source links.
+func (s *Service) finish(summary, dir string, fileMap map[string]string) (string, error) {
+	if s.summaryCheck != nil {
+		if err := s.summaryCheck(summary); err != nil {
+			return "", fmt.Errorf("summary check failed for %s: %w", dir, err)
+		}
+	}
+	for i, process := range s.postProcessors {
+		processed, err := process(summary)
+		if err != nil {
+			s.log.WithFields(logrus.Fields{
+				"directory":      dir,
+				"operation":      "post_process",
+				"post_processor": i,
+				"error":          err,
+				"status":         "failed",
+			}).Error("Output post-processor failed")
+			return "", fmt.Errorf("post-processor %d failed for %s: %w", i, dir, err)
+		}
+		summary = processed
+	}
+	if len(s.postProcessors) > 0 && strings.TrimSpace(summary) == "" {
+		return "", fmt.Errorf("post-processing %s: %w", dir, ErrEmptyResponse)
+	}
+	return s.appendSourceLinks(summary, dir, fileMap), nil
+}
+
+// appendSourceLinks adds the Sources section to summary when source links are
+// enabled. It runs after caching, so cached summaries never contain links.
+func (s *Service) appendSourceLinks(summary, dir string, fileMap map[string]string) string {
+	if !s.sourceLinks {
+		return summary
+	}
+	files := make([]string, 0, len(fileMap))
+	for name := range fileMap {
+		files = append(files, name)
+	}
+	return strings.TrimRight(summary, "\n") + RenderSourceLinks(dir, files, s.sourceLinkBase)
+}
+
+// ErrEmptyResponse is returned when the client reports success but the response
+// is empty or whitespace-only. Like any generation failure it leaves the
+// directory's glance output unchanged, so the next run retries it.
+var ErrEmptyResponse = errors.New("LLM returned an empty response")
+
+// errTokenCountUnsupported stands in for a CountTokens error when the client
+// reports that it cannot count tokens.
+var errTokenCountUnsupported = errors.New("client does not support token counting")
+
+// estimateTokens approximates a prompt's token count when the client cannot count it,
+// using the common rule of thumb of four bytes per token.
+func estimateTokens(prompt string) int64 {
+	return int64(len(prompt)+3) / 4
+}
+
+// ModelName returns the model name the service was configured with.
+func (s *Service) ModelName() string {
+	return s.modelName
+}
+
+// TokensCounted returns the total number of prompt tokens counted by this service so far.
+// Prompts whose token count could not be determined are not included.
+func (s *Service) TokensCounted() int64 {
+	return s.tokensCounted.Load()
+}
@@ -0,0 +1,93 @@
+package llm
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/cache"
+	"glance/internal/mocks"
+)
+
+func TestServiceSummaryCache(t *testing.T) {
+	const summary = "# pkg\n\nGenerated summary.\n"
+	files := map[string]string{"main.go": "package main"}
+
+	newMock := func() *mocks.LLMClient {
+		mockClient := new(mocks.LLMClient)
+		mockClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil)
+		mockClient.On("Generate", mock.Anything, mock.Anything).Return(summary, nil)
+		return mockClient
+	}
+
+	t.Run("Identical input is served from cache", func(t *testing.T) {
+		store, err := cache.NewFileStore(filepath.Join(t.TempDir(), "cache"))
+		require.NoError(t, err)
+
+		mockClient := newMock()
+		service, err := NewService(NewMockClientAdapter(mockClient),
+			WithPromptTemplate("{{.Directory}}\n{{.FileContents}}"),
+			WithSummaryCache(store))
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			out, genErr := service.GenerateGlanceMarkdown(context.Background(), "pkg", files, "")
+			require.NoError(t, genErr)
+			assert.Equal(t, summary, out)
+		}
+		mockClient.AssertNumberOfCalls(t, "Generate", 1)
+
+		// A fresh service sharing the store (e.g. a later run) also hits the cache
+		otherMock := newMock()
+		other, err := NewService(NewMockClientAdapter(otherMock),
+			WithPromptTemplate("{{.Directory}}\n{{.FileContents}}"),
+			WithSummaryCache(store))
+		require.NoError(t, err)
+		_, err = other.GenerateGlanceMarkdown(context.Background(), "pkg", files, "")
+		require.NoError(t, err)
+		otherMock.AssertNotCalled(t, "Generate", mock.Anything, mock.Anything)
+
+		// Changed content misses the cache
+		_, err = service.GenerateGlanceMarkdown(context.Background(), "pkg", map[string]string{"main.go": "package changed"}, "")
+		require.NoError(t, err)
+		mockClient.AssertNumberOfCalls(t, "Generate", 2)
+	})
+
+	t.Run("Changed generation settings miss the cache", func(t *testing.T) {
+		store, err := cache.NewFileStore(filepath.Join(t.TempDir(), "cache"))
+		require.NoError(t, err)
+
+		generate := func(systemInstructions, generationOptions string) *mocks.LLMClient {
+			mockClient := newMock()
+			service, err := NewService(NewMockClientAdapter(mockClient),
+				WithPromptTemplate("{{.Directory}}\n{{.FileContents}}"),
+				WithGenerationSettings(systemInstructions, generationOptions),
+				WithSummaryCache(store))
+			require.NoError(t, err)
+			_, err = service.GenerateGlanceMarkdown(context.Background(), "pkg", files, "")
+			require.NoError(t, err)
+			return mockClient
+		}
+
+		generate("Write for new contributors.", "").AssertNumberOfCalls(t, "Generate", 1)
+		generate("Write for new contributors.", "").AssertNotCalled(t, "Generate", mock.Anything, mock.Anything)
+		generate("Write for security reviewers.", "").AssertNumberOfCalls(t, "Generate", 1)
+		generate("Write for new contributors.", "deterministic").AssertNumberOfCalls(t, "Generate", 1)
+	})
+
+	t.Run("Without a cache every call generates", func(t *testing.T) {
+		mockClient := newMock()
+		service, err := NewService(NewMockClientAdapter(mockClient), WithPromptTemplate("{{.FileContents}}"))
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			_, genErr := service.GenerateGlanceMarkdown(context.Background(), "pkg", files, "")
+			require.NoError(t, genErr)
+		}
+		mockClient.AssertNumberOfCalls(t, "Generate", 2)
+	})
+}
@@ -0,0 +1,63 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/internal/mocks"
+)
+
+func TestServiceMaxInflight(t *testing.T) {
+	const workers = 12
+	const maxInflight = 3
+
+	probe := &concurrencyProbe{}
+	service, err := NewService(probe, WithPromptTemplate("{{.Directory}}"), WithMaxInflight(maxInflight))
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out, genErr := service.GenerateGlanceMarkdown(context.Background(), fmt.Sprintf("dir-%d", i), nil, "")
+			assert.NoError(t, genErr)
+			assert.Equal(t, "ok", out)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, probe.peak.Load(), int32(maxInflight), "in-flight generate calls exceeded the bound")
+	assert.Equal(t, int32(maxInflight), probe.peak.Load(), "the bound should be saturated by %d workers", workers)
+}
+
+func TestServiceMaxInflightRespectsContext(t *testing.T) {
+	started := make(chan struct{})
+	blocked := make(chan struct{})
+	holder := new(mocks.LLMClient)
+	holder.On("CountTokens", context.Background(), "a").Return(1, nil)
+	holder.On("Generate", context.Background(), "a").Run(func(_ mock.Arguments) {
+		close(started)
+		<-blocked
+	}).Return("ok", nil).Once()
+
+	service, err := NewService(NewMockClientAdapter(holder), WithPromptTemplate("{{.Directory}}"), WithMaxInflight(1))
+	require.NoError(t, err)
+
+	go func() { _, _ = service.GenerateGlanceMarkdown(context.Background(), "a", nil, "") }()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	holder.On("CountTokens", ctx, "b").Return(1, nil)
+	_, genErr := service.GenerateGlanceMarkdown(ctx, "b", nil, "")
+	assert.ErrorIs(t, genErr, context.DeadlineExceeded)
+	close(blocked)
+}
@@ -5,13 +5,19 @@ package llm
 import (
 	"context"
 	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"glance/cache"
+	"glance/filesystem"
 	"glance/internal/mocks"
 )
 
@@ -178,6 +184,85 @@ func TestGenerateGlanceMarkdown(t *testing.T) {
 	})
 }
 
+func TestGenerateGlanceMarkdownWithFileScorer(t *testing.T) {
+	mockClient := new(mocks.LLMClient)
+	adapter := NewMockClientAdapter(mockClient)
+
+	// Rank files by reverse alphabetical order to prove the scorer controls prompt order
+	reverse := filesystem.FileScorerFunc(func(path, content string) float64 {
+		return float64(path[0])
+	})
+
+	service, err := NewService(adapter,
+		WithPromptTemplate("{{.FileContents}}"),
+		WithFileScorer(reverse),
+	)
+	assert.NoError(t, err)
+
+	var capturedPrompt string
+	mockClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil)
+	mockClient.On("Generate", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { capturedPrompt = args.String(1) }).
+		Return("summary", nil)
+
+	_, err = service.GenerateGlanceMarkdown(context.Background(), "dir",
+		map[string]string{"a.go": "A", "b.go": "B"}, "")
+	assert.NoError(t, err)
+
+	aPos := strings.Index(capturedPrompt, "=== file: a.go ===")
+	bPos := strings.Index(capturedPrompt, "=== file: b.go ===")
+	assert.True(t, aPos > -1 && bPos > -1)
+	assert.True(t, bPos < aPos, "higher-scored file should appear first")
+}
+
+func TestRenderPromptRewrite(t *testing.T) {
+	service, err := NewService(NewMockClientAdapter(new(mocks.LLMClient)),
+		WithPromptTemplate("{{.Directory}}\n{{.FileContents}}"),
+	)
+	assert.NoError(t, err)
+
+	redact := func(s string) string { return strings.ReplaceAll(s, "/home/alice", "<root>") }
+	prompt, err := service.RenderPrompt("pkg",
+		map[string]string{"a.go": "// see /home/alice/pkg/b.go"}, "",
+		WithPromptRewrite(redact))
+	assert.NoError(t, err)
+	assert.Contains(t, prompt, "// see <root>/pkg/b.go")
+	assert.NotContains(t, prompt, "/home/alice")
+}
+
+func TestRenderPromptVars(t *testing.T) {
+	template := "project: {{.Vars.project}}\naudience: {{.Vars.audience}}\nteam: {{.Vars.team}}\n{{.Directory}}"
+
+	service, err := NewService(NewMockClientAdapter(new(mocks.LLMClient)),
+		WithPromptTemplate(template),
+		WithPromptVars(map[string]string{"project": "glance", "audience": "new contributors"}),
+	)
+	require.NoError(t, err)
+
+	prompt, err := service.RenderPrompt("pkg", map[string]string{"a.go": "package pkg"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "project: glance\naudience: new contributors\nteam: \npkg", prompt,
+		"supplied vars render together and a missing one renders empty")
+
+	strict, err := NewService(NewMockClientAdapter(new(mocks.LLMClient)),
+		WithPromptTemplate(template),
+		WithPromptVars(map[string]string{"project": "glance", "audience": "new contributors"}),
+		WithStrictTemplate(true),
+	)
+	require.NoError(t, err)
+	_, err = strict.RenderPrompt("pkg", map[string]string{"a.go": "package pkg"}, "")
+	assert.ErrorContains(t, err, "team", "a strict template fails on a missing var")
+
+	noVars, err := NewService(NewMockClientAdapter(new(mocks.LLMClient)),
+		WithPromptTemplate("{{.Directory}}"),
+		WithStrictTemplate(true),
+	)
+	require.NoError(t, err)
+	prompt, err = noVars.RenderPrompt("pkg", nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, "pkg", prompt, "a strict template without var references renders normally")
+}
+
 func TestServiceConfig(t *testing.T) {
 	// Test default config
 	defaults := DefaultServiceConfig()
@@ -311,3 +396,85 @@ func TestStructuredLogging(t *testing.T) {
 	assert.True(t, foundCountTokens, "Should have count_tokens operation log")
 	assert.True(t, foundGenerateContent, "Should have generate_content operation log")
 }
+
+func TestTokenCounting(t *testing.T) {
+	ctx := context.Background()
+
+	// newService wraps the mock in a single-tier FallbackClient, which owns
+	// count retries just as it owns generation retries.
+	newService := func(t *testing.T, mockClient *mocks.LLMClient, options ...func(*ServiceConfig)) *Service {
+		t.Helper()
+		client, err := NewFallbackClientWithBackoff(
+			[]FallbackTier{{Name: "primary", Client: NewMockClientAdapter(mockClient)}},
+			2, time.Millisecond, time.Millisecond,
+		)
+		assert.NoError(t, err)
+		service, err := NewService(client, append([]func(*ServiceConfig){WithPromptTemplate("{{.FileContents}}")}, options...)...)
+		assert.NoError(t, err)
+		return service
+	}
+
+	t.Run("transient count failure is retried", func(t *testing.T) {
+		mockClient := new(mocks.LLMClient)
+		mockClient.On("CountTokens", mock.Anything, mock.Anything).Return(0, errors.New("503 unavailable")).Once()
+		mockClient.On("CountTokens", mock.Anything, mock.Anything).Return(42, nil).Once()
+		mockClient.On("Generate", mock.Anything, mock.Anything).Return("summary", nil).Once()
+		service := newService(t, mockClient)
+
+		result, err := service.GenerateGlanceMarkdown(ctx, "dir", map[string]string{"a.go": "A"}, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "summary", result)
+		assert.Equal(t, int64(42), service.TokensCounted())
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("persistent count failure still generates", func(t *testing.T) {
+		mockClient := new(mocks.LLMClient)
+		mockClient.On("CountTokens", mock.Anything, mock.Anything).Return(0, errors.New("count failed")).Times(3)
+		mockClient.On("Generate", mock.Anything, mock.Anything).Return("summary", nil).Once()
+		service := newService(t, mockClient)
+
+		result, err := service.GenerateGlanceMarkdown(ctx, "dir", map[string]string{"a.go": "A"}, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "summary", result)
+		assert.Zero(t, service.TokensCounted())
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("required count failure fails before generating", func(t *testing.T) {
+		mockClient := new(mocks.LLMClient)
+		mockClient.On("CountTokens", mock.Anything, mock.Anything).Return(0, errors.New("count failed")).Times(3)
+		service := newService(t, mockClient, WithTokenCountOptional(false))
+
+		result, err := service.GenerateGlanceMarkdown(ctx, "dir", map[string]string{"a.go": "A"}, "")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to count tokens")
+		assert.Empty(t, result)
+		mockClient.AssertNotCalled(t, "Generate", mock.Anything, mock.Anything)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestServiceRejectsEmptyResponse(t *testing.T) {
+	mockClient := new(mocks.LLMClient)
+	mockClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil)
+	mockClient.On("Generate", mock.Anything, mock.Anything).Return("  \n", nil).Once()
+	mockClient.On("Generate", mock.Anything, mock.Anything).Return("# Summary\n", nil).Once()
+
+	store, err := cache.NewFileStore(filepath.Join(t.TempDir(), "cache"))
+	require.NoError(t, err)
+	service, err := NewService(NewMockClientAdapter(mockClient),
+		WithPromptTemplate("{{.Directory}}"),
+		WithSummaryCache(store),
+	)
+	require.NoError(t, err)
+
+	_, err = service.GenerateGlanceMarkdown(context.Background(), "pkg", nil, "")
+	require.ErrorIs(t, err, ErrEmptyResponse)
+
+	// The blank result was not cached, so the next call generates again
+	got, err := service.GenerateGlanceMarkdown(context.Background(), "pkg", nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, "# Summary\n", got)
+	mockClient.AssertExpectations(t)
+}
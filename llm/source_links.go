@@ -0,0 +1,99 @@
+// Package llm provides abstractions and implementations for interacting with
+// Large Language Model APIs in the glance application.
+package llm
+
+import (
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// GitHubPermalinkBase converts a GitHub remote URL and commit SHA into the base
+// URL of permalinks for files at that commit, e.g.
+// "https://github.com/acme/widgets/blob/<sha>". HTTPS, SSH
+// ("git@github.com:acme/widgets.git"), and ssh:// remotes are understood.
+//
+// Parameters:
+//   - remoteURL: The origin remote URL
+//   - commit: The commit SHA the links should point at
+//
+// Returns:
+//   - The permalink base URL, without a trailing slash
+//   - Whether the remote is a recognized GitHub repository
+func GitHubPermalinkBase(remoteURL, commit string) (string, bool) {
+	commit = strings.TrimSpace(commit)
+	remote := strings.TrimSpace(remoteURL)
+	if commit == "" || remote == "" {
+		return "", false
+	}
+
+	var repoPath string
+	if rest, ok := strings.CutPrefix(remote, "git@github.com:"); ok {
+		repoPath = rest
+	} else {
+		parsed, err := url.Parse(remote)
+		if err != nil || !strings.EqualFold(parsed.Hostname(), "github.com") {
+			return "", false
+		}
+		switch parsed.Scheme {
+		case "https", "http", "ssh", "git":
+		default:
+			return "", false
+		}
+		repoPath = parsed.Path
+	}
+
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	parts := strings.Split(repoPath, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", false
+	}
+
+	return "https://github.com/" + parts[0] + "/" + parts[1] + "/blob/" + commit, true
+}
+
+// RenderSourceLinks renders a markdown "Sources" section linking to each file
+// a summary was generated from. The section is built from the gathered file
+// set, never from LLM output, so it is deterministic for the same input.
+//
+// Parameters:
+//   - dir: The directory the summary describes, relative to the permalink base
+//   - files: The file names, relative to dir
+//   - permalinkBase: A URL such as one from GitHubPermalinkBase that dir is
+//     resolved against; when empty, links are relative to the summary file
+//
+// Returns:
+//   - The rendered section, starting with a blank line, or "" when there are no files
+func RenderSourceLinks(dir string, files []string, permalinkBase string) string {
+	if len(files) == 0 {
+		return ""
+	}
+
+	sorted := make([]string, len(files))
+	copy(sorted, files)
+	sort.Strings(sorted)
+
+	var b strings.Builder
+	b.WriteString("\n\n## Sources\n\n")
+	for _, file := range sorted {
+		name := filepath.ToSlash(file)
+		target := escapePath(name)
+		if permalinkBase != "" {
+			target = strings.TrimRight(permalinkBase, "/") + "/" + escapePath(path.Join(filepath.ToSlash(dir), name))
+		}
+		b.WriteString("- [" + name + "](" + target + ")\n")
+	}
+	return b.String()
+}
+
+// escapePath percent-encodes each segment of a slash-separated path so it can
+// be used as a markdown link target.
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,82 @@
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/internal/mocks"
+)
+
+const testSHA = "0123456789abcdef0123456789abcdef01234567"
+
+func TestGitHubPermalinkBase(t *testing.T) {
+	want := "https://github.com/acme/widgets/blob/" + testSHA
+
+	for _, remote := range []string{
+		"https://github.com/acme/widgets.git",
+		"https://github.com/acme/widgets",
+		"git@github.com:acme/widgets.git",
+		"ssh://git@github.com/acme/widgets.git",
+	} {
+		base, ok := GitHubPermalinkBase(remote, testSHA)
+		assert.True(t, ok, remote)
+		assert.Equal(t, want, base, remote)
+	}
+
+	for _, remote := range []string{
+		"https://gitlab.com/acme/widgets.git",
+		"https://github.com/acme",
+		"/srv/git/widgets.git",
+		"",
+	} {
+		_, ok := GitHubPermalinkBase(remote, testSHA)
+		assert.False(t, ok, remote)
+	}
+
+	_, ok := GitHubPermalinkBase("https://github.com/acme/widgets.git", "")
+	assert.False(t, ok, "a commit is required")
+}
+
+func TestRenderSourceLinks(t *testing.T) {
+	t.Run("relative links", func(t *testing.T) {
+		got := RenderSourceLinks("pkg/api", []string{"z.go", "a b.go", "sub/c.go"}, "")
+		assert.Equal(t, "\n\n## Sources\n\n"+
+			"- [a b.go](a%20b.go)\n"+
+			"- [sub/c.go](sub/c.go)\n"+
+			"- [z.go](z.go)\n", got)
+	})
+
+	t.Run("permalinks", func(t *testing.T) {
+		base := "https://github.com/acme/widgets/blob/" + testSHA
+		got := RenderSourceLinks("pkg/api", []string{"main.go"}, base)
+		assert.Equal(t, "\n\n## Sources\n\n- [main.go]("+base+"/pkg/api/main.go)\n", got)
+
+		got = RenderSourceLinks(".", []string{"main.go"}, base+"/")
+		assert.Equal(t, "\n\n## Sources\n\n- [main.go]("+base+"/main.go)\n", got, "the root directory adds no segment")
+	})
+
+	t.Run("no files", func(t *testing.T) {
+		assert.Empty(t, RenderSourceLinks("pkg", nil, ""))
+	})
+}
+
+func TestServiceSourceLinks(t *testing.T) {
+	mockClient := new(mocks.LLMClient)
+	mockClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil)
+	mockClient.On("Generate", mock.Anything, mock.Anything).Return("# Summary\n\nDoes things.\n", nil)
+
+	service, err := NewService(NewMockClientAdapter(mockClient),
+		WithPromptTemplate("{{.FileContents}}"),
+		WithSourceLinks(""),
+	)
+	require.NoError(t, err)
+
+	got, err := service.GenerateGlanceMarkdown(context.Background(), "pkg",
+		map[string]string{"b.go": "B", "a.go": "A"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "# Summary\n\nDoes things.\n\n## Sources\n\n- [a.go](a.go)\n- [b.go](b.go)\n", got)
+}
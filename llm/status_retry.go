@@ -0,0 +1,97 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Default first waits before a chat-completions client retries, by failure
+// class. Server errors usually clear within moments, while a rate limit
+// needs the provider's window to pass, so it starts an order of magnitude
+// longer. Both double with each attempt up to maxStatusBackoff.
+const (
+	defaultServerErrorBackoff = 100 * time.Millisecond
+	defaultRateLimitBackoff   = time.Second
+	maxStatusBackoff          = 30 * time.Second
+)
+
+// StatusError is an HTTP error response from a chat-completions endpoint,
+// other than a rate limit, which is reported as a RateLimitError. It carries
+// the status code so the retry loop can tell server errors from client errors.
+type StatusError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *StatusError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// retryDelay returns how long to wait before retrying after err on the given
+// 1-based attempt, and false when err is not worth retrying:
+//   - 429: the Retry-After delay when the provider gave one, otherwise the
+//     rate-limit backoff
+//   - 5xx: the short server-error backoff
+//   - other 4xx: no retry, since repeating the same request fails the same way
+//   - anything else, such as a network error or an empty response: the
+//     client's general schedule, 100ms times the attempt squared
+func (c *chatCompletionsClient) retryDelay(attempt int, err error) (time.Duration, bool) {
+	if retryAfter, limited := rateLimitDelay(err); limited {
+		if retryAfter > 0 {
+			return retryAfter, true
+		}
+		return doublingBackoff(attempt, orDefault(c.options.RateLimitBackoff, defaultRateLimitBackoff)), true
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		if statusErr.StatusCode >= http.StatusInternalServerError {
+			return doublingBackoff(attempt, orDefault(c.options.ServerErrorBackoff, defaultServerErrorBackoff)), true
+		}
+		return 0, false
+	}
+
+	return time.Duration(100*attempt*attempt) * time.Millisecond, true
+}
+
+// rejectedStatus reports whether err is a client error response other than a
+// rate limit. Repeating the same request would fail the same way, so
+// FallbackClient moves straight to the next tier instead of retrying it.
+func rejectedStatus(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) &&
+		statusErr.StatusCode >= http.StatusBadRequest &&
+		statusErr.StatusCode < http.StatusInternalServerError &&
+		statusErr.StatusCode != http.StatusTooManyRequests
+}
+
+// doublingBackoff returns base*2^(attempt-1), capped at maxStatusBackoff.
+func doublingBackoff(attempt int, base time.Duration) time.Duration {
+	wait := base
+	for i := 1; i < attempt && wait < maxStatusBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, maxStatusBackoff)
+}
+
+// orDefault returns d, or fallback when d is not positive.
+func orDefault(d, fallback time.Duration) time.Duration {
+	if d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+// attemptsSummary describes how many attempts a failed request made.
+func attemptsSummary(attempts int) string {
+	if attempts == 1 {
+		return "after 1 attempt"
+	}
+	return fmt.Sprintf("after %d attempts", attempts)
+}
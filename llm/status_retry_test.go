@@ -0,0 +1,172 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/internal/mocks"
+)
+
+// statusSequenceServer answers with the given statuses in order, then 200 with
+// a completion. headers are set on the response of the same index.
+func statusSequenceServer(t *testing.T, statuses []int, headers []map[string]string) (*httptest.Server, *int) {
+	t.Helper()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := requests
+		requests++
+		if i < len(statuses) {
+			if i < len(headers) {
+				for name, value := range headers[i] {
+					w.Header().Set(name, value)
+				}
+			}
+			w.WriteHeader(statuses[i])
+			_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"message": http.StatusText(statuses[i])}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]any{"content": "ok"}}},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestOpenRouterClientRetryByStatusClass(t *testing.T) {
+	tests := []struct {
+		name       string
+		statuses   []int
+		headers    []map[string]string
+		options    []ClientOption
+		wantErr    string
+		wantCalls  int
+		wantSleeps []time.Duration
+	}{
+		{
+			name:       "5xx is retried with the short server-error backoff",
+			statuses:   []int{http.StatusInternalServerError},
+			wantCalls:  2,
+			wantSleeps: []time.Duration{defaultServerErrorBackoff},
+		},
+		{
+			name:       "5xx backoff is configurable and doubles",
+			statuses:   []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+			options:    []ClientOption{WithRetryBackoff(50*time.Millisecond, 0)},
+			wantCalls:  3,
+			wantSleeps: []time.Duration{50 * time.Millisecond, 100 * time.Millisecond},
+		},
+		{
+			name:       "429 waits as long as Retry-After asks",
+			statuses:   []int{http.StatusTooManyRequests},
+			headers:    []map[string]string{{"Retry-After": "3"}},
+			wantCalls:  2,
+			wantSleeps: []time.Duration{3 * time.Second},
+		},
+		{
+			name:       "429 without Retry-After uses the longer rate-limit backoff",
+			statuses:   []int{http.StatusTooManyRequests},
+			wantCalls:  2,
+			wantSleeps: []time.Duration{defaultRateLimitBackoff},
+		},
+		{
+			name:       "429 backoff is configurable",
+			statuses:   []int{http.StatusTooManyRequests},
+			options:    []ClientOption{WithRetryBackoff(0, 5*time.Second)},
+			wantCalls:  2,
+			wantSleeps: []time.Duration{5 * time.Second},
+		},
+		{
+			name:      "4xx fails immediately",
+			statuses:  []int{http.StatusBadRequest},
+			wantErr:   "after 1 attempt",
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := useFakeClock(t)
+			server, requests := statusSequenceServer(t, tt.statuses, tt.headers)
+
+			client, err := newOpenRouterClient("test-key", append([]ClientOption{WithMaxRetries(3)}, tt.options...)...)
+			require.NoError(t, err)
+			client.baseURL = server.URL
+
+			result, err := client.Generate(context.Background(), "prompt")
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Contains(t, err.Error(), "status 400")
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "ok", result)
+			}
+			assert.Equal(t, tt.wantCalls, *requests)
+			if len(tt.wantSleeps) == 0 {
+				assert.Empty(t, fake.Sleeps(), "no retry, so no wait")
+			} else {
+				assert.Equal(t, tt.wantSleeps, fake.Sleeps())
+			}
+		})
+	}
+}
+
+// TestFallbackClientRejectedStatus verifies that a tier answering with a
+// client error fails over at once, while server errors are retried on the tier.
+func TestFallbackClientRejectedStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		wantPrimary int
+	}{
+		{name: "4xx fails over without retrying the tier", status: http.StatusBadRequest, wantPrimary: 1},
+		{name: "403 fails over without retrying the tier", status: http.StatusForbidden, wantPrimary: 1},
+		{name: "5xx retries the tier first", status: http.StatusServiceUnavailable, wantPrimary: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeClock(t)
+			server, requests := statusSequenceServer(t, []int{tt.status, tt.status, tt.status}, nil)
+			primary, err := newOpenRouterClient("test-key", WithMaxRetries(0))
+			require.NoError(t, err)
+			primary.baseURL = server.URL
+
+			secondaryMock := new(mocks.LLMClient)
+			secondaryMock.On("Generate", mock.Anything, "prompt").Return("from secondary", nil).Once()
+
+			client, err := NewFallbackClientWithBackoff(
+				[]FallbackTier{
+					{Name: "primary", Client: primary},
+					{Name: "secondary", Client: NewMockClientAdapter(secondaryMock)},
+				},
+				2,
+				time.Millisecond,
+				time.Millisecond,
+			)
+			require.NoError(t, err)
+
+			result, err := client.Generate(context.Background(), "prompt")
+			require.NoError(t, err)
+			assert.Equal(t, "from secondary", result)
+			assert.Equal(t, tt.wantPrimary, *requests)
+			secondaryMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDoublingBackoff(t *testing.T) {
+	assert.Equal(t, 100*time.Millisecond, doublingBackoff(1, 100*time.Millisecond))
+	assert.Equal(t, 400*time.Millisecond, doublingBackoff(3, 100*time.Millisecond))
+	assert.Equal(t, maxStatusBackoff, doublingBackoff(20, time.Second), "waits are capped")
+	assert.Equal(t, maxStatusBackoff, doublingBackoff(1, time.Hour))
+}
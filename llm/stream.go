@@ -0,0 +1,93 @@
+package llm
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// streamRestartNotice separates a discarded stream from the regenerated text
+// that follows it.
+const streamRestartNotice = "\n[stream discarded; regenerating the summary]\n\n"
+
+// generateStreamed generates the text for prompt, writing it to w as it
+// arrives and returning it assembled. Clients that only fake streaming fall
+// back to a single Generate call (which keeps the fallback client's retries
+// and validation) and write its result whole. So do streams that fail, or
+// whose assembled text fails the client's ValidateResponse check; any text
+// already written is followed by streamRestartNotice.
+func (s *Service) generateStreamed(ctx context.Context, prompt string, w io.Writer) (string, error) {
+	if !CapabilitiesOf(s.client).SupportsStreaming {
+		return s.generateThenWrite(ctx, prompt, w)
+	}
+
+	chunks, err := s.client.GenerateStream(ctx, prompt)
+	if err != nil {
+		s.log.WithFields(logrus.Fields{
+			"model":     s.modelName,
+			"operation": "generate_stream",
+			"error":     err,
+		}).Debug("Streaming unavailable - generating without streaming")
+		return s.generateThenWrite(ctx, prompt, w)
+	}
+
+	// Keep reading after an error so the producer can finish and close the channel
+	var assembled strings.Builder
+	var streamErr error
+	for chunk := range chunks {
+		if streamErr != nil {
+			continue
+		}
+		if chunk.Error != nil {
+			streamErr = chunk.Error
+			continue
+		}
+		if chunk.Text != "" {
+			assembled.WriteString(chunk.Text)
+			_, _ = io.WriteString(w, chunk.Text)
+		}
+	}
+	if streamErr == nil {
+		streamErr = validateResponse(s.client, assembled.String())
+	}
+	if streamErr != nil {
+		if assembled.Len() > 0 {
+			endStreamed(w, assembled.String())
+			_, _ = io.WriteString(w, streamRestartNotice)
+		}
+		s.log.WithFields(logrus.Fields{
+			"model":     s.modelName,
+			"operation": "generate_stream",
+			"error":     streamErr,
+		}).Warn("Stream failed or produced an invalid response - regenerating without streaming")
+		return s.generateThenWrite(ctx, prompt, w)
+	}
+	endStreamed(w, assembled.String())
+	return assembled.String(), nil
+}
+
+// generateThenWrite generates the text for prompt in one call and writes it
+// to w once it is complete.
+func (s *Service) generateThenWrite(ctx context.Context, prompt string, w io.Writer) (string, error) {
+	result, err := s.client.Generate(ctx, prompt)
+	if err == nil {
+		writeStreamed(w, result)
+	}
+	return result, err
+}
+
+// writeStreamed writes a complete text to a stream writer.
+func writeStreamed(w io.Writer, text string) {
+	_, _ = io.WriteString(w, text)
+	endStreamed(w, text)
+}
+
+// endStreamed ends streamed output with a newline, so whatever is printed next
+// starts on its own line.
+func endStreamed(w io.Writer, text string) {
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		_, _ = io.WriteString(w, "\n")
+	}
+}
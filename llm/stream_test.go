@@ -0,0 +1,149 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamingStub is a Client whose GenerateStream yields fixed chunks and whose
+// Generate returns a fixed result, recording which was used.
+type streamingStub struct {
+	chunks       []StreamChunk
+	startErr     error
+	generated    string
+	streaming    bool
+	generateUsed bool
+}
+
+func (c *streamingStub) Generate(context.Context, string) (string, error) {
+	c.generateUsed = true
+	return c.generated, nil
+}
+
+func (c *streamingStub) GenerateStream(context.Context, string) (<-chan StreamChunk, error) {
+	if c.startErr != nil {
+		return nil, c.startErr
+	}
+	ch := make(chan StreamChunk)
+	go func() {
+		defer close(ch)
+		for _, chunk := range c.chunks {
+			ch <- chunk
+		}
+	}()
+	return ch, nil
+}
+
+func (c *streamingStub) CountTokens(context.Context, string) (int, error) { return 1, nil }
+
+func (c *streamingStub) Close() {}
+
+func (c *streamingStub) Capabilities() ClientCapabilities {
+	return ClientCapabilities{SupportsStreaming: c.streaming, SupportsTokenCount: true}
+}
+
+func TestGenerateGlanceMarkdownStreams(t *testing.T) {
+	generate := func(t *testing.T, client *streamingStub) (string, string, error) {
+		t.Helper()
+		service, err := NewService(client, WithPromptTemplate("{{.Directory}}"))
+		require.NoError(t, err)
+		var out bytes.Buffer
+		summary, err := service.GenerateGlanceMarkdown(context.Background(), "pkg",
+			map[string]string{"a.go": "package pkg"}, "", WithStreamWriter(&out))
+		return summary, out.String(), err
+	}
+
+	t.Run("chunks are written in order and assembled", func(t *testing.T) {
+		client := &streamingStub{streaming: true, chunks: []StreamChunk{
+			{Text: "## Purpose\n"}, {Text: "Handles "}, {Text: "packages."}, {Done: true},
+		}}
+		summary, out, err := generate(t, client)
+		require.NoError(t, err)
+		assert.Equal(t, "## Purpose\nHandles packages.", summary)
+		assert.Equal(t, "## Purpose\nHandles packages.\n", out, "streamed output ends on its own line")
+		assert.False(t, client.generateUsed)
+	})
+
+	t.Run("a client without streaming writes the whole result", func(t *testing.T) {
+		client := &streamingStub{generated: "## Purpose\nWhole.\n"}
+		summary, out, err := generate(t, client)
+		require.NoError(t, err)
+		assert.Equal(t, "## Purpose\nWhole.\n", summary)
+		assert.Equal(t, summary, out)
+		assert.True(t, client.generateUsed)
+	})
+
+	t.Run("a stream that cannot start falls back to Generate", func(t *testing.T) {
+		client := &streamingStub{streaming: true, startErr: errors.New("no stream"), generated: "## Purpose\nFallback.\n"}
+		summary, out, err := generate(t, client)
+		require.NoError(t, err)
+		assert.Equal(t, "## Purpose\nFallback.\n", summary)
+		assert.Equal(t, summary, out)
+	})
+
+	t.Run("a stream failing before any text falls back to Generate", func(t *testing.T) {
+		client := &streamingStub{streaming: true, chunks: []StreamChunk{{Error: errors.New("reset")}}, generated: "## Purpose\nFallback.\n"}
+		summary, _, err := generate(t, client)
+		require.NoError(t, err)
+		assert.Equal(t, "## Purpose\nFallback.\n", summary)
+	})
+
+	t.Run("a stream failing midway falls back to Generate", func(t *testing.T) {
+		client := &streamingStub{streaming: true, chunks: []StreamChunk{{Text: "## Purpose\n"}, {Error: errors.New("reset")}}, generated: "## Purpose\nFallback.\n"}
+		summary, out, err := generate(t, client)
+		require.NoError(t, err)
+		assert.Equal(t, "## Purpose\nFallback.\n", summary)
+		assert.Equal(t, "## Purpose\n"+streamRestartNotice+"## Purpose\nFallback.\n", out)
+		assert.True(t, client.generateUsed)
+	})
+}
+
+func TestGenerateStreamedValidatesFallbackResponse(t *testing.T) {
+	valid := "## Purpose\nRegenerated without streaming, with the code fences intact.\n"
+	tier := &streamingStub{streaming: true, chunks: []StreamChunk{
+		{Text: "## Purpose\n```go\n"}, {Text: "func truncated() {"}, {Done: true},
+	}, generated: valid}
+	client, err := NewFallbackClient([]FallbackTier{{Name: "primary", Client: tier}}, 0,
+		WithResponseValidator(ValidateMarkdown))
+	require.NoError(t, err)
+
+	service, err := NewService(client, WithPromptTemplate("{{.Directory}}"))
+	require.NoError(t, err)
+	var out bytes.Buffer
+	summary, err := service.GenerateGlanceMarkdown(context.Background(), "pkg",
+		map[string]string{"a.go": "package pkg"}, "", WithStreamWriter(&out))
+
+	require.NoError(t, err)
+	assert.Equal(t, valid, summary, "malformed streamed markdown is replaced by a validated Generate")
+	assert.True(t, tier.generateUsed)
+	assert.Contains(t, out.String(), streamRestartNotice)
+	assert.True(t, strings.HasSuffix(out.String(), valid))
+}
+
+func TestFallbackClientGenerateStreamHoldsTierSlot(t *testing.T) {
+	tier := &streamingStub{streaming: true, chunks: []StreamChunk{{Text: "## Purpose\n"}, {Done: true}}}
+	client, err := NewFallbackClient([]FallbackTier{{Name: "primary", Client: tier, MaxConcurrent: 1}}, 0)
+	require.NoError(t, err)
+
+	first, err := client.GenerateStream(context.Background(), "prompt")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.GenerateStream(ctx, "prompt")
+	require.ErrorIs(t, err, context.DeadlineExceeded, "a second stream waits for the tier's only slot")
+
+	for range first {
+	}
+	second, err := client.GenerateStream(context.Background(), "prompt")
+	require.NoError(t, err, "draining the first stream releases its slot")
+	for range second {
+	}
+}
@@ -0,0 +1,143 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// Throttle is a backoff signal shared between concurrent callers. When one
+// caller is rate limited it records a "throttle until" time, and every caller
+// waits out that time before its next request instead of discovering the limit
+// on its own. It coordinates backoff without rate limiting steady traffic.
+// A Throttle is safe for concurrent use; the zero value is ready to use.
+type Throttle struct {
+	until atomic.Int64 // unix nanoseconds on backoffClock; zero when never throttled
+}
+
+// NewThrottle returns a Throttle that is not holding anyone back.
+func NewThrottle() *Throttle {
+	return &Throttle{}
+}
+
+// Wait blocks until the throttle window has passed, returning ctx.Err() early
+// if ctx is done first. It returns at once when no window is open.
+func (t *Throttle) Wait(ctx context.Context) error {
+	for {
+		remaining := t.Remaining()
+		if remaining <= 0 {
+			return nil
+		}
+		if err := sleepWithContext(ctx, remaining); err != nil {
+			return err
+		}
+		// Loop: another caller may have extended the window while we slept.
+	}
+}
+
+// Remaining returns how long callers must still wait, or zero when they may
+// proceed.
+func (t *Throttle) Remaining() time.Duration {
+	until := t.until.Load()
+	if until == 0 {
+		return 0
+	}
+	remaining := time.Duration(until - backoffClock.Now().UnixNano())
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// Observe holds every caller back for d from now. A window that already ends
+// later is kept, so concurrent reports never shorten the backoff.
+func (t *Throttle) Observe(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	next := backoffClock.Now().Add(d).UnixNano()
+	for {
+		current := t.until.Load()
+		if current >= next || t.until.CompareAndSwap(current, next) {
+			return
+		}
+	}
+}
+
+// RateLimitError reports that a provider rejected a request for exceeding its
+// rate limit (HTTP 429). RetryAfter is the delay the provider asked for, or
+// zero when it did not say.
+type RateLimitError struct {
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *RateLimitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// rateLimitDelay reports whether err is a rate-limit rejection and, if so, the
+// delay the provider asked for (zero when unspecified). It recognizes
+// RateLimitError and Gemini's 429 APIError with its RetryInfo detail.
+func rateLimitDelay(err error) (time.Duration, bool) {
+	var rateErr *RateLimitError
+	if errors.As(err, &rateErr) {
+		return rateErr.RetryAfter, true
+	}
+
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusTooManyRequests {
+		return geminiRetryDelay(apiErr.Details), true
+	}
+	var apiErrPtr *genai.APIError
+	if errors.As(err, &apiErrPtr) && apiErrPtr.Code == http.StatusTooManyRequests {
+		return geminiRetryDelay(apiErrPtr.Details), true
+	}
+	return 0, false
+}
+
+// geminiRetryDelay extracts the retryDelay (e.g. "30s") from a
+// google.rpc.RetryInfo error detail, or returns zero if there is none.
+func geminiRetryDelay(details []map[string]any) time.Duration {
+	for _, detail := range details {
+		if typ, _ := detail["@type"].(string); !strings.HasSuffix(typ, "google.rpc.RetryInfo") {
+			continue
+		}
+		raw, _ := detail["retryDelay"].(string)
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// parseRetryAfter parses an HTTP Retry-After header, given either as seconds
+// or as an HTTP date, returning zero when it is absent or malformed.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		if d := at.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,166 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+)
+
+func TestThrottleObserveAndWait(t *testing.T) {
+	fake := useFakeClock(t)
+	throttle := NewThrottle()
+
+	assert.Zero(t, throttle.Remaining(), "a new throttle holds no one back")
+	require.NoError(t, throttle.Wait(context.Background()))
+	assert.Empty(t, fake.Sleeps(), "waiting on an open throttle does not sleep")
+
+	throttle.Observe(5 * time.Second)
+	throttle.Observe(2 * time.Second)
+	assert.Equal(t, 5*time.Second, throttle.Remaining(), "a shorter report never shortens the window")
+
+	require.NoError(t, throttle.Wait(context.Background()))
+	assert.Equal(t, []time.Duration{5 * time.Second}, fake.Sleeps())
+	assert.Zero(t, throttle.Remaining())
+
+	throttle.Observe(time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, throttle.Wait(ctx), context.Canceled)
+}
+
+func TestRateLimitDelay(t *testing.T) {
+	delay, limited := rateLimitDelay(fmt.Errorf("wrapped: %w", &RateLimitError{RetryAfter: 3 * time.Second, Err: errors.New("429")}))
+	assert.True(t, limited)
+	assert.Equal(t, 3*time.Second, delay)
+
+	geminiErr := genai.APIError{
+		Code: http.StatusTooManyRequests,
+		Details: []map[string]any{
+			{"@type": "type.googleapis.com/google.rpc.QuotaFailure"},
+			{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "17s"},
+		},
+	}
+	delay, limited = rateLimitDelay(fmt.Errorf("generate: %w", geminiErr))
+	assert.True(t, limited)
+	assert.Equal(t, 17*time.Second, delay)
+
+	delay, limited = rateLimitDelay(genai.APIError{Code: http.StatusTooManyRequests})
+	assert.True(t, limited, "a 429 without RetryInfo is still a rate limit")
+	assert.Zero(t, delay)
+
+	_, limited = rateLimitDelay(genai.APIError{Code: http.StatusInternalServerError})
+	assert.False(t, limited)
+	_, limited = rateLimitDelay(errors.New("boom"))
+	assert.False(t, limited)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 30*time.Second, parseRetryAfter("30", now))
+	assert.Equal(t, 90*time.Second, parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now))
+	assert.Zero(t, parseRetryAfter("", now))
+	assert.Zero(t, parseRetryAfter("-4", now))
+	assert.Zero(t, parseRetryAfter("soon", now))
+	assert.Zero(t, parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now), "a date in the past asks for no delay")
+}
+
+func TestOpenRouterClientReportsRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "12")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	clientIface, err := NewOpenRouterClient("test-key", WithModelName("x-ai/grok-4.1-fast"))
+	require.NoError(t, err)
+	client := clientIface.(*OpenRouterClient)
+	client.baseURL = server.URL
+
+	_, genErr := client.Generate(context.Background(), "prompt")
+	require.Error(t, genErr)
+
+	delay, limited := rateLimitDelay(genErr)
+	assert.True(t, limited)
+	assert.Equal(t, 12*time.Second, delay)
+}
+
+// throttledProbe is a Client whose first Generate call is rate limited. It
+// records when every later call starts.
+type throttledProbe struct {
+	retryAfter time.Duration
+	calls      atomic.Int32
+	limitedAt  chan time.Time
+
+	mu     sync.Mutex
+	starts []time.Time
+}
+
+func (p *throttledProbe) Generate(context.Context, string) (string, error) {
+	now := time.Now()
+	if p.calls.Add(1) == 1 {
+		p.limitedAt <- now
+		return "", &RateLimitError{RetryAfter: p.retryAfter, Err: errors.New("status 429")}
+	}
+	p.mu.Lock()
+	p.starts = append(p.starts, now)
+	p.mu.Unlock()
+	return "ok", nil
+}
+
+func (p *throttledProbe) GenerateStream(context.Context, string) (<-chan StreamChunk, error) {
+	return nil, errors.New("not supported")
+}
+
+func (p *throttledProbe) CountTokens(context.Context, string) (int, error) { return 0, nil }
+
+func (p *throttledProbe) Close() {}
+
+func TestFallbackClientSharedThrottle(t *testing.T) {
+	const workers = 6
+	retryAfter := 150 * time.Millisecond
+	probe := &throttledProbe{retryAfter: retryAfter, limitedAt: make(chan time.Time, 1)}
+
+	client, err := NewFallbackClientWithBackoff(
+		[]FallbackTier{{Name: "only", Client: probe, Throttle: NewThrottle()}},
+		1,
+		time.Millisecond,
+		time.Millisecond,
+	)
+	require.NoError(t, err)
+
+	// The first worker hits the rate limit; the rest start only afterwards, so
+	// each must learn of the throttle from the shared signal, not its own 429.
+	var wg sync.WaitGroup
+	generate := func() {
+		defer wg.Done()
+		out, genErr := client.Generate(context.Background(), "prompt")
+		assert.NoError(t, genErr)
+		assert.Equal(t, "ok", out)
+	}
+	wg.Add(1)
+	go generate()
+	limitedAt := <-probe.limitedAt
+	for i := 1; i < workers; i++ {
+		wg.Add(1)
+		go generate()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(workers+1), probe.calls.Load(), "only the first call was rate limited")
+	require.Len(t, probe.starts, workers)
+	for _, start := range probe.starts {
+		assert.GreaterOrEqual(t, start.Sub(limitedAt), retryAfter,
+			"every worker waits out the Retry-After window before its next call")
+	}
+}
@@ -0,0 +1,117 @@
+// Package llm provides abstractions and implementations for interacting with
+// Large Language Model APIs in the glance application.
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+
+	customerrors "glance/errors"
+)
+
+// imageDescriptionPrompt asks a vision model for a short, factual description
+// that can stand in for the image in a text-only summary prompt.
+const imageDescriptionPrompt = `describe this image from a source code repository in at most three sentences.
+state what it shows (e.g. an architecture diagram, a UI screenshot, a logo) and any text, labels, or components visible in it.
+do not speculate beyond what is visible.`
+
+// VisionDescriber describes images in text, so they can inform a summary
+// without making the main generation multimodal.
+type VisionDescriber interface {
+	// DescribeImage returns a short description of one image.
+	DescribeImage(ctx context.Context, name, mimeType string, data []byte) (string, error)
+
+	// Close releases any resources held by the describer.
+	Close()
+}
+
+// NewGeminiVisionDescriber creates a VisionDescriber backed by a Gemini model.
+// It accepts the same options as NewGeminiClient.
+func NewGeminiVisionDescriber(apiKey string, options ...ClientOption) (VisionDescriber, error) {
+	return newGeminiClient(apiKey, options...)
+}
+
+// DescribeImage implements VisionDescriber for GeminiClient by sending the image
+// inline alongside a description prompt. It makes a single attempt.
+//
+// Parameters:
+//   - ctx: The context for the request
+//   - name: The image's file name, given to the model as context
+//   - mimeType: The image's MIME type, e.g. "image/png"
+//   - data: The image content
+//
+// Returns:
+//   - The description
+//   - An error if the request fails or no description is returned
+func (c *GeminiClient) DescribeImage(ctx context.Context, name, mimeType string, data []byte) (string, error) {
+	if c.client == nil || c.model == "" {
+		return "", customerrors.NewValidationError("client is not properly initialized", nil).
+			WithCode("GENAI-026").
+			WithSuggestion("Ensure the client was created with a valid API key and model name")
+	}
+
+	if c.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(c.options.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	contents := []*genai.Content{genai.NewContentFromParts([]*genai.Part{
+		genai.NewPartFromText(imageDescriptionPrompt + "\nfile name: " + name),
+		genai.NewPartFromBytes(data, mimeType),
+	}, genai.RoleUser)}
+
+	genConfig := &genai.GenerateContentConfig{}
+	if c.options.MaxOutputTokens > 0 {
+		genConfig.MaxOutputTokens = c.options.MaxOutputTokens
+	}
+
+	resp, err := c.client.Models.GenerateContent(ctx, c.model, contents, genConfig)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return "", customerrors.WrapAPIError(err, "image description timed out").
+				WithCode("GENAI-027").
+				WithSuggestion("Consider increasing the timeout value")
+		}
+		return "", customerrors.WrapAPIError(err, fmt.Sprintf("failed to describe image %s", name)).
+			WithCode("GENAI-027")
+	}
+
+	var description string
+	if resp != nil {
+		description = strings.TrimSpace(resp.Text())
+	}
+	if description == "" {
+		return "", customerrors.NewAPIError(fmt.Sprintf("no description returned for image %s", name), nil).
+			WithCode("GENAI-028")
+	}
+	return description, nil
+}
+
+// FormatImageDescriptions formats image descriptions for the ImageDescriptions
+// prompt field, in name order, using the layout of FormatFileContents.
+//
+// Parameters:
+//   - descriptions: A map of image file names to their descriptions
+//
+// Returns:
+//   - The formatted descriptions, or "" when there are none
+func FormatImageDescriptions(descriptions map[string]string) string {
+	names := make([]string, 0, len(descriptions))
+	for name := range descriptions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var builder strings.Builder
+	for _, name := range names {
+		builder.WriteString(fmt.Sprintf("=== image: %s ===\n%s\n\n", name, descriptions[name]))
+	}
+	return builder.String()
+}
@@ -0,0 +1,69 @@
+package llm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeminiDescribeImage(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"candidates": []map[string]any{{
+				"content":      map[string]any{"role": "model", "parts": []map[string]any{{"text": " An architecture diagram. \n"}}},
+				"finishReason": "STOP",
+			}},
+		})
+	}))
+	defer server.Close()
+
+	describer, err := NewGeminiVisionDescriber("test-key", WithBaseURL(server.URL))
+	require.NoError(t, err)
+	defer describer.Close()
+
+	got, err := describer.DescribeImage(context.Background(), "arch.png", "image/png", []byte("PNGDATA"))
+	require.NoError(t, err)
+	assert.Equal(t, "An architecture diagram.", got)
+
+	raw, err := json.Marshal(body)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), `"mimeType":"image/png"`)
+	assert.Contains(t, string(raw), base64.StdEncoding.EncodeToString([]byte("PNGDATA")), "the image is sent inline")
+	assert.Contains(t, string(raw), "file name: arch.png")
+}
+
+func TestGeminiDescribeImageEmpty(t *testing.T) {
+	client, _ := newFinishReasonGeminiClient(t, "STOP", "  ")
+
+	_, err := client.DescribeImage(context.Background(), "logo.png", "image/png", []byte("x"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no description returned for image logo.png")
+}
+
+func TestFormatImageDescriptions(t *testing.T) {
+	assert.Empty(t, FormatImageDescriptions(nil))
+	assert.Equal(t,
+		"=== image: a.png ===\nFirst.\n\n=== image: b.png ===\nSecond.\n\n",
+		FormatImageDescriptions(map[string]string{"b.png": "Second.", "a.png": "First."}))
+}
+
+func TestDefaultTemplateImageDescriptions(t *testing.T) {
+	data := BuildPromptData("pkg", "", map[string]string{"main.go": "package main"})
+	prompt, err := GeneratePrompt(data, DefaultTemplate())
+	require.NoError(t, err)
+	assert.NotContains(t, prompt, "image descriptions", "the section is omitted without descriptions")
+
+	WithImageDescriptions("=== image: a.png ===\nA diagram.\n\n")(data)
+	prompt, err = GeneratePrompt(data, DefaultTemplate())
+	require.NoError(t, err)
+	assert.Contains(t, prompt, "image descriptions (generated by a vision model from the directory's images):\n=== image: a.png ===\nA diagram.")
+}
@@ -0,0 +1,125 @@
+// Package metrics collects run statistics and exports them in the Prometheus
+// textfile exposition format for CI observability.
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"glance/filesystem"
+)
+
+// Metric names written to the textfile.
+const (
+	DirectoriesTotal   = "glance_directories_total"
+	DirectoriesFailed  = "glance_directories_failed"
+	TokensTotal        = "glance_tokens_total"
+	RunDurationSeconds = "glance_run_duration_seconds"
+)
+
+// TextfileMode is the permission of the written metrics file. Unlike glance's
+// other output it is world-readable, because textfile collectors such as the
+// node_exporter usually run as a different user. It holds only counts and a
+// duration, no paths or source content.
+const TextfileMode = 0o644
+
+// Collector accumulates run metrics. It is safe for concurrent use.
+type Collector struct {
+	mu                sync.Mutex
+	directoriesTotal  int
+	directoriesFailed int
+	tokensTotal       int64
+	runDuration       time.Duration
+}
+
+// NewCollector creates an empty metrics collector.
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// RecordDirectory records the outcome of processing a single directory.
+func (c *Collector) RecordDirectory(success bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.directoriesTotal++
+	if !success {
+		c.directoriesFailed++
+	}
+}
+
+// AddTokens adds n prompt tokens to the running total.
+func (c *Collector) AddTokens(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokensTotal += int64(n)
+}
+
+// SetRunDuration records the wall-clock duration of the run.
+func (c *Collector) SetRunDuration(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.runDuration = d
+}
+
+// WriteTo writes the collected metrics to w in the Prometheus exposition format.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var buf bytes.Buffer
+	writeMetric(&buf, DirectoriesTotal, "counter", "Directories considered in the run.", float64(c.directoriesTotal))
+	writeMetric(&buf, DirectoriesFailed, "counter", "Directories that failed to process.", float64(c.directoriesFailed))
+	writeMetric(&buf, TokensTotal, "counter", "Prompt tokens counted across LLM requests.", float64(c.tokensTotal))
+	writeMetric(&buf, RunDurationSeconds, "gauge", "Wall-clock duration of the run in seconds.", c.runDuration.Seconds())
+
+	return buf.WriteTo(w)
+}
+
+// WriteTextfile writes the metrics to path, which must lie within baseDir.
+// The file is written to a temporary sibling and renamed into place so that
+// textfile collectors never observe a partially-written file.
+func (c *Collector) WriteTextfile(path, baseDir string) error {
+	if baseDir == "" {
+		return errors.New("baseDir cannot be empty for validation")
+	}
+
+	validPath, err := filesystem.ValidateFilePath(path, baseDir, false, false)
+	if err != nil {
+		return fmt.Errorf("invalid metrics file path: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := c.WriteTo(&buf); err != nil {
+		return fmt.Errorf("failed to render metrics: %w", err)
+	}
+
+	tmpPath := filepath.Join(filepath.Dir(validPath), "."+filepath.Base(validPath)+".tmp")
+	// #nosec G306 -- Path validated; TextfileMode (0644) is deliberate so collectors
+	// running as another user can read the file, which holds only aggregate counts
+	if err := os.WriteFile(tmpPath, buf.Bytes(), TextfileMode); err != nil {
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+	// WriteFile leaves a stale temporary file's mode alone and is subject to the umask
+	if err := os.Chmod(tmpPath, TextfileMode); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to set metrics file mode: %w", err)
+	}
+	if err := os.Rename(tmpPath, validPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to move metrics file into place: %w", err)
+	}
+	return nil
+}
+
+// writeMetric appends a single metric with HELP and TYPE lines.
+func writeMetric(buf *bytes.Buffer, name, metricType, help string, value float64) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buf, "# TYPE %s %s\n", name, metricType)
+	fmt.Fprintf(buf, "%s %g\n", name, value)
+}
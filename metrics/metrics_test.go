@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseExposition parses Prometheus exposition text into a name->value map,
+// failing the test on any malformed line.
+func parseExposition(t *testing.T, text string) map[string]float64 {
+	t.Helper()
+	values := make(map[string]float64)
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		if strings.HasPrefix(line, "#") {
+			fields := strings.Fields(line)
+			require.GreaterOrEqual(t, len(fields), 3, "malformed comment line: %q", line)
+			require.Contains(t, []string{"HELP", "TYPE"}, fields[1])
+			continue
+		}
+		fields := strings.Fields(line)
+		require.Len(t, fields, 2, "malformed sample line: %q", line)
+		v, err := strconv.ParseFloat(fields[1], 64)
+		require.NoError(t, err, "malformed sample value: %q", line)
+		values[fields[0]] = v
+	}
+	return values
+}
+
+func TestCollectorWriteTo(t *testing.T) {
+	c := NewCollector()
+	c.RecordDirectory(true)
+	c.RecordDirectory(true)
+	c.RecordDirectory(false)
+	c.AddTokens(120)
+	c.AddTokens(30)
+	c.SetRunDuration(1500 * time.Millisecond)
+
+	var buf bytes.Buffer
+	_, err := c.WriteTo(&buf)
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "# TYPE glance_directories_total counter")
+	assert.Contains(t, buf.String(), "# TYPE glance_run_duration_seconds gauge")
+
+	values := parseExposition(t, buf.String())
+	assert.Equal(t, map[string]float64{
+		DirectoriesTotal:   3,
+		DirectoriesFailed:  1,
+		TokensTotal:        150,
+		RunDurationSeconds: 1.5,
+	}, values)
+}
+
+func TestCollectorConcurrentUpdates(t *testing.T) {
+	c := NewCollector()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.RecordDirectory(true)
+			c.AddTokens(2)
+		}()
+	}
+	wg.Wait()
+
+	var buf bytes.Buffer
+	_, err := c.WriteTo(&buf)
+	require.NoError(t, err)
+	values := parseExposition(t, buf.String())
+	assert.Equal(t, float64(50), values[DirectoriesTotal])
+	assert.Equal(t, float64(100), values[TokensTotal])
+}
+
+func TestWriteTextfile(t *testing.T) {
+	base := t.TempDir()
+	c := NewCollector()
+	c.RecordDirectory(true)
+
+	t.Run("Writes within base directory", func(t *testing.T) {
+		path := filepath.Join(base, "glance.prom")
+		require.NoError(t, c.WriteTextfile(path, base))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, float64(1), parseExposition(t, string(data))[DirectoriesTotal])
+
+		_, err = os.Stat(filepath.Join(base, ".glance.prom.tmp"))
+		assert.True(t, os.IsNotExist(err), "temporary file should be renamed away")
+
+		if runtime.GOOS != "windows" {
+			info, err := os.Stat(path)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(TextfileMode), info.Mode().Perm(), "collectors running as another user can read it")
+		}
+	})
+
+	t.Run("Rejects path outside base directory", func(t *testing.T) {
+		outside := filepath.Join(filepath.Dir(base), "escape.prom")
+		assert.Error(t, c.WriteTextfile(outside, base))
+	})
+
+	t.Run("Rejects empty base directory", func(t *testing.T) {
+		assert.Error(t, c.WriteTextfile(filepath.Join(base, "x.prom"), ""))
+	})
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+	"glance/metrics"
+)
+
+// TestExportMetricsForMockedRun verifies the metrics textfile reflects a mocked run.
+func TestExportMetricsForMockedRun(t *testing.T) {
+	root := t.TempDir()
+	good := filepath.Join(root, "good")
+	bad := filepath.Join(root, "bad")
+	require.NoError(t, os.MkdirAll(good, 0755))
+	require.NoError(t, os.MkdirAll(bad, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(good, "a.go"), []byte("package good\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(bad, "b.go"), []byte("package bad\n"), 0600))
+
+	mockLLMClient := new(mocks.LLMClient)
+	mockClient := &MockClient{LLMClient: mockLLMClient}
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(25, nil)
+	mockLLMClient.On("Generate", mock.Anything, mock.MatchedBy(func(p string) bool {
+		return strings.Contains(p, "package bad")
+	})).Return("", assert.AnError)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).Return("# summary\n", nil)
+
+	service, err := llm.NewService(mockClient, llm.WithPromptTemplate("{{.FileContents}}"))
+	require.NoError(t, err)
+
+	dirsList, ignoreChains, err := filesystem.ListDirsWithIgnores(root)
+	require.NoError(t, err)
+	reverseSlice(dirsList)
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root)
+	results, _ := processDirectories(context.Background(), dirsList, ignoreChains, cfg, service, io.Discard)
+
+	outDir := t.TempDir()
+	t.Chdir(outDir)
+	metricsPath := filepath.Join(outDir, "glance.prom")
+	require.NoError(t, exportMetrics(metricsPath, results, service.TokensCounted(), 2*time.Second))
+
+	data, err := os.ReadFile(metricsPath)
+	require.NoError(t, err)
+	text := string(data)
+
+	// Root summarizes the successful child only; two LLM calls succeed, one fails.
+	assert.Contains(t, text, metrics.DirectoriesTotal+" 3\n")
+	assert.Contains(t, text, metrics.DirectoriesFailed+" 1\n")
+	assert.Contains(t, text, metrics.TokensTotal+" 75\n")
+	assert.Contains(t, text, metrics.RunDurationSeconds+" 2\n")
+}
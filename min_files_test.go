@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// writeGoFiles creates n small Go files in dir.
+func writeGoFiles(t *testing.T, dir string, n int) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	for i := 0; i < n; i++ {
+		name := filepath.Join(dir, fmt.Sprintf("file%d.go", i))
+		require.NoError(t, os.WriteFile(name, []byte("package pkg\n"), 0600))
+	}
+}
+
+func TestMinFiles(t *testing.T) {
+	newService := func(t *testing.T) (*mocks.LLMClient, *llm.Service) {
+		mockLLMClient := new(mocks.LLMClient)
+		mockLLMClient.On("Generate", mock.Anything, mock.Anything).Return("# summary\n", nil).Maybe()
+		mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+		service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient})
+		require.NoError(t, err)
+		return mockLLMClient, service
+	}
+
+	t.Run("Below the threshold writes a stub without calling the LLM", func(t *testing.T) {
+		dir := t.TempDir()
+		writeGoFiles(t, dir, 2)
+		mockLLMClient, service := newService(t)
+		cfg := config.NewDefaultConfig().WithTargetDir(dir).WithMinFiles(3)
+
+		r := processDirectory(context.Background(), dir, true, nil, cfg, service)
+
+		require.True(t, r.success, "processDirectory should succeed: %v", r.err)
+		assert.Equal(t, 1, r.attempts, "a stub still counts as processed")
+		mockLLMClient.AssertNotCalled(t, "Generate", mock.Anything, mock.Anything)
+		content, err := os.ReadFile(filepath.Join(dir, filesystem.GlanceFilename))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "Too few files to summarize: `file0.go`, `file1.go`.")
+	})
+
+	t.Run("At the threshold calls the LLM", func(t *testing.T) {
+		dir := t.TempDir()
+		writeGoFiles(t, dir, 3)
+		mockLLMClient, service := newService(t)
+		cfg := config.NewDefaultConfig().WithTargetDir(dir).WithMinFiles(3)
+
+		r := processDirectory(context.Background(), dir, true, nil, cfg, service)
+
+		require.True(t, r.success, "processDirectory should succeed: %v", r.err)
+		mockLLMClient.AssertNumberOfCalls(t, "Generate", 1)
+		content, err := os.ReadFile(filepath.Join(dir, filesystem.GlanceFilename))
+		require.NoError(t, err)
+		assert.Equal(t, "# summary\n", string(content))
+	})
+
+	t.Run("Child summaries qualify a directory below the threshold", func(t *testing.T) {
+		dir := t.TempDir()
+		writeGoFiles(t, dir, 1)
+		child := filepath.Join(dir, "child")
+		require.NoError(t, os.Mkdir(child, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(child, filesystem.GlanceFilename), []byte("# child\n"), 0600))
+		mockLLMClient, service := newService(t)
+		cfg := config.NewDefaultConfig().WithTargetDir(dir).WithMinFiles(3)
+
+		r := processDirectory(context.Background(), dir, true, nil, cfg, service)
+
+		require.True(t, r.success, "processDirectory should succeed: %v", r.err)
+		mockLLMClient.AssertNumberOfCalls(t, "Generate", 1)
+	})
+
+	t.Run("No stub is written with --no-empty-stubs", func(t *testing.T) {
+		dir := t.TempDir()
+		writeGoFiles(t, dir, 1)
+		mockLLMClient, service := newService(t)
+		cfg := config.NewDefaultConfig().WithTargetDir(dir).WithMinFiles(2).WithNoEmptyStubs(true)
+
+		r := processDirectory(context.Background(), dir, true, nil, cfg, service)
+
+		require.True(t, r.success, "processDirectory should succeed: %v", r.err)
+		assert.Zero(t, r.attempts, "nothing written, so parents are not marked for regeneration")
+		mockLLMClient.AssertNotCalled(t, "Generate", mock.Anything, mock.Anything)
+		assert.NoFileExists(t, filepath.Join(dir, filesystem.GlanceFilename))
+	})
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+
+	"glance/config"
+)
+
+// checkChainAllowed verifies, before any client is created, that every model
+// in the failover chain is on the configured allowlist. The OpenRouter tier is
+// checked only when it will be used.
+func checkChainAllowed(cfg *config.Config, withOpenRouter bool) error {
+	type tierModel struct{ provider, model string }
+	models := []tierModel{
+		{config.ProviderGemini, primaryModel},
+		{config.ProviderGemini, stableModel},
+	}
+	if withOpenRouter {
+		models = append(models, tierModel{config.ProviderOpenRouter, openRouterModel})
+		for _, fallback := range cfg.OpenRouterModels {
+			models = append(models, tierModel{config.ProviderOpenRouter, fallback})
+		}
+	}
+
+	for _, m := range models {
+		if err := cfg.CheckModelAllowed(m.provider, m.model); err != nil {
+			return fmt.Errorf("model not permitted: %w", err)
+		}
+	}
+	return nil
+}
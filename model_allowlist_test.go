@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+)
+
+func TestCreateLLMServiceAllowedModels(t *testing.T) {
+	t.Setenv("OPENROUTER_API_KEY", "")
+	base := config.NewDefaultConfig().WithAPIKey("test-api-key").WithNoCache(true)
+
+	t.Run("allowed chain proceeds", func(t *testing.T) {
+		cfg := base.WithAllowedModels([]string{"gemini:" + primaryModel, "gemini:" + stableModel})
+		client, service, err := createLLMService(cfg)
+		require.NoError(t, err)
+		require.NotNil(t, service)
+		client.Close()
+	})
+
+	t.Run("disallowed model fails before any client is created", func(t *testing.T) {
+		cfg := base.WithAllowedModels([]string{"gemini:" + stableModel})
+		client, service, err := createLLMService(cfg)
+		require.Error(t, err)
+		assert.Nil(t, client)
+		assert.Nil(t, service)
+		assert.Contains(t, err.Error(), "gemini:"+primaryModel)
+	})
+
+	t.Run("OpenRouter tier is checked only when it is used", func(t *testing.T) {
+		cfg := base.WithAllowedModels([]string{"gemini:" + primaryModel, "gemini:" + stableModel})
+		assert.NoError(t, checkChainAllowed(cfg, false))
+		assert.ErrorContains(t, checkChainAllowed(cfg, true), "openrouter:"+openRouterModel)
+	})
+
+	t.Run("OpenRouter fallback models are checked too", func(t *testing.T) {
+		allowed := []string{"gemini:" + primaryModel, "gemini:" + stableModel, "openrouter:" + openRouterModel}
+		cfg := base.WithAllowedModels(allowed).WithOpenRouterModels([]string{"openai/gpt-4o-mini"})
+		assert.ErrorContains(t, checkChainAllowed(cfg, true), "openrouter:openai/gpt-4o-mini")
+		assert.NoError(t, checkChainAllowed(cfg.WithAllowedModels(append(allowed, "openrouter:openai/gpt-4o-mini")), true))
+	})
+}
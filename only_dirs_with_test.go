@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestOnlyDirsWithFilter verifies that --only-dirs-with skips directories lacking the
+// listed file types, unless a qualifying descendant regenerated beneath them, in
+// which case bubble-up still summarizes them.
+func TestOnlyDirsWithFilter(t *testing.T) {
+	root := t.TempDir()
+	code := filepath.Join(root, "code")
+	docs := filepath.Join(root, "docs")
+	assets := filepath.Join(root, "assets")
+	nested := filepath.Join(assets, "scripts")
+	for _, d := range []string{code, docs, assets, nested} {
+		require.NoError(t, os.MkdirAll(d, 0755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(code, "lib.py"), []byte("print('hi')\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(docs, "guide.txt"), []byte("guide\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(assets, "logo.txt"), []byte("logo\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(nested, "run.go"), []byte("package scripts\n"), 0600))
+
+	mockLLMClient := new(mocks.LLMClient)
+	mockClient := &MockClient{LLMClient: mockLLMClient}
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).Return("# summary\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(mockClient)
+	require.NoError(t, err)
+
+	dirsList, ignoreChains, err := filesystem.ListDirsWithIgnores(root)
+	require.NoError(t, err)
+	reverseSlice(dirsList)
+
+	cfg := config.NewDefaultConfig().
+		WithTargetDir(root).
+		WithOnlyDirsWith([]string{".go", ".py"})
+
+	results, needsRegen := processDirectories(context.Background(), dirsList, ignoreChains, cfg, service, io.Discard)
+
+	assert.Len(t, results, 5, "every scanned directory should have a result")
+	for _, r := range results {
+		assert.True(t, r.success, "directory %s should not fail", r.dir)
+	}
+
+	assert.FileExists(t, filepath.Join(root, filesystem.GlanceFilename))
+	assert.FileExists(t, filepath.Join(code, filesystem.GlanceFilename))
+	assert.FileExists(t, filepath.Join(nested, filesystem.GlanceFilename))
+	assert.NoFileExists(t, filepath.Join(docs, filesystem.GlanceFilename),
+		"non-qualifying directory must not get a summary or stub")
+	assert.FileExists(t, filepath.Join(assets, filesystem.GlanceFilename),
+		"a non-qualifying parent of a regenerated qualifying child is still summarized")
+
+	assert.True(t, needsRegen[assets], "bubble-up should reach the non-qualifying parent")
+	mockLLMClient.AssertNumberOfCalls(t, "Generate", 4)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestOnly verifies that --only processes just one subtree while .gitignore
+// rules from directories above it still apply.
+func TestOnly(t *testing.T) {
+	root := t.TempDir()
+	parent := filepath.Join(root, "a")
+	subtree := filepath.Join(parent, "b")
+	child := filepath.Join(subtree, "c")
+	sibling := filepath.Join(root, "z")
+	require.NoError(t, os.MkdirAll(child, 0755))
+	require.NoError(t, os.MkdirAll(sibling, 0755))
+	for _, d := range []string{root, parent, subtree, child, sibling} {
+		require.NoError(t, os.WriteFile(filepath.Join(d, "main.go"), []byte("package "+filepath.Base(d)+"\n"), 0600))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("*.log\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(subtree, "debug.log"), []byte("SECRET LOG LINE"), 0600))
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithOnly(subtree).WithForce(true)
+
+	dirs, ignoreChains, err := scanDirectories(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{child, subtree}, dirs, "only the subtree, deepest first")
+
+	var prompts []string
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { prompts = append(prompts, args.String(1)) }).
+		Return("# summary\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+		llm.WithPromptTemplate("dir {{.Directory}}\n{{.FileContents}}"))
+	require.NoError(t, err)
+
+	results, needsRegen := processDirectories(context.Background(), dirs, ignoreChains, cfg, service, io.Discard)
+	require.Len(t, results, 2)
+	for _, r := range results {
+		assert.True(t, r.success, "processing %s failed: %v", r.dir, r.err)
+	}
+	assert.Equal(t, map[string]bool{}, needsRegen, "regeneration does not bubble up past the subtree root")
+
+	require.Len(t, prompts, 2)
+	assert.Contains(t, prompts[1], "dir "+filepath.Join("a", "b")+"\n", "prompts keep paths relative to the target directory")
+	assert.NotContains(t, prompts[1], "SECRET LOG LINE", "the root .gitignore applies inside the subtree")
+
+	for _, d := range []string{child, subtree} {
+		assert.FileExists(t, filepath.Join(d, filesystem.GlanceFilename))
+	}
+	for _, d := range []string{root, parent, sibling} {
+		assert.NoFileExists(t, filepath.Join(d, filesystem.GlanceFilename))
+	}
+}
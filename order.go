@@ -0,0 +1,54 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/sirupsen/logrus"
+
+	"glance/config"
+	"glance/filesystem"
+)
+
+// orderDirectories reorders dirs, which must already list every child before its
+// parent, according to order (one of the config.Order* values).
+//
+// config.OrderDepth keeps the given order. The size orders group directories by
+// height (see dirHeights), that is their distance from the deepest directory
+// beneath them, and process lower groups first. No directory shares a group with
+// its ancestors, so children still finish before their parents and regeneration
+// still bubbles up. Within a group, directories are sorted by
+// filesystem.DirInputSize (applying opts), either smallest or largest first, and
+// ties keep their original order.
+func orderDirectories(dirs []string, ignoreChains map[string]filesystem.IgnoreChain, order string, opts ...filesystem.IgnoreOption) []string {
+	if order != config.OrderSizeAsc && order != config.OrderSizeDesc {
+		return dirs
+	}
+
+	height := dirHeights(dirs)
+
+	size := make(map[string]int64, len(dirs))
+	for _, d := range dirs {
+		n, err := filesystem.DirInputSize(d, ignoreChains[d], opts...)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"directory": d,
+				"error":     err,
+			}).Debug("Couldn't estimate directory size for ordering")
+		}
+		size[d] = n
+	}
+
+	ordered := make([]string, len(dirs))
+	copy(ordered, dirs)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if height[a] != height[b] {
+			return height[a] < height[b]
+		}
+		if order == config.OrderSizeDesc {
+			return size[a] > size[b]
+		}
+		return size[a] < size[b]
+	})
+	return ordered
+}
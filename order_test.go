@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+)
+
+// TestOrderDirectories verifies size ordering within dependency levels.
+func TestOrderDirectories(t *testing.T) {
+	root := t.TempDir()
+	sizes := map[string]int{
+		".":                0,
+		"big":              500,
+		"big/leaf":         300,
+		"small":            5,
+		"mid":              100,
+		"mid/deep":         2000,
+		"mid/deep/deeper":  1,
+		"mid/deep/.hidden": 0, // hidden directories are never scanned
+	}
+	for rel, n := range sizes {
+		dir := filepath.Join(root, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "data.txt"), make([]byte, n), 0600))
+	}
+
+	dirs, ignoreChains, err := filesystem.ListDirsWithIgnores(root)
+	require.NoError(t, err)
+	reverseSlice(dirs)
+
+	rel := func(ordered []string) []string {
+		out := make([]string, len(ordered))
+		for i, d := range ordered {
+			out[i] = filepath.ToSlash(displayDir(root, d))
+		}
+		return out
+	}
+
+	t.Run("depth keeps the scan order", func(t *testing.T) {
+		assert.Equal(t, dirs, orderDirectories(dirs, ignoreChains, config.OrderDepth))
+	})
+
+	t.Run("size-asc", func(t *testing.T) {
+		ordered := orderDirectories(dirs, ignoreChains, config.OrderSizeAsc)
+		assert.Equal(t, []string{
+			"mid/deep/deeper", "small", "big/leaf", // leaves
+			"big", "mid/deep",
+			"mid",
+			".",
+		}, rel(ordered))
+		assertChildrenFirst(t, ordered)
+	})
+
+	t.Run("size-desc", func(t *testing.T) {
+		ordered := orderDirectories(dirs, ignoreChains, config.OrderSizeDesc)
+		assert.Equal(t, []string{
+			"big/leaf", "small", "mid/deep/deeper",
+			"mid/deep", "big",
+			"mid",
+			".",
+		}, rel(ordered))
+		assertChildrenFirst(t, ordered)
+	})
+
+	t.Run("input is not modified", func(t *testing.T) {
+		before := append([]string(nil), dirs...)
+		_ = orderDirectories(dirs, ignoreChains, config.OrderSizeDesc)
+		assert.Equal(t, before, dirs)
+	})
+}
+
+// assertChildrenFirst fails if any directory appears before one of its descendants.
+func assertChildrenFirst(t *testing.T, ordered []string) {
+	t.Helper()
+	for i, parent := range ordered {
+		for _, later := range ordered[i+1:] {
+			assert.False(t, strings.HasPrefix(later, parent+string(filepath.Separator)),
+				"%s is processed before its descendant %s", parent, later)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+// dirOutcome classifies how a directory finished, so reporting does not have to
+// infer it from success and attempts.
+type dirOutcome int
+
+const (
+	// outcomeFailed is the zero value, so a result abandoned on an early error
+	// reads as failed without further bookkeeping
+	outcomeFailed dirOutcome = iota
+
+	// outcomeGenerated means the LLM summarized the directory and glance.md was written
+	outcomeGenerated
+
+	// outcomeStub means a minimal stub was written without calling the LLM
+	outcomeStub
+
+	// outcomeSkipped means the directory was deliberately left alone: it was up to
+	// date, did not qualify, or was cut off by the LLM budget or --max-runtime
+	outcomeSkipped
+)
+
+// String returns the outcome's name as used in logs and the event stream.
+func (o dirOutcome) String() string {
+	switch o {
+	case outcomeGenerated:
+		return "generated"
+	case outcomeStub:
+		return "stub"
+	case outcomeSkipped:
+		return "skipped"
+	default:
+		return "failed"
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+func TestProcessDirectoryOutcome(t *testing.T) {
+	newService := func(t *testing.T, err error) *llm.Service {
+		mockLLMClient := new(mocks.LLMClient)
+		mockLLMClient.On("Generate", mock.Anything, mock.Anything).Return("# summary\n", err).Maybe()
+		mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+		service, serviceErr := llm.NewService(&MockClient{LLMClient: mockLLMClient})
+		require.NoError(t, serviceErr)
+		return service
+	}
+	withFile := func(t *testing.T) string {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0600))
+		return dir
+	}
+
+	tests := []struct {
+		name     string
+		dir      func(t *testing.T) string
+		force    bool
+		llmErr   error
+		expected dirOutcome
+	}{
+		{"Forced regeneration is generated", withFile, true, nil, outcomeGenerated},
+		{"Up-to-date directory is skipped", withFile, false, nil, outcomeSkipped},
+		{"Empty directory gets a stub", func(t *testing.T) string { return t.TempDir() }, true, nil, outcomeStub},
+		{"LLM error is a failure", withFile, true, errors.New("boom"), outcomeFailed},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := tc.dir(t)
+			cfg := config.NewDefaultConfig().WithTargetDir(dir)
+
+			r := processDirectory(context.Background(), dir, tc.force, nil, cfg, newService(t, tc.llmErr))
+
+			assert.Equal(t, tc.expected, r.outcome, "got %s", r.outcome)
+		})
+	}
+}
+
+func TestDirOutcomeString(t *testing.T) {
+	assert.Equal(t, "generated", outcomeGenerated.String())
+	assert.Equal(t, "stub", outcomeStub.String())
+	assert.Equal(t, "skipped", outcomeSkipped.String())
+	assert.Equal(t, "failed", outcomeFailed.String())
+	assert.Equal(t, "failed", dirOutcome(0).String(), "the zero value is a failure")
+}
+
+func TestPrintDebriefCountsOutcomes(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	printDebrief([]result{
+		{dir: "a", success: true, outcome: outcomeGenerated, attempts: 1},
+		{dir: "b", success: true, outcome: outcomeGenerated, attempts: 1},
+		{dir: "c", success: true, outcome: outcomeStub, attempts: 1},
+		{dir: "d", success: true, outcome: outcomeSkipped},
+		{dir: "e", outcome: outcomeFailed, err: errors.New("boom"), attempts: 1},
+	}, false)
+
+	var summary *logrus.Entry
+	for _, entry := range hook.AllEntries() {
+		if entry.Message == "Directory processing summary" {
+			summary = entry
+		}
+	}
+	require.NotNil(t, summary)
+	assert.Equal(t, 5, summary.Data["total_dirs"])
+	assert.Equal(t, 2, summary.Data["generated_count"])
+	assert.Equal(t, 1, summary.Data["stub_count"])
+	assert.Equal(t, 1, summary.Data["skipped_count"])
+	assert.Equal(t, 1, summary.Data["failure_count"])
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"glance/config"
+	"glance/filesystem"
+)
+
+// outputDirMode restricts mirror directories created under --output-dir to the
+// current user, matching the permissions of the files written into them.
+const outputDirMode = 0o700
+
+// glanceOutputDir returns the directory dir's glance output lives in: dir
+// itself, or its mirror under cfg.OutputDir.
+func glanceOutputDir(cfg *config.Config, dir string) string {
+	if cfg.OutputDir == "" {
+		return dir
+	}
+	rel, err := filepath.Rel(cfg.TargetDir, dir)
+	if err != nil {
+		// Unreachable for scanned directories; keeps the mirror inside the root
+		rel = filepath.Base(dir)
+	}
+	return filepath.Join(cfg.OutputDir, rel)
+}
+
+// glanceOutputDirs maps each directory to its glance output directory.
+func glanceOutputDirs(cfg *config.Config, dirs []string) []string {
+	out := make([]string, len(dirs))
+	for i, d := range dirs {
+		out[i] = glanceOutputDir(cfg, d)
+	}
+	return out
+}
+
+// glanceWritePath returns the validated path dir's glance output should be
+// written to. With --output-dir the path is validated against the output root
+// and its mirror directory is created if missing.
+//
+// Parameters:
+//   - cfg: The run configuration
+//   - dir: The directory being summarized
+//
+// Returns:
+//   - The validated output file path
+//   - An error if the path escapes its root or the mirror directory cannot be created
+func glanceWritePath(cfg *config.Config, dir string) (string, error) {
+	if cfg.OutputDir == "" {
+		return filesystem.ValidateFilePath(filepath.Join(dir, filesystem.GlanceFilename), dir, true, false)
+	}
+
+	outDir, err := filesystem.ValidateDirPath(glanceOutputDir(cfg, dir), cfg.OutputDir, true, false)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(outDir, outputDirMode); err != nil {
+		return "", fmt.Errorf("failed to create output directory %q: %w", outDir, err)
+	}
+	return filesystem.ValidateFilePath(filepath.Join(outDir, filesystem.GlanceFilename), cfg.OutputDir, true, false)
+}
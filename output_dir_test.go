@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestOutputDir verifies that --output-dir writes summaries into a mirror tree,
+// leaves the source tree untouched, and feeds parents from the mirror.
+func TestOutputDir(t *testing.T) {
+	root := t.TempDir()
+	outRoot := filepath.Join(t.TempDir(), "out")
+	child := filepath.Join(root, "a")
+	grandchild := filepath.Join(child, "b")
+	require.NoError(t, os.MkdirAll(grandchild, 0755))
+	for _, d := range []string{root, child, grandchild} {
+		require.NoError(t, os.WriteFile(filepath.Join(d, "main.go"), []byte("package "+filepath.Base(d)+"\n"), 0600))
+	}
+	// An in-tree summary must not be read once output is mirrored
+	require.NoError(t, os.WriteFile(filepath.Join(child, filesystem.GlanceFilename), []byte("STALE IN-TREE SUMMARY"), 0600))
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithOutputDir(outRoot).WithForce(true)
+
+	var prompts []string
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { prompts = append(prompts, args.String(1)) }).
+		Return("MIRRORED SUMMARY\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+		llm.WithPromptTemplate("dir {{.Directory}}\nsubs [{{.SubGlances}}]"))
+	require.NoError(t, err)
+
+	dirs, ignoreChains, err := scanDirectories(cfg)
+	require.NoError(t, err)
+	results, _ := processDirectories(context.Background(), dirs, ignoreChains, cfg, service, io.Discard)
+	for _, r := range results {
+		assert.True(t, r.success, "processing %s failed: %v", r.dir, r.err)
+	}
+
+	for _, rel := range []string{".", "a", filepath.Join("a", "b")} {
+		assert.FileExists(t, filepath.Join(outRoot, rel, filesystem.GlanceFilename), "mirror of %s", rel)
+	}
+	assert.NoFileExists(t, filepath.Join(root, filesystem.GlanceFilename), "the source tree is untouched")
+	assert.NoFileExists(t, filepath.Join(grandchild, filesystem.GlanceFilename), "the source tree is untouched")
+
+	// Bottom-up: b, then a, then the root
+	require.Len(t, prompts, 3)
+	assert.Contains(t, prompts[0], "subs []")
+	for _, prompt := range prompts[1:] {
+		assert.Contains(t, prompt, "MIRRORED SUMMARY", "subglances come from the mirror")
+		assert.NotContains(t, prompt, "STALE IN-TREE SUMMARY")
+	}
+
+	// A second run finds the mirrored outputs fresh
+	results, _ = processDirectories(context.Background(), dirs, ignoreChains, cfg.WithForce(false), service, io.Discard)
+	for _, r := range results {
+		assert.Zero(t, r.attempts, "%s should be up to date", r.dir)
+	}
+	assert.Len(t, prompts, 3)
+}
+
+// TestOutputDirInsideTarget verifies that an --output-dir root inside the
+// target directory is neither scanned as source nor makes its parent stale.
+func TestOutputDirInsideTarget(t *testing.T) {
+	root := t.TempDir()
+	docs := filepath.Join(root, "docs")
+	require.NoError(t, os.MkdirAll(docs, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(docs, "guide.md"), []byte("# Guide\n"), 0600))
+	outRoot := filepath.Join(docs, "glance")
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithOutputDir(outRoot)
+
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).Return("MIRRORED SUMMARY\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+		llm.WithPromptTemplate("dir {{.Directory}}\nsubs [{{.SubGlances}}]"))
+	require.NoError(t, err)
+
+	dirs, ignoreChains, err := scanDirectories(cfg)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{root, docs}, dirs)
+
+	results, _ := processDirectories(context.Background(), dirs, ignoreChains, cfg, service, io.Discard)
+	for _, r := range results {
+		assert.True(t, r.success, "processing %s failed: %v", r.dir, r.err)
+	}
+	assert.FileExists(t, filepath.Join(outRoot, filesystem.GlanceFilename))
+	assert.FileExists(t, filepath.Join(outRoot, "docs", filesystem.GlanceFilename))
+	mockLLMClient.AssertNumberOfCalls(t, "Generate", 2)
+
+	// The mirror written under docs/ does not make docs or the root look stale
+	dirs, ignoreChains, err = scanDirectories(cfg)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{root, docs}, dirs, "the output root is never scanned")
+	results, _ = processDirectories(context.Background(), dirs, ignoreChains, cfg, service, io.Discard)
+	for _, r := range results {
+		assert.Zero(t, r.attempts, "%s should be up to date", r.dir)
+	}
+	mockLLMClient.AssertNumberOfCalls(t, "Generate", 2)
+}
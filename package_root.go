@@ -0,0 +1,37 @@
+package main
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"glance/config"
+	"glance/filesystem"
+)
+
+// packageRootConfig returns cfg adjusted for dir when dir is a package root (see
+// filesystem.IsPackageRoot) and package-root settings are configured. The
+// package-root template applies unless a .glance.toml prompt_file already set one.
+// The package-root file limit only ever raises MaxFileBytes; an unlimited
+// MaxFileBytes (zero) is left alone.
+func packageRootConfig(cfg *config.Config, dir string) *config.Config {
+	raisesFileLimit := cfg.MaxFileBytes > 0 && cfg.PackageRootMaxFileBytes > cfg.MaxFileBytes
+	if cfg.PackageRootTemplate == "" && !raisesFileLimit {
+		return cfg
+	}
+
+	isRoot, manifest := filesystem.IsPackageRoot(dir)
+	if !isRoot {
+		return cfg
+	}
+	logrus.WithFields(logrus.Fields{
+		"directory": dir,
+		"manifest":  manifest,
+	}).Debug("Directory is a package root")
+
+	if cfg.PackageRootTemplate != "" && cfg.DirPromptTemplate == "" {
+		cfg = cfg.WithDirPromptTemplate(cfg.PackageRootTemplate)
+	}
+	if raisesFileLimit {
+		cfg = cfg.WithMaxFileBytes(cfg.PackageRootMaxFileBytes)
+	}
+	return cfg
+}
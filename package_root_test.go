@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestPackageRootSettings verifies that package-root directories get the package
+// template and larger file budget while other directories keep the defaults.
+func TestPackageRootSettings(t *testing.T) {
+	root := t.TempDir()
+	pkg := filepath.Join(root, "services", "api")
+	plain := filepath.Join(root, "docs")
+	require.NoError(t, os.MkdirAll(pkg, 0755))
+	require.NoError(t, os.MkdirAll(plain, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pkg, "go.mod"), []byte("module api\n"), 0600))
+	big := strings.Repeat("x", 200)
+	require.NoError(t, os.WriteFile(filepath.Join(pkg, "main.go"), []byte(big), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(plain, "notes.txt"), []byte(big), 0600))
+
+	cfg := config.NewDefaultConfig().
+		WithTargetDir(root).
+		WithMaxFileBytes(50).
+		WithPackageRootTemplate("package {{.Directory}}\n{{.FileContents}}").
+		WithPackageRootMaxFileBytes(500)
+
+	generate := func(t *testing.T, dir string, cfg *config.Config) string {
+		t.Helper()
+		var prompt string
+		mockLLMClient := new(mocks.LLMClient)
+		mockLLMClient.On("Generate", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { prompt = args.String(1) }).
+			Return("# summary\n", nil)
+		mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+		service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient}, llm.WithPromptTemplate("default {{.Directory}}\n{{.FileContents}}"))
+		require.NoError(t, err)
+
+		r := processDirectory(context.Background(), dir, true, filesystem.IgnoreChain{}, cfg, service)
+		require.True(t, r.success, "processDirectory failed: %v", r.err)
+		return prompt
+	}
+
+	t.Run("package root", func(t *testing.T) {
+		prompt := generate(t, pkg, cfg)
+		assert.True(t, strings.HasPrefix(prompt, "package services/api\n"), "package template is used: %q", prompt)
+		assert.Contains(t, prompt, big, "larger file budget keeps the whole file")
+	})
+
+	t.Run("plain directory", func(t *testing.T) {
+		prompt := generate(t, plain, cfg)
+		assert.True(t, strings.HasPrefix(prompt, "default docs\n"), "default template is used: %q", prompt)
+		assert.NotContains(t, prompt, big, "normal file budget truncates")
+	})
+
+	t.Run("unlimited file size stays unlimited", func(t *testing.T) {
+		unlimited := cfg.WithMaxFileBytes(0).WithPackageRootMaxFileBytes(100)
+		assert.Zero(t, packageRootConfig(unlimited, pkg).MaxFileBytes)
+
+		prompt := generate(t, pkg, unlimited)
+		assert.Contains(t, prompt, big, "the package-root limit must not truncate an unlimited budget")
+	})
+
+	t.Run("per-directory prompt_file takes precedence", func(t *testing.T) {
+		prompt := generate(t, pkg, cfg.WithDirPromptTemplate("custom {{.Directory}}"))
+		assert.Equal(t, "custom services/api", prompt)
+	})
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// infoMessages returns the messages of all captured entries at info level or above.
+func infoMessages(hook *test.Hook) []string {
+	var messages []string
+	for _, entry := range hook.AllEntries() {
+		if entry.Level <= logrus.InfoLevel {
+			messages = append(messages, entry.Message)
+		}
+	}
+	return messages
+}
+
+// TestInfoLoggingIsConcise verifies that info-level output does not grow with the
+// number of directories processed: only milestones and the final summary are logged.
+func TestInfoLoggingIsConcise(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+	origLevel := logrus.GetLevel()
+	logrus.SetLevel(logrus.InfoLevel)
+	defer logrus.SetLevel(origLevel)
+
+	root := t.TempDir()
+	for i := 0; i < 5; i++ {
+		dir := filepath.Join(root, fmt.Sprintf("pkg%d", i))
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0600))
+	}
+
+	mockLLMClient := new(mocks.LLMClient)
+	mockClient := &MockClient{LLMClient: mockLLMClient}
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).Return("# summary\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(mockClient)
+	require.NoError(t, err)
+
+	dirsList, ignoreChains, err := filesystem.ListDirsWithIgnores(root)
+	require.NoError(t, err)
+	reverseSlice(dirsList)
+
+	t.Run("Default output", func(t *testing.T) {
+		hook.Reset()
+		cfg := config.NewDefaultConfig().WithTargetDir(root).WithForce(true)
+
+		results, _ := processDirectories(context.Background(), dirsList, ignoreChains, cfg, service, io.Discard)
+		printDebrief(results, cfg.QuietSuccess)
+
+		assert.Equal(t, []string{
+			"Preparing to generate glance output files...",
+			"All done! glance output files have been generated for your codebase",
+			"Directory processing summary",
+		}, infoMessages(hook))
+	})
+
+	t.Run("Quiet success suppresses success lines", func(t *testing.T) {
+		hook.Reset()
+		cfg := config.NewDefaultConfig().WithTargetDir(root).WithForce(true).WithQuietSuccess(true)
+
+		results, _ := processDirectories(context.Background(), dirsList, ignoreChains, cfg, service, io.Discard)
+		printDebrief(results, cfg.QuietSuccess)
+
+		assert.Equal(t, []string{"Preparing to generate glance output files..."}, infoMessages(hook))
+	})
+
+	t.Run("Quiet success still reports failures", func(t *testing.T) {
+		hook.Reset()
+		results := []result{
+			{dir: "ok", success: true, attempts: 1},
+			{dir: "broken", success: false, attempts: 1, err: errors.New("boom")},
+		}
+
+		printDebrief(results, true)
+
+		entries := hook.AllEntries()
+		require.Len(t, entries, 1)
+		assert.Equal(t, logrus.ErrorLevel, entries[0].Level)
+		assert.Contains(t, entries[0].Data["context"], "broken")
+	})
+}
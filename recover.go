@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/sirupsen/logrus"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/llm"
+)
+
+// processDirectorySafely runs processDirectory, turning a panic, such as one
+// from a buggy post-processor or template function, into a failed result for
+// that directory so the rest of the run carries on. The stack is logged at
+// error level.
+func processDirectorySafely(ctx context.Context, dir string, forceDir bool, ignoreChain filesystem.IgnoreChain, cfg *config.Config, llmService *llm.Service) (r result) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logrus.WithFields(logrus.Fields{
+				"directory": dir,
+				"panic":     recovered,
+				"stack":     string(debug.Stack()),
+			}).Error("Recovered from panic while processing directory")
+			r = result{dir: dir, err: fmt.Errorf("panic while processing directory: %v", recovered)}
+		}
+	}()
+	return processDirectory(ctx, dir, forceDir, ignoreChain, cfg, llmService)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestProcessDirectoriesRecoversFromPanic verifies that a panic while
+// processing one directory fails only that directory and the run completes.
+func TestProcessDirectoriesRecoversFromPanic(t *testing.T) {
+	root := t.TempDir()
+	writePackageDirs(t, root, "good", "bad")
+
+	mockLLMClient := new(mocks.LLMClient)
+	isBad := func(prompt string) bool { return strings.Contains(prompt, "package bad") }
+	mockLLMClient.On("Generate", mock.Anything, mock.MatchedBy(isBad)).Return("boom", nil)
+	mockLLMClient.On("Generate", mock.Anything, mock.MatchedBy(func(prompt string) bool { return !isBad(prompt) })).
+		Return("a fine summary", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+		llm.WithPromptTemplate("dir {{.Directory}}\n{{.FileContents}}"),
+		llm.WithOutputPostProcessor(func(summary string) (string, error) {
+			if summary == "boom" {
+				panic("post-processor bug")
+			}
+			return summary, nil
+		}),
+	)
+	require.NoError(t, err)
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithForce(true)
+	var byDir map[string]result
+	require.NotPanics(t, func() {
+		byDir = processTree(t, cfg, service)
+	})
+	require.Len(t, byDir, 3, "every directory is processed")
+
+	bad := byDir[filepath.Join(root, "bad")]
+	assert.False(t, bad.success)
+	assert.Equal(t, outcomeFailed, bad.outcome)
+	require.Error(t, bad.err)
+	assert.Contains(t, bad.err.Error(), "panic while processing directory: post-processor bug")
+	assert.NoFileExists(t, filepath.Join(root, "bad", filesystem.GlanceFilename))
+
+	good := byDir[filepath.Join(root, "good")]
+	assert.True(t, good.success, "other directories are unaffected")
+	assert.FileExists(t, filepath.Join(root, "good", filesystem.GlanceFilename))
+	assert.True(t, byDir[root].success, "the parent, processed after the panic, still runs")
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/llm"
+)
+
+// repoContext holds the formatted --context-file contents. It is loaded once
+// at startup by loadRepoContext and shared by every directory's prompt.
+var repoContext string
+
+// loadRepoContext reads the --context-file matches from the target directory
+// and installs them as repoContext. It does nothing when no patterns are set.
+func loadRepoContext(cfg *config.Config) error {
+	if len(cfg.ContextFiles) == 0 {
+		return nil
+	}
+
+	files, err := filesystem.LoadContextFiles(cfg.TargetDir, cfg.ContextFiles, cfg.MaxContextBytes)
+	if err != nil {
+		return err
+	}
+	repoContext = llm.FormatFileContents(files)
+
+	logrus.WithFields(logrus.Fields{
+		"file_count": len(files),
+		"bytes":      len(repoContext),
+	}).Debug("Loaded repository context files")
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestRepoContextSharedAcrossDirectories verifies that --context-file contents
+// are loaded once at startup and reach the prompt of every directory.
+func TestRepoContextSharedAcrossDirectories(t *testing.T) {
+	t.Cleanup(func() { repoContext = "" })
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("Project overview\n"), 0600))
+	for _, name := range []string{"api", "store"} {
+		dir := filepath.Join(root, name)
+		require.NoError(t, os.Mkdir(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package "+name+"\n"), 0600))
+	}
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithForce(true).WithContextFiles([]string{"README.md"})
+	require.NoError(t, loadRepoContext(cfg))
+
+	// Later edits are not picked up, since the files were read once at startup
+	require.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("Edited\n"), 0600))
+
+	var prompts []string
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { prompts = append(prompts, args.String(1)) }).
+		Return("# summary\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+		llm.WithPromptTemplate("dir: {{.Directory}}\n{{.RepoContext}}"))
+	require.NoError(t, err)
+
+	dirs := []string{filepath.Join(root, "api"), filepath.Join(root, "store")}
+	chains := map[string]filesystem.IgnoreChain{}
+	results, _ := processDirectories(context.Background(), dirs, chains, cfg, service, io.Discard)
+	require.Len(t, results, 2)
+
+	require.Len(t, prompts, 2)
+	assert.Contains(t, prompts[0], "dir: api\n")
+	assert.Contains(t, prompts[1], "dir: store\n")
+	for _, prompt := range prompts {
+		assert.Contains(t, prompt, "=== file: README.md ===\nProject overview\n")
+		assert.NotContains(t, prompt, "Edited")
+	}
+}
+
+func TestLoadRepoContextWithoutPatterns(t *testing.T) {
+	t.Cleanup(func() { repoContext = "" })
+
+	require.NoError(t, loadRepoContext(config.NewDefaultConfig().WithTargetDir(t.TempDir())))
+	assert.Empty(t, repoContext)
+	assert.Empty(t, promptOptions(config.NewDefaultConfig(), "dir"), "no context option without context files")
+}
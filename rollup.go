@@ -0,0 +1,65 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"glance/config"
+	"glance/filesystem"
+)
+
+// dirHeights returns each directory's height in the scanned tree, that is its
+// distance from the deepest directory beneath it: 0 for a directory with no
+// scanned subdirectories, otherwise one more than its tallest child. dirs may
+// be in any order.
+func dirHeights(dirs []string) map[string]int {
+	height := make(map[string]int, len(dirs))
+	for _, d := range dirs {
+		height[d] = 0
+	}
+
+	// Visit the deepest directories first so each child is final before its parent
+	ordered := make([]string, len(dirs))
+	copy(ordered, dirs)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return pathDepth(ordered[i]) > pathDepth(ordered[j])
+	})
+	for _, d := range ordered {
+		parent := filepath.Dir(d)
+		if parentHeight, ok := height[parent]; ok && parent != d && height[d]+1 > parentHeight {
+			height[parent] = height[d] + 1
+		}
+	}
+	return height
+}
+
+// pathDepth counts the separators in a cleaned path.
+func pathDepth(path string) int {
+	return strings.Count(filepath.Clean(path), string(filepath.Separator))
+}
+
+// rollupConfig returns cfg marked as a roll-up when dir's height (see
+// dirHeights) reaches cfg.RollupDepth.
+func rollupConfig(cfg *config.Config, dir string, heights map[string]int) *config.Config {
+	if cfg.RollupDepth <= 0 || heights[dir] < cfg.RollupDepth {
+		return cfg
+	}
+	logrus.WithFields(logrus.Fields{
+		"directory": dir,
+		"height":    heights[dir],
+	}).Debug("Directory is a roll-up - summarizing from subdirectory summaries only")
+	return cfg.WithRollup(true)
+}
+
+// localFilesFor returns the files in dir to include in its prompt. Roll-up
+// directories are summarized only from their subdirectory summaries, so their
+// own files are never read.
+func localFilesFor(dir string, ignoreChain filesystem.IgnoreChain, cfg *config.Config) (map[string]string, error) {
+	if cfg.Rollup {
+		return map[string]string{}, nil
+	}
+	return gatherLocalFiles(dir, ignoreChain, cfg)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestRollupDepth verifies that roll-up directories are summarized from their
+// subdirectory summaries alone, while shallower directories keep their files.
+func TestRollupDepth(t *testing.T) {
+	root := t.TempDir()
+	child := filepath.Join(root, "a")
+	grandchild := filepath.Join(child, "b")
+	require.NoError(t, os.MkdirAll(grandchild, 0755))
+	for _, d := range []string{root, child, grandchild} {
+		require.NoError(t, os.WriteFile(filepath.Join(d, "loose.go"), []byte("package LOOSE_"+filepath.Base(d)+"\n"), 0600))
+	}
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithForce(true).WithRollupDepth(2)
+
+	dirs, ignoreChains, err := scanDirectories(cfg)
+	require.NoError(t, err)
+
+	prompts := make(map[string]string)
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			prompt := args.String(1)
+			prompts[strings.TrimPrefix(strings.SplitN(prompt, "\n", 2)[0], "dir ")] = prompt
+		}).
+		Return("CHILD SUMMARY\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+		llm.WithPromptTemplate("dir {{.Directory}}\nsubs [{{.SubGlances}}]\nfiles [{{.FileContents}}]"))
+	require.NoError(t, err)
+
+	results, _ := processDirectories(context.Background(), dirs, ignoreChains, cfg, service, io.Discard)
+	require.Len(t, results, 3)
+	for _, r := range results {
+		assert.True(t, r.success, "processing %s failed: %v", r.dir, r.err)
+	}
+
+	nested := filepath.Join("a", "b")
+	require.Contains(t, prompts, nested)
+	assert.Contains(t, prompts[nested], "LOOSE_b", "a leaf keeps its files")
+	require.Contains(t, prompts, "a")
+	assert.Contains(t, prompts["a"], "LOOSE_a", "a directory below the roll-up depth keeps its files")
+	assert.Contains(t, prompts["a"], "CHILD SUMMARY")
+
+	require.Contains(t, prompts, ".")
+	assert.Contains(t, prompts["."], "files []", "the roll-up's own files are left out")
+	assert.NotContains(t, prompts["."], "LOOSE_.")
+	assert.Contains(t, prompts["."], "CHILD SUMMARY", "the roll-up is built from its subglances")
+}
+
+func TestDirHeights(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "repo")
+	dirs := []string{
+		filepath.Join(root, "a", "b", "c"),
+		filepath.Join(root, "a", "b"),
+		filepath.Join(root, "a"),
+		filepath.Join(root, "d"),
+		root,
+	}
+
+	assert.Equal(t, map[string]int{
+		filepath.Join(root, "a", "b", "c"): 0,
+		filepath.Join(root, "a", "b"):      1,
+		filepath.Join(root, "a"):           2,
+		filepath.Join(root, "d"):           0,
+		root:                               3,
+	}, dirHeights(dirs))
+
+	cfg := config.NewDefaultConfig().WithRollupDepth(2)
+	heights := dirHeights(dirs)
+	assert.True(t, rollupConfig(cfg, filepath.Join(root, "a"), heights).Rollup)
+	assert.False(t, rollupConfig(cfg, filepath.Join(root, "a", "b"), heights).Rollup)
+	assert.False(t, rollupConfig(config.NewDefaultConfig(), root, heights).Rollup, "roll-ups are off by default")
+}
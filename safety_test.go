@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"glance/config"
+	"glance/llm"
+)
+
+// TestSafetyClientOptions verifies that --safety reaches the Gemini tiers' options.
+func TestSafetyClientOptions(t *testing.T) {
+	cfg := config.NewDefaultConfig().WithSafetySettings([]llm.SafetySetting{
+		{Category: llm.HarmCategoryDangerousContent, Threshold: llm.HarmBlockNone},
+		{Category: llm.HarmCategoryHarassment, Threshold: llm.HarmBlockHighAndAbove},
+	})
+
+	gemini := applyClientOptions(geminiClientOptions(cfg, "gemini-2.5-flash"))
+	assert.Equal(t, []*llm.SafetySetting{
+		{Category: llm.HarmCategoryDangerousContent, Threshold: llm.HarmBlockNone},
+		{Category: llm.HarmCategoryHarassment, Threshold: llm.HarmBlockHighAndAbove},
+	}, gemini.SafetySettings)
+
+	defaults := applyClientOptions(geminiClientOptions(config.NewDefaultConfig(), "gemini-2.5-flash"))
+	assert.Empty(t, defaults.SafetySettings)
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/llm"
+)
+
+const (
+	// maxServeRequestBytes caps a /summarize request body, inline files included
+	maxServeRequestBytes = 10 << 20
+
+	// serveReadHeaderTimeout bounds how long a client may take to send headers
+	serveReadHeaderTimeout = 10 * time.Second
+
+	// serveShutdownTimeout is how long in-flight requests get to finish after
+	// the server is told to stop
+	serveShutdownTimeout = 30 * time.Second
+)
+
+// summarizeRequest is the body of POST /summarize. Exactly one of Directory and
+// Files must be set.
+type summarizeRequest struct {
+	// Directory is a directory to summarize, absolute or relative to the
+	// server's root; it must lie within the root
+	Directory string `json:"directory,omitempty"`
+
+	// Files maps file names to contents to summarize without touching disk
+	Files map[string]string `json:"files,omitempty"`
+
+	// Name is the directory name shown to the model for inline Files
+	Name string `json:"name,omitempty"`
+}
+
+// summarizeResponse is the JSON body of every /summarize reply.
+type summarizeResponse struct {
+	Summary string `json:"summary,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// newServeHandler returns the HTTP API for "glance serve":
+//
+//	GET  /healthz    reports that the server is up; never requires the token
+//	POST /summarize  summarizes a directory under cfg.TargetDir or an inline
+//	                 file map, returning {"summary": ...}
+//
+// When token is non-empty, /summarize requires "Authorization: Bearer <token>".
+// Nothing is written to disk: directory summaries are generated from the same
+// inputs a normal run would use and returned instead of saved.
+func newServeHandler(cfg *config.Config, service *llm.Service, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	mux.Handle("POST /summarize", requireToken(token, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handleSummarize(w, r, cfg, service)
+	})))
+	return mux
+}
+
+// requireToken rejects requests that do not carry the shared bearer token. An
+// empty token disables the check.
+func requireToken(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeSummarizeResponse(w, http.StatusUnauthorized, summarizeResponse{Error: "missing or invalid token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleSummarize serves POST /summarize.
+func handleSummarize(w http.ResponseWriter, r *http.Request, cfg *config.Config, service *llm.Service) {
+	var req summarizeRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxServeRequestBytes)).Decode(&req); err != nil {
+		writeSummarizeResponse(w, http.StatusBadRequest, summarizeResponse{Error: "invalid request body: " + err.Error()})
+		return
+	}
+	if (req.Directory == "") == (req.Files == nil) {
+		writeSummarizeResponse(w, http.StatusBadRequest, summarizeResponse{Error: `exactly one of "directory" and "files" is required`})
+		return
+	}
+
+	var (
+		summary string
+		err     error
+	)
+	if req.Directory != "" {
+		var status int
+		summary, status, err = summarizeDirectory(r.Context(), cfg, service, req.Directory)
+		if err != nil && status != http.StatusBadGateway {
+			writeSummarizeResponse(w, status, summarizeResponse{Error: err.Error()})
+			return
+		}
+	} else {
+		name := req.Name
+		if name == "" {
+			name = "."
+		}
+		summary, err = service.GenerateGlanceMarkdown(r.Context(), name, req.Files, "")
+	}
+	if err != nil {
+		logrus.WithField("error", err).Warn("Summary request failed")
+		writeSummarizeResponse(w, http.StatusBadGateway, summarizeResponse{Error: "summary generation failed: " + err.Error()})
+		return
+	}
+	writeSummarizeResponse(w, http.StatusOK, summarizeResponse{Summary: summary})
+}
+
+// summarizeDirectory summarizes dir, which must lie within cfg.TargetDir and be
+// a directory the normal scan would process.
+//
+// Returns:
+//   - The generated summary
+//   - The HTTP status describing err: 403 outside the root, 404 for a missing
+//     or ignored directory, 502 when generation itself fails
+//   - An error if the directory is rejected or generation fails
+func summarizeDirectory(ctx context.Context, cfg *config.Config, service *llm.Service, dir string) (string, int, error) {
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(cfg.TargetDir, dir)
+	}
+	validDir, err := filesystem.ValidateDirPath(dir, cfg.TargetDir, true, true)
+	switch {
+	case errors.Is(err, filesystem.ErrPathOutsideBase):
+		return "", http.StatusForbidden, errors.New("directory is outside the server root")
+	case err != nil:
+		return "", http.StatusNotFound, errors.New("directory not found")
+	}
+
+	inputs, err := gatherDirectoryInputs(ctx, cfg, validDir)
+	if errors.Is(err, errDirNotProcessed) {
+		return "", http.StatusNotFound, errors.New("directory is hidden or ignored")
+	}
+	if err != nil {
+		return "", http.StatusInternalServerError, err
+	}
+
+	summary, err := service.GenerateGlanceMarkdown(ctx, inputs.promptDir, inputs.files, inputs.subGlances, inputs.options...)
+	if err != nil {
+		return "", http.StatusBadGateway, err
+	}
+	return summary, http.StatusOK, nil
+}
+
+// writeSummarizeResponse writes resp as JSON with the given status.
+func writeSummarizeResponse(w http.ResponseWriter, status int, resp summarizeResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logrus.WithField("error", err).Debug("Failed to write response")
+	}
+}
+
+// runServer serves the HTTP API on cfg.ServeAddr until interrupted, then lets
+// in-flight requests finish before returning.
+func runServer(cfg *config.Config, service *llm.Service) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if cfg.ServeToken == "" {
+		logrus.Warn("GLANCE_SERVE_TOKEN is not set; the HTTP API accepts unauthenticated requests")
+	}
+
+	server := &http.Server{
+		Addr:              cfg.ServeAddr,
+		Handler:           newServeHandler(cfg, service, cfg.ServeToken),
+		ReadHeaderTimeout: serveReadHeaderTimeout,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		logrus.WithFields(logrus.Fields{
+			"addr": cfg.ServeAddr,
+			"root": cfg.TargetDir,
+		}).Info("Serving glance HTTP API")
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	logrus.Info("Shutting down glance HTTP API")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// newTestServer starts the HTTP API over root with a mock client that answers
+// "# served summary" and sends each prompt it receives on the returned channel.
+func newTestServer(t *testing.T, root, token string) (*httptest.Server, *mocks.LLMClient, <-chan string) {
+	t.Helper()
+	prompts := make(chan string, 10)
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { prompts <- args.String(1) }).
+		Return("# served summary\n", nil).Maybe()
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+		llm.WithPromptTemplate("dir {{.Directory}}\n{{.FileContents}}"))
+	require.NoError(t, err)
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root)
+	server := httptest.NewServer(newServeHandler(cfg, service, token))
+	t.Cleanup(server.Close)
+	return server, mockLLMClient, prompts
+}
+
+// postSummarize sends body to /summarize and decodes the JSON reply.
+func postSummarize(t *testing.T, server *httptest.Server, token, body string) (int, summarizeResponse) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/summarize", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	var out summarizeResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return resp.StatusCode, out
+}
+
+func TestServeSummarizesInlineFiles(t *testing.T) {
+	server, _, prompts := newTestServer(t, t.TempDir(), "secret")
+
+	status, resp := postSummarize(t, server, "secret",
+		`{"name": "snippet", "files": {"main.go": "package main // inline-marker"}}`)
+	require.Equal(t, http.StatusOK, status, resp.Error)
+	assert.Contains(t, resp.Summary, "served summary")
+
+	prompt := <-prompts
+	assert.Contains(t, prompt, "dir snippet")
+	assert.Contains(t, prompt, "inline-marker")
+}
+
+func TestServeSummarizesDirectoryWithoutWriting(t *testing.T) {
+	root := t.TempDir()
+	pkg := filepath.Join(root, "pkg")
+	require.NoError(t, os.Mkdir(pkg, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pkg, "lib.go"), []byte("package pkg // dir-marker"), 0600))
+	server, _, prompts := newTestServer(t, root, "")
+
+	status, resp := postSummarize(t, server, "", `{"directory": "pkg"}`)
+	require.Equal(t, http.StatusOK, status, resp.Error)
+	assert.Contains(t, resp.Summary, "served summary")
+
+	prompt := <-prompts
+	assert.Contains(t, prompt, "dir pkg")
+	assert.Contains(t, prompt, "dir-marker")
+
+	entries, err := os.ReadDir(pkg)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "serving must not write glance output")
+}
+
+func TestServeRejectsDirectoryOutsideRoot(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "root")
+	outside := filepath.Join(parent, "outside")
+	require.NoError(t, os.Mkdir(root, 0755))
+	require.NoError(t, os.Mkdir(outside, 0755))
+	server, mockLLMClient, _ := newTestServer(t, root, "")
+
+	for _, dir := range []string{outside, "../outside"} {
+		body, err := json.Marshal(summarizeRequest{Directory: dir})
+		require.NoError(t, err)
+		status, resp := postSummarize(t, server, "", string(body))
+		assert.Equal(t, http.StatusForbidden, status, dir)
+		assert.NotEmpty(t, resp.Error)
+	}
+	mockLLMClient.AssertNotCalled(t, "Generate", mock.Anything, mock.Anything)
+}
+
+func TestServeRejectsBadRequests(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, ".hidden"), 0755))
+	server, _, _ := newTestServer(t, root, "secret")
+
+	status, _ := postSummarize(t, server, "", `{"files": {"a.go": "x"}}`)
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _ = postSummarize(t, server, "wrong", `{"files": {"a.go": "x"}}`)
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, _ = postSummarize(t, server, "secret", `{}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = postSummarize(t, server, "secret", `{"directory": ".", "files": {"a.go": "x"}}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = postSummarize(t, server, "secret", `not json`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = postSummarize(t, server, "secret", `{"directory": "missing"}`)
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = postSummarize(t, server, "secret", `{"directory": ".hidden"}`)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestServeHealthzNeedsNoToken(t *testing.T) {
+	server, _, _ := newTestServer(t, t.TempDir(), "secret")
+
+	resp, err := server.Client().Get(server.URL + "/healthz")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
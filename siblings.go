@@ -0,0 +1,51 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+
+	"glance/config"
+)
+
+// siblingDirs returns, for each scanned directory, the sorted base names of
+// the other scanned directories under the same parent. Directories whose
+// parent was not scanned, such as the target root, have no siblings.
+// dirs may be in any order.
+func siblingDirs(dirs []string) map[string][]string {
+	scanned := make(map[string]bool, len(dirs))
+	for _, d := range dirs {
+		scanned[d] = true
+	}
+
+	children := make(map[string][]string)
+	for _, d := range dirs {
+		parent := filepath.Dir(d)
+		if parent != d && scanned[parent] {
+			children[parent] = append(children[parent], d)
+		}
+	}
+
+	siblings := make(map[string][]string, len(dirs))
+	for _, peers := range children {
+		for _, d := range peers {
+			var names []string
+			for _, peer := range peers {
+				if peer != d {
+					names = append(names, filepath.Base(peer))
+				}
+			}
+			sort.Strings(names)
+			siblings[d] = names
+		}
+	}
+	return siblings
+}
+
+// siblingsConfig returns cfg carrying dir's sibling names (see siblingDirs)
+// when cfg.IncludeSiblings is set.
+func siblingsConfig(cfg *config.Config, dir string, siblings map[string][]string) *config.Config {
+	if !cfg.IncludeSiblings || len(siblings[dir]) == 0 {
+		return cfg
+	}
+	return cfg.WithSiblings(siblings[dir])
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestSiblingDirs verifies that each directory's siblings are its scanned
+// peers under the same parent, excluding itself.
+func TestSiblingDirs(t *testing.T) {
+	root := "repo"
+	dirs := []string{
+		filepath.Join(root, "web", "components"),
+		filepath.Join(root, "api"),
+		filepath.Join(root, "web"),
+		filepath.Join(root, "cmd"),
+		root,
+	}
+
+	siblings := siblingDirs(dirs)
+
+	assert.Equal(t, []string{"cmd", "web"}, siblings[filepath.Join(root, "api")])
+	assert.Equal(t, []string{"api", "cmd"}, siblings[filepath.Join(root, "web")])
+	assert.Equal(t, []string{"api", "web"}, siblings[filepath.Join(root, "cmd")])
+	assert.Empty(t, siblings[filepath.Join(root, "web", "components")], "an only child has no siblings")
+	assert.Empty(t, siblings[root], "the target root has no siblings")
+}
+
+// TestProcessDirectoriesIncludeSiblings verifies that sibling names reach the
+// prompt only when --include-siblings is set.
+func TestProcessDirectoriesIncludeSiblings(t *testing.T) {
+	root := t.TempDir()
+	writePackageDirs(t, root, "api", "web", "cmd")
+
+	run := func(t *testing.T, includeSiblings bool) []string {
+		var mu sync.Mutex
+		var prompts []string
+		record := func(prompt string) bool {
+			mu.Lock()
+			defer mu.Unlock()
+			prompts = append(prompts, prompt)
+			return true
+		}
+
+		mockLLMClient := new(mocks.LLMClient)
+		mockLLMClient.On("Generate", mock.Anything, mock.MatchedBy(record)).Return("a fine summary", nil)
+		mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+		service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+			llm.WithPromptTemplate("dir {{.Directory}}\n{{.FileContents}}{{range .Siblings}}sibling {{.}}\n{{end}}"))
+		require.NoError(t, err)
+
+		processTree(t, config.NewDefaultConfig().WithTargetDir(root).WithForce(true).WithIncludeSiblings(includeSiblings), service)
+		return prompts
+	}
+
+	promptFor := func(prompts []string, pkg string) string {
+		for _, prompt := range prompts {
+			if strings.Contains(prompt, "package "+pkg) {
+				return prompt
+			}
+		}
+		t.Fatalf("no prompt for package %s", pkg)
+		return ""
+	}
+
+	t.Run("Included when enabled", func(t *testing.T) {
+		prompt := promptFor(run(t, true), "api")
+		assert.Contains(t, prompt, "sibling cmd\nsibling web\n")
+		assert.NotContains(t, prompt, "sibling api")
+	})
+
+	t.Run("Omitted by default", func(t *testing.T) {
+		for _, prompt := range run(t, false) {
+			assert.NotContains(t, prompt, "sibling")
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"glance/config"
+	"glance/filesystem"
+)
+
+// skipReason returns why dir is left out of the run without a summary or stub,
+// or "" when it should be processed: a .glanceskip marker in dir or a recursive
+// one in an ancestor within the target directory, no files of the types
+// --only-dirs-with requires, or more gitignored files than --max-ignored-ratio
+// allows. A directory marked by a regenerated descendant (bubbled) is exempt
+// from --only-dirs-with, so its summary keeps covering that descendant.
+func skipReason(cfg *config.Config, dir string, ignoreChain filesystem.IgnoreChain, bubbled bool) string {
+	if filesystem.SkippedByMarker(dir, cfg.TargetDir) {
+		logrus.WithFields(logrus.Fields{
+			"directory": dir,
+			"action":    "skip",
+		}).Debug("Skipping directory - marked with " + filesystem.SkipMarkerFilename)
+		return filesystem.SkipMarkerFilename + " marker"
+	}
+	if len(cfg.OnlyDirsWith) > 0 && !bubbled && !dirQualifies(dir, cfg.OnlyDirsWith, ignoreChain, ignoreOptions(cfg)...) {
+		return "no qualifying file types"
+	}
+	if reason := mostlyIgnoredReason(cfg, dir, ignoreChain); reason != "" {
+		return reason
+	}
+	return ""
+}
+
+// mostlyIgnoredReason returns a skip reason when more than --max-ignored-ratio
+// of dir's files are gitignored, or "" otherwise. Directories without files and
+// unreadable directories are never skipped this way.
+func mostlyIgnoredReason(cfg *config.Config, dir string, ignoreChain filesystem.IgnoreChain) string {
+	if cfg.MaxIgnoredRatio <= 0 {
+		return ""
+	}
+	total, ignored, err := filesystem.CountGitignoredFiles(dir, ignoreChain, ignoreOptions(cfg)...)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"directory": dir,
+			"error":     err,
+		}).Warn("Couldn't count gitignored files")
+		return ""
+	}
+	if total == 0 || float64(ignored)/float64(total) <= cfg.MaxIgnoredRatio {
+		return ""
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"directory":         dir,
+		"files":             total,
+		"ignored_files":     ignored,
+		"max_ignored_ratio": cfg.MaxIgnoredRatio,
+		"action":            "skip",
+	}).Debug("Skipping directory - mostly gitignored")
+	return fmt.Sprintf("%d of %d files gitignored", ignored, total)
+}
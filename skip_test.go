@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestGlanceSkipMarker verifies that a .glanceskip marker skips its directory,
+// and with "recursive" its descendants too, without a summary or stub.
+func TestGlanceSkipMarker(t *testing.T) {
+	root := t.TempDir()
+	for _, rel := range []string{"single/child", "tree/child", "kept"} {
+		dir := filepath.Join(root, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package "+filepath.Base(dir)+"\n"), 0600))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, "single", "single.go"), []byte("package single\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "single", filesystem.SkipMarkerFilename), nil, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "tree", filesystem.SkipMarkerFilename), []byte("recursive\n"), 0600))
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithForce(true)
+	dirs, ignoreChains, err := scanDirectories(cfg)
+	require.NoError(t, err)
+	assert.NotContains(t, dirs, filepath.Join(root, "tree"), "recursively skipped directories are not scanned")
+	assert.NotContains(t, dirs, filepath.Join(root, "tree", "child"))
+
+	var prompts []string
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { prompts = append(prompts, strings.SplitN(args.String(1), "\n", 2)[0]) }).
+		Return("# summary\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+		llm.WithPromptTemplate("dir {{.Directory}}\n{{.FileContents}}"))
+	require.NoError(t, err)
+
+	results, _ := processDirectories(context.Background(), dirs, ignoreChains, cfg, service, io.Discard)
+	for _, r := range results {
+		assert.True(t, r.success, "processing %s failed: %v", r.dir, r.err)
+	}
+
+	assert.ElementsMatch(t, []string{
+		"dir " + filepath.Join("single", "child"),
+		"dir kept",
+		"dir .",
+	}, prompts, "only unmarked directories reach the LLM")
+	assert.FileExists(t, filepath.Join(root, "single", "child", filesystem.GlanceFilename),
+		"a non-recursive marker leaves subdirectories alone")
+	for _, rel := range []string{"single", "tree", "tree/child"} {
+		assert.NoFileExists(t, filepath.Join(root, filepath.FromSlash(rel), filesystem.GlanceFilename),
+			"%s is skipped without a summary or stub", rel)
+	}
+}
+
+// TestGlanceSkipMarkerUnderScanRoot verifies that a recursive marker above the
+// --only subtree still skips it.
+func TestGlanceSkipMarkerUnderScanRoot(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "vendor", "lib")
+	require.NoError(t, os.MkdirAll(sub, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "vendor", filesystem.SkipMarkerFilename), []byte("Recursive"), 0600))
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithOnly(sub)
+	assert.Equal(t, filesystem.SkipMarkerFilename+" marker", skipReason(cfg, sub, nil, false))
+	assert.Empty(t, skipReason(cfg, root, nil, false))
+}
+
+// TestMaxIgnoredRatio verifies that --max-ignored-ratio skips directories whose
+// gitignored share of files exceeds the limit, and keeps those at or below it.
+func TestMaxIgnoredRatio(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("*.gen\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0600))
+
+	// Directory name -> ignored and kept file counts
+	layouts := map[string][2]int{
+		"half":  {1, 1}, // 0.5
+		"at":    {3, 1}, // 0.75, exactly the limit
+		"over":  {4, 1}, // 0.8
+		"all":   {2, 0}, // 1.0
+		"empty": {0, 0},
+	}
+	for name, counts := range layouts {
+		dir := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		for i := 0; i < counts[0]; i++ {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("out%d.gen", i)), []byte("generated\n"), 0600))
+		}
+		for i := 0; i < counts[1]; i++ {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("src%d.go", i)), []byte("package "+name+"\n"), 0600))
+		}
+	}
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithForce(true).WithMaxIgnoredRatio(0.75)
+	dirs, ignoreChains, err := scanDirectories(cfg)
+	require.NoError(t, err)
+
+	dir := func(name string) (*config.Config, string, filesystem.IgnoreChain, bool) {
+		d := filepath.Join(root, name)
+		return cfg, d, ignoreChains[d], false
+	}
+	assert.Empty(t, skipReason(dir("half")))
+	assert.Empty(t, skipReason(dir("at")), "a ratio equal to the limit is kept")
+	assert.Equal(t, "4 of 5 files gitignored", skipReason(dir("over")))
+	assert.Equal(t, "2 of 2 files gitignored", skipReason(dir("all")))
+	assert.Empty(t, skipReason(dir("empty")), "directories without files are never skipped this way")
+	assert.Empty(t, skipReason(cfg.WithMaxIgnoredRatio(0), filepath.Join(root, "all"), ignoreChains[filepath.Join(root, "all")], false), "0 disables the check")
+
+	var prompts []string
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { prompts = append(prompts, strings.SplitN(args.String(1), "\n", 2)[0]) }).
+		Return("# summary\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+		llm.WithPromptTemplate("dir {{.Directory}}\n{{.FileContents}}"))
+	require.NoError(t, err)
+
+	results, _ := processDirectories(context.Background(), dirs, ignoreChains, cfg, service, io.Discard)
+	for _, r := range results {
+		assert.True(t, r.success, "processing %s failed: %v", r.dir, r.err)
+	}
+	assert.ElementsMatch(t, []string{"dir half", "dir at", "dir ."}, prompts)
+	assert.NoFileExists(t, filepath.Join(root, "over", filesystem.GlanceFilename))
+	assert.NoFileExists(t, filepath.Join(root, "all", filesystem.GlanceFilename))
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+
+	"glance/filesystem"
+)
+
+// restrictToStaged keeps the directories in dirs that hold a file with staged
+// changes, or are an ancestor of one, so --staged regenerates only what a
+// commit touches while summaries still bubble up to the root.
+func restrictToStaged(root string, dirs []string) ([]string, error) {
+	files, err := filesystem.StagedFiles(root)
+	if err != nil {
+		return nil, err
+	}
+
+	affected := make(map[string]bool)
+	for _, file := range files {
+		for dir := filepath.Dir(file); ; dir = filepath.Dir(dir) {
+			if affected[dir] {
+				break
+			}
+			affected[dir] = true
+			if dir == root || dir == filepath.Dir(dir) {
+				break
+			}
+		}
+	}
+
+	var kept []string
+	for _, d := range dirs {
+		if affected[d] {
+			kept = append(kept, d)
+		}
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"staged_files": len(files),
+		"directories":  len(kept),
+	}).Debug("Restricted processing to directories with staged changes")
+	return kept, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+)
+
+func TestStagedRestrictsScan(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git binary not available")
+	}
+
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, "git %v failed: %s", args, out)
+	}
+
+	repo := t.TempDir()
+	services := filepath.Join(repo, "services")
+	api := filepath.Join(services, "api")
+	web := filepath.Join(services, "web")
+	docs := filepath.Join(repo, "docs")
+	for _, dir := range []string{api, web, docs} {
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("v1\n"), 0600))
+	}
+	git(repo, "init", "-q")
+	git(repo, "add", "-A")
+	git(repo, "commit", "-q", "-m", "init")
+
+	cfg := config.NewDefaultConfig().WithTargetDir(repo).WithStaged(true)
+
+	t.Run("No staged changes is a no-op", func(t *testing.T) {
+		// An unstaged edit does not count
+		require.NoError(t, os.WriteFile(filepath.Join(web, "file.txt"), []byte("v2\n"), 0600))
+
+		dirs, _, err := scanDirectories(cfg)
+		require.NoError(t, err)
+		assert.Empty(t, dirs)
+	})
+
+	t.Run("Only the staged directory and its ancestors are processed", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(api, "file.txt"), []byte("v2\n"), 0600))
+		git(repo, "add", filepath.Join("services", "api", "file.txt"))
+
+		dirs, _, err := scanDirectories(cfg)
+		require.NoError(t, err)
+		assert.Equal(t, []string{api, services, repo}, dirs, "deepest first, siblings left out")
+	})
+
+	t.Run("Outside a repository is a no-op", func(t *testing.T) {
+		plain := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(plain, "file.txt"), []byte("v1\n"), 0600))
+
+		dirs, _, err := scanDirectories(config.NewDefaultConfig().WithTargetDir(plain).WithStaged(true))
+		require.NoError(t, err)
+		assert.Empty(t, dirs)
+	})
+}
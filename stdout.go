@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"glance/config"
+	"glance/filesystem"
+)
+
+// stdoutSink receives every summary for --stdout instead of glance.md files.
+// It stays nil, which disables it, unless --stdout is given.
+var stdoutSink *summaryStream
+
+// summaryStream writes summaries to a single stream, each under a
+// "===== path =====" header, and keeps them in memory so parents can be
+// summarized from their children without reading anything from disk.
+type summaryStream struct {
+	out       io.Writer
+	root      string
+	summaries map[string]string
+}
+
+// newSummaryStream returns a summaryStream writing to out, naming directories
+// by their path relative to root.
+func newSummaryStream(out io.Writer, root string) *summaryStream {
+	return &summaryStream{out: out, root: root, summaries: make(map[string]string)}
+}
+
+// enableStdout sends every summary to out for --stdout. --explain decisions
+// then go to stderr so they do not mix with the summaries.
+func enableStdout(cfg *config.Config, out io.Writer) {
+	if !cfg.Stdout {
+		return
+	}
+	stdoutSink = newSummaryStream(out, cfg.TargetDir)
+	explainOut = os.Stderr
+}
+
+// streamGlance finishes r for --stdout: content is written to stdoutSink and
+// remembered for r.dir's parent instead of being saved as glance.md.
+func streamGlance(r result, content string, outcome dirOutcome) result {
+	if err := stdoutSink.write(r.dir, content); err != nil {
+		r.err = fmt.Errorf("failed writing summary for %s to stdout: %w", r.dir, err)
+		return r
+	}
+	logrus.WithField("directory", r.dir).Debug("Wrote summary to stdout")
+
+	r.success = true
+	r.outcome = outcome
+	r.attempts = 1
+	return r
+}
+
+// write emits dir's summary under its header and records it.
+func (s *summaryStream) write(dir, content string) error {
+	s.summaries[dir] = content
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	_, err := fmt.Fprintf(s.out, "===== %s =====\n%s", filepath.ToSlash(displayDir(s.root, dir)), content)
+	return err
+}
+
+// subGlances is gatherSubGlancesLimited over the summaries written so far:
+// dirs without one, such as skipped or failed directories, are left out.
+func (s *summaryStream) subGlances(baseDir string, dirs []string, maxBytes int64) string {
+	var children []subGlance
+	for _, d := range dirs {
+		content, ok := s.summaries[d]
+		if !ok {
+			continue
+		}
+		name, err := filepath.Rel(baseDir, d)
+		if err != nil {
+			name = filepath.Base(d)
+		}
+		children = append(children, subGlance{name: filepath.ToSlash(name), content: filesystem.StripFooter(content)})
+	}
+	return limitSubGlances(children, maxBytes)
+}
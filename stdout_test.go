@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestStdoutStreamsSummaries verifies that --stdout writes one delimited
+// section per processed directory, in processing order, builds parents from
+// the in-memory child summaries, and creates no files.
+func TestStdoutStreamsSummaries(t *testing.T) {
+	root := t.TempDir()
+	for _, rel := range []string{".", "api", "web"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, rel), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, rel, "main.go"), []byte("package main\n"), 0600))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(root, "empty"), 0755))
+	before := listFiles(t, root)
+
+	var (
+		mu      sync.Mutex
+		prompts = map[string]string{}
+	)
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			mu.Lock()
+			defer mu.Unlock()
+			prompt := args.String(1)
+			prompts[strings.SplitN(prompt, "\n", 2)[0]] = prompt
+		}).
+		Return("# summary\n", nil).Maybe()
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+		llm.WithPromptTemplate("dir {{.Directory}}\n{{.SubGlances}}"))
+	require.NoError(t, err)
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithStdout(true)
+	dirs, ignoreChains, err := scanDirectories(cfg)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	savedExplainOut := explainOut
+	enableStdout(cfg, &out)
+	t.Cleanup(func() {
+		stdoutSink = nil
+		explainOut = savedExplainOut
+	})
+	results, _ := processDirectories(context.Background(), dirs, ignoreChains, cfg, service, io.Discard)
+
+	for _, r := range results {
+		assert.True(t, r.success, "%s: %v", r.dir, r.err)
+	}
+
+	var headers []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if strings.HasPrefix(line, "===== ") {
+			headers = append(headers, line)
+		}
+	}
+	expected := make([]string, 0, len(dirs))
+	for _, d := range dirs {
+		expected = append(expected, "===== "+filepath.ToSlash(displayDir(root, d))+" =====")
+	}
+	assert.Equal(t, expected, headers, "one section per directory, in processing order")
+	assert.Contains(t, out.String(), "===== empty =====\n# empty\n")
+
+	// The root is summarized from its children's in-memory summaries
+	rootPrompt := prompts["dir ."]
+	assert.Contains(t, rootPrompt, "# summary")
+	assert.Contains(t, rootPrompt, "# empty")
+
+	assert.Equal(t, before, listFiles(t, root), "--stdout must not create files")
+}
+
+// listFiles returns every file under root, relative to it.
+func listFiles(t *testing.T, root string) []string {
+	t.Helper()
+	var files []string
+	require.NoError(t, filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		require.NoError(t, err)
+		if !d.IsDir() {
+			rel, relErr := filepath.Rel(root, path)
+			require.NoError(t, relErr)
+			files = append(files, rel)
+		}
+		return nil
+	}))
+	return files
+}
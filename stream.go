@@ -0,0 +1,32 @@
+package main
+
+import (
+	"io"
+
+	"glance/config"
+	"glance/llm"
+)
+
+// streamOut receives summaries as they are generated. It is nil, and nothing
+// streams, unless enableStreaming turned it on for the run.
+var streamOut io.Writer
+
+// enableStreaming streams summaries to out when the run processes a single
+// directory (e.g. with --only on a leaf directory), so an interactive user
+// watches it appear instead of waiting for the whole block. Bulk runs, and
+// --compare and --stdout, which write no files, keep the plain non-streaming
+// path.
+func enableStreaming(cfg *config.Config, dirs []string, out io.Writer) {
+	if len(dirs) == 1 && !cfg.Compare && !cfg.Stdout {
+		streamOut = out
+	}
+}
+
+// streamPromptOptions returns the option that streams a directory's summary to
+// streamOut, or none when streaming is off.
+func streamPromptOptions() []llm.PromptDataOption {
+	if streamOut == nil {
+		return nil
+	}
+	return []llm.PromptDataOption{llm.WithStreamWriter(streamOut)}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+func TestEnableStreaming(t *testing.T) {
+	t.Cleanup(func() { streamOut = nil })
+	var out bytes.Buffer
+	cfg := config.NewDefaultConfig()
+
+	enableStreaming(cfg, []string{"a", "b"}, &out)
+	assert.Nil(t, streamOut, "bulk runs do not stream")
+
+	enableStreaming(cfg.WithCompare(true), []string{"a"}, &out)
+	assert.Nil(t, streamOut, "--compare does not stream")
+
+	enableStreaming(cfg, []string{"a"}, &out)
+	assert.Equal(t, &out, streamOut)
+}
+
+func TestProcessDirectoryStreamsSummary(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0600))
+
+	chunks := make(chan mocks.StreamChunk, 3)
+	chunks <- mocks.StreamChunk{Text: "## Purpose\n"}
+	chunks <- mocks.StreamChunk{Text: "Streams in.\n"}
+	chunks <- mocks.StreamChunk{Done: true}
+	close(chunks)
+
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil)
+	mockLLMClient.On("GenerateStream", mock.Anything, mock.Anything).Return((<-chan mocks.StreamChunk)(chunks), nil).Once()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	cfg := config.NewDefaultConfig().WithTargetDir(dir)
+	enableStreaming(cfg, []string{dir}, &out)
+	t.Cleanup(func() { streamOut = nil })
+
+	r := processDirectory(context.Background(), dir, true, nil, cfg, service)
+	require.True(t, r.success, "processDirectory should succeed: %v", r.err)
+
+	assert.Equal(t, "## Purpose\nStreams in.\n", out.String(), "chunks are printed in order")
+	content, err := os.ReadFile(filepath.Join(dir, filesystem.GlanceFilename))
+	require.NoError(t, err)
+	assert.Equal(t, "## Purpose\nStreams in.\n", string(content), "the assembled summary is written")
+	mockLLMClient.AssertNotCalled(t, "Generate", mock.Anything, mock.Anything)
+}
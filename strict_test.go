@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestStrictUnreadableFile verifies that a file that cannot be read fails the
+// directory under --strict and is skipped otherwise.
+func TestStrictUnreadableFile(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0600))
+		// A dangling symlink can't be read regardless of the user's privileges
+		require.NoError(t, os.Symlink(filepath.Join(dir, "gone.go"), filepath.Join(dir, "broken.go")))
+
+		mockLLMClient := new(mocks.LLMClient)
+		mockLLMClient.On("Generate", mock.Anything, mock.Anything).Return("# summary\n", nil)
+		mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+		service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient}, llm.WithPromptTemplate("{{.Directory}}"))
+		require.NoError(t, err)
+
+		cfg := config.NewDefaultConfig().WithTargetDir(dir).WithStrict(strict)
+		r := processDirectory(context.Background(), dir, true, filesystem.IgnoreChain{}, cfg, service)
+
+		if strict {
+			assert.False(t, r.success)
+			require.Error(t, r.err)
+			assert.Contains(t, r.err.Error(), "broken.go")
+			assert.NoFileExists(t, filepath.Join(dir, filesystem.GlanceFilename))
+			mockLLMClient.AssertNotCalled(t, "Generate", mock.Anything, mock.Anything)
+		} else {
+			assert.True(t, r.success, "lenient mode skips the file: %v", r.err)
+			assert.FileExists(t, filepath.Join(dir, filesystem.GlanceFilename))
+		}
+	}
+}
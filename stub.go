@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"glance/config"
+	"glance/filesystem"
+)
+
+// writeStub finishes r by writing a minimal glance.md with the given body for
+// a directory that is not worth an LLM call, or by writing nothing at all with
+// --no-empty-stubs.
+func writeStub(cfg *config.Config, r result, description string) result {
+	if cfg.NoEmptyStubs {
+		logrus.WithField("directory", r.dir).Debug("Not writing stub - empty stubs disabled")
+		r.success = true
+		r.outcome = outcomeSkipped
+		r.attempts = 0 // Nothing written, so parents are not marked for regeneration
+		return r
+	}
+
+	// Base(dir) is intentional: stub heading is a display label, not a path reference.
+	stub := fmt.Sprintf("# %s\n\n%s\n", filepath.Base(r.dir), description)
+	if cfg.Compare {
+		return compareGlance(cfg, r, stub, outcomeStub)
+	}
+	if stdoutSink != nil {
+		return streamGlance(r, stub, outcomeStub)
+	}
+	validatedPath, pathErr := glanceWritePath(cfg, r.dir)
+	if pathErr != nil {
+		r.err = fmt.Errorf("invalid glance.md path for %s: %w", r.dir, pathErr)
+		return r
+	}
+	if werr := filesystem.WriteFileAtomic(validatedPath, []byte(stub), cfg.TempDir); werr != nil {
+		r.err = fmt.Errorf("failed writing stub glance.md to %s: %w", r.dir, werr)
+		return r
+	}
+	r.success = true
+	r.outcome = outcomeStub
+	r.attempts = 1 // Counts as processed: triggers BubbleUpParents for parent regen
+	return r
+}
+
+// belowMinFiles reports whether a directory has too few analyzable files for
+// --min-files. Directories with child summaries always qualify.
+func belowMinFiles(cfg *config.Config, fileContents map[string]string, subGlances string) bool {
+	return len(fileContents) < cfg.MinFiles && strings.TrimSpace(subGlances) == ""
+}
+
+// minFilesStubDescription returns the stub body for a directory below the
+// --min-files threshold, naming the files it does have.
+func minFilesStubDescription(fileContents map[string]string) string {
+	names := make([]string, 0, len(fileContents))
+	for name := range fileContents {
+		names = append(names, "`"+name+"`")
+	}
+	sort.Strings(names)
+	return "Too few files to summarize: " + strings.Join(names, ", ") + "."
+}
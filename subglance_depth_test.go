@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+)
+
+func TestSubGlanceDepth(t *testing.T) {
+	// root/a/b/c and root/d, each with a summary naming its directory
+	root := t.TempDir()
+	for _, rel := range []string{"a", "a/b", "a/b/c", "d"} {
+		dir := filepath.Join(root, filepath.FromSlash(rel))
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, filesystem.GlanceFilename), []byte("summary of "+rel), 0600))
+	}
+	subdirs, err := readSubdirectories(root, nil)
+	require.NoError(t, err)
+	cfg := config.NewDefaultConfig().WithTargetDir(root)
+
+	got, err := subGlancesFor(cfg.WithSubGlanceDepth(1), root, subdirs, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "summary of a\n\nsummary of d", got, "depth 1 gathers immediate children only")
+
+	got, err = subGlancesFor(cfg.WithSubGlanceDepth(2), root, subdirs, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "summary of a\n\nsummary of a/b\n\nsummary of d", got,
+		"depth 2 adds grandchildren, each after its parent")
+	assert.NotContains(t, got, "summary of a/b/c")
+
+	got, err = subGlancesFor(cfg.WithSubGlanceDepth(5), root, subdirs, nil)
+	require.NoError(t, err)
+	assert.Contains(t, got, "summary of a/b/c", "depths beyond the tree gather every descendant")
+
+	got, err = subGlancesFor(cfg.WithSubGlanceDepth(2).WithMaxSubGlanceBytes(40), root, subdirs, nil)
+	require.NoError(t, err)
+	assert.Contains(t, got, "[a/b: summary omitted", "descendants are referenced by their relative path")
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestSubGlanceFreshness verifies that a parent regenerates when a child summary
+// changed but kept an mtime older than the parent's glance output.
+func TestSubGlanceFreshness(t *testing.T) {
+	root := t.TempDir()
+	child := filepath.Join(root, "child")
+	require.NoError(t, os.Mkdir(child, 0755))
+
+	past := time.Now().Add(-2 * time.Hour)
+	writeAt := func(path, content string, at time.Time) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		require.NoError(t, os.Chtimes(path, at, at))
+	}
+	writeAt(filepath.Join(root, "main.go"), "package main\n", past)
+	writeAt(filepath.Join(child, "lib.go"), "package child\n", past)
+	writeAt(filepath.Join(child, filesystem.GlanceFilename), "# child v1\n", past)
+
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).Return("# root summary\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient}, llm.WithPromptTemplate("{{.SubGlances}}"))
+	require.NoError(t, err)
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithExplain(true)
+	var explained bytes.Buffer
+	origOut := explainOut
+	explainOut = &explained
+	defer func() { explainOut = origOut }()
+
+	run := func() string {
+		explained.Reset()
+		processDirectories(context.Background(), []string{root}, map[string]filesystem.IgnoreChain{}, cfg, service, io.Discard)
+		return explained.String()
+	}
+
+	// First run writes the root summary with the hash of the child summaries
+	assert.Equal(t, ".: "+filesystem.GlanceFilename+" missing\n", run())
+	written, err := os.ReadFile(filepath.Join(root, filesystem.GlanceFilename))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(written), "# root summary\n"))
+	assert.Contains(t, string(written), filesystem.RenderSubGlanceHash(filesystem.HashSubGlances("# child v1\n")))
+
+	// Make the root output newest so only the hash can trigger regeneration
+	now := time.Now()
+	require.NoError(t, os.Chtimes(filepath.Join(root, filesystem.GlanceFilename), now, now))
+	require.NoError(t, os.Chtimes(child, past, past))
+
+	assert.Equal(t, ".: skipped (up-to-date)\n", run(), "unchanged child summaries keep the parent fresh")
+
+	// A restored child summary with an old mtime but different content
+	writeAt(filepath.Join(child, filesystem.GlanceFilename), "# child v2 from backup\n", past)
+	require.NoError(t, os.Chtimes(child, past, past))
+
+	assert.Equal(t, ".: child summaries changed\n", run())
+	mockLLMClient.AssertNumberOfCalls(t, "Generate", 2)
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"glance/config"
+	"glance/filesystem"
+)
+
+// subGlanceSeparator joins child summaries in the combined subglances text.
+const subGlanceSeparator = "\n\n"
+
+// minSubGlanceExcerpt is the smallest excerpt worth keeping from a child summary
+// when the combined subglances are limited; below it only a reference remains.
+const minSubGlanceExcerpt = 80
+
+// subGlance is one child directory's glance output.
+type subGlance struct {
+	name    string // subdirectory path relative to the parent, used in omission references
+	content string
+}
+
+// subGlancesFor returns the subdirectory summaries to include in dir's prompt
+// under cfg, read from the --output-dir mirror when one is set. subdirs are
+// dir's immediate subdirectories; with a --subglance-depth above 1, deeper
+// descendants' summaries follow each child's. In top-down mode children are
+// processed after their parent, so their summaries would be stale or missing;
+// none are gathered.
+func subGlancesFor(cfg *config.Config, dir string, subdirs []string, ignoreChain filesystem.IgnoreChain) (string, error) {
+	if cfg.TopDown {
+		return "", nil
+	}
+	dirs, err := descendantDirs(subdirs, cfg.SubGlanceDepth-1, ignoreChain, ignoreOptions(cfg))
+	if err != nil {
+		return "", err
+	}
+	if stdoutSink != nil {
+		return stdoutSink.subGlances(dir, dirs, cfg.MaxSubGlanceBytes), nil
+	}
+	return gatherSubGlancesLimited(glanceOutputDir(cfg, dir), glanceOutputDirs(cfg, dirs), cfg.MaxSubGlanceBytes, cfg.Strict)
+}
+
+// descendantDirs returns each of dirs followed by its descendants up to levels
+// further down, in pre-order, skipping hidden (unless included by opts) and
+// ignored directories. Deeper levels are filtered with the same ignoreChain,
+// as in filesystem.LatestModTime.
+func descendantDirs(dirs []string, levels int, ignoreChain filesystem.IgnoreChain, opts []filesystem.IgnoreOption) ([]string, error) {
+	if levels <= 0 {
+		return dirs, nil
+	}
+	var out []string
+	for _, d := range dirs {
+		children, err := readSubdirectories(d, ignoreChain, opts...)
+		if err != nil {
+			return nil, err
+		}
+		descendants, err := descendantDirs(children, levels-1, ignoreChain, opts)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+		out = append(out, descendants...)
+	}
+	return out, nil
+}
+
+// subGlancesChanged reports whether dir's current subdirectory summaries differ
+// from the hash stored in its glance output, so a parent regenerates even when
+// a child summary changed without getting a newer mtime. Outputs without a
+// stored hash, and any read failure, report false and leave the mtime check in
+// charge.
+func subGlancesChanged(dir string, ignoreChain filesystem.IgnoreChain, cfg *config.Config) bool {
+	if cfg.TopDown {
+		return false
+	}
+
+	stored, ok, err := filesystem.StoredSubGlanceHash(glanceOutputDir(cfg, dir))
+	if err != nil || !ok {
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"directory": dir,
+				"error":     err,
+			}).Debug("Couldn't read stored subglance hash")
+		}
+		return false
+	}
+
+	subdirs, err := readSubdirectories(dir, ignoreChain, ignoreOptions(cfg)...)
+	if err != nil {
+		return false
+	}
+	subGlances, err := subGlancesFor(cfg, dir, subdirs, ignoreChain)
+	if err != nil {
+		return false
+	}
+
+	if filesystem.HashSubGlances(subGlances) == stored {
+		return false
+	}
+	logrus.WithField("directory", dir).Debug("Child summaries changed since the glance output was written")
+	return true
+}
+
+// gatherSubGlances merges the contents of existing subdirectory glance output files.
+// Falls back to the legacy filename (glance.md) when the current filename (.glance.md)
+// is absent, so parent summaries remain complete during the upgrade migration window.
+// The baseDir parameter defines the security boundary for path validations within the function.
+func gatherSubGlances(baseDir string, subdirs []string) (string, error) {
+	return gatherSubGlancesLimited(baseDir, subdirs, 0, false)
+}
+
+// gatherSubGlancesLimited is gatherSubGlances with the combined output capped at
+// maxBytes (0 for no limit); see limitSubGlances. With strict set, a subdirectory
+// or glance output that fails validation or cannot be read is an error instead of
+// being skipped. A subdirectory with no glance output at all, including a missing
+// --output-dir mirror directory, is never an error.
+func gatherSubGlancesLimited(baseDir string, subdirs []string, maxBytes int64, strict bool) (string, error) {
+	var children []subGlance
+	for _, sd := range subdirs {
+		// Validate the subdirectory using the provided baseDir for consistent security boundary
+		validDir, err := filesystem.ValidateDirPath(sd, baseDir, true, true)
+		if errors.Is(err, fs.ErrNotExist) {
+			logrus.Debugf("Skipping subdirectory without glance output: %s", sd)
+			continue
+		}
+		if err != nil {
+			if strict {
+				return "", fmt.Errorf("strict mode: invalid subdirectory %s: %w", sd, err)
+			}
+			logrus.Warnf("Skipping invalid subdirectory for glance output collection: %v", err)
+			continue
+		}
+
+		// Resolve the glance output path: prefer current filename, fall back to legacy.
+		candidateNames := []string{filesystem.GlanceFilename, filesystem.LegacyGlanceFilename}
+		var validPath string
+		for _, name := range candidateNames {
+			p := filepath.Join(validDir, name)
+			vp, vpErr := filesystem.ValidateFilePath(p, validDir, true, true)
+			if vpErr == nil {
+				validPath = vp
+				break
+			}
+			if strict && !errors.Is(vpErr, fs.ErrNotExist) {
+				return "", fmt.Errorf("strict mode: invalid glance output %s: %w", p, vpErr)
+			}
+		}
+		if validPath == "" {
+			logrus.Debugf("Skipping invalid glance output path for subdirectory: %s", validDir)
+			continue
+		}
+
+		// Use filesystem.ReadTextFile instead of os.ReadFile
+		// This provides better validation and UTF-8 handling
+		content, err := filesystem.ReadTextFile(validPath, 0, validDir)
+		if err != nil {
+			if strict {
+				return "", fmt.Errorf("strict mode: failed to read %s: %w", validPath, err)
+			}
+			logrus.WithFields(logrus.Fields{
+				"path":  validPath,
+				"error": err,
+			}).Debug("Skipping unreadable glance output")
+			continue
+		}
+		// A child's --footer changes on every write; leaving it out keeps the
+		// parent's prompt, summary cache key, and stored hash stable
+		// Descendants below --subglance-depth 1 are named by their path from baseDir
+		name, relErr := filepath.Rel(baseDir, validDir)
+		if relErr != nil {
+			name = filepath.Base(validDir)
+		}
+		children = append(children, subGlance{name: filepath.ToSlash(name), content: filesystem.StripFooter(content)})
+	}
+	return limitSubGlances(children, maxBytes), nil
+}
+
+// limitSubGlances joins child summaries, keeping the result within maxBytes
+// (0 for no limit). When the summaries do not fit, the budget is shared fairly:
+// short summaries are kept whole and long ones are cut to an excerpt ending in a
+// reference such as "[api: 5120 more bytes omitted]". Children whose share is too
+// small for an excerpt are replaced by a reference alone, so every child is still
+// named. If the references alone exceed maxBytes they are kept anyway.
+func limitSubGlances(children []subGlance, maxBytes int64) string {
+	contents := make([]string, len(children))
+	total := int64(0)
+	for i, c := range children {
+		contents[i] = c.content
+		total += int64(len(c.content))
+	}
+	if len(children) > 1 {
+		total += int64((len(children) - 1) * len(subGlanceSeparator))
+	}
+	if maxBytes <= 0 || total <= maxBytes {
+		return strings.Join(contents, subGlanceSeparator)
+	}
+
+	// Water-fill from the shortest summary up, so short summaries stay whole and
+	// unused share flows to longer ones.
+	order := make([]int, len(children))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return len(children[order[a]].content) < len(children[order[b]].content)
+	})
+
+	remaining := maxBytes - int64((len(children)-1)*len(subGlanceSeparator))
+	for left, idx := len(order), 0; idx < len(order); left, idx = left-1, idx+1 {
+		i := order[idx]
+		share := remaining / int64(left)
+		contents[i] = fitSubGlance(children[i], share)
+		remaining -= int64(len(contents[i]))
+	}
+
+	out := strings.Join(contents, subGlanceSeparator)
+	if int64(len(out)) > maxBytes {
+		logrus.WithFields(logrus.Fields{
+			"max_bytes":   maxBytes,
+			"actual":      len(out),
+			"child_count": len(children),
+		}).Warn("Subdirectory references alone exceed --max-subglance-bytes")
+	}
+	return out
+}
+
+// fitSubGlance returns c's content if it fits in share bytes, otherwise an
+// excerpt with a trailing omission reference, or just a reference when the
+// share is too small for a useful excerpt.
+func fitSubGlance(c subGlance, share int64) string {
+	if int64(len(c.content)) <= share {
+		return c.content
+	}
+
+	// Size the excerpt against the widest possible reference
+	widest := int64(len(excerptReference(c.name, len(c.content))))
+	keep := share - widest
+	if keep < minSubGlanceExcerpt {
+		return fmt.Sprintf("[%s: summary omitted, %d bytes]", c.name, len(c.content))
+	}
+
+	excerpt := c.content[:keep]
+	// Prefer ending on a line boundary when one is reasonably close
+	if cut := strings.LastIndexByte(excerpt, '\n'); cut > len(excerpt)/2 {
+		excerpt = excerpt[:cut]
+	}
+	// Drop a trailing partial UTF-8 sequence
+	excerpt = strings.ToValidUTF8(excerpt, "")
+	return excerpt + excerptReference(c.name, len(c.content)-len(excerpt))
+}
+
+// excerptReference marks where a child summary was cut and how much was dropped.
+func excerptReference(name string, omitted int) string {
+	return fmt.Sprintf("\n[%s: %d more bytes omitted]", name, omitted)
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"glance/config"
+)
+
+// TestProviderTimeoutClientOptions verifies that each tier's client gets its
+// provider's default timeout, and --timeout when set.
+func TestProviderTimeoutClientOptions(t *testing.T) {
+	defaults := config.NewDefaultConfig()
+	assert.Equal(t, config.DefaultGeminiTimeoutSeconds,
+		applyClientOptions(geminiClientOptions(defaults, "gemini-2.5-flash")).Timeout)
+	assert.Equal(t, config.DefaultOpenRouterTimeoutSeconds,
+		applyClientOptions(openRouterClientOptions(defaults, "x-ai/grok-4.1-fast")).Timeout)
+
+	configured := defaults.WithTimeoutSeconds(300)
+	assert.Equal(t, 300, applyClientOptions(geminiClientOptions(configured, "gemini-2.5-flash")).Timeout)
+	assert.Equal(t, 300, applyClientOptions(openRouterClientOptions(configured, "x-ai/grok-4.1-fast")).Timeout)
+}
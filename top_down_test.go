@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestTopDown verifies that --top-down processes parents before children and
+// never puts child summaries into a prompt.
+func TestTopDown(t *testing.T) {
+	root := t.TempDir()
+	child := filepath.Join(root, "a")
+	grandchild := filepath.Join(child, "b")
+	require.NoError(t, os.MkdirAll(grandchild, 0755))
+	for _, d := range []string{root, child, grandchild} {
+		require.NoError(t, os.WriteFile(filepath.Join(d, "main.go"), []byte("package "+filepath.Base(d)+"\n"), 0600))
+	}
+	const childSummary = "EXISTING CHILD SUMMARY"
+	require.NoError(t, os.WriteFile(filepath.Join(child, filesystem.GlanceFilename), []byte(childSummary), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(grandchild, filesystem.GlanceFilename), []byte(childSummary), 0600))
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithForce(true).WithTopDown(true)
+
+	dirs, ignoreChains, err := scanDirectories(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{root, child, grandchild}, dirs, "parents come before children")
+
+	var prompts []string
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { prompts = append(prompts, args.String(1)) }).
+		Return("# summary\n", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+		llm.WithPromptTemplate("dir {{.Directory}}\nsubs [{{.SubGlances}}]\n{{.FileContents}}"))
+	require.NoError(t, err)
+
+	results, needsRegen := processDirectories(context.Background(), dirs, ignoreChains, cfg, service, io.Discard)
+	require.Len(t, results, 3)
+	for _, r := range results {
+		assert.True(t, r.success, "processing %s failed: %v", r.dir, r.err)
+	}
+	assert.Empty(t, needsRegen, "children never mark parents for regeneration")
+
+	require.Len(t, prompts, 3)
+	for i, want := range []string{"dir .\n", "dir a\n", "dir " + filepath.Join("a", "b") + "\n"} {
+		assert.True(t, strings.HasPrefix(prompts[i], want), "prompt %d: %q", i, prompts[i])
+		assert.Contains(t, prompts[i], "subs []", "no subglance content is gathered")
+		assert.NotContains(t, prompts[i], childSummary)
+	}
+}
+
+func TestSubGlancesFor(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(root, "a"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "a", filesystem.GlanceFilename), []byte("child summary"), 0600))
+	subdirs := []string{filepath.Join(root, "a")}
+	cfg := config.NewDefaultConfig().WithTargetDir(root)
+
+	got, err := subGlancesFor(cfg, root, subdirs, nil)
+	require.NoError(t, err)
+	assert.Contains(t, got, "child summary")
+
+	got, err = subGlancesFor(cfg.WithTopDown(true), root, subdirs, nil)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
@@ -2,6 +2,10 @@
 package ui
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/briandowns/spinner"
@@ -13,28 +17,63 @@ import (
 // -----------------------------------------------------------------------------
 
 // Spinner represents a terminal spinner for visual feedback during operations
-// that don't have easily quantifiable progress.
+// that don't have easily quantifiable progress. When its writer isn't a
+// terminal it prints its messages as plain lines instead of animating. All
+// methods are safe to call from multiple goroutines.
 type Spinner struct {
 	spinner  *spinner.Spinner
 	suffix   string
 	finalMsg string
 	speed    time.Duration
+
+	// Plain output, used instead of the animation when set
+	mu     sync.Mutex
+	writer io.Writer
+	plain  bool
 }
 
-// Start activates the spinner animation.
+// Start activates the spinner animation, or prints its message in plain mode.
 func (s *Spinner) Start() {
+	if s.plain {
+		s.printLine(s.suffix)
+		return
+	}
 	s.spinner.Start()
 }
 
 // Stop halts the spinner animation and displays the final message.
 func (s *Spinner) Stop() {
+	if s.plain {
+		s.printLine(s.finalMsg)
+		return
+	}
 	s.spinner.FinalMSG = s.finalMsg
 	s.spinner.Stop()
 }
 
-// UpdateMessage changes the message displayed alongside the spinner.
+// UpdateMessage changes the message displayed alongside the spinner. In plain
+// mode the new message is printed on its own line.
 func (s *Spinner) UpdateMessage(message string) {
+	s.spinner.Lock()
 	s.spinner.Suffix = " " + message
+	s.spinner.Unlock()
+	if s.plain {
+		s.printLine(message)
+	}
+}
+
+// printLine writes message as one plain line, adding the newline if missing.
+func (s *Spinner) printLine(message string) {
+	if message == "" {
+		return
+	}
+	if message[len(message)-1] != '\n' {
+		message += "\n"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Ignore error for non-critical UI
+	_, _ = fmt.Fprint(s.writer, message)
 }
 
 // SpinnerOption is a function type that configures a Spinner.
@@ -55,6 +94,18 @@ func WithFinalMessage(message string) SpinnerOption {
 	}
 }
 
+// WithWriter sets where the spinner writes; the default is standard output.
+// Plain lines are used when w isn't a terminal (see IsTerminal).
+func WithWriter(w io.Writer) SpinnerOption {
+	return func(s *Spinner) {
+		s.writer = w
+		s.spinner.Writer = w
+		if f, ok := w.(*os.File); ok {
+			s.spinner.WriterFile = f
+		}
+	}
+}
+
 // WithCharset sets the spinner's animation character set.
 func WithCharset(charset int) SpinnerOption {
 	return func(s *Spinner) {
@@ -80,6 +131,7 @@ func NewCustomSpinner(options ...SpinnerOption) *Spinner {
 		suffix:   "Processing...",
 		finalMsg: "Done!\n",
 		speed:    120 * time.Millisecond,
+		writer:   os.Stdout,
 	}
 
 	// Apply suffix to the spinner
@@ -89,24 +141,27 @@ func NewCustomSpinner(options ...SpinnerOption) *Spinner {
 	for _, option := range options {
 		option(s)
 	}
+	s.plain = !IsTerminal(s.writer)
 
 	return s
 }
 
 // NewScanner creates a spinner specifically for directory scanning operations.
-func NewScanner() *Spinner {
-	return NewCustomSpinner(
+// Options are applied after the scanner's defaults.
+func NewScanner(options ...SpinnerOption) *Spinner {
+	return NewCustomSpinner(append([]SpinnerOption{
 		WithSuffix("Scanning directories and loading .gitignore files..."),
 		WithFinalMessage("Scan complete!\n"),
-	)
+	}, options...)...)
 }
 
 // NewGenerator creates a spinner specifically for content generation operations.
-func NewGenerator() *Spinner {
-	return NewCustomSpinner(
+// Options are applied after the generator's defaults.
+func NewGenerator(options ...SpinnerOption) *Spinner {
+	return NewCustomSpinner(append([]SpinnerOption{
 		WithSuffix("Generating content..."),
 		WithFinalMessage("Generation complete!\n"),
-	)
+	}, options...)...)
 }
 
 // -----------------------------------------------------------------------------
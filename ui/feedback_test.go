@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"bytes"
 	"errors"
 	"strings"
 	"testing"
@@ -252,3 +253,16 @@ func TestSpinnerOnly(t *testing.T) {
 	// If we got here without panicking, the test passes
 	assert.True(t, true)
 }
+
+func TestSpinnerPlainOutput(t *testing.T) {
+	var out bytes.Buffer
+	s := NewScanner(WithWriter(&out))
+
+	s.Start()
+	s.UpdateMessage("Loading .gitignore files")
+	s.Stop()
+
+	assert.NotContains(t, out.String(), "\x1b", "no ANSI escape sequences")
+	assert.NotContains(t, out.String(), "\r", "no carriage-return redraws")
+	assert.Equal(t, "Scanning directories and loading .gitignore files...\nLoading .gitignore files\nScan complete!\n", out.String())
+}
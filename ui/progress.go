@@ -0,0 +1,169 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	progressbar "github.com/schollz/progressbar/v3"
+)
+
+// -----------------------------------------------------------------------------
+// Progress
+// -----------------------------------------------------------------------------
+
+// maxActiveShown caps how many in-flight directory names the description lists.
+const maxActiveShown = 3
+
+// plainSteps is how many progress lines plain output prints over a full run
+// by default, i.e. one line every 10% of the items.
+const plainSteps = 10
+
+// Progress is a progress bar that also shows which items are currently in
+// flight. When its writer isn't a terminal it prints plain progress lines
+// such as "Creating glance files: processed 20/100" instead of redrawing a
+// bar. All methods are safe to call from multiple goroutines.
+type Progress struct {
+	mu          sync.Mutex
+	bar         *progressbar.ProgressBar
+	description string
+	active      []string
+	completed   int
+
+	// Plain output, used instead of bar when set
+	plain    bool
+	out      io.Writer
+	total    int
+	step     int
+	reported int
+}
+
+// ProgressOption is a function type that configures a Progress.
+type ProgressOption func(*Progress)
+
+// WithPlainOutput forces plain progress lines on or off, overriding the
+// terminal detection.
+func WithPlainOutput(plain bool) ProgressOption {
+	return func(p *Progress) {
+		p.plain = plain
+	}
+}
+
+// WithPlainStep sets how many items complete between plain progress lines.
+// The default prints a line every 10% of the total. Values below 1 are ignored.
+func WithPlainStep(step int) ProgressOption {
+	return func(p *Progress) {
+		if step > 0 {
+			p.step = step
+		}
+	}
+}
+
+// NewProgress creates a progress bar for total items, written to out.
+// Pass io.Discard to suppress output. Plain progress lines are used when out
+// isn't a terminal (see IsTerminal).
+func NewProgress(total int, description string, out io.Writer, options ...ProgressOption) *Progress {
+	p := &Progress{
+		description: description,
+		plain:       !IsTerminal(out),
+		out:         out,
+		total:       total,
+		step:        max(1, total/plainSteps),
+	}
+	for _, option := range options {
+		option(p)
+	}
+
+	if !p.plain {
+		p.bar = progressbar.NewOptions(total,
+			progressbar.OptionSetDescription(description),
+			progressbar.OptionShowCount(),
+			progressbar.OptionSetWidth(40),
+			progressbar.OptionSetPredictTime(false),
+			progressbar.OptionSetWriter(out),
+		)
+	}
+	return p
+}
+
+// Increment marks one item as complete.
+func (p *Progress) Increment() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.completed++
+	if p.plain {
+		if p.completed%p.step == 0 || p.completed == p.total {
+			p.report()
+		}
+		return
+	}
+	// Ignore error for non-critical UI
+	_ = p.bar.Add(1)
+}
+
+// SetActive replaces the list of in-flight item names shown next to the bar.
+// Pass nil when nothing is in flight. Plain output doesn't show them.
+func (p *Progress) SetActive(names []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active = append([]string(nil), names...)
+	if !p.plain {
+		p.bar.Describe(formatActive(p.description, p.active))
+	}
+}
+
+// Active returns a copy of the in-flight item names.
+func (p *Progress) Active() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.active...)
+}
+
+// Completed returns the number of items marked complete.
+func (p *Progress) Completed() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.completed
+}
+
+// Finish clears the in-flight list and completes the bar.
+func (p *Progress) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active = nil
+	if p.plain {
+		if p.completed != p.reported {
+			p.report()
+		}
+		return
+	}
+	p.bar.Describe(p.description)
+	// Ignore error for non-critical UI
+	_ = p.bar.Finish()
+}
+
+// report prints a plain progress line for the current count. The caller must
+// hold p.mu.
+func (p *Progress) report() {
+	p.reported = p.completed
+	// Ignore error for non-critical UI
+	_, _ = fmt.Fprintf(p.out, "%s: processed %d/%d\n", p.description, p.completed, p.total)
+}
+
+// formatActive renders the bar description with the active count and up to
+// maxActiveShown names, e.g. "Creating glance files (2 active: pkg, cmd)".
+func formatActive(description string, active []string) string {
+	if len(active) == 0 {
+		return description
+	}
+	shown := active
+	if len(shown) > maxActiveShown {
+		shown = shown[:maxActiveShown]
+	}
+	names := strings.Join(shown, ", ")
+	if extra := len(active) - len(shown); extra > 0 {
+		names += fmt.Sprintf(", +%d more", extra)
+	}
+	return fmt.Sprintf("%s (%d active: %s)", description, len(active), names)
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressConcurrentUpdates(t *testing.T) {
+	const workers = 8
+	const perWorker = 25
+
+	p := NewProgress(workers*perWorker, "Creating glance files", io.Discard)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				p.SetActive([]string{fmt.Sprintf("worker-%d/dir-%d", w, i)})
+				_ = p.Active()
+				p.Increment()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, workers*perWorker, p.Completed())
+
+	p.Finish()
+	assert.Empty(t, p.Active(), "Finish should clear in-flight names")
+}
+
+func TestProgressSetActiveCopies(t *testing.T) {
+	p := NewProgress(1, "Creating glance files", io.Discard)
+	names := []string{"pkg"}
+	p.SetActive(names)
+	names[0] = "mutated"
+	assert.Equal(t, []string{"pkg"}, p.Active())
+}
+
+func TestFormatActive(t *testing.T) {
+	assert.Equal(t, "Working", formatActive("Working", nil))
+	assert.Equal(t, "Working (1 active: pkg)", formatActive("Working", []string{"pkg"}))
+	assert.Equal(t, "Working (3 active: a, b, c)", formatActive("Working", []string{"a", "b", "c"}))
+	assert.Equal(t, "Working (5 active: a, b, c, +2 more)", formatActive("Working", []string{"a", "b", "c", "d", "e"}))
+}
+
+func TestProgressPlainOutput(t *testing.T) {
+	var out bytes.Buffer
+	p := NewProgress(25, "Creating glance files", &out)
+
+	for i := 0; i < 25; i++ {
+		p.SetActive([]string{fmt.Sprintf("dir-%d", i)})
+		p.Increment()
+	}
+	p.Finish()
+
+	assert.False(t, IsTerminal(&out))
+	assert.NotContains(t, out.String(), "\x1b", "no ANSI escape sequences")
+	assert.NotContains(t, out.String(), "\r", "no carriage-return redraws")
+	assert.Equal(t, []string{
+		"Creating glance files: processed 2/25",
+		"Creating glance files: processed 4/25",
+		"Creating glance files: processed 6/25",
+		"Creating glance files: processed 8/25",
+		"Creating glance files: processed 10/25",
+		"Creating glance files: processed 12/25",
+		"Creating glance files: processed 14/25",
+		"Creating glance files: processed 16/25",
+		"Creating glance files: processed 18/25",
+		"Creating glance files: processed 20/25",
+		"Creating glance files: processed 22/25",
+		"Creating glance files: processed 24/25",
+		"Creating glance files: processed 25/25",
+	}, strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n"))
+}
+
+func TestProgressPlainStep(t *testing.T) {
+	var out bytes.Buffer
+	p := NewProgress(100, "Working", &out, WithPlainStep(40))
+	for i := 0; i < 90; i++ {
+		p.Increment()
+	}
+	p.Finish()
+	assert.Equal(t, "Working: processed 40/100\nWorking: processed 80/100\nWorking: processed 90/100\n", out.String(),
+		"Finish reports the final count when the run stops early")
+}
+
+func TestProgressForcedBar(t *testing.T) {
+	var out bytes.Buffer
+	p := NewProgress(2, "Working", &out, WithPlainOutput(false))
+	p.Increment()
+	p.Increment()
+	p.Finish()
+	assert.Contains(t, out.String(), "\r", "the bar redraws in place")
+	assert.NotContains(t, out.String(), "processed")
+}
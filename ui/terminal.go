@@ -0,0 +1,19 @@
+package ui
+
+import (
+	"io"
+
+	"github.com/mattn/go-isatty"
+)
+
+// IsTerminal reports whether w writes to a terminal. Writers that aren't
+// backed by a file descriptor, such as buffers and io.Discard, never are.
+// Animated output is only used for terminals; everything else gets plain
+// text lines that read well in CI logs.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(interface{ Fd() uintptr })
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// gatherFailure records r as failed at stage with err, logging message at
+// error level. A directory deleted since it was scanned, as can happen during
+// a long run or alongside another job, is a benign skip logged at debug
+// instead, so it does not show up as a failure in the run summary.
+func gatherFailure(r result, stage, message string, err error) result {
+	if _, statErr := os.Stat(r.dir); os.IsNotExist(statErr) {
+		logrus.WithFields(logrus.Fields{
+			"directory": r.dir,
+			"error":     err,
+			"stage":     stage,
+		}).Debug("Skipping directory - it no longer exists")
+		r.success = true
+		r.outcome = outcomeSkipped
+		return r
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"directory": r.dir,
+		"error":     err,
+		"stage":     stage,
+	}).Error(message)
+	r.err = err
+	return r
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/internal/mocks"
+	"glance/llm"
+)
+
+// TestProcessDirectoriesVanishedDirectory verifies that a directory deleted
+// after scanning but before processing is recorded as skipped, not failed.
+func TestProcessDirectoriesVanishedDirectory(t *testing.T) {
+	root := t.TempDir()
+	kept := filepath.Join(root, "kept")
+	gone := filepath.Join(kept, "gone")
+	require.NoError(t, os.MkdirAll(gone, 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(kept, "kept.go"), []byte("package kept"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(gone, "gone.go"), []byte("package gone"), 0600))
+
+	mockLLMClient := new(mocks.LLMClient)
+	mockLLMClient.On("Generate", mock.Anything, mock.Anything).Return("a fine summary", nil)
+	mockLLMClient.On("CountTokens", mock.Anything, mock.Anything).Return(10, nil).Maybe()
+	service, err := llm.NewService(&MockClient{LLMClient: mockLLMClient},
+		llm.WithPromptTemplate("dir {{.Directory}}\n{{.FileContents}}"))
+	require.NoError(t, err)
+
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithForce(true)
+	dirs, ignoreChains, err := scanDirectories(cfg)
+	require.NoError(t, err)
+	require.Contains(t, dirs, gone)
+
+	// Delete the directory between the scan and processing, as a concurrent job might
+	require.NoError(t, os.RemoveAll(gone))
+	results, _ := processDirectories(context.Background(), dirs, ignoreChains, cfg, service, io.Discard)
+	require.Len(t, results, 3)
+
+	vanished := results[0]
+	require.Equal(t, gone, vanished.dir, "the deepest directory is processed first")
+	assert.True(t, vanished.success)
+	assert.Equal(t, outcomeSkipped, vanished.outcome)
+	assert.NoError(t, vanished.err)
+	assert.NoDirExists(t, gone, "nothing is written for a vanished directory")
+
+	for _, r := range results[1:] {
+		assert.Equal(t, outcomeGenerated, r.outcome, "%s is unaffected", r.dir)
+	}
+}
+
+// TestGatherFailureExistingDirectory verifies that a gather error for a
+// directory that still exists remains a failure.
+func TestGatherFailureExistingDirectory(t *testing.T) {
+	dir := t.TempDir()
+	errRead := errors.New("permission denied")
+
+	r := gatherFailure(result{dir: dir}, "gather_local_files", "Failed to gather local files", errRead)
+
+	assert.False(t, r.success)
+	assert.Equal(t, outcomeFailed, r.outcome)
+	assert.ErrorIs(t, r.err, errRead)
+}
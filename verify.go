@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"glance/config"
+	"glance/filesystem"
+	"glance/llm"
+)
+
+// runVerify checks every existing glance file under cfg.TargetDir, or under
+// cfg.OutputDir when output is mirrored there, without regenerating anything.
+// It writes one line per problem found plus a count to out, and returns how
+// many files have problems.
+func runVerify(cfg *config.Config, out io.Writer) (int, error) {
+	root := cfg.TargetDir
+	if cfg.OutputDir != "" {
+		root = cfg.OutputDir
+	}
+	paths, err := filesystem.ListGlanceFiles(root, scanOptions(cfg)...)
+	if err != nil {
+		return 0, err
+	}
+
+	problems := 0
+	for _, path := range paths {
+		if err := verifyGlanceFile(path, root); err != nil {
+			problems++
+			fmt.Fprintf(out, "%s: %v\n", displayDir(root, path), err)
+		}
+	}
+
+	noun := "files"
+	if len(paths) == 1 {
+		noun = "file"
+	}
+	fmt.Fprintf(out, "Verified %d %s %s: %d with problems\n", len(paths), filesystem.GlanceFilename, noun, problems)
+	return problems, nil
+}
+
+// verifyExitCode runs --verify and returns the process exit code: 1 if any
+// glance file has problems, 0 otherwise. A failed scan is fatal.
+func verifyExitCode(cfg *config.Config, out io.Writer) int {
+	problems, err := runVerify(cfg, out)
+	if err != nil {
+		logrus.WithField("error", err).Fatal("Failed to verify glance files")
+	}
+	if problems > 0 {
+		return 1
+	}
+	return 0
+}
+
+// verifyGlanceFile returns the first sign that a glance file is corrupt or
+// truncated: invalid UTF-8, no content, or a code fence left open.
+func verifyGlanceFile(path, root string) error {
+	content, err := filesystem.ReadUTF8File(path, root)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(content) == "" {
+		return errors.New("file is empty")
+	}
+	return llm.CheckCodeFences(content)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"glance/config"
+	"glance/filesystem"
+)
+
+func TestRunVerify(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"clean":     "# clean\n\nA package with an example:\n\n```go\nfmt.Println(\"hi\")\n```\n",
+		"stub":      "# stub\n\nEmpty directory.\n",
+		"binary":    "# binary\n\nTruncated mid-rune: \xe2\x82\n",
+		"fence":     "# fence\n\nAn example:\n\n```go\nfmt.Println(\"hi\")\n",
+		"empty":     " \n\n",
+		"no-glance": "",
+	}
+	for dir, content := range files {
+		require.NoError(t, os.Mkdir(filepath.Join(root, dir), 0750))
+		if dir != "no-glance" {
+			require.NoError(t, os.WriteFile(filepath.Join(root, dir, filesystem.GlanceFilename), []byte(content), 0600))
+		}
+	}
+	cfg := config.NewDefaultConfig().WithTargetDir(root).WithVerify(true)
+
+	var out bytes.Buffer
+	problems, err := runVerify(cfg, &out)
+	require.NoError(t, err)
+	assert.Equal(t, 3, problems)
+
+	report := out.String()
+	assert.Contains(t, report, filepath.Join("binary", filesystem.GlanceFilename)+": content is not valid UTF-8 (invalid byte sequence at offset 30)")
+	assert.Contains(t, report, filepath.Join("fence", filesystem.GlanceFilename)+": ")
+	assert.Contains(t, report, "unclosed code fence opened on line 5")
+	assert.Contains(t, report, filepath.Join("empty", filesystem.GlanceFilename)+": file is empty")
+	assert.NotContains(t, report, filepath.Join("clean", filesystem.GlanceFilename)+":")
+	assert.NotContains(t, report, filepath.Join("stub", filesystem.GlanceFilename)+":", "short stubs are valid")
+	assert.Contains(t, report, "Verified 5 "+filesystem.GlanceFilename+" files: 3 with problems")
+	assert.Equal(t, 1, verifyExitCode(cfg, &bytes.Buffer{}))
+
+	for _, dir := range []string{"binary", "fence", "empty"} {
+		require.NoError(t, os.Remove(filepath.Join(root, dir, filesystem.GlanceFilename)))
+	}
+	out.Reset()
+	problems, err = runVerify(cfg, &out)
+	require.NoError(t, err)
+	assert.Zero(t, problems)
+	assert.Equal(t, "Verified 2 "+filesystem.GlanceFilename+" files: 0 with problems\n", out.String())
+	assert.Equal(t, 0, verifyExitCode(cfg, &bytes.Buffer{}))
+}